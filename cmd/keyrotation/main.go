@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"reflect"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gidyon/config"
+	"github.com/gidyon/micros"
+	"github.com/gidyon/pandemic-api/internal/encryption"
+	"github.com/gidyon/pandemic-api/internal/rest"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/locationstore"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	batchSize         = flag.Int("batch-size", 500, "number of rows re-encrypted in a transaction")
+	rotateDataKey     = flag.Bool("rotate-data-key", true, "create a new data key and re-encrypt rows with it")
+	dropLegacyColumns = flag.Bool("drop-legacy-columns", false, "drop plaintext location columns once they are encrypted")
+)
+
+func main() {
+	ctx := context.Background()
+
+	cfg, err := config.New()
+	handleErr(err)
+
+	service, err := micros.NewService(ctx, cfg, micros.NewLogger("keyrotation"))
+	handleErr(err)
+
+	sqlDB := service.GormDB()
+
+	cipher, err := encryption.NewCipherFromEnv(ctx, sqlDB)
+	handleErr(err)
+
+	encryption.SetDefault(cipher)
+
+	// Wrap data keys with the current key encryption key
+	rewrapped, err := cipher.RewrapDataKeys(ctx)
+	handleErr(err)
+
+	service.Logger().Infof("re-wrapped %d data keys", rewrapped)
+
+	if *rotateDataKey {
+		id, err := cipher.Rotate(ctx)
+		handleErr(err)
+
+		service.Logger().Infof("created data key %d", id)
+	}
+
+	// Locations may be kept in a different database
+	locationsDB, err := locationstore.DBFromEnv(sqlDB)
+	handleErr(err)
+
+	// Locations saved before coordinates were encrypted
+	count, err := encryptLegacyCoordinates(locationsDB, *batchSize)
+	handleErr(err)
+
+	service.Logger().Infof("encrypted coordinates of %d locations", count)
+
+	for _, table := range []struct {
+		db    *gorm.DB
+		model interface{}
+	}{
+		{sqlDB, &rest.Account{}},
+		{sqlDB, &rest.ConfirmedPatient{}},
+		{locationsDB, &services.LocationModel{}},
+	} {
+		if !table.db.HasTable(table.model) {
+			continue
+		}
+
+		count, err := reencryptTable(table.db, table.model, *batchSize)
+		handleErr(err)
+
+		service.Logger().Infof("re-encrypted %d rows in %s", count, table.db.NewScope(table.model).TableName())
+	}
+
+	if *dropLegacyColumns {
+		for _, column := range []string{"latitude", "longitude"} {
+			if !locationsDB.Dialect().HasColumn(services.LocationsTable, column) {
+				continue
+			}
+			handleErr(locationsDB.Model(&services.LocationModel{}).DropColumn(column).Error)
+		}
+	}
+
+	service.Logger().Infoln("key rotation complete")
+}
+
+// encryptLegacyCoordinates encrypts latitude and longitude of locations that have no coordinates
+func encryptLegacyCoordinates(sqlDB *gorm.DB, batchSize int) (int, error) {
+	if !sqlDB.Dialect().HasColumn(services.LocationsTable, "latitude") {
+		return 0, nil
+	}
+
+	type legacyLocation struct {
+		ID        uint
+		Latitude  float32
+		Longitude float32
+	}
+
+	total := 0
+
+	for {
+		locations := make([]*legacyLocation, 0, batchSize)
+		err := sqlDB.Table(services.LocationsTable).Select("id, latitude, longitude").
+			Where("coordinates=''").Order("id ASC").Limit(batchSize).Scan(&locations).Error
+		if err != nil {
+			return total, err
+		}
+
+		if len(locations) == 0 {
+			return total, nil
+		}
+
+		tx := sqlDB.Begin()
+		if tx.Error != nil {
+			return total, tx.Error
+		}
+
+		for _, loc := range locations {
+			coordinates, err := encryption.Encrypt(services.FormatCoordinates(loc.Latitude, loc.Longitude))
+			if err != nil {
+				tx.Rollback()
+				return total, err
+			}
+
+			err = tx.Table(services.LocationsTable).Where("id=?", loc.ID).
+				UpdateColumn("coordinates", coordinates).Error
+			if err != nil {
+				tx.Rollback()
+				return total, err
+			}
+		}
+
+		err = tx.Commit().Error
+		if err != nil {
+			return total, err
+		}
+
+		total += len(locations)
+	}
+}
+
+// reencryptTable loads rows in primary key order and saves them back.
+// Models decrypt their fields after find and encrypt them with the current data key before save,
+// so values already encrypted are re-encrypted rather than encrypted twice.
+func reencryptTable(sqlDB *gorm.DB, model interface{}, batchSize int) (int, error) {
+	var (
+		modelType  = reflect.TypeOf(model)
+		primaryKey = sqlDB.NewScope(model).PrimaryKey()
+		lastKey    interface{}
+		total      = 0
+	)
+
+	for {
+		rows := reflect.New(reflect.SliceOf(modelType))
+
+		db := sqlDB.Unscoped().Order(primaryKey + " ASC").Limit(batchSize)
+		if lastKey != nil {
+			db = db.Where(primaryKey+">?", lastKey)
+		}
+
+		err := db.Find(rows.Interface()).Error
+		if err != nil {
+			return total, err
+		}
+
+		n := rows.Elem().Len()
+		if n == 0 {
+			return total, nil
+		}
+
+		tx := sqlDB.Unscoped().Begin()
+		if tx.Error != nil {
+			return total, tx.Error
+		}
+
+		for i := 0; i < n; i++ {
+			err = tx.Save(rows.Elem().Index(i).Interface()).Error
+			if err != nil {
+				tx.Rollback()
+				return total, err
+			}
+		}
+
+		err = tx.Commit().Error
+		if err != nil {
+			return total, err
+		}
+
+		lastKey = sqlDB.NewScope(rows.Elem().Index(n - 1).Interface()).PrimaryKeyValue()
+		total += n
+	}
+}
+
+func handleErr(err error) {
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+}
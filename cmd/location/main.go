@@ -4,8 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/gidyon/micros/utils/healthcheck"
+	"github.com/gidyon/pandemic-api/internal/encryption"
+	"github.com/gidyon/pandemic-api/internal/pseudonym"
 	location_app "github.com/gidyon/pandemic-api/internal/services/location"
+	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
+	"github.com/gidyon/pandemic-api/internal/services/location/geocoding"
+	"github.com/gidyon/pandemic-api/internal/services/location/throttle"
+	"github.com/gidyon/pandemic-api/internal/services/locationstore"
 	http_error "github.com/gidyon/pandemic-api/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"net/http"
 	"os"
@@ -43,6 +50,9 @@ func main() {
 		AutoMigrator: func() error { return nil },
 	}))
 
+	// Ingestion metrics
+	app.AddEndpoint("/api/v1/locations/metrics", promhttp.Handler())
+
 	// Token endpoint
 	app.AddEndpointFunc("/api/v1/users/token/", func(w http.ResponseWriter, r *http.Request) {
 		phone := r.URL.Query().Get("phone_number")
@@ -83,11 +93,47 @@ func main() {
 
 		app.Logger().Infoln("connected to messaging service")
 
+		// Encryption of location coordinates
+		cipher, err := encryption.NewCipherFromEnv(ctx, app.GormDB())
+		handleErr(err)
+
+		encryption.SetDefault(cipher)
+
+		// Pseudonymous user ids for location data
+		pseudonymizer, err := pseudonym.NewPseudonymizerFromEnv(ctx, app.GormDB())
+		handleErr(err)
+
+		// Cells used as geo fences, must be the same in location and tracing services
+		cells, err := conversion.NewCellSystemFromEnv()
+		handleErr(err)
+
+		// Time windows used to match locations, must be the same in location and tracing services
+		timeBuckets, err := conversion.NewTimeBucketsFromEnv()
+		handleErr(err)
+
+		// Storage for location history
+		locationStore, err := locationstore.NewFromEnv(ctx, app.GormDB())
+		handleErr(err)
+
+		// Limits on real-time alerts sent to users
+		alertThrottler, err := throttle.NewFromEnv(app.RedisClient())
+		handleErr(err)
+
+		// Administrative boundaries for resolving the county, constituency and ward of locations
+		geocoder, err := geocoding.NewFromEnv()
+		handleErr(err)
+
 		// Create location tracing instance
 		locationAPI, err := location_app.NewLocationTracing(ctx, &location_app.Options{
 			LogsDB:          app.GormDB(),
 			EventsDB:        app.RedisClient(),
 			MessagingClient: messagingClient,
+			Pseudonymizer:   pseudonymizer,
+			CellSystem:      cells,
+			TimeBuckets:     timeBuckets,
+			LocationStore:   locationStore,
+			AlertThrottler:  alertThrottler,
+			Geocoder:        geocoder,
 			Logger:          app.Logger(),
 			RealTimeAlerts:  os.Getenv("ENABLE_REALTIME_ALERTS") == "true",
 		})
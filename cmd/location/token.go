@@ -10,6 +10,7 @@ import (
 	"github.com/jinzhu/gorm"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 func getToken(sqlDB *gorm.DB, phone, deviceID string) (string, error) {
@@ -28,9 +29,22 @@ func getToken(sqlDB *gorm.DB, phone, deviceID string) (string, error) {
 		return "", err
 	}
 
-	// Compare id
-	if userDB.DeviceToken != deviceID {
-		return "", status.Errorf(codes.InvalidArgument, "device id %s do not match with user", deviceID)
+	// Device must be registered to the user
+	deviceDB := &services.UserDevice{}
+	err = sqlDB.First(deviceDB, "phone_number=? AND device_token=?", phone, deviceID).Error
+	switch {
+	case err == nil:
+		err = sqlDB.Model(deviceDB).Update("last_seen_at", time.Now()).Error
+		if err != nil {
+			return "", status.Errorf(codes.Internal, "failed to update device: %v", err)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Users who have not registered devices use the device token saved with them
+		if userDB.DeviceToken != deviceID {
+			return "", status.Errorf(codes.InvalidArgument, "device id %s do not match with user", deviceID)
+		}
+	default:
+		return "", status.Errorf(codes.Internal, "failed to get device: %v", err)
 	}
 
 	// Generate token
@@ -39,6 +53,6 @@ func getToken(sqlDB *gorm.DB, phone, deviceID string) (string, error) {
 		FullName:     randomdata.SillyName(),
 		EmailAddress: randomdata.Email(),
 		PhoneNumber:  phone,
-		Group:        "USER",
+		Group:        auth.UserGroup,
 	}, "", 0)
 }
@@ -10,6 +10,7 @@ import (
 
 	messaging_app "github.com/gidyon/pandemic-api/internal/services/messaging"
 
+	"github.com/gidyon/pandemic-api/pkg/api/location"
 	"github.com/gidyon/pandemic-api/pkg/api/messaging"
 
 	"github.com/gidyon/micros"
@@ -41,16 +42,45 @@ func main() {
 		AutoMigrator: func() error { return nil },
 	}))
 
+	// USSD menu of unread messages, served when a gateway is configured
+	ussdHandler, err := messaging_app.NewUSSDHandlerFromEnv(app.GormDB(), app.Logger())
+	handleErr(err)
+	if ussdHandler != nil {
+		app.AddEndpoint("/api/v1/messaging/ussd", ussdHandler)
+	}
+
 	app.Start(ctx, func() error {
 		// FCM client
 		fcmClient, err := fcm.NewClient(os.Getenv("FCM_SERVER_KEY"))
 		handleErr(err)
 
+		// SMS and email channels
+		smsChannel, err := messaging_app.NewSMSChannelFromEnv()
+		handleErr(err)
+
+		emailChannel, err := messaging_app.NewEmailChannelFromEnv()
+		handleErr(err)
+
+		// FCM topics broadcasts are pushed to
+		topicManager, err := messaging_app.NewInstanceIDTopicManagerFromEnv()
+		handleErr(err)
+
+		// Location service resolves users seen in geo fences. It is not waited for since it connects
+		// to this service on startup
+		cc, err := app.DialExternalService(ctx, "location", nil)
+		handleErr(err)
+
+		locationClient := location.NewLocationTracingAPIClient(cc)
+
 		// Create messaging tracing instance
 		messagingAPI, err := messaging_app.NewMessagingServer(ctx, &messaging_app.Options{
-			SQLDB:     app.GormDB(),
-			FCMClient: fcmClient,
-			Logger:    app.Logger(),
+			SQLDB:          app.GormDB(),
+			RedisClient:    app.RedisClient(),
+			FCMClient:      fcmClient,
+			TopicManager:   topicManager,
+			LocationClient: locationClient,
+			Channels:       []messaging_app.Channel{smsChannel, emailChannel},
+			Logger:         app.Logger(),
 		})
 		handleErr(err)
 
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 
 	"github.com/gidyon/micros"
+	"github.com/gidyon/pandemic-api/internal/encryption"
 	"github.com/gidyon/pandemic-api/internal/rest"
 	"github.com/gidyon/pandemic-api/pkg/middleware"
 	"github.com/julienschmidt/httprouter"
@@ -27,6 +29,12 @@ func registerHandlers(srv *micros.Service) {
 	// Start revisions manager
 	rest.StartRevisionManager(srv.GormDB())
 
+	// Encryption of personal data in accounts and confirmed cases
+	cipher, err := encryption.NewCipherFromEnv(context.Background(), srv.GormDB())
+	handleErr(err)
+
+	encryption.SetDefault(cipher)
+
 	// Account API
 	rest.RegisterAccountAPI(router, srv.GormDB())
 
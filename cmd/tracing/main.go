@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"github.com/gidyon/micros/utils/healthcheck"
+	"github.com/gidyon/pandemic-api/internal/pseudonym"
+	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
 	"github.com/gidyon/pandemic-api/pkg/api/messaging"
 	"strings"
 
@@ -52,11 +54,26 @@ func main() {
 
 	app.Logger().Infoln("connected to messaging service")
 
+	// Pseudonymous user ids for location data
+	pseudonymizer, err := pseudonym.NewPseudonymizerFromEnv(ctx, app.GormDB())
+	handleErr(err)
+
+	// Cells used as geo fences, must be the same in location and tracing services
+	cells, err := conversion.NewCellSystemFromEnv()
+	handleErr(err)
+
+	// Time windows used to match locations, must be the same in location and tracing services
+	timeBuckets, err := conversion.NewTimeBucketsFromEnv()
+	handleErr(err)
+
 	// Create contact_tracing tracing instance
 	tracingAPI, err := tracing_service.NewContactTracingAPI(ctx, &tracing_service.Options{
 		SQLDB:           app.GormDB(),
 		RedisClient:     app.RedisClient(),
 		MessagingClient: messagingClient,
+		Pseudonymizer:   pseudonymizer,
+		CellSystem:      cells,
+		TimeBuckets:     timeBuckets,
 		Logger:          app.Logger(),
 	})
 	handleErr(err)
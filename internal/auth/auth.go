@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+
 	"github.com/gidyon/pandemic-api/internal/services"
 
 	"google.golang.org/grpc/codes"
@@ -11,6 +13,15 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// Groups of token holders
+const (
+	UserGroup          = "USER"
+	HealthOfficerGroup = "HEALTH_OFFICER"
+	AdminGroup         = "ADMIN"
+	// ServiceGroup holds services calling internal APIs of other services for requests they authorized
+	ServiceGroup = "SERVICE"
+)
+
 // AuthenticateRequest authenticates incoming request
 func AuthenticateRequest(ctx context.Context) error {
 	_, err := ParseFromCtx(ctx)
@@ -52,6 +63,25 @@ func AuthenticateGroup(ctx context.Context, group string) (*Payload, error) {
 	return claims.Payload, nil
 }
 
+// AuthenticateGroups authenticates whether token belongs to member of any of the groups
+func AuthenticateGroups(ctx context.Context, groups ...string) (*Payload, error) {
+	claims, err := ParseFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if claims.Payload.Group == group {
+			return claims.Payload, nil
+		}
+	}
+	return nil, status.Errorf(
+		codes.PermissionDenied,
+		"permission denied: group %s does not belong to any of %s groups",
+		claims.Payload.Group,
+		strings.Join(groups, ", "),
+	)
+}
+
 // AuthenticateGroupAndID authenticates member of a particular group and ad having given ID
 func AuthenticateGroupAndID(ctx context.Context, group, tokenID string) error {
 	payload, err := AuthenticateGroup(ctx, group)
@@ -67,6 +67,16 @@ func GenToken(
 	return token.SignedString(signingKey)
 }
 
+// GenServiceToken generates a token identifying a service to the internal APIs of other services
+func GenServiceToken(ctx context.Context, service string, expires int64) (string, error) {
+	return GenToken(ctx, &Payload{
+		ID:       service,
+		FullName: service,
+		Label:    service,
+		Group:    ServiceGroup,
+	}, ServiceGroup, expires)
+}
+
 // ParseToken parses a jwt token and return claims or error if token is invalid
 func ParseToken(tokenString string) (claims *Claims, err error) {
 	// Handling any panic is good trust me!
@@ -0,0 +1,116 @@
+package devicetoken
+
+import (
+	"time"
+
+	"github.com/appleboy/go-fcm"
+	"github.com/jinzhu/gorm"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+)
+
+// None is the device token of users without a device that can receive push notifications
+const None = "NA"
+
+// Invalid reports whether FCM rejected a token for good, such that messages to it will never be delivered
+func Invalid(err error) bool {
+	switch err {
+	case fcm.ErrNotRegistered, fcm.ErrInvalidRegistration, fcm.ErrMissingRegistration, fcm.ErrMismatchSenderID:
+		return true
+	}
+	return false
+}
+
+// Update is a change of a device token reported by FCM
+type Update struct {
+	Token string
+	// Canonical is the token the device is now registered with
+	Canonical string
+	Invalid   bool
+}
+
+// FromResult returns the update of a token from the result of sending a message to it, nil when it did not change
+func FromResult(token string, result fcm.Result) *Update {
+	switch {
+	case Invalid(result.Error):
+		return &Update{Token: token, Invalid: true}
+	case result.RegistrationID != "" && result.RegistrationID != token:
+		return &Update{Token: token, Canonical: result.RegistrationID}
+	}
+	return nil
+}
+
+// FromResponse returns the updates of tokens a multicast message was sent to.
+// Results of a multicast message are in the order of its registration ids.
+func FromResponse(tokens []string, res *fcm.Response) []*Update {
+	if res == nil {
+		return nil
+	}
+
+	updates := make([]*Update, 0, res.Failure+res.CanonicalIDs)
+	for i, result := range res.Results {
+		if i >= len(tokens) {
+			break
+		}
+		if update := FromResult(tokens[i], result); update != nil {
+			updates = append(updates, update)
+		}
+	}
+
+	return updates
+}
+
+// Apply removes devices with invalid tokens and replaces tokens with their canonical tokens.
+// Users whose token is cleared fall back to their most recently seen device, or None when they have no other device.
+// Cleared tokens are marked with the time they were found invalid so that they can be counted.
+func Apply(db *gorm.DB, updates []*Update) error {
+	for _, update := range updates {
+		var err error
+		switch {
+		case update.Invalid:
+			err = removeToken(db, update.Token)
+		case update.Canonical != "":
+			err = replaceToken(db, update.Token, update.Canonical)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeToken(db *gorm.DB, token string) error {
+	err := db.Unscoped().Delete(&services.UserDevice{}, "device_token = ?", token).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Table(services.UsersTable).Where("device_token = ?", token).Updates(map[string]interface{}{
+		"device_token": gorm.Expr(
+			"COALESCE((SELECT device_token FROM "+services.UserDevicesTable+
+				" WHERE phone_number = "+services.UsersTable+".phone_number ORDER BY last_seen_at DESC LIMIT 1), ?)",
+			None,
+		),
+		"device_token_invalid_at": time.Now(),
+	}).Error
+}
+
+func replaceToken(db *gorm.DB, token, canonical string) error {
+	var registered int
+	err := db.Model(&services.UserDevice{}).Where("device_token = ?", canonical).Count(&registered).Error
+	if err != nil {
+		return err
+	}
+
+	// Device tokens are unique, a device registered with both tokens keeps the canonical one
+	if registered > 0 {
+		err = db.Unscoped().Delete(&services.UserDevice{}, "device_token = ?", token).Error
+	} else {
+		err = db.Model(&services.UserDevice{}).Where("device_token = ?", token).Update("device_token", canonical).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	return db.Table(services.UsersTable).Where("device_token = ?", token).Update("device_token", canonical).Error
+}
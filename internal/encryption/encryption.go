@@ -0,0 +1,431 @@
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	keySize        = 32
+	prefix         = "enc:v1:"
+	blindIndexName = "blind-index"
+	dataKeyPrefix  = "dek-"
+)
+
+// DataKeysTable is table containing wrapped data encryption keys
+const DataKeysTable = "data_keys"
+
+// DataKey is a data encryption key wrapped with a key encryption key
+type DataKey struct {
+	ID         uint   `gorm:"primary_key"`
+	Name       string `gorm:"unique_index;type:varchar(50);not null"`
+	KeyID      string `gorm:"type:varchar(100);not null"`
+	WrappedKey []byte `gorm:"type:blob;not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TableName returns the name of the table
+func (*DataKey) TableName() string {
+	return DataKeysTable
+}
+
+// Cipher encrypts values with envelope encryption.
+//
+// Values are encrypted with AES-GCM data keys, which are stored in the database wrapped with a
+// key encryption key from the key provider. Ciphertexts carry the id of their data key so that
+// values encrypted with older data keys can still be decrypted after rotation.
+type Cipher struct {
+	sqlDB    *gorm.DB
+	provider KeyProvider
+	mu       *sync.RWMutex
+	current  uint
+	dataKeys map[uint]cipher.AEAD
+	indexKey []byte
+}
+
+// Options contains parameters for NewCipher
+type Options struct {
+	SQLDB       *gorm.DB
+	KeyProvider KeyProvider
+}
+
+// NewCipher creates a cipher that encrypts with the newest data key, creating one if none exists
+func NewCipher(ctx context.Context, opt *Options) (*Cipher, error) {
+	var err error
+	// Validation
+	switch {
+	case ctx == nil:
+		err = errors.New("non-nil context must not be nil")
+	case opt == nil:
+		err = errors.New("non-nil options is required")
+	case opt.SQLDB == nil:
+		err = errors.New("non-nil sqlDB is required")
+	case opt.KeyProvider == nil:
+		err = errors.New("non-nil key provider is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cipher{
+		sqlDB:    opt.SQLDB,
+		provider: opt.KeyProvider,
+		mu:       &sync.RWMutex{},
+		dataKeys: make(map[uint]cipher.AEAD),
+	}
+
+	// Automigration
+	err = c.sqlDB.AutoMigrate(&DataKey{}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to automigrate: %v", err)
+	}
+
+	// The blind index key is never rotated, otherwise all indexes would have to be recomputed
+	c.indexKey, err = c.getOrCreateKey(ctx, blindIndexName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blind index key: %v", err)
+	}
+
+	// Newest data key
+	dataKeyDB := &DataKey{}
+	err = c.sqlDB.Order("id DESC").First(dataKeyDB, "name LIKE ?", dataKeyPrefix+"%").Error
+	switch {
+	case err == nil:
+		_, err = c.getDataKey(ctx, dataKeyDB.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.current = dataKeyDB.ID
+	case gorm.IsRecordNotFoundError(err):
+		_, err = c.Rotate(ctx)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("failed to get data key: %v", err)
+	}
+
+	return c, nil
+}
+
+func newKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	_, err := io.ReadFull(rand.Reader, key)
+	return key, err
+}
+
+func (c *Cipher) createKey(ctx context.Context, name string) (*DataKey, []byte, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+
+	keyID, wrapped, err := c.provider.WrapKey(ctx, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to wrap key: %v", err)
+	}
+
+	dataKeyDB := &DataKey{
+		Name:       name,
+		KeyID:      keyID,
+		WrappedKey: wrapped,
+	}
+
+	err = c.sqlDB.Create(dataKeyDB).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dataKeyDB, key, nil
+}
+
+func (c *Cipher) getOrCreateKey(ctx context.Context, name string) ([]byte, error) {
+	dataKeyDB := &DataKey{}
+	err := c.sqlDB.First(dataKeyDB, "name=?", name).Error
+	switch {
+	case err == nil:
+	case gorm.IsRecordNotFoundError(err):
+		_, key, err := c.createKey(ctx, name)
+		if err == nil {
+			return key, nil
+		}
+		// Another instance may have created the key first
+		err = c.sqlDB.First(dataKeyDB, "name=?", name).Error
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	return c.provider.UnwrapKey(ctx, dataKeyDB.KeyID, dataKeyDB.WrappedKey)
+}
+
+func (c *Cipher) getDataKey(ctx context.Context, id uint) (cipher.AEAD, error) {
+	c.mu.RLock()
+	gcm, ok := c.dataKeys[id]
+	c.mu.RUnlock()
+	if ok {
+		return gcm, nil
+	}
+
+	dataKeyDB := &DataKey{}
+	err := c.sqlDB.First(dataKeyDB, "id=?", id).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data key %d: %v", id, err)
+	}
+
+	key, err := c.provider.UnwrapKey(ctx, dataKeyDB.KeyID, dataKeyDB.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %d: %v", id, err)
+	}
+
+	gcm, err = newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.dataKeys[id] = gcm
+	c.mu.Unlock()
+
+	return gcm, nil
+}
+
+// Rotate creates a new data key and uses it for subsequent encryptions.
+//
+// Other running instances keep using their data key until they are restarted.
+func (c *Cipher) Rotate(ctx context.Context) (uint, error) {
+	dataKeyDB, key, err := c.createKey(ctx, dataKeyPrefix+strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create data key: %v", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.dataKeys[dataKeyDB.ID] = gcm
+	c.current = dataKeyDB.ID
+	c.mu.Unlock()
+
+	return dataKeyDB.ID, nil
+}
+
+// RewrapDataKeys wraps all data keys with the current key encryption key of the key provider
+func (c *Cipher) RewrapDataKeys(ctx context.Context) (int, error) {
+	dataKeysDB := make([]*DataKey, 0)
+	err := c.sqlDB.Find(&dataKeysDB, "key_id<>?", c.provider.CurrentKeyID()).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get data keys: %v", err)
+	}
+
+	for _, dataKeyDB := range dataKeysDB {
+		key, err := c.provider.UnwrapKey(ctx, dataKeyDB.KeyID, dataKeyDB.WrappedKey)
+		if err != nil {
+			return 0, fmt.Errorf("failed to unwrap data key %d: %v", dataKeyDB.ID, err)
+		}
+
+		keyID, wrapped, err := c.provider.WrapKey(ctx, key)
+		if err != nil {
+			return 0, fmt.Errorf("failed to wrap data key %d: %v", dataKeyDB.ID, err)
+		}
+
+		err = c.sqlDB.Model(dataKeyDB).Updates(map[string]interface{}{
+			"key_id":      keyID,
+			"wrapped_key": wrapped,
+		}).Error
+		if err != nil {
+			return 0, fmt.Errorf("failed to update data key %d: %v", dataKeyDB.ID, err)
+		}
+	}
+
+	return len(dataKeysDB), nil
+}
+
+// IsEncrypted checks whether the value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt encrypts value with the current data key, empty values are not encrypted.
+//
+// Values that look encrypted are encrypted as well, values already encrypted are decrypted before they are re-encrypted.
+func (c *Cipher) Encrypt(value string) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+
+	c.mu.RLock()
+	id, gcm := c.current, c.dataKeys[c.current]
+	c.mu.RUnlock()
+
+	ciphertext, err := seal(gcm, []byte(value))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt: %v", err)
+	}
+
+	return fmt.Sprintf("%s%d:%s", prefix, id, base64.RawStdEncoding.EncodeToString(ciphertext)), nil
+}
+
+// Decrypt decrypts a value returned by Encrypt, values that are not encrypted are returned as is
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, prefix), ":", 2)
+	if len(parts) != 2 {
+		return "", errors.New("malformed ciphertext")
+	}
+
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("malformed ciphertext data key: %v", err)
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed ciphertext: %v", err)
+	}
+
+	gcm, err := c.getDataKey(context.Background(), uint(id))
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(gcm, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %v", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// BlindIndex returns a keyed hash of value that can be used for equality lookups
+func (c *Cipher) BlindIndex(value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrNoDefaultCipher is returned when fields are encrypted or indexed before a default cipher is set
+var ErrNoDefaultCipher = errors.New("no default cipher is set")
+
+var (
+	defaultCipher *Cipher
+	// plaintext passes values through as is when no default cipher is set
+	plaintext bool
+)
+
+// SetDefault sets the cipher used by models to encrypt their fields
+func SetDefault(c *Cipher) {
+	defaultCipher = c
+}
+
+// Default returns the cipher used by models to encrypt their fields
+func Default() *Cipher {
+	return defaultCipher
+}
+
+// SetPlaintextForTesting makes Encrypt, Decrypt and BlindIndex return values as is when no default cipher is set.
+// It is only for tests; services fail to save personal data until a default cipher is set.
+func SetPlaintextForTesting(enabled bool) {
+	plaintext = enabled
+}
+
+// Encrypt encrypts value with the default cipher
+func Encrypt(value string) (string, error) {
+	if defaultCipher == nil {
+		if plaintext {
+			return value, nil
+		}
+		return "", ErrNoDefaultCipher
+	}
+	return defaultCipher.Encrypt(value)
+}
+
+// Decrypt decrypts value with the default cipher
+func Decrypt(value string) (string, error) {
+	if defaultCipher == nil {
+		if plaintext {
+			return value, nil
+		}
+		return "", ErrNoDefaultCipher
+	}
+	return defaultCipher.Decrypt(value)
+}
+
+// BlindIndex returns the blind index of value with the default cipher
+func BlindIndex(value string) (string, error) {
+	if defaultCipher == nil {
+		if plaintext {
+			return value, nil
+		}
+		return "", ErrNoDefaultCipher
+	}
+	return defaultCipher.BlindIndex(value), nil
+}
+
+// EncryptFields encrypts the given fields in place
+func EncryptFields(fields ...*string) error {
+	for _, field := range fields {
+		value, err := Encrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+	return nil
+}
+
+// DecryptFields decrypts the given fields in place
+func DecryptFields(fields ...*string) error {
+	for _, field := range fields {
+		value, err := Decrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+	return nil
+}
+
+// NewCipherFromEnv creates a cipher using the local key file at ENCRYPTION_KEY_FILE
+func NewCipherFromEnv(ctx context.Context, sqlDB *gorm.DB) (*Cipher, error) {
+	keyFile := strings.TrimSpace(os.Getenv("ENCRYPTION_KEY_FILE"))
+	if keyFile == "" {
+		return nil, errors.New("ENCRYPTION_KEY_FILE is required")
+	}
+
+	provider, err := NewLocalKeyProvider(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCipher(ctx, &Options{
+		SQLDB:       sqlDB,
+		KeyProvider: provider,
+	})
+}
@@ -0,0 +1,333 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+
+	// sqlite driver for the data keys table
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// testDB opens a sqlite database in a temporary directory removed when the test ends
+func testDB(t *testing.T) *gorm.DB {
+	dir, err := ioutil.TempDir("", "encryption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	sqlDB, err := gorm.Open("sqlite3", filepath.Join(dir, "keys.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return sqlDB
+}
+
+// testKeyFile writes a local key file with the key encryption keys and returns its path
+func testKeyFile(t *testing.T, current string, keys map[string][]byte) string {
+	kf := &localKeyFile{Current: current, Keys: make(map[string]string, len(keys))}
+	for keyID, key := range keys {
+		kf.Keys[keyID] = base64.StdEncoding.EncodeToString(key)
+	}
+
+	bs, err := json.Marshal(kf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "encryption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	keyFile := filepath.Join(dir, "keys.json")
+	err = ioutil.WriteFile(keyFile, bs, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return keyFile
+}
+
+func testKey(t *testing.T) []byte {
+	key := make([]byte, keySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// testCipher creates a cipher on the database whose data keys are wrapped with the key encryption keys
+func testCipher(t *testing.T, sqlDB *gorm.DB, current string, keys map[string][]byte) *Cipher {
+	provider, err := NewLocalKeyProvider(testKeyFile(t, current, keys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewCipher(context.Background(), &Options{SQLDB: sqlDB, KeyProvider: provider})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	c := testCipher(t, testDB(t), "k1", map[string][]byte{"k1": testKey(t)})
+
+	for _, tt := range []struct {
+		name  string
+		value string
+	}{
+		{"phone number", "+254712345678"},
+		{"unicode", "Wanjiku Kamau — Nyeri"},
+		{"value that looks encrypted", prefix + "1:AAAA"},
+		{"prefix only", prefix},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := c.Encrypt(tt.value)
+			if err != nil {
+				t.Fatalf("failed to encrypt: %v", err)
+			}
+			if encrypted == tt.value || !IsEncrypted(encrypted) {
+				t.Fatalf("value %q was not encrypted: %q", tt.value, encrypted)
+			}
+
+			decrypted, err := c.Decrypt(encrypted)
+			if err != nil {
+				t.Fatalf("failed to decrypt: %v", err)
+			}
+			if decrypted != tt.value {
+				t.Fatalf("decrypted %q, want %q", decrypted, tt.value)
+			}
+		})
+	}
+}
+
+func TestEncryptEmpty(t *testing.T) {
+	c := testCipher(t, testDB(t), "k1", map[string][]byte{"k1": testKey(t)})
+
+	encrypted, err := c.Encrypt("")
+	if err != nil || encrypted != "" {
+		t.Fatalf("Encrypt(\"\") = %q, %v; want empty value", encrypted, err)
+	}
+}
+
+func TestDecryptPlaintext(t *testing.T) {
+	c := testCipher(t, testDB(t), "k1", map[string][]byte{"k1": testKey(t)})
+
+	// Values saved before encryption are read as is
+	decrypted, err := c.Decrypt("+254712345678")
+	if err != nil || decrypted != "+254712345678" {
+		t.Fatalf("Decrypt of plaintext = %q, %v", decrypted, err)
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	sqlDB := testDB(t)
+	c := testCipher(t, sqlDB, "k1", map[string][]byte{"k1": testKey(t)})
+
+	encrypted, err := c.Encrypt("+254712345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Flips a bit of the last byte of the ciphertext
+	parts := strings.SplitN(strings.TrimPrefix(encrypted, prefix), ":", 2)
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext[len(ciphertext)-1] ^= 1
+	tampered := prefix + parts[0] + ":" + base64.RawStdEncoding.EncodeToString(ciphertext)
+
+	// The same data key id in another database holds another key
+	other := testCipher(t, testDB(t), "k1", map[string][]byte{"k1": testKey(t)})
+
+	for _, tt := range []struct {
+		name   string
+		cipher *Cipher
+		value  string
+	}{
+		{"tampered ciphertext", c, tampered},
+		{"wrong data key", other, encrypted},
+		{"unknown data key", c, prefix + "999:" + parts[1]},
+		{"missing data key id", c, prefix + parts[1]},
+		{"malformed data key id", c, prefix + "x:" + parts[1]},
+		{"malformed ciphertext", c, prefix + parts[0] + ":%%%"},
+		{"short ciphertext", c, prefix + parts[0] + ":AAAA"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			decrypted, err := tt.cipher.Decrypt(tt.value)
+			if err == nil {
+				t.Fatalf("decrypted %q, want an error", decrypted)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	sqlDB := testDB(t)
+	keys := map[string][]byte{"k1": testKey(t)}
+	c := testCipher(t, sqlDB, "k1", keys)
+
+	before, err := c.Encrypt("+254712345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := c.Rotate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := c.Encrypt("+254712345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(after, prefix+strconv.FormatUint(uint64(id), 10)+":") {
+		t.Fatalf("value %q is not encrypted with the new data key %d", after, id)
+	}
+
+	// Another instance encrypts with the newest data key and decrypts with older ones
+	restarted := testCipher(t, sqlDB, "k1", keys)
+	if restarted.current != id {
+		t.Fatalf("restarted cipher uses data key %d, want %d", restarted.current, id)
+	}
+
+	for _, value := range []string{before, after} {
+		decrypted, err := restarted.Decrypt(value)
+		if err != nil || decrypted != "+254712345678" {
+			t.Fatalf("Decrypt(%q) = %q, %v", value, decrypted, err)
+		}
+	}
+}
+
+func TestRewrapDataKeys(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		sqlDB  = testDB(t)
+		oldKEK = testKey(t)
+		newKEK = testKey(t)
+	)
+
+	c := testCipher(t, sqlDB, "k1", map[string][]byte{"k1": oldKEK})
+	indexed := c.BlindIndex("+254712345678")
+
+	encrypted, err := c.Encrypt("+254712345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The new key encryption key is made current while the old one is kept
+	rotating := testCipher(t, sqlDB, "k2", map[string][]byte{"k1": oldKEK, "k2": newKEK})
+	rewrapped, err := rotating.RewrapDataKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The blind index key and the data key
+	if rewrapped != 2 {
+		t.Fatalf("rewrapped %d data keys, want 2", rewrapped)
+	}
+
+	rewrapped, err = rotating.RewrapDataKeys(ctx)
+	if err != nil || rewrapped != 0 {
+		t.Fatalf("rewrapping again = %d, %v; want no data keys", rewrapped, err)
+	}
+
+	// Data keys are kept, so values encrypted before are read once the old key encryption key is removed
+	rotated := testCipher(t, sqlDB, "k2", map[string][]byte{"k2": newKEK})
+
+	decrypted, err := rotated.Decrypt(encrypted)
+	if err != nil || decrypted != "+254712345678" {
+		t.Fatalf("Decrypt after rewrapping = %q, %v", decrypted, err)
+	}
+
+	if rotated.BlindIndex("+254712345678") != indexed {
+		t.Fatal("blind index changed after rewrapping")
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	c := testCipher(t, testDB(t), "k1", map[string][]byte{"k1": testKey(t)})
+	other := testCipher(t, testDB(t), "k1", map[string][]byte{"k1": testKey(t)})
+
+	for _, tt := range []struct {
+		name  string
+		a, b  string
+		cA    *Cipher
+		cB    *Cipher
+		equal bool
+	}{
+		{"same value", "+254712345678", "+254712345678", c, c, true},
+		{"other value", "+254712345678", "+254712345679", c, c, false},
+		{"other index key", "+254712345678", "+254712345678", c, other, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if equal := tt.cA.BlindIndex(tt.a) == tt.cB.BlindIndex(tt.b); equal != tt.equal {
+				t.Fatalf("blind indexes equal = %v, want %v", equal, tt.equal)
+			}
+		})
+	}
+
+	if index := c.BlindIndex(""); index != "" {
+		t.Fatalf("BlindIndex(\"\") = %q, want empty", index)
+	}
+}
+
+func TestDefaultCipher(t *testing.T) {
+	defer SetDefault(nil)
+	defer SetPlaintextForTesting(false)
+
+	SetDefault(nil)
+	SetPlaintextForTesting(false)
+
+	// Personal data is not saved until a cipher is set
+	if _, err := Encrypt("+254712345678"); err != ErrNoDefaultCipher {
+		t.Fatalf("Encrypt without a cipher = %v, want %v", err, ErrNoDefaultCipher)
+	}
+	if _, err := Decrypt("+254712345678"); err != ErrNoDefaultCipher {
+		t.Fatalf("Decrypt without a cipher = %v, want %v", err, ErrNoDefaultCipher)
+	}
+	if _, err := BlindIndex("+254712345678"); err != ErrNoDefaultCipher {
+		t.Fatalf("BlindIndex without a cipher = %v, want %v", err, ErrNoDefaultCipher)
+	}
+
+	SetPlaintextForTesting(true)
+	if value, err := Encrypt("+254712345678"); err != nil || value != "+254712345678" {
+		t.Fatalf("Encrypt in plaintext mode = %q, %v", value, err)
+	}
+
+	SetDefault(testCipher(t, testDB(t), "k1", map[string][]byte{"k1": testKey(t)}))
+
+	fullName, phone := "Wanjiku Kamau", "+254712345678"
+	err := EncryptFields(&fullName, &phone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(fullName) || !IsEncrypted(phone) {
+		t.Fatalf("fields were not encrypted: %q, %q", fullName, phone)
+	}
+
+	err = DecryptFields(&fullName, &phone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fullName != "Wanjiku Kamau" || phone != "+254712345678" {
+		t.Fatalf("fields were not decrypted: %q, %q", fullName, phone)
+	}
+}
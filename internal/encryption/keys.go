@@ -0,0 +1,127 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// KeyProvider wraps and unwraps data encryption keys with a key encryption key.
+//
+// Key encryption keys never leave the provider, which makes it possible to back it with a KMS.
+type KeyProvider interface {
+	// WrapKey encrypts a data key with the current key encryption key and returns the id of that key
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey decrypts a data key that was wrapped with the key encryption key with the given id
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	// CurrentKeyID returns the id of the key used by WrapKey
+	CurrentKeyID() string
+}
+
+// localKeyFile is the format of the key file used by the local key provider
+//
+//	{"current": "k2", "keys": {"k1": "<base64 key>", "k2": "<base64 key>"}}
+type localKeyFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+type localKeyProvider struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewLocalKeyProvider creates a key provider that reads 256 bit key encryption keys from a json file.
+//
+// To rotate keys, add a new key to the file and make it current; old keys must be kept until
+// the key rotation command has re-wrapped all data keys.
+func NewLocalKeyProvider(keyFile string) (KeyProvider, error) {
+	bs, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %v", err)
+	}
+
+	kf := &localKeyFile{}
+	err = json.Unmarshal(bs, kf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to json unmarshal key file: %v", err)
+	}
+
+	if _, ok := kf.Keys[kf.Current]; !ok {
+		return nil, errors.New("current key is missing from key file")
+	}
+
+	p := &localKeyProvider{
+		current: kf.Current,
+		keys:    make(map[string]cipher.AEAD, len(kf.Keys)),
+	}
+
+	for keyID, keyStr := range kf.Keys {
+		key, err := base64.StdEncoding.DecodeString(keyStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key %s: %v", keyID, err)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %s must be %d bytes", keyID, keySize)
+		}
+		p.keys[keyID], err = newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func (p *localKeyProvider) CurrentKeyID() string {
+	return p.current
+}
+
+func (p *localKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	wrapped, err := seal(p.keys[p.current], dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return p.current, wrapped, nil
+}
+
+func (p *localKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	gcm, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in key file", keyID)
+	}
+	return open(gcm, wrapped)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext and prefixes the result with a random nonce
+func seal(gcm cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(gcm cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,237 @@
+package pseudonym
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/jinzhu/gorm"
+
+	// mysql driver for the pseudonyms database
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const (
+	minKeyLength = 16
+	idLength     = 32
+	day          = 24 * 60 * 60
+	// lookupBatchSize is the maximum number of ids looked up in a single query
+	lookupBatchSize = 1000
+)
+
+// Pseudonymizer derives pseudonymous user ids from phone numbers.
+//
+// Ids are an HMAC of the phone number and the current rotation epoch, so they change
+// every rotation period and cannot be linked back to a phone number without the key.
+// The mapping from ids to phone numbers is kept in a separate database.
+type Pseudonymizer struct {
+	sqlDB        *gorm.DB
+	key          []byte
+	rotationDays int64
+	registered   *sync.Map
+}
+
+// Options contains parameters for NewPseudonymizer
+type Options struct {
+	// SQLDB holds the mapping table, it should not be the database holding locations
+	SQLDB *gorm.DB
+	// Key is the secret used to derive ids
+	Key []byte
+	// RotationDays is the number of days an id is valid for, defaults to 1
+	RotationDays int
+}
+
+// NewPseudonymizer creates a pseudonymizer
+func NewPseudonymizer(ctx context.Context, opt *Options) (*Pseudonymizer, error) {
+	var err error
+	// Validation
+	switch {
+	case ctx == nil:
+		err = errors.New("non-nil context must not be nil")
+	case opt == nil:
+		err = errors.New("non-nil options is required")
+	case opt.SQLDB == nil:
+		err = errors.New("non-nil sqlDB is required")
+	case len(opt.Key) < minKeyLength:
+		err = fmt.Errorf("pseudonym key must be at least %d bytes", minKeyLength)
+	case opt.RotationDays < 0:
+		err = errors.New("rotation days must not be negative")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.RotationDays == 0 {
+		opt.RotationDays = 1
+	}
+
+	p := &Pseudonymizer{
+		sqlDB:        opt.SQLDB,
+		key:          opt.Key,
+		rotationDays: int64(opt.RotationDays),
+		registered:   &sync.Map{},
+	}
+
+	// Automigration
+	err = p.sqlDB.AutoMigrate(&services.UserPseudonym{}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to automigrate: %v", err)
+	}
+
+	return p, nil
+}
+
+// epoch is the rotation period containing the calendar date of t
+func (p *Pseudonymizer) epoch(t time.Time) int64 {
+	y, m, d := t.Date()
+	days := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / day
+	return days / p.rotationDays
+}
+
+// ID returns the pseudonymous id of the user on the date of t
+func (p *Pseudonymizer) ID(phoneNumber string, t time.Time) string {
+	epoch := make([]byte, 8)
+	binary.BigEndian.PutUint64(epoch, uint64(p.epoch(t)))
+
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write(epoch)
+	mac.Write([]byte(phoneNumber))
+
+	return hex.EncodeToString(mac.Sum(nil))[:idLength]
+}
+
+// Register returns the pseudonymous id of the user on the date of t and saves its mapping
+func (p *Pseudonymizer) Register(phoneNumber string, t time.Time) (string, error) {
+	id := p.ID(phoneNumber, t)
+
+	if _, ok := p.registered.Load(id); ok {
+		return id, nil
+	}
+
+	err := p.sqlDB.FirstOrCreate(&services.UserPseudonym{}, &services.UserPseudonym{
+		Pseudonym:   id,
+		PhoneNumber: phoneNumber,
+		Epoch:       p.epoch(t),
+	}).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to save pseudonym: %v", err)
+	}
+
+	p.registered.Store(id, struct{}{})
+
+	return id, nil
+}
+
+// IDs returns all pseudonymous ids that have been registered for the user
+func (p *Pseudonymizer) IDs(phoneNumber string) ([]string, error) {
+	ids := make([]string, 0)
+	err := p.sqlDB.Model(&services.UserPseudonym{}).Where("phone_number=?", phoneNumber).
+		Order("epoch ASC").Pluck("pseudonym", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pseudonyms: %v", err)
+	}
+	return ids, nil
+}
+
+// PhoneNumber returns the phone number of the user with the given pseudonymous id
+func (p *Pseudonymizer) PhoneNumber(id string) (string, error) {
+	mappingDB := &services.UserPseudonym{}
+	err := p.sqlDB.Select("phone_number").First(mappingDB, "pseudonym=?", id).Error
+	if err != nil {
+		return "", err
+	}
+	return mappingDB.PhoneNumber, nil
+}
+
+// PhoneNumbers returns the phone numbers of the users with the given pseudonymous ids, each once
+func (p *Pseudonymizer) PhoneNumbers(ids []string) ([]string, error) {
+	var (
+		phoneNumbers = make([]string, 0, len(ids))
+		seen         = make(map[string]bool, len(ids))
+	)
+
+	for first := 0; first < len(ids); first += lookupBatchSize {
+		last := first + lookupBatchSize
+		if last > len(ids) {
+			last = len(ids)
+		}
+
+		batch := make([]string, 0, last-first)
+		err := p.sqlDB.Model(&services.UserPseudonym{}).Where("pseudonym IN(?)", ids[first:last]).
+			Pluck("DISTINCT phone_number", &batch).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get phone numbers: %v", err)
+		}
+
+		for _, phoneNumber := range batch {
+			if !seen[phoneNumber] {
+				seen[phoneNumber] = true
+				phoneNumbers = append(phoneNumbers, phoneNumber)
+			}
+		}
+	}
+
+	return phoneNumbers, nil
+}
+
+// Delete removes all pseudonymous ids of the user
+func (p *Pseudonymizer) Delete(phoneNumber string) error {
+	ids, err := p.IDs(phoneNumber)
+	if err != nil {
+		return err
+	}
+
+	err = p.sqlDB.Unscoped().Delete(&services.UserPseudonym{}, "phone_number=?", phoneNumber).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete pseudonyms: %v", err)
+	}
+
+	for _, id := range ids {
+		p.registered.Delete(id)
+	}
+
+	return nil
+}
+
+// NewPseudonymizerFromEnv creates a pseudonymizer configured from the environment.
+//
+// PSEUDONYM_KEY is the secret key and PSEUDONYM_ROTATION_DAYS how often ids change.
+// PSEUDONYMS_DB_DSN is the mysql dsn of the database holding the mapping table,
+// it should use credentials that are separate from the locations database; defaultDB is used when not set.
+func NewPseudonymizerFromEnv(ctx context.Context, defaultDB *gorm.DB) (*Pseudonymizer, error) {
+	var (
+		sqlDB        = defaultDB
+		rotationDays int
+		err          error
+	)
+
+	if dsn := strings.TrimSpace(os.Getenv("PSEUDONYMS_DB_DSN")); dsn != "" {
+		sqlDB, err = gorm.Open("mysql", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open pseudonyms database: %v", err)
+		}
+	}
+
+	if days := strings.TrimSpace(os.Getenv("PSEUDONYM_ROTATION_DAYS")); days != "" {
+		rotationDays, err = strconv.Atoi(days)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pseudonym rotation days: %v", err)
+		}
+	}
+
+	return NewPseudonymizer(ctx, &Options{
+		SQLDB:        sqlDB,
+		Key:          []byte(os.Getenv("PSEUDONYM_KEY")),
+		RotationDays: rotationDays,
+	})
+}
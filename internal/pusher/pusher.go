@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/appleboy/go-fcm"
+	"github.com/gidyon/pandemic-api/internal/devicetoken"
 	"github.com/gidyon/pandemic-api/internal/services"
 	"github.com/go-redis/redis"
 	"github.com/jinzhu/gorm"
@@ -84,7 +85,7 @@ func StartWorker(ctx context.Context, opt *Options) error {
 	for condition {
 		devices = make([]string, 0, limit)
 		rows, err := dm.sqlDB.Table(services.UsersTable).Limit(limit).Offset(offset).Select("device_token").
-			Where("phone_number=?", "+254716484395").Rows()
+			Where("phone_number=?", "+254716484395").Where("device_token != ?", devicetoken.None).Rows()
 		if err != nil {
 			return fmt.Errorf("failed to get rows: %v", err)
 		}
@@ -118,6 +119,8 @@ func (dm *deviceManager) startWorker(ctx context.Context) error {
 
 			// Send to devices
 			wg := &sync.WaitGroup{}
+			mu := &sync.Mutex{}
+			updates := make([]*devicetoken.Update, 0)
 
 			for _, devices := range dm.devices {
 				wg.Add(1)
@@ -130,7 +133,7 @@ func (dm *deviceManager) startWorker(ctx context.Context) error {
 					dm.logger.Infof("devices: %d", len(devices))
 					dm.logger.Infof("device 0: %s", devices[0])
 
-					_, err := dm.fcmClient.Send(&fcm.Message{
+					res, err := dm.fcmClient.Send(&fcm.Message{
 						RegistrationIDs: devices,
 						Data: map[string]interface{}{
 							"type": "UPDATE",
@@ -148,11 +151,48 @@ func (dm *deviceManager) startWorker(ctx context.Context) error {
 						return
 					}
 
+					mu.Lock()
+					updates = append(updates, devicetoken.FromResponse(devices, res)...)
+					mu.Unlock()
+
 					dm.logger.Infoln("semding was susccessful")
 				}(devices)
 			}
 
 			wg.Wait()
+
+			if len(updates) > 0 {
+				dm.updateDevices(updates)
+			}
+		}
+	}
+}
+
+// updateDevices clears invalid tokens and replaces tokens with their canonical tokens, in the database and in the device lists
+func (dm *deviceManager) updateDevices(updates []*devicetoken.Update) {
+	err := devicetoken.Apply(dm.sqlDB, updates)
+	if err != nil {
+		dm.logger.Errorf("failed to update device tokens: %v", err)
+	}
+
+	changes := make(map[string]*devicetoken.Update, len(updates))
+	for _, update := range updates {
+		changes[update.Token] = update
+	}
+
+	for index, devices := range dm.devices {
+		updated := make([]string, 0, len(devices))
+		for _, device := range devices {
+			update, ok := changes[device]
+			switch {
+			case !ok:
+				updated = append(updated, device)
+			case update.Canonical != "":
+				updated = append(updated, update.Canonical)
+			}
 		}
+		dm.devices[index] = updated
 	}
+
+	dm.logger.Infof("updated %d device tokens", len(updates))
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/gidyon/pandemic-api/internal/auth"
+	"github.com/gidyon/pandemic-api/internal/encryption"
 	http_error "github.com/gidyon/pandemic-api/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
@@ -55,6 +56,10 @@ func RegisterAccountAPI(router *httprouter.Router, sqlDB *gorm.DB) {
 	err = acc.sqlDB.AutoMigrate(&Account{}).Error
 	handleError(err)
 
+	// Encrypted values are longer than the original columns and are not unique
+	err = migrateEncryptedColumns(acc.sqlDB, &Account{}, "national_id", "full_name", "email", "phone")
+	handleError(err)
+
 	router.GET("/rest/v1/accounts", acc.ListAccounts)
 	router.GET("/rest/v1/accounts/:accountId", acc.GetAccount)
 	router.POST("/rest/v1/accounts/file", acc.AddUsersFromFile)
@@ -68,10 +73,10 @@ func RegisterAccountAPI(router *httprouter.Router, sqlDB *gorm.DB) {
 // Account is an entity performing actions
 type Account struct {
 	AccountID  string     `json:"account_id,omitempty" gorm:"primary_key;type:varchar(50);not null"`
-	NationalID string     `json:"national_id,omitempty" gorm:"index:query_index;type:varchar(15);unique_index;not null"`
-	FullName   string     `json:"full_name,omitempty" gorm:"type:varchar(50);not null"`
-	Email      string     `json:"email,omitempty" gorm:"index:query_index;type:varchar(50);unique_index;not null"`
-	Phone      string     `json:"phone,omitempty" gorm:"index:query_index;type:varchar(15);unique_index;not null"`
+	NationalID string     `json:"national_id,omitempty" gorm:"type:varchar(255);not null"`
+	FullName   string     `json:"full_name,omitempty" gorm:"type:varchar(255);not null"`
+	Email      string     `json:"email,omitempty" gorm:"type:varchar(255);not null"`
+	Phone      string     `json:"phone,omitempty" gorm:"type:varchar(255);not null"`
 	County     string     `json:"county,omitempty" gorm:"type:varchar(50);not null"`
 	Profession string     `json:"profession,omitempty" gorm:"type:varchar(50);not null"`
 	Gender     string     `json:"gender,omitempty" gorm:"type:varchar(10);default:'unknown'"`
@@ -80,6 +85,11 @@ type Account struct {
 	Password   string     `json:"password,omitempty" gorm:"type:text"`
 	CreatedAt  time.Time  `json:"	-"`
 	DeletedAt  *time.Time `json:"-"`
+
+	// Blind indexes for looking up encrypted values
+	NationalIDIndex *string `json:"-" gorm:"type:varchar(64);unique_index"`
+	EmailIndex      *string `json:"-" gorm:"type:varchar(64);unique_index"`
+	PhoneIndex      *string `json:"-" gorm:"type:varchar(64);unique_index"`
 }
 
 // BeforeCreate is a hook that is set before creating object
@@ -92,6 +102,47 @@ func (*Account) TableName() string {
 	return accountsTable
 }
 
+// BeforeSave encrypts personal data of the account
+func (acc *Account) BeforeSave() error {
+	err := acc.setBlindIndexes()
+	if err != nil {
+		return err
+	}
+	return acc.encryptFields()
+}
+
+// AfterSave restores personal data of the account
+func (acc *Account) AfterSave() error {
+	return acc.decryptFields()
+}
+
+// AfterFind decrypts personal data of the account
+func (acc *Account) AfterFind() error {
+	return acc.decryptFields()
+}
+
+func (acc *Account) setBlindIndexes() error {
+	var err error
+	acc.NationalIDIndex, err = blindIndex(acc.NationalID)
+	if err != nil {
+		return err
+	}
+	acc.EmailIndex, err = blindIndex(acc.Email)
+	if err != nil {
+		return err
+	}
+	acc.PhoneIndex, err = blindIndex(acc.Phone)
+	return err
+}
+
+func (acc *Account) encryptFields() error {
+	return encryption.EncryptFields(&acc.NationalID, &acc.FullName, &acc.Email, &acc.Phone)
+}
+
+func (acc *Account) decryptFields() error {
+	return encryption.DecryptFields(&acc.NationalID, &acc.FullName, &acc.Email, &acc.Phone)
+}
+
 func (accountAPI *Account) validate() error {
 	var err error
 
@@ -111,6 +162,41 @@ func (accountAPI *Account) validate() error {
 	return err
 }
 
+// blindIndex returns the blind index of value, empty values are not indexed
+func blindIndex(value string) (*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	index, err := encryption.BlindIndex(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blind index: %v", err)
+	}
+	return &index, nil
+}
+
+// migrateEncryptedColumns widens columns of tables created before their values were encrypted.
+//
+// Their unique indexes are dropped too, as encrypted values differ even when the plaintext is the same;
+// uniqueness is kept by the unique blind indexes instead.
+func migrateEncryptedColumns(db *gorm.DB, model interface{}, columns ...string) error {
+	table := db.NewScope(model).TableName()
+	for _, column := range columns {
+		index := fmt.Sprintf("uix_%s_%s", table, column)
+		if db.Dialect().HasIndex(table, index) {
+			err := db.Model(model).RemoveIndex(index).Error
+			if err != nil {
+				return fmt.Errorf("failed to drop index %s: %v", index, err)
+			}
+		}
+
+		err := db.Model(model).ModifyColumn(column, "varchar(255) NOT NULL").Error
+		if err != nil {
+			return fmt.Errorf("failed to modify column %s: %v", column, err)
+		}
+	}
+	return nil
+}
+
 // generates hashed version of password
 func genHash(password string) (string, error) {
 	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -157,6 +243,8 @@ func (accountAPI *accountAPI) CreateAccount(w http.ResponseWriter, r *http.Reque
 	switch {
 	case err == nil:
 	case strings.Contains(strings.ToLower(err.Error()), "duplicate"):
+		// Fields are left encrypted when saving fails
+		acc.decryptFields()
 		errStr := strings.ToLower(err.Error())
 		var errMsg string
 		switch {
@@ -247,7 +335,16 @@ func (accountAPI *accountAPI) Login(w http.ResponseWriter, r *http.Request, _ ht
 
 	// Get the acc
 	acc := &Account{}
-	err = accountAPI.sqlDB.First(acc, "email=? OR phone=?", login.UserName, login.UserName).Error
+	// Rows that have not been encrypted yet are matched by their plaintext values
+	userName, err := encryption.BlindIndex(login.UserName)
+	if err != nil {
+		http_error.Write(w, http_error.New("failed to get account from db", err, http.StatusInternalServerError))
+		return
+	}
+	err = accountAPI.sqlDB.First(
+		acc, "email_index=? OR phone_index=? OR email=? OR phone=?",
+		userName, userName, login.UserName, login.UserName,
+	).Error
 	switch {
 	case err == nil:
 	case gorm.IsRecordNotFoundError(err):
@@ -311,12 +408,25 @@ func (accountAPI *accountAPI) UpdateAccount(w http.ResponseWriter, r *http.Reque
 		acc.Verified = false
 	}
 
+	// Hooks do not run for table updates
+	err = acc.setBlindIndexes()
+	if err != nil {
+		http_error.Write(w, http_error.New("failed to index account", err, http.StatusInternalServerError))
+		return
+	}
+	err = acc.encryptFields()
+	if err != nil {
+		http_error.Write(w, http_error.New("failed to encrypt account", err, http.StatusInternalServerError))
+		return
+	}
+
 	// Update in database
 	err = accountAPI.sqlDB.Table(accountsTable).Unscoped().Omit("account_id, verified, group, password").
 		Where("account_id=?", accountID).Updates(acc).Error
 	switch {
 	case err == nil:
 	case strings.Contains(strings.ToLower(err.Error()), "duplicate"):
+		acc.decryptFields()
 		errStr := strings.ToLower(err.Error())
 		var errMsg string
 		switch {
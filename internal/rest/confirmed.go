@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gidyon/pandemic-api/internal/encryption"
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 	"github.com/julienschmidt/httprouter"
@@ -39,6 +40,10 @@ func RegisterConfirmedCasesAPI(router *httprouter.Router, sqlDB *gorm.DB) {
 	err = c.sqlDB.AutoMigrate(&ConfirmedPatient{}).Error
 	handleError(err)
 
+	// Encrypted values are longer than the original columns and are not unique
+	err = migrateEncryptedColumns(c.sqlDB, &ConfirmedPatient{}, "national_id", "full_name", "email", "phone")
+	handleError(err)
+
 	// Update endpoints
 	router.POST("/rest/v1/cases/confirmed", c.AddConfirmedPatient)
 	router.PATCH("/rest/v1/cases/confirmed/:caseId/attend", c.MarkAttended)
@@ -49,10 +54,10 @@ func RegisterConfirmedCasesAPI(router *httprouter.Router, sqlDB *gorm.DB) {
 // ConfirmedPatient is a pandemic confirmed patient
 type ConfirmedPatient struct {
 	CaseID       string     `json:"case_id,omitempty" gorm:"primary_key;type:varchar(50);not null"`
-	NationalID   string     `json:"national_id,omitempty" gorm:"index:query_index;type:varchar(15);unique_index;not null"`
-	FullName     string     `json:"full_name,omitempty" gorm:"type:varchar(50);not null"`
-	Email        string     `json:"email,omitempty" gorm:"index:query_index;type:varchar(50);unique_index;not null"`
-	Phone        string     `json:"phone,omitempty" gorm:"index:query_index;type:varchar(15);unique_index;not null"`
+	NationalID   string     `json:"national_id,omitempty" gorm:"type:varchar(255);not null"`
+	FullName     string     `json:"full_name,omitempty" gorm:"type:varchar(255);not null"`
+	Email        string     `json:"email,omitempty" gorm:"type:varchar(255);not null"`
+	Phone        string     `json:"phone,omitempty" gorm:"type:varchar(255);not null"`
 	County       string     `json:"county,omitempty" gorm:"type:varchar(50);not null"`
 	Constituency string     `json:"constituency,omitempty" gorm:"type:varchar(50);not null"`
 	Ward         string     `json:"ward,omitempty" gorm:"type:varchar(50);not null"`
@@ -63,6 +68,11 @@ type ConfirmedPatient struct {
 	Attended     bool       `json:"attended" gorm:"type:tinyint(1);default:0"`
 	CreatedAt    time.Time  `json:"-"`
 	DeletedAt    *time.Time `json:"-"`
+
+	// Blind indexes for looking up encrypted values
+	NationalIDIndex *string `json:"-" gorm:"type:varchar(64);unique_index"`
+	EmailIndex      *string `json:"-" gorm:"type:varchar(64);unique_index"`
+	PhoneIndex      *string `json:"-" gorm:"type:varchar(64);unique_index"`
 }
 
 // BeforeCreate is a hook that is set before creating object
@@ -75,6 +85,38 @@ func (*ConfirmedPatient) TableName() string {
 	return confirmedCasesTable
 }
 
+// BeforeSave encrypts personal data of the patient
+func (patient *ConfirmedPatient) BeforeSave() error {
+	var err error
+	patient.NationalIDIndex, err = blindIndex(patient.NationalID)
+	if err != nil {
+		return err
+	}
+	patient.EmailIndex, err = blindIndex(patient.Email)
+	if err != nil {
+		return err
+	}
+	patient.PhoneIndex, err = blindIndex(patient.Phone)
+	if err != nil {
+		return err
+	}
+	return encryption.EncryptFields(&patient.NationalID, &patient.FullName, &patient.Email, &patient.Phone)
+}
+
+// AfterSave restores personal data of the patient
+func (patient *ConfirmedPatient) AfterSave() error {
+	return patient.decryptFields()
+}
+
+// AfterFind decrypts personal data of the patient
+func (patient *ConfirmedPatient) AfterFind() error {
+	return patient.decryptFields()
+}
+
+func (patient *ConfirmedPatient) decryptFields() error {
+	return encryption.DecryptFields(&patient.NationalID, &patient.FullName, &patient.Email, &patient.Phone)
+}
+
 func (patient *ConfirmedPatient) validate() error {
 	var err error
 	switch {
@@ -122,6 +164,8 @@ func (cap *confirmedCasesAPI) AddConfirmedPatient(w http.ResponseWriter, r *http
 	switch {
 	case err == nil:
 	case strings.Contains(strings.ToLower(err.Error()), "duplicate"):
+		// Fields are left encrypted when saving fails
+		patient.decryptFields()
 		errStr := strings.ToLower(err.Error())
 		var errMsg string
 		switch {
@@ -19,6 +19,17 @@ func fakeUser() *location.User {
 	}
 }
 
+// addFakeUser adds a fake user and returns their phone number
+func addFakeUser(ctx context.Context) string {
+	addReq := &location.AddUserRequest{
+		User: fakeUser(),
+	}
+	addRes, err := LocationAPI.AddUser(ctx, addReq)
+	Expect(err).ShouldNot(HaveOccurred())
+	Expect(addRes).ShouldNot(BeNil())
+	return addReq.User.PhoneNumber
+}
+
 var _ = Describe("Adding a user into the database #add", func() {
 	var (
 		addReq *location.AddUserRequest
@@ -68,6 +79,20 @@ var _ = Describe("Adding a user into the database #add", func() {
 			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
 			Expect(addRes).Should(BeNil())
 		})
+		It("should fail if email is malformed ", func() {
+			addReq.User.Email = randomdata.SillyName()
+			addRes, err := LocationAPI.AddUser(ctx, addReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(addRes).Should(BeNil())
+		})
+		It("should fail if preferred language is unknown ", func() {
+			addReq.User.PreferredLanguage = "fr"
+			addRes, err := LocationAPI.AddUser(ctx, addReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(addRes).Should(BeNil())
+		})
 	})
 
 	When("Adding user with well-formed request", func() {
@@ -80,6 +105,16 @@ var _ = Describe("Adding a user into the database #add", func() {
 			userPhone = addReq.User.PhoneNumber
 		})
 
+		It("should save the preferred language of the user", func() {
+			addReq.User.PreferredLanguage = "sw"
+			_, err := LocationAPI.AddUser(ctx, addReq)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			getRes, err := LocationAPI.GetUser(ctx, &location.GetUserRequest{PhoneNumber: addReq.User.PhoneNumber})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.PreferredLanguage).Should(Equal("sw"))
+		})
+
 		When("Adding a user who exists, it should succeed but do an update", func() {
 			It("should succeed", func() {
 				addReq.User.PhoneNumber = userPhone
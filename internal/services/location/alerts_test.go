@@ -0,0 +1,88 @@
+package location
+
+import (
+	"context"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services/location/throttle"
+	"github.com/gidyon/pandemic-api/internal/services/messaging/templates"
+	"os"
+	"time"
+)
+
+var _ = Describe("Throttling alerts to users close to cases #alerts", func() {
+	var (
+		ctx       context.Context
+		userID    string
+		throttler *throttle.Throttler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		userID = randomdata.RandStringRunes(32)
+		throttler = throttle.New(LocationServer.eventsDB, &throttle.Options{
+			Cooldown:    100 * time.Millisecond,
+			DailyCap:    2,
+			MergeWindow: time.Minute,
+		})
+	})
+
+	It("should send the first alert", func() {
+		alert, err := throttler.Throttle(ctx, userID, proximityAlerts, time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(alert.Send).Should(BeTrue())
+		Expect(alert.Count).Should(Equal(1))
+		Expect(alert.CollapseKey).ShouldNot(BeEmpty())
+	})
+
+	It("should merge alerts in the cooldown into the next message", func() {
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			alert, err := throttler.Throttle(ctx, userID, proximityAlerts, start.Add(time.Duration(i)*time.Second))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(alert.Send).Should(Equal(i == 0))
+			Expect(alert.Count).Should(Equal(i + 1))
+		}
+
+		time.Sleep(150 * time.Millisecond)
+
+		alert, err := throttler.Throttle(ctx, userID, proximityAlerts, time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(alert.Send).Should(BeTrue())
+		Expect(alert.Count).Should(Equal(4))
+		Expect(alert.Since.Unix()).Should(Equal(start.Unix()))
+		// Messages show the time in the zone of their templates, whatever the zone of the server
+		Expect(alert.Since.Location()).Should(Equal(templates.TimeZone))
+	})
+
+	It("should not send more alerts than the daily cap", func() {
+		for i := 0; i < 2; i++ {
+			alert, err := throttler.Throttle(ctx, userID, proximityAlerts, time.Now())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(alert.Send).Should(BeTrue())
+			time.Sleep(150 * time.Millisecond)
+		}
+
+		alert, err := throttler.Throttle(ctx, userID, proximityAlerts, time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(alert.Send).Should(BeFalse())
+		Expect(alert.Decision).Should(Equal(throttle.DecisionDailyCap))
+	})
+
+	It("should fail to create a throttler when a limit is not positive", func() {
+		os.Setenv("ALERT_DAILY_CAP", "0")
+		defer os.Unsetenv("ALERT_DAILY_CAP")
+
+		_, err := throttle.NewFromEnv(LocationServer.eventsDB)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should throttle alerts of users separately", func() {
+		alert, err := throttler.Throttle(ctx, userID, proximityAlerts, time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(alert.Send).Should(BeTrue())
+
+		alert, err = throttler.Throttle(ctx, randomdata.RandStringRunes(32), proximityAlerts, time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(alert.Send).Should(BeTrue())
+	})
+})
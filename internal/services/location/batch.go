@@ -0,0 +1,441 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/encryption"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
+	"github.com/gidyon/pandemic-api/internal/services/location/ingestion"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/go-redis/redis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxDeviceIDLength = 64
+	// Retransmissions after the sequences expire are dropped by the unique index on locations
+	sequencesExpiration = 7 * 24 * time.Hour
+)
+
+// getSequencesKey is the key of the set of sequences received from a device
+func getSequencesKey(deviceID string) (string, error) {
+	index, err := encryption.BlindIndex(deviceID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("device:%s:sequences", index), nil
+}
+
+// getDedupKey returns the key that identifies a location sent from a device
+func getDedupKey(deviceID string, sequence int64) (*string, error) {
+	if deviceID == "" || sequence <= 0 {
+		return nil, nil
+	}
+	dedupKey, err := encryption.BlindIndex(fmt.Sprintf("%s:%d", deviceID, sequence))
+	if err != nil {
+		return nil, err
+	}
+	return &dedupKey, nil
+}
+
+// getDeviceID returns the device id of the locations, which must be the same for all of them
+func getDeviceID(locations []*location.Location) (string, error) {
+	deviceID := ""
+	for _, locationPB := range locations {
+		switch {
+		case locationPB.GetDeviceId() == "":
+		case len(locationPB.DeviceId) > maxDeviceIDLength:
+			return "", status.Errorf(codes.InvalidArgument, "device id must be at most %d characters", maxDeviceIDLength)
+		case deviceID == "":
+			deviceID = locationPB.DeviceId
+		case deviceID != locationPB.DeviceId:
+			return "", status.Error(codes.InvalidArgument, "all locations must be sent from the same device")
+		}
+	}
+	return deviceID, nil
+}
+
+// savedLocations is the outcome of saving a batch of locations
+type savedLocations struct {
+	results []*location.LocationResult
+	// userID is the current pseudonymous id of the user, locations are saved with the id of their day
+	userID string
+	// danger is the latest saved location that was close to a case, nil if there was none
+	danger *location.Location
+	// deviceID is the device that sent the locations, empty if they had none
+	deviceID string
+	// acknowledgedSequence is the highest sequence of the locations, whether they were accepted or not
+	acknowledgedSequence int64
+}
+
+// saveLocations filters a batch of user locations and saves the accepted ones with one redis pipeline and bulk inserts.
+//
+// Malformed and filtered locations are reported in the results instead of failing the batch.
+// Locations whose device sequence was received before are reported as duplicates and not saved again.
+// offset is the index of the first location in a stream, checkDanger looks up cases close to the locations.
+func (lapi *locationAPIServer) saveLocations(
+	ctx context.Context, sendReq *location.SendLocationsRequest, offset int, checkDanger bool,
+) (*savedLocations, error) {
+	// Validation
+	var err error
+	switch {
+	case sendReq.GetUserId() == "":
+		err = services.MissingFieldError("user id")
+	case sendReq.GetStatusId().String() == "":
+		err = services.MissingFieldError("status id")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deviceID, err := getDeviceID(sendReq.Locations)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users who withdrew consent are not tracked
+	withdrawn, err := lapi.tracingConsentWithdrawn(ctx, sendReq.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if withdrawn {
+		return nil, status.Error(codes.FailedPrecondition, "user has withdrawn consent for location tracing")
+	}
+
+	// Recent location data is keyed by the current pseudonymous id of the user
+	now := time.Now()
+	userID, err := lapi.pseudonyms.Register(sendReq.UserId, now)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user pseudonym: %v", err)
+	}
+
+	lastLocation, err := lapi.getLastLocation(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get last location: %v", err)
+	}
+
+	received, err := lapi.receivedSequences(ctx, deviceID, sendReq.Locations)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get received sequences: %v", err)
+	}
+
+	var (
+		saved = &savedLocations{
+			results:  make([]*location.LocationResult, len(sendReq.Locations)),
+			userID:   userID,
+			deviceID: deviceID,
+		}
+		sequences   = make([]interface{}, 0, len(sendReq.Locations))
+		order       = make([]int, len(sendReq.Locations))
+		confident   = make([]*location.Location, 0, len(sendReq.Locations))
+		locationsDB = make([]*services.LocationModel, 0, len(sendReq.Locations))
+		// contactPoints are the contact points of every day keyed by the set of the day
+		contactPoints = make(map[string][]interface{})
+		// homes are the administrative units of confident locations at night
+		homes = make([]string, 0)
+	)
+
+	// Devices that were offline may send locations out of order, they are filtered in time order
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return sendReq.Locations[order[i]].GetTimestamp() < sendReq.Locations[order[j]].GetTimestamp()
+	})
+
+	for _, i := range order {
+		locationPB := sendReq.Locations[i]
+		result := &location.LocationResult{Index: int32(offset + i)}
+		saved.results[i] = result
+
+		if sequence := locationPB.GetSequence(); deviceID != "" && sequence > 0 {
+			if sequence > saved.acknowledgedSequence {
+				saved.acknowledgedSequence = sequence
+			}
+			if received[sequence] {
+				result.Reason = ingestion.ReasonDuplicateSequence
+				ingestion.Record(&ingestion.Result{Reason: ingestion.ReasonDuplicateSequence})
+				continue
+			}
+			received[sequence] = true
+			sequences = append(sequences, sequence)
+		}
+
+		if validateLocation(locationPB) != nil {
+			result.Reason = ingestion.ReasonInvalid
+			ingestion.Record(&ingestion.Result{Reason: ingestion.ReasonInvalid})
+			continue
+		}
+
+		// Filter out inaccurate, duplicate and spoofed locations
+		res := lapi.filter.Check(lastLocation, locationPB, now)
+		ingestion.Record(res)
+
+		result.Accepted, result.LowConfidence, result.Reason = res.Accepted, res.LowConfidence, res.Reason
+		if !res.Accepted {
+			continue
+		}
+
+		lastLocation = locationPB
+
+		// Update location on server
+		locationPB.TimeId = lapi.timeBuckets.TimeID(locationPB.Timestamp)
+		locationPB.GeoFenceId = lapi.cells.CellID(float64(locationPB.Latitude), float64(locationPB.Longitude))
+
+		// Locations are keyed by the pseudonymous id of the day they were recorded on, so that
+		// locations sent late by devices that were offline are traced on that day
+		day := time.Unix(locationPB.Timestamp, 0).UTC()
+		dayUserID, err := lapi.pseudonyms.Register(sendReq.UserId, day)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get user pseudonym: %v", err)
+		}
+
+		locationDB := services.GetLocationDB(locationPB)
+		locationDB.UserID = dayUserID
+		locationDB.DedupKey, err = getDedupKey(deviceID, locationPB.Sequence)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get location dedup key: %v", err)
+		}
+		locationsDB = append(locationsDB, locationDB)
+
+		units := lapi.geocoder.Resolve(float64(locationPB.Latitude), float64(locationPB.Longitude))
+		locationDB.County, locationDB.Constituency, locationDB.Ward = units.County, units.Constituency, units.Ward
+
+		// Inaccurate locations are kept in history but would produce false contacts
+		if !res.LowConfidence {
+			confident = append(confident, locationPB)
+			key := getUserSetKey(dayUserID, day)
+			contactPoints[key] = append(contactPoints[key], conversion.ContactPoint(locationPB.GeoFenceId, locationPB.TimeId))
+			if units.County != "" && lapi.geocoder.AtHome(time.Unix(locationPB.Timestamp, 0)) {
+				homes = append(homes, units.Key())
+			}
+		}
+	}
+
+	if len(locationsDB) == 0 && len(sequences) == 0 {
+		return saved, nil
+	}
+
+	// Locations are saved before their sequences so that a failed request can be retried
+	err = lapi.locations.Insert(ctx, locationsDB)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to add locations to db: %v", err)
+	}
+
+	var (
+		pipeliner    = lapi.eventsDB.Pipeline()
+		positive     = sendReq.StatusId == location.Status_POSITIVE
+		dangerChecks = make(map[string]*redis.BoolCmd)
+	)
+
+	if len(sequences) > 0 {
+		sequencesKey, err := getSequencesKey(deviceID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get device sequences key: %v", err)
+		}
+		pipeliner.SAdd(ctx, sequencesKey, sequences...)
+		pipeliner.Expire(ctx, sequencesKey, sequencesExpiration)
+	}
+
+	if len(locationsDB) > 0 {
+		lastLocationValue, err := formatLastLocation(lastLocation)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encrypt last location: %v", err)
+		}
+		pipeliner.Set(ctx, getLastLocationKey(userID), lastLocationValue, lastLocationExpiration)
+	}
+
+	for key, members := range contactPoints {
+		pipeliner.SAdd(ctx, key, members...)
+	}
+
+	if len(homes) > 0 {
+		homeKey, err := getHomeKey(sendReq.UserId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get home key: %v", err)
+		}
+		for _, home := range homes {
+			pipeliner.HIncrBy(ctx, homeKey, home, 1)
+		}
+		pipeliner.Expire(ctx, homeKey, homeExpiration)
+	}
+
+	for _, locationPB := range confident {
+		if positive {
+			// Add to blacklist
+			pipeliner.SAdd(ctx, getTimeKey(locationPB.TimeId), locationPB.GeoFenceId)
+			continue
+		}
+
+		if !checkDanger {
+			continue
+		}
+
+		// Cases in the same or neighbouring cells at the same or adjacent times
+		for _, timeID := range lapi.timeBuckets.TimeAndAdjacent(locationPB.TimeId) {
+			for _, cellID := range conversion.CellAndNeighbours(lapi.cells, locationPB.GeoFenceId) {
+				key := conversion.ContactPoint(cellID, timeID)
+				if _, ok := dangerChecks[key]; !ok {
+					dangerChecks[key] = pipeliner.SIsMember(ctx, getTimeKey(timeID), cellID)
+				}
+			}
+		}
+	}
+
+	_, err = pipeliner.Exec(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, status.Errorf(codes.Internal, "failed to save locations in cache: %v", err)
+	}
+
+	if len(dangerChecks) > 0 {
+		for _, locationPB := range confident {
+			if saved.danger != nil && locationPB.Timestamp <= saved.danger.Timestamp {
+				continue
+			}
+		checks:
+			for _, timeID := range lapi.timeBuckets.TimeAndAdjacent(locationPB.TimeId) {
+				for _, cellID := range conversion.CellAndNeighbours(lapi.cells, locationPB.GeoFenceId) {
+					if dangerChecks[conversion.ContactPoint(cellID, timeID)].Val() {
+						saved.danger = locationPB
+						break checks
+					}
+				}
+			}
+		}
+	}
+
+	if len(homes) > 0 {
+		err = lapi.updateHome(ctx, sendReq.UserId)
+		if err != nil {
+			lapi.logger.Errorf("failed to update user home: %v", err)
+		}
+	}
+
+	// Inaccurate locations could send messages about geo fences the user is not in
+	err = lapi.evaluateGeoFences(ctx, sendReq.UserId, userID, confident)
+	if err != nil {
+		lapi.logger.Errorf("failed to evaluate geo fences: %v", err)
+	}
+
+	return saved, nil
+}
+
+// receivedSequences returns the sequences of the locations that were received before from the device
+func (lapi *locationAPIServer) receivedSequences(
+	ctx context.Context, deviceID string, locations []*location.Location,
+) (map[int64]bool, error) {
+	received := make(map[int64]bool)
+	if deviceID == "" {
+		return received, nil
+	}
+
+	sequencesKey, err := getSequencesKey(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pipeliner = lapi.eventsDB.Pipeline()
+		checks    = make(map[int64]*redis.BoolCmd)
+	)
+
+	for _, locationPB := range locations {
+		sequence := locationPB.GetSequence()
+		if sequence <= 0 || checks[sequence] != nil {
+			continue
+		}
+		checks[sequence] = pipeliner.SIsMember(ctx, sequencesKey, strconv.FormatInt(sequence, 10))
+	}
+
+	if len(checks) == 0 {
+		return received, nil
+	}
+
+	_, err = pipeliner.Exec(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	for sequence, check := range checks {
+		received[sequence] = check.Val()
+	}
+
+	return received, nil
+}
+
+func (lapi *locationAPIServer) StreamLocations(stream location.LocationTracingAPI_StreamLocationsServer) error {
+	ctx := stream.Context()
+
+	// Authenticate request
+	err := lapi.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		streamRes   = &location.StreamLocationsResponse{}
+		phoneNumber string
+		userID      string
+		danger      *location.Location
+	)
+
+	for {
+		sendReq, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Unknown, "failed to receive locations: %v", err)
+		}
+
+		if phoneNumber == "" {
+			phoneNumber = sendReq.GetUserId()
+		}
+		if sendReq.GetUserId() != phoneNumber {
+			return status.Error(codes.InvalidArgument, "all locations in a stream must belong to the same user")
+		}
+
+		saved, err := lapi.saveLocations(ctx, sendReq, len(streamRes.Results), lapi.realtimeAlerts)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range saved.results {
+			if result.Accepted {
+				streamRes.AcceptedCount++
+			} else {
+				streamRes.RejectedCount++
+			}
+		}
+		streamRes.Results = append(streamRes.Results, saved.results...)
+
+		if saved.deviceID != "" {
+			if streamRes.DeviceId != "" && streamRes.DeviceId != saved.deviceID {
+				return status.Error(codes.InvalidArgument, "all locations in a stream must be sent from the same device")
+			}
+			streamRes.DeviceId = saved.deviceID
+		}
+		if saved.acknowledgedSequence > streamRes.AcknowledgedSequence {
+			streamRes.AcknowledgedSequence = saved.acknowledgedSequence
+		}
+
+		if saved.danger != nil && (danger == nil || saved.danger.Timestamp > danger.Timestamp) {
+			userID, danger = saved.userID, saved.danger
+		}
+	}
+
+	if danger != nil {
+		// Send user a notification
+		go lapi.sendUserWarning(phoneNumber, userID, danger)
+	}
+
+	return stream.SendAndClose(streamRes)
+}
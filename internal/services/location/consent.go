@@ -0,0 +1,295 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// consentPurposes are the purposes a user can consent to
+var consentPurposes = []location.ConsentPurpose{
+	location.ConsentPurpose_TRACING,
+	location.ConsentPurpose_ANALYTICS,
+	location.ConsentPurpose_RESEARCH,
+}
+
+// getWithdrawnConsentKey is the set containing users who have withdrawn consent for the purpose
+func getWithdrawnConsentKey(purpose location.ConsentPurpose) string {
+	return fmt.Sprintf("consents:withdrawn:%s", purpose.String())
+}
+
+// expandPurposes returns the purposes in the request, ALL_PURPOSES or no purpose means every purpose
+func expandPurposes(purposes []location.ConsentPurpose) []location.ConsentPurpose {
+	if len(purposes) == 0 {
+		return consentPurposes
+	}
+	seen := make(map[location.ConsentPurpose]bool, len(purposes))
+	expanded := make([]location.ConsentPurpose, 0, len(purposes))
+	for _, purpose := range purposes {
+		if purpose == location.ConsentPurpose_ALL_PURPOSES {
+			return consentPurposes
+		}
+		if seen[purpose] {
+			continue
+		}
+		seen[purpose] = true
+		expanded = append(expanded, purpose)
+	}
+	return expanded
+}
+
+func validatePurposes(purposes []location.ConsentPurpose) error {
+	for _, purpose := range purposes {
+		if _, ok := location.ConsentPurpose_name[int32(purpose)]; !ok {
+			return status.Errorf(codes.InvalidArgument, "unknown consent purpose %d", purpose)
+		}
+	}
+	return nil
+}
+
+func (lapi *locationAPIServer) GrantConsent(
+	ctx context.Context, grantReq *location.GrantConsentRequest,
+) (*location.Consents, error) {
+	// Request must not be nil
+	if grantReq == nil {
+		return nil, services.NilRequestError("GrantConsentRequest")
+	}
+
+	// Validation
+	var err error
+	switch {
+	case grantReq.PhoneNumber == "":
+		err = services.MissingFieldError("phone number")
+	case grantReq.PrivacyNoticeVersion == "":
+		err = services.MissingFieldError("privacy notice version")
+	default:
+		err = validatePurposes(grantReq.Purposes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the user can grant consent
+	err = lapi.authorize(ctx, grantReq.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	purposes := expandPurposes(grantReq.Purposes)
+	now := time.Now()
+
+	tx := lapi.logsDB.Begin()
+	defer func() {
+		if err := recover(); err != nil {
+			tx.Rollback()
+		}
+	}()
+	if tx.Error != nil {
+		return nil, services.FailedToBeginTx(tx.Error)
+	}
+
+	for _, purpose := range purposes {
+		consentDB := &services.Consent{}
+		err = tx.Where(&services.Consent{
+			PhoneNumber: grantReq.PhoneNumber,
+			Purpose:     int8(purpose),
+		}).Assign(map[string]interface{}{
+			"granted":                true,
+			"privacy_notice_version": grantReq.PrivacyNoticeVersion,
+			"granted_at":             &now,
+			"withdrawn_at":           nil,
+		}).FirstOrCreate(consentDB).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, status.Errorf(codes.Internal, "failed to save consent: %v", err)
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return nil, services.FailedToCommitTx(err)
+	}
+
+	// Users who grant consent are no longer excluded
+	for _, purpose := range purposes {
+		err = lapi.eventsDB.SRem(ctx, getWithdrawnConsentKey(purpose), grantReq.PhoneNumber).Err()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to update withdrawn consents: %v", err)
+		}
+	}
+
+	return lapi.getConsents(grantReq.PhoneNumber)
+}
+
+func (lapi *locationAPIServer) WithdrawConsent(
+	ctx context.Context, withdrawReq *location.WithdrawConsentRequest,
+) (*location.Consents, error) {
+	// Request must not be nil
+	if withdrawReq == nil {
+		return nil, services.NilRequestError("WithdrawConsentRequest")
+	}
+
+	// Validation
+	var err error
+	switch {
+	case withdrawReq.PhoneNumber == "":
+		err = services.MissingFieldError("phone number")
+	default:
+		err = validatePurposes(withdrawReq.Purposes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the user can withdraw consent
+	err = lapi.authorize(ctx, withdrawReq.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	purposes := expandPurposes(withdrawReq.Purposes)
+	now := time.Now()
+
+	tx := lapi.logsDB.Begin()
+	defer func() {
+		if err := recover(); err != nil {
+			tx.Rollback()
+		}
+	}()
+	if tx.Error != nil {
+		return nil, services.FailedToBeginTx(tx.Error)
+	}
+
+	for _, purpose := range purposes {
+		consentDB := &services.Consent{}
+		err = tx.Where(&services.Consent{
+			PhoneNumber: withdrawReq.PhoneNumber,
+			Purpose:     int8(purpose),
+		}).Assign(map[string]interface{}{
+			"granted":      false,
+			"withdrawn_at": &now,
+		}).FirstOrCreate(consentDB).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, status.Errorf(codes.Internal, "failed to save consent: %v", err)
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return nil, services.FailedToCommitTx(err)
+	}
+
+	for _, purpose := range purposes {
+		err = lapi.eventsDB.SAdd(ctx, getWithdrawnConsentKey(purpose), withdrawReq.PhoneNumber).Err()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to update withdrawn consents: %v", err)
+		}
+
+		// Contact points are only held for tracing
+		if purpose == location.ConsentPurpose_TRACING {
+			err = lapi.deleteUserContactPoints(ctx, withdrawReq.PhoneNumber)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return lapi.getConsents(withdrawReq.PhoneNumber)
+}
+
+func (lapi *locationAPIServer) GetConsents(
+	ctx context.Context, getReq *location.GetConsentsRequest,
+) (*location.Consents, error) {
+	// Request must not be nil
+	if getReq == nil {
+		return nil, services.NilRequestError("GetConsentsRequest")
+	}
+
+	// Validation
+	if getReq.PhoneNumber == "" {
+		return nil, services.MissingFieldError("phone number")
+	}
+
+	// Authorize request
+	err := lapi.authorize(ctx, getReq.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return lapi.getConsents(getReq.PhoneNumber)
+}
+
+func (lapi *locationAPIServer) getConsents(phoneNumber string) (*location.Consents, error) {
+	consentsDB := make([]*services.Consent, 0, len(consentPurposes))
+	err := lapi.logsDB.Order("purpose ASC").Find(&consentsDB, "phone_number=?", phoneNumber).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get consents: %v", err)
+	}
+
+	consentsPB := make([]*location.Consent, 0, len(consentsDB))
+	for _, consentDB := range consentsDB {
+		consentsPB = append(consentsPB, getConsentPB(consentDB))
+	}
+
+	return &location.Consents{Consents: consentsPB}, nil
+}
+
+func getConsentPB(consentDB *services.Consent) *location.Consent {
+	consentPB := &location.Consent{
+		Purpose:              location.ConsentPurpose(consentDB.Purpose),
+		Granted:              consentDB.Granted,
+		PrivacyNoticeVersion: consentDB.PrivacyNoticeVersion,
+	}
+	if consentDB.GrantedAt != nil {
+		consentPB.GrantedTimestamp = consentDB.GrantedAt.Unix()
+	}
+	if consentDB.WithdrawnAt != nil {
+		consentPB.WithdrawnTimestamp = consentDB.WithdrawnAt.Unix()
+	}
+	return consentPB
+}
+
+// tracingConsentWithdrawn checks whether the user has withdrawn consent for location tracing
+func (lapi *locationAPIServer) tracingConsentWithdrawn(ctx context.Context, phoneNumber string) (bool, error) {
+	withdrawn, err := lapi.eventsDB.SIsMember(
+		ctx, getWithdrawnConsentKey(location.ConsentPurpose_TRACING), phoneNumber,
+	).Result()
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to check user consent: %v", err)
+	}
+	return withdrawn, nil
+}
+
+// loadWithdrawnConsents copies withdrawn consents from the database to redis
+func (lapi *locationAPIServer) loadWithdrawnConsents(ctx context.Context) error {
+	for _, purpose := range consentPurposes {
+		phones := make([]string, 0)
+		err := lapi.logsDB.Model(&services.Consent{}).
+			Where("purpose=? AND granted=?", int8(purpose), false).
+			Pluck("phone_number", &phones).Error
+		if err != nil {
+			return err
+		}
+
+		if len(phones) == 0 {
+			continue
+		}
+
+		members := make([]interface{}, 0, len(phones))
+		for _, phone := range phones {
+			members = append(members, phone)
+		}
+
+		err = lapi.eventsDB.SAdd(ctx, getWithdrawnConsentKey(purpose), members...).Err()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
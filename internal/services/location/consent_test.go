@@ -0,0 +1,176 @@
+package location
+
+import (
+	"context"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("Managing user consents #consent", func() {
+	var (
+		grantReq    *location.GrantConsentRequest
+		withdrawReq *location.WithdrawConsentRequest
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		grantReq = &location.GrantConsentRequest{
+			PhoneNumber:          randomdata.PhoneNumber(),
+			Purposes:             []location.ConsentPurpose{location.ConsentPurpose_TRACING},
+			PrivacyNoticeVersion: "v1",
+		}
+		withdrawReq = &location.WithdrawConsentRequest{
+			PhoneNumber: randomdata.PhoneNumber(),
+			Purposes:    []location.ConsentPurpose{location.ConsentPurpose_TRACING},
+		}
+		ctx = context.Background()
+	})
+
+	Describe("Granting consent with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			grantReq = nil
+			grantRes, err := LocationAPI.GrantConsent(ctx, grantReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(grantRes).Should(BeNil())
+		})
+		It("should fail when phone number is missing", func() {
+			grantReq.PhoneNumber = ""
+			grantRes, err := LocationAPI.GrantConsent(ctx, grantReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(grantRes).Should(BeNil())
+		})
+		It("should fail when privacy notice version is missing", func() {
+			grantReq.PrivacyNoticeVersion = ""
+			grantRes, err := LocationAPI.GrantConsent(ctx, grantReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(grantRes).Should(BeNil())
+		})
+		It("should fail when purpose is unknown", func() {
+			grantReq.Purposes = []location.ConsentPurpose{location.ConsentPurpose(20)}
+			grantRes, err := LocationAPI.GrantConsent(ctx, grantReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(grantRes).Should(BeNil())
+		})
+	})
+
+	Describe("Withdrawing consent with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			withdrawReq = nil
+			withdrawRes, err := LocationAPI.WithdrawConsent(ctx, withdrawReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(withdrawRes).Should(BeNil())
+		})
+		It("should fail when phone number is missing", func() {
+			withdrawReq.PhoneNumber = ""
+			withdrawRes, err := LocationAPI.WithdrawConsent(ctx, withdrawReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(withdrawRes).Should(BeNil())
+		})
+	})
+
+	When("Managing consent with well-formed request", func() {
+		var userPhone string
+
+		Describe("Granting consent for all purposes", func() {
+			It("should succeed", func() {
+				userPhone = grantReq.PhoneNumber
+				grantReq.Purposes = nil
+				grantRes, err := LocationAPI.GrantConsent(ctx, grantReq)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(status.Code(err)).Should(Equal(codes.OK))
+				Expect(grantRes.Consents).Should(HaveLen(len(consentPurposes)))
+				for _, consent := range grantRes.Consents {
+					Expect(consent.Granted).Should(BeTrue())
+					Expect(consent.PrivacyNoticeVersion).Should(Equal("v1"))
+					Expect(consent.GrantedTimestamp).ShouldNot(BeZero())
+				}
+			})
+		})
+
+		Describe("Sending location after granting consent", func() {
+			It("should succeed", func() {
+				sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+					UserId:   userPhone,
+					StatusId: location.Status_NEGATIVE,
+					Location: fakeLocation(),
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(sendRes).ShouldNot(BeNil())
+			})
+		})
+
+		Describe("Withdrawing consent for tracing", func() {
+			It("should succeed", func() {
+				withdrawReq.PhoneNumber = userPhone
+				withdrawRes, err := LocationAPI.WithdrawConsent(ctx, withdrawReq)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(status.Code(err)).Should(Equal(codes.OK))
+				for _, consent := range withdrawRes.Consents {
+					if consent.Purpose == location.ConsentPurpose_TRACING {
+						Expect(consent.Granted).Should(BeFalse())
+						Expect(consent.WithdrawnTimestamp).ShouldNot(BeZero())
+						continue
+					}
+					Expect(consent.Granted).Should(BeTrue())
+				}
+			})
+			It("should remove the user contact points", func() {
+				userIDs, err := LocationServer.pseudonyms.IDs(userPhone)
+				Expect(err).ShouldNot(HaveOccurred())
+				for _, userID := range userIDs {
+					keys, err := LocationServer.eventsDB.Keys(ctx, userID+":*").Result()
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(keys).Should(BeEmpty())
+				}
+			})
+		})
+
+		Describe("Sending location after withdrawing consent", func() {
+			It("should fail", func() {
+				sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+					UserId:   userPhone,
+					StatusId: location.Status_NEGATIVE,
+					Location: fakeLocation(),
+				})
+				Expect(err).Should(HaveOccurred())
+				Expect(status.Code(err)).Should(Equal(codes.FailedPrecondition))
+				Expect(sendRes).Should(BeNil())
+			})
+		})
+
+		Describe("Getting user consents", func() {
+			It("should succeed", func() {
+				getRes, err := LocationAPI.GetConsents(ctx, &location.GetConsentsRequest{
+					PhoneNumber: userPhone,
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(status.Code(err)).Should(Equal(codes.OK))
+				Expect(getRes.Consents).Should(HaveLen(len(consentPurposes)))
+			})
+		})
+
+		Describe("Granting consent for tracing again", func() {
+			It("should allow sending locations", func() {
+				grantReq.PhoneNumber = userPhone
+				_, err := LocationAPI.GrantConsent(ctx, grantReq)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+					UserId:   userPhone,
+					StatusId: location.Status_NEGATIVE,
+					Location: fakeLocation(),
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(sendRes).ShouldNot(BeNil())
+			})
+		})
+	})
+})
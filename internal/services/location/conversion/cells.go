@@ -0,0 +1,267 @@
+package conversion
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/s2"
+)
+
+// CellSystem divides the earth into cells that are used as geo fences.
+//
+// Two locations are considered close when they are in the same cell or in neighbouring cells.
+type CellSystem interface {
+	// Name returns the name of the cell system and its precision
+	Name() string
+	// CellID returns the id of the cell containing the coordinates
+	CellID(latitude, longitude float64) string
+	// Neighbours returns the ids of cells sharing an edge or a corner with the cell
+	Neighbours(cellID string) []string
+}
+
+// CellAndNeighbours returns the id of the cell followed by the ids of its neighbours
+func CellAndNeighbours(cells CellSystem, cellID string) []string {
+	return append([]string{cellID}, cells.Neighbours(cellID)...)
+}
+
+// Supported cell systems
+const (
+	GeohashCells = "geohash"
+	S2Cells      = "s2"
+)
+
+const (
+	// DefaultGeohashPrecision gives cells of about 38m x 19m
+	DefaultGeohashPrecision = 8
+	// DefaultS2Level gives cells with edges of about 30m
+	DefaultS2Level = 18
+)
+
+// NewCellSystem creates a cell system by name. A zero precision uses the default precision of the cell system.
+func NewCellSystem(name string, precision int) (CellSystem, error) {
+	switch strings.ToLower(name) {
+	case "", GeohashCells:
+		if precision == 0 {
+			precision = DefaultGeohashPrecision
+		}
+		return NewGeohash(precision)
+	case S2Cells:
+		if precision == 0 {
+			precision = DefaultS2Level
+		}
+		return NewS2(precision)
+	default:
+		return nil, fmt.Errorf("unknown cell system %q", name)
+	}
+}
+
+// DefaultCellSystem returns geohash cells with the default precision
+func DefaultCellSystem() CellSystem {
+	cells, _ := NewGeohash(DefaultGeohashPrecision)
+	return cells
+}
+
+// NewCellSystemFromEnv creates a cell system from GEO_CELL_SYSTEM and GEO_CELL_PRECISION.
+//
+// All services matching locations must use the same cell system.
+func NewCellSystemFromEnv() (CellSystem, error) {
+	var (
+		precision int
+		err       error
+	)
+
+	if value := strings.TrimSpace(os.Getenv("GEO_CELL_PRECISION")); value != "" {
+		precision, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cell precision: %v", err)
+		}
+	}
+
+	return NewCellSystem(strings.TrimSpace(os.Getenv("GEO_CELL_SYSTEM")), precision)
+}
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+type geohashCells struct {
+	precision int
+}
+
+// NewGeohash creates a cell system of geohashes with the given number of characters
+func NewGeohash(precision int) (CellSystem, error) {
+	if precision < 1 || precision > 12 {
+		return nil, fmt.Errorf("geohash precision must be between 1 and 12, got %d", precision)
+	}
+	return &geohashCells{precision: precision}, nil
+}
+
+func (g *geohashCells) Name() string {
+	return fmt.Sprintf("%s:%d", GeohashCells, g.precision)
+}
+
+func (g *geohashCells) CellID(latitude, longitude float64) string {
+	return encodeGeohash(latitude, longitude, g.precision)
+}
+
+func (g *geohashCells) Neighbours(cellID string) []string {
+	minLat, maxLat, minLong, maxLong, ok := decodeGeohash(cellID)
+	if !ok {
+		return []string{}
+	}
+
+	var (
+		latitude   = (minLat + maxLat) / 2
+		longitude  = (minLong + maxLong) / 2
+		height     = maxLat - minLat
+		width      = maxLong - minLong
+		neighbours = make([]string, 0, 8)
+	)
+
+	for _, dLat := range []float64{-1, 0, 1} {
+		for _, dLong := range []float64{-1, 0, 1} {
+			if dLat == 0 && dLong == 0 {
+				continue
+			}
+			lat := latitude + dLat*height
+			if lat > 90 || lat < -90 {
+				// No cells beyond the poles
+				continue
+			}
+			long := math.Mod(longitude+dLong*width+540, 360) - 180
+			neighbours = append(neighbours, encodeGeohash(lat, long, len(cellID)))
+		}
+	}
+
+	return neighbours
+}
+
+func encodeGeohash(latitude, longitude float64, precision int) string {
+	var (
+		hash           = make([]byte, 0, precision)
+		minLat, maxLat = -90.0, 90.0
+		minLon, maxLon = -180.0, 180.0
+		bits, ch       = 0, 0
+		even           = true
+	)
+
+	for len(hash) < precision {
+		if even {
+			mid := (minLon + maxLon) / 2
+			if longitude >= mid {
+				ch = ch<<1 | 1
+				minLon = mid
+			} else {
+				ch = ch << 1
+				maxLon = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if latitude >= mid {
+				ch = ch<<1 | 1
+				minLat = mid
+			} else {
+				ch = ch << 1
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		bits++
+		if bits == 5 {
+			hash = append(hash, geohashAlphabet[ch])
+			bits, ch = 0, 0
+		}
+	}
+
+	return string(hash)
+}
+
+func decodeGeohash(hash string) (minLat, maxLat, minLong, maxLong float64, ok bool) {
+	minLat, maxLat = -90.0, 90.0
+	minLong, maxLong = -180.0, 180.0
+	even := true
+
+	if hash == "" {
+		return
+	}
+
+	for i := 0; i < len(hash); i++ {
+		index := strings.IndexByte(geohashAlphabet, hash[i])
+		if index < 0 {
+			return
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := index>>uint(bit)&1 == 1
+			if even {
+				mid := (minLong + maxLong) / 2
+				if set {
+					minLong = mid
+				} else {
+					maxLong = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if set {
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	ok = true
+	return
+}
+
+type s2Cells struct {
+	level int
+}
+
+// NewS2 creates a cell system of S2 cells at the given level
+func NewS2(level int) (CellSystem, error) {
+	if level < 0 || level > s2.MaxLevel {
+		return nil, fmt.Errorf("s2 level must be between 0 and %d, got %d", s2.MaxLevel, level)
+	}
+	return &s2Cells{level: level}, nil
+}
+
+func (c *s2Cells) Name() string {
+	return fmt.Sprintf("%s:%d", S2Cells, c.level)
+}
+
+func (c *s2Cells) CellID(latitude, longitude float64) string {
+	return s2.CellIDFromLatLng(s2.LatLngFromDegrees(latitude, longitude)).Parent(c.level).ToToken()
+}
+
+func (c *s2Cells) Neighbours(cellID string) []string {
+	id := s2.CellIDFromToken(cellID)
+	if !id.IsValid() {
+		return []string{}
+	}
+
+	neighbourIDs := id.AllNeighbors(id.Level())
+	neighbours := make([]string, 0, len(neighbourIDs))
+	for _, neighbourID := range neighbourIDs {
+		neighbours = append(neighbours, neighbourID.ToToken())
+	}
+
+	return neighbours
+}
+
+// ContactPoint returns the member stored in a user location set for a cell at a time
+func ContactPoint(cellID, timeID string) string {
+	return cellID + ":" + timeID
+}
+
+// ParseContactPoint returns the cell and time ids of a contact point
+func ParseContactPoint(contactPoint string) (cellID, timeID string, ok bool) {
+	index := strings.LastIndex(contactPoint, ":")
+	if index <= 0 || index == len(contactPoint)-1 {
+		return "", "", false
+	}
+	return contactPoint[:index], contactPoint[index+1:], true
+}
@@ -28,23 +28,17 @@ func NewDMS(loc *location.Location, radius float64) *DMS {
 	}
 	loc.Latitude = float32(lat)
 
-	// println(int(loc.Longitude * 1000000))
+	// Degrees, minutes and seconds are computed on absolute values, the sign is kept separately
+	lat, long = math.Abs(lat), math.Abs(long)
 
-	if loc.Longitude < 0 {
-		loc.Longitude = loc.Longitude - loc.Longitude - loc.Longitude
-	}
-	if loc.Latitude < 0 {
-		loc.Latitude = loc.Latitude - loc.Latitude - loc.Latitude
-	}
-
-	latMinute := 60 * (float64(loc.Latitude) - math.Trunc(float64(loc.Latitude)))
-	longMinute := 60 * (float64(loc.Longitude) - math.Trunc(float64(loc.Longitude)))
+	latMinute := 60 * (lat - math.Trunc(lat))
+	longMinute := 60 * (long - math.Trunc(long))
 
 	dms := &DMS{
 		location: loc,
 
-		latDegree:  int(loc.Latitude),
-		longDegree: int(loc.Longitude),
+		latDegree:  int(lat),
+		longDegree: int(long),
 
 		latMinute:  int(latMinute),
 		longMinute: int(longMinute),
@@ -113,15 +107,15 @@ func (dms *DMS) LongitudeSeconds() float64 {
 // NewDD creates a new DD
 func NewDD(dms *DMS) *DD {
 	dd := &DD{
-		longitude: float64(dms.longDegree+(dms.longMinute/60)) + dms.longSecond/3600.00,
-		latitude:  float64(dms.latDegree+(dms.latMinute/60)) + dms.latSecond/3600.00,
+		longitude: float64(dms.longDegree) + float64(dms.longMinute)/60 + dms.longSecond/3600.00,
+		latitude:  float64(dms.latDegree) + float64(dms.latMinute)/60 + dms.latSecond/3600.00,
 	}
 
 	if dms.latLessZero {
-		dd.latitude = dd.latitude - dd.latitude - dd.latitude
+		dd.latitude = -dd.latitude
 	}
 	if dms.longLessZero {
-		dd.longitude = dd.longitude - dd.longitude - dd.longitude
+		dd.longitude = -dd.longitude
 	}
 
 	return dd
@@ -157,6 +151,8 @@ func (dms *DMS) getBox(sec float64) float64 {
 }
 
 // GeoFenceID returns the geo fence id of a location
+//
+// Deprecated: boxes do not have neighbours, use a CellSystem instead
 func GeoFenceID(loc *location.Location, radius float64) string {
 	dms := NewDMS(loc, radius)
 	dms.latSecond = dms.getBox(float64(dms.latSecond))
@@ -166,6 +162,8 @@ func GeoFenceID(loc *location.Location, radius float64) string {
 }
 
 // GetTimestampRange gets lower and upper timestamp range
+//
+// Deprecated: use TimeBuckets
 func GetTimestampRange(loc *location.Location, dur time.Duration) (int64, int64) {
 	min := loc.Timestamp / dur.Milliseconds()
 	max := min + dur.Milliseconds()
@@ -173,8 +171,27 @@ func GetTimestampRange(loc *location.Location, dur time.Duration) (int64, int64)
 }
 
 // GetTimeID gets time id of a location
+//
+// Deprecated: use TimeBuckets, which has a canonical time id format and adjacent windows
 func GetTimeID(loc *location.Location, dur time.Duration) string {
 	rangeTime := loc.Timestamp / dur.Milliseconds()
 	max := rangeTime*dur.Milliseconds() + rangeTime
 	return fmt.Sprint(max)
 }
+
+const earthRadius = 6371000.0 // meters
+
+// Distance returns the great-circle distance in meters between two coordinates
+func Distance(lat1, long1, lat2, long2 float64) float64 {
+	var (
+		phi1     = lat1 * math.Pi / 180
+		phi2     = lat2 * math.Pi / 180
+		deltaPhi = (lat2 - lat1) * math.Pi / 180
+		deltaLam = (long2 - long1) * math.Pi / 180
+	)
+
+	a := math.Sin(deltaPhi/2)*math.Sin(deltaPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(deltaLam/2)*math.Sin(deltaLam/2)
+
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,99 @@
+package conversion
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeBucketWidth is the width of time windows used when none is configured
+const DefaultTimeBucketWidth = 5 * time.Minute
+
+// TimeBuckets divides time into consecutive windows of equal width.
+//
+// The canonical time id of a window is the unix time in seconds at which the window starts,
+// formatted in base 10. With 5 minute windows, a location at 10:04:59 UTC on 2020-05-13 has the
+// time id "1589364000" (10:00:00) and one at 10:06:00 has "1589364300" (10:05:00).
+//
+// Two people are considered to be at a place at the same time when their time ids are equal or
+// adjacent, so the effective matching window is up to twice the width.
+type TimeBuckets struct {
+	width int64
+}
+
+// NewTimeBuckets creates time buckets of the given width, which must be a whole number of seconds
+func NewTimeBuckets(width time.Duration) (*TimeBuckets, error) {
+	if width < time.Second || width%time.Second != 0 {
+		return nil, fmt.Errorf("time bucket width must be a positive whole number of seconds, got %v", width)
+	}
+	return &TimeBuckets{width: int64(width / time.Second)}, nil
+}
+
+// DefaultTimeBuckets returns time buckets of the default width
+func DefaultTimeBuckets() *TimeBuckets {
+	tb, _ := NewTimeBuckets(DefaultTimeBucketWidth)
+	return tb
+}
+
+// NewTimeBucketsFromEnv creates time buckets with a width of TIME_BUCKET_MINUTES.
+//
+// All services matching locations must use the same width.
+func NewTimeBucketsFromEnv() (*TimeBuckets, error) {
+	value := strings.TrimSpace(os.Getenv("TIME_BUCKET_MINUTES"))
+	if value == "" {
+		return DefaultTimeBuckets(), nil
+	}
+
+	minutes, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time bucket minutes: %v", err)
+	}
+
+	return NewTimeBuckets(time.Duration(minutes) * time.Minute)
+}
+
+// Width returns the width of a time window
+func (tb *TimeBuckets) Width() time.Duration {
+	return time.Duration(tb.width) * time.Second
+}
+
+func (tb *TimeBuckets) start(timestamp int64) int64 {
+	start := timestamp - timestamp%tb.width
+	if timestamp < 0 && timestamp%tb.width != 0 {
+		start -= tb.width
+	}
+	return start
+}
+
+// TimeID returns the time id of the window containing the unix timestamp in seconds
+func (tb *TimeBuckets) TimeID(timestamp int64) string {
+	return strconv.FormatInt(tb.start(timestamp), 10)
+}
+
+// Time returns the start of the window with the time id
+func (tb *TimeBuckets) Time(timeID string) (time.Time, error) {
+	start, err := strconv.ParseInt(timeID, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed time id %q: %v", timeID, err)
+	}
+	return time.Unix(start, 0), nil
+}
+
+// Adjacent returns the time ids of the windows before and after the window with the time id
+func (tb *TimeBuckets) Adjacent(timeID string) []string {
+	start, err := strconv.ParseInt(timeID, 10, 64)
+	if err != nil {
+		return []string{}
+	}
+	return []string{
+		strconv.FormatInt(tb.start(start)-tb.width, 10),
+		strconv.FormatInt(tb.start(start)+tb.width, 10),
+	}
+}
+
+// TimeAndAdjacent returns the time id followed by the time ids of its adjacent windows
+func (tb *TimeBuckets) TimeAndAdjacent(timeID string) []string {
+	return append([]string{timeID}, tb.Adjacent(timeID)...)
+}
@@ -0,0 +1,176 @@
+package location
+
+import (
+	"context"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+var _ = Describe("Deleting user account #delete", func() {
+	var (
+		delReq *location.DeleteMyAccountRequest
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		delReq = &location.DeleteMyAccountRequest{
+			PhoneNumber: randomdata.PhoneNumber(),
+		}
+		ctx = context.Background()
+	})
+
+	Describe("Deleting account with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			delReq = nil
+			delRes, err := LocationAPI.DeleteMyAccount(ctx, delReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(delRes).Should(BeNil())
+		})
+		It("should fail when phone number is missing", func() {
+			delReq.PhoneNumber = ""
+			delRes, err := LocationAPI.DeleteMyAccount(ctx, delReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(delRes).Should(BeNil())
+		})
+		It("should fail when user does not exist", func() {
+			delRes, err := LocationAPI.DeleteMyAccount(ctx, delReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(delRes).Should(BeNil())
+		})
+	})
+
+	When("Deleting account with well-formed request", func() {
+		var (
+			userPhone  string
+			deliveryID uint
+		)
+		Describe("Create user with locations first", func() {
+			It("should succeed", func() {
+				addReq := &location.AddUserRequest{
+					User: fakeUser(),
+				}
+				addRes, err := LocationAPI.AddUser(ctx, addReq)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(addRes).ShouldNot(BeNil())
+				userPhone = addReq.User.PhoneNumber
+
+				sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+					UserId:   userPhone,
+					StatusId: location.Status_NEGATIVE,
+					Location: fakeLocation(),
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(sendRes).ShouldNot(BeNil())
+
+				// A message pending delivery to the user
+				messageDB := &services.Message{UserPhone: userPhone, Title: "title", Message: "message"}
+				Expect(LocationServer.logsDB.Create(messageDB).Error).ShouldNot(HaveOccurred())
+
+				deliveryDB := &services.Delivery{
+					MessageID:     messageDB.ID,
+					UserPhone:     userPhone,
+					Status:        services.DeliveryPending,
+					NextAttemptAt: time.Now(),
+				}
+				Expect(LocationServer.logsDB.Create(deliveryDB).Error).ShouldNot(HaveOccurred())
+
+				err = LocationServer.logsDB.Create(&services.DeliveryAttempt{
+					DeliveryID: deliveryDB.ID,
+					Channel:    "push",
+				}).Error
+				Expect(err).ShouldNot(HaveOccurred())
+				deliveryID = deliveryDB.ID
+			})
+		})
+
+		Describe("Deleting the account", func() {
+			var userIDs []string
+			It("should succeed", func() {
+				var err error
+				userIDs, err = LocationServer.pseudonyms.IDs(userPhone)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(userIDs).ShouldNot(BeEmpty())
+
+				delReq.PhoneNumber = userPhone
+				delRes, err := LocationAPI.DeleteMyAccount(ctx, delReq)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(status.Code(err)).Should(Equal(codes.OK))
+				Expect(delRes).ShouldNot(BeNil())
+			})
+
+			It("should remove the user and their locations", func() {
+				var count int
+				err := LocationServer.logsDB.Unscoped().Model(&services.UserModel{}).
+					Where("phone_number=?", userPhone).Count(&count).Error
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(count).Should(BeZero())
+
+				err = LocationServer.logsDB.Unscoped().Model(&services.LocationModel{}).
+					Where("user_id IN (?)", userIDs).Count(&count).Error
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(count).Should(BeZero())
+
+				for _, userID := range userIDs {
+					keys, err := LocationServer.eventsDB.Keys(ctx, userID+":*").Result()
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(keys).Should(BeEmpty())
+				}
+			})
+
+			It("should remove deliveries of messages to the user and their attempts", func() {
+				var count int
+				err := LocationServer.logsDB.Unscoped().Model(&services.Delivery{}).
+					Where("user_phone=?", userPhone).Count(&count).Error
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(count).Should(BeZero())
+
+				err = LocationServer.logsDB.Unscoped().Model(&services.DeliveryAttempt{}).
+					Where("delivery_id=?", deliveryID).Count(&count).Error
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(count).Should(BeZero())
+			})
+
+			It("should remove the user pseudonyms", func() {
+				userIDs, err := LocationServer.pseudonyms.IDs(userPhone)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(userIDs).Should(BeEmpty())
+			})
+		})
+
+		Describe("Retrying a deletion that failed before the user was deleted", func() {
+			It("should delete the user", func() {
+				phoneNumber := addFakeUser(ctx)
+
+				sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+					UserId:   phoneNumber,
+					StatusId: location.Status_NEGATIVE,
+					Location: fakeLocation(),
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(sendRes).ShouldNot(BeNil())
+
+				// Pseudonyms are deleted before the user by the failed request
+				Expect(LocationServer.pseudonyms.Delete(phoneNumber)).ShouldNot(HaveOccurred())
+
+				delRes, err := LocationAPI.DeleteMyAccount(ctx, &location.DeleteMyAccountRequest{
+					PhoneNumber: phoneNumber,
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(delRes).ShouldNot(BeNil())
+
+				var count int
+				err = LocationServer.logsDB.Unscoped().Model(&services.UserModel{}).
+					Where("phone_number=?", phoneNumber).Count(&count).Error
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(count).Should(BeZero())
+			})
+		})
+	})
+})
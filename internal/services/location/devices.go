@@ -0,0 +1,267 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/devicetoken"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+func (lapi *locationAPIServer) RegisterDevice(
+	ctx context.Context, registerReq *location.RegisterDeviceRequest,
+) (*location.Device, error) {
+	// Request must not be nil
+	if registerReq == nil {
+		return nil, services.NilRequestError("RegisterDeviceRequest")
+	}
+
+	// Validation
+	var err error
+	switch {
+	case registerReq.PhoneNumber == "":
+		err = services.MissingFieldError("phone number")
+	case registerReq.Device == nil:
+		err = services.MissingFieldError("device")
+	case registerReq.Device.DeviceToken == "" || registerReq.Device.DeviceToken == devicetoken.None:
+		err = services.MissingFieldError("device token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := location.DevicePlatform_name[int32(registerReq.Device.Platform)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown device platform %d", registerReq.Device.Platform)
+	}
+
+	// Only the user can register their devices
+	err = lapi.authorize(ctx, registerReq.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// User must exist
+	err = lapi.logsDB.Select("id").First(&services.UserModel{}, "phone_number=?", registerReq.PhoneNumber).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "user with phone number %s not found", registerReq.PhoneNumber)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+
+	tx := lapi.logsDB.Begin()
+	defer func() {
+		if err := recover(); err != nil {
+			tx.Rollback()
+		}
+	}()
+	if tx.Error != nil {
+		return nil, services.FailedToBeginTx(tx.Error)
+	}
+
+	deviceDB, err := saveDevice(tx, registerReq.PhoneNumber, registerReq.Device)
+	if err != nil {
+		tx.Rollback()
+		return nil, status.Errorf(codes.Internal, "failed to save device: %v", err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return nil, services.FailedToCommitTx(err)
+	}
+
+	go lapi.syncTopicSubscriptions(registerReq.PhoneNumber)
+
+	return getDevicePB(deviceDB), nil
+}
+
+func (lapi *locationAPIServer) UnregisterDevice(
+	ctx context.Context, unregisterReq *location.UnregisterDeviceRequest,
+) (*empty.Empty, error) {
+	// Request must not be nil
+	if unregisterReq == nil {
+		return nil, services.NilRequestError("UnregisterDeviceRequest")
+	}
+
+	// Validation
+	var err error
+	switch {
+	case unregisterReq.PhoneNumber == "":
+		err = services.MissingFieldError("phone number")
+	case unregisterReq.DeviceToken == "":
+		err = services.MissingFieldError("device token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the user can unregister their devices
+	err = lapi.authorize(ctx, unregisterReq.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := lapi.logsDB.Begin()
+	defer func() {
+		if err := recover(); err != nil {
+			tx.Rollback()
+		}
+	}()
+	if tx.Error != nil {
+		return nil, services.FailedToBeginTx(tx.Error)
+	}
+
+	db := tx.Unscoped().Delete(
+		&services.UserDevice{}, "phone_number=? AND device_token=?", unregisterReq.PhoneNumber, unregisterReq.DeviceToken,
+	)
+	switch {
+	case db.Error != nil:
+		tx.Rollback()
+		return nil, status.Errorf(codes.Internal, "failed to delete device: %v", db.Error)
+	case db.RowsAffected == 0:
+		tx.Rollback()
+		return nil, status.Errorf(codes.NotFound, "device not registered for user %s", unregisterReq.PhoneNumber)
+	}
+
+	err = syncDeviceToken(tx, unregisterReq.PhoneNumber)
+	if err != nil {
+		tx.Rollback()
+		return nil, status.Errorf(codes.Internal, "failed to update user device token: %v", err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return nil, services.FailedToCommitTx(err)
+	}
+
+	go lapi.syncTopicSubscriptions(unregisterReq.PhoneNumber)
+
+	return &empty.Empty{}, nil
+}
+
+// syncTopicSubscriptions keeps the FCM topics the devices of a user are subscribed to in line with their county,
+// status and language
+func (lapi *locationAPIServer) syncTopicSubscriptions(phoneNumber string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := lapi.messagingClient.SyncTopicSubscriptions(ctx, &messaging.SyncTopicSubscriptionsRequest{
+		PhoneNumber: phoneNumber,
+	}, grpc.WaitForReady(true))
+	if err != nil {
+		lapi.logger.Errorf("failed to sync topic subscriptions of %s: %v", phoneNumber, err)
+	}
+}
+
+func (lapi *locationAPIServer) ListDevices(
+	ctx context.Context, listReq *location.ListDevicesRequest,
+) (*location.Devices, error) {
+	// Request must not be nil
+	if listReq == nil {
+		return nil, services.NilRequestError("ListDevicesRequest")
+	}
+
+	// Validation
+	if listReq.PhoneNumber == "" {
+		return nil, services.MissingFieldError("phone number")
+	}
+
+	// Only the user can list their devices
+	err := lapi.authorize(ctx, listReq.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	devicesDB := make([]*services.UserDevice, 0)
+	err = lapi.logsDB.Order("last_seen_at DESC").Find(&devicesDB, "phone_number=?", listReq.PhoneNumber).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get devices: %v", err)
+	}
+
+	devicesPB := make([]*location.Device, 0, len(devicesDB))
+	for _, deviceDB := range devicesDB {
+		devicesPB = append(devicesPB, getDevicePB(deviceDB))
+	}
+
+	return &location.Devices{
+		Devices: devicesPB,
+	}, nil
+}
+
+// saveDevice registers the device to the user, taking it from any user it was registered to
+func saveDevice(tx *gorm.DB, phoneNumber string, devicePB *location.Device) (*services.UserDevice, error) {
+	updates := map[string]interface{}{
+		"phone_number": phoneNumber,
+		"last_seen_at": time.Now(),
+	}
+	// Devices saved with the user carry only the token
+	if devicePB.Platform != location.DevicePlatform_PLATFORM_UNSPECIFIED {
+		updates["platform"] = int8(devicePB.Platform)
+	}
+	if devicePB.AppVersion != "" {
+		updates["app_version"] = devicePB.AppVersion
+	}
+
+	deviceDB := &services.UserDevice{}
+	err := tx.Where(&services.UserDevice{
+		DeviceToken: devicePB.DeviceToken,
+	}).Assign(updates).FirstOrCreate(deviceDB).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// A phone that changed hands no longer notifies its previous owner
+	previousOwners := make([]string, 0)
+	err = tx.Table(services.UsersTable).Where("device_token=? AND phone_number<>?", devicePB.DeviceToken, phoneNumber).
+		Pluck("phone_number", &previousOwners).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, owner := range append(previousOwners, phoneNumber) {
+		err = syncDeviceToken(tx, owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return deviceDB, nil
+}
+
+// syncDeviceToken sets the device token of the user to their most recently seen device, for clients of the single token
+func syncDeviceToken(tx *gorm.DB, phoneNumber string) error {
+	deviceDB := &services.UserDevice{}
+	err := tx.Select("device_token").Order("last_seen_at DESC").First(deviceDB, "phone_number=?", phoneNumber).Error
+	switch {
+	case err == nil:
+		return tx.Table(services.UsersTable).Where("phone_number=?", phoneNumber).Updates(map[string]interface{}{
+			"device_token":            deviceDB.DeviceToken,
+			"device_token_invalid_at": nil,
+		}).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return tx.Table(services.UsersTable).Where("phone_number=?", phoneNumber).
+			Update("device_token", devicetoken.None).Error
+	default:
+		return err
+	}
+}
+
+func getDevicePB(deviceDB *services.UserDevice) *location.Device {
+	return &location.Device{
+		DeviceToken:         deviceDB.DeviceToken,
+		Platform:            location.DevicePlatform(deviceDB.Platform),
+		AppVersion:          deviceDB.AppVersion,
+		LastSeenTimestamp:   deviceDB.LastSeenAt.Unix(),
+		RegisteredTimestamp: deviceDB.CreatedAt.Unix(),
+	}
+}
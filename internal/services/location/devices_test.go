@@ -0,0 +1,187 @@
+package location
+
+import (
+	"context"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/devicetoken"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func fakeDevice() *location.Device {
+	return &location.Device{
+		DeviceToken: randomdata.MacAddress(),
+		Platform:    location.DevicePlatform_ANDROID,
+		AppVersion:  "1.2.0",
+	}
+}
+
+var _ = Describe("Managing user devices #devices", func() {
+	var (
+		registerReq   *location.RegisterDeviceRequest
+		unregisterReq *location.UnregisterDeviceRequest
+		ctx           context.Context
+	)
+
+	BeforeEach(func() {
+		registerReq = &location.RegisterDeviceRequest{
+			PhoneNumber: randomdata.PhoneNumber()[:15],
+			Device:      fakeDevice(),
+		}
+		unregisterReq = &location.UnregisterDeviceRequest{
+			PhoneNumber: randomdata.PhoneNumber()[:15],
+			DeviceToken: randomdata.MacAddress(),
+		}
+		ctx = context.Background()
+	})
+
+	Describe("Registering device with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			registerReq = nil
+			registerRes, err := LocationAPI.RegisterDevice(ctx, registerReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(registerRes).Should(BeNil())
+		})
+		It("should fail when phone number is missing", func() {
+			registerReq.PhoneNumber = ""
+			registerRes, err := LocationAPI.RegisterDevice(ctx, registerReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(registerRes).Should(BeNil())
+		})
+		It("should fail when device is missing", func() {
+			registerReq.Device = nil
+			registerRes, err := LocationAPI.RegisterDevice(ctx, registerReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(registerRes).Should(BeNil())
+		})
+		It("should fail when device token is missing", func() {
+			registerReq.Device.DeviceToken = devicetoken.None
+			registerRes, err := LocationAPI.RegisterDevice(ctx, registerReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(registerRes).Should(BeNil())
+		})
+		It("should fail when platform is unknown", func() {
+			registerReq.Device.Platform = location.DevicePlatform(20)
+			registerRes, err := LocationAPI.RegisterDevice(ctx, registerReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(registerRes).Should(BeNil())
+		})
+		It("should fail when user does not exist", func() {
+			registerRes, err := LocationAPI.RegisterDevice(ctx, registerReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(registerRes).Should(BeNil())
+		})
+	})
+
+	Describe("Unregistering device with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			unregisterReq = nil
+			unregisterRes, err := LocationAPI.UnregisterDevice(ctx, unregisterReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(unregisterRes).Should(BeNil())
+		})
+		It("should fail when device token is missing", func() {
+			unregisterReq.DeviceToken = ""
+			unregisterRes, err := LocationAPI.UnregisterDevice(ctx, unregisterReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(unregisterRes).Should(BeNil())
+		})
+		It("should fail when device is not registered", func() {
+			unregisterRes, err := LocationAPI.UnregisterDevice(ctx, unregisterReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(unregisterRes).Should(BeNil())
+		})
+	})
+
+	When("Managing devices with well-formed request", func() {
+		var userPB *location.User
+
+		getUser := func(phoneNumber string) *location.User {
+			getRes, err := LocationAPI.GetUser(ctx, &location.GetUserRequest{PhoneNumber: phoneNumber})
+			Expect(err).ShouldNot(HaveOccurred())
+			return getRes
+		}
+
+		listDevices := func(phoneNumber string) []*location.Device {
+			listRes, err := LocationAPI.ListDevices(ctx, &location.ListDevicesRequest{PhoneNumber: phoneNumber})
+			Expect(err).ShouldNot(HaveOccurred())
+			return listRes.Devices
+		}
+
+		BeforeEach(func() {
+			userPB = fakeUser()
+			_, err := LocationAPI.AddUser(ctx, &location.AddUserRequest{User: userPB})
+			Expect(err).ShouldNot(HaveOccurred())
+			registerReq.PhoneNumber = userPB.PhoneNumber
+		})
+
+		It("should register the device saved with the user", func() {
+			devices := listDevices(userPB.PhoneNumber)
+			Expect(devices).Should(HaveLen(1))
+			Expect(devices[0].DeviceToken).Should(Equal(userPB.DeviceToken))
+		})
+
+		It("should keep devices registered before", func() {
+			registerRes, err := LocationAPI.RegisterDevice(ctx, registerReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(registerRes.DeviceToken).Should(Equal(registerReq.Device.DeviceToken))
+			Expect(registerRes.Platform).Should(Equal(location.DevicePlatform_ANDROID))
+			Expect(registerRes.AppVersion).Should(Equal("1.2.0"))
+
+			devices := listDevices(userPB.PhoneNumber)
+			Expect(devices).Should(HaveLen(2))
+			Expect(devices[0].DeviceToken).Should(Equal(registerReq.Device.DeviceToken))
+
+			// The user token is of the most recently seen device
+			Expect(getUser(userPB.PhoneNumber).DeviceToken).Should(Equal(registerReq.Device.DeviceToken))
+		})
+
+		It("should fall back to the remaining device when a device is unregistered", func() {
+			_, err := LocationAPI.RegisterDevice(ctx, registerReq)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = LocationAPI.UnregisterDevice(ctx, &location.UnregisterDeviceRequest{
+				PhoneNumber: userPB.PhoneNumber,
+				DeviceToken: registerReq.Device.DeviceToken,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(listDevices(userPB.PhoneNumber)).Should(HaveLen(1))
+			Expect(getUser(userPB.PhoneNumber).DeviceToken).Should(Equal(userPB.DeviceToken))
+
+			_, err = LocationAPI.UnregisterDevice(ctx, &location.UnregisterDeviceRequest{
+				PhoneNumber: userPB.PhoneNumber,
+				DeviceToken: userPB.DeviceToken,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(listDevices(userPB.PhoneNumber)).Should(BeEmpty())
+			Expect(getUser(userPB.PhoneNumber).DeviceToken).Should(Equal(devicetoken.None))
+		})
+
+		It("should move a device registered by another user", func() {
+			otherPB := fakeUser()
+			_, err := LocationAPI.AddUser(ctx, &location.AddUserRequest{User: otherPB})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			registerReq.Device.DeviceToken = otherPB.DeviceToken
+			_, err = LocationAPI.RegisterDevice(ctx, registerReq)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(listDevices(otherPB.PhoneNumber)).Should(BeEmpty())
+			Expect(getUser(otherPB.PhoneNumber).DeviceToken).Should(Equal(devicetoken.None))
+			Expect(listDevices(userPB.PhoneNumber)).Should(HaveLen(2))
+		})
+	})
+})
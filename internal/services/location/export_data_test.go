@@ -0,0 +1,103 @@
+package location
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("Exporting user data #export", func() {
+	var (
+		exportReq *location.ExportMyDataRequest
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		exportReq = &location.ExportMyDataRequest{
+			PhoneNumber: randomdata.PhoneNumber(),
+		}
+		ctx = context.Background()
+	})
+
+	Describe("Exporting user data with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			exportReq = nil
+			exportRes, err := LocationAPI.ExportMyData(ctx, exportReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(exportRes).Should(BeNil())
+		})
+		It("should fail when phone number is missing", func() {
+			exportReq.PhoneNumber = ""
+			exportRes, err := LocationAPI.ExportMyData(ctx, exportReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(exportRes).Should(BeNil())
+		})
+		It("should fail when user does not exist", func() {
+			exportRes, err := LocationAPI.ExportMyData(ctx, exportReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(exportRes).Should(BeNil())
+		})
+	})
+
+	When("Exporting user data with well-formed request", func() {
+		var userPhone string
+		Describe("Create user with locations first", func() {
+			It("should succeed", func() {
+				addReq := &location.AddUserRequest{
+					User: fakeUser(),
+				}
+				addRes, err := LocationAPI.AddUser(ctx, addReq)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(addRes).ShouldNot(BeNil())
+				userPhone = addReq.User.PhoneNumber
+
+				sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+					UserId:   userPhone,
+					StatusId: location.Status_NEGATIVE,
+					Location: fakeLocation(),
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(sendRes).ShouldNot(BeNil())
+			})
+		})
+
+		Describe("Exporting the data as json", func() {
+			It("should succeed", func() {
+				exportReq.PhoneNumber = userPhone
+				exportRes, err := LocationAPI.ExportMyData(ctx, exportReq)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(status.Code(err)).Should(Equal(codes.OK))
+				Expect(exportRes.ContentType).Should(Equal("application/json"))
+
+				export := &userDataExport{}
+				err = json.Unmarshal(exportRes.Data, export)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(export.Profile.PhoneNumber).Should(Equal(userPhone))
+				Expect(export.Locations).Should(HaveLen(1))
+			})
+		})
+
+		Describe("Exporting the data as zip", func() {
+			It("should succeed", func() {
+				exportReq.PhoneNumber = userPhone
+				exportReq.Format = location.ExportFormat_ZIP
+				exportRes, err := LocationAPI.ExportMyData(ctx, exportReq)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(status.Code(err)).Should(Equal(codes.OK))
+				Expect(exportRes.ContentType).Should(Equal("application/zip"))
+
+				zr, err := zip.NewReader(bytes.NewReader(exportRes.Data), int64(len(exportRes.Data)))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(zr.File).Should(HaveLen(5))
+			})
+		})
+	})
+})
@@ -0,0 +1,279 @@
+package geocoding
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services/location/geofence"
+)
+
+// Administrative levels resolved by the geocoder
+const (
+	County       = "county"
+	Constituency = "constituency"
+	Ward         = "ward"
+)
+
+// Hours in which users are assumed to be at home
+const (
+	homeHoursStart = "22:00"
+	homeHoursEnd   = "05:00"
+)
+
+// Units are the administrative units containing a location, empty when it is in none
+type Units struct {
+	County       string
+	Constituency string
+	Ward         string
+}
+
+// Key returns the units as a single string, the inverse of ParseKey
+func (units *Units) Key() string {
+	return strings.Join([]string{units.County, units.Constituency, units.Ward}, "|")
+}
+
+// ParseKey returns the units of a key created with Key
+func ParseKey(key string) *Units {
+	parts := append(strings.SplitN(key, "|", 3), "", "")
+	return &Units{County: parts[0], Constituency: parts[1], Ward: parts[2]}
+}
+
+// Options contains parameters for New
+type Options struct {
+	// Files with GeoJSON feature collections of the boundaries of each level, levels without a file are not resolved
+	CountyFile       string
+	ConstituencyFile string
+	WardFile         string
+	// NameProperties are the feature properties tried in order for the name of a unit, defaults to name and NAME
+	NameProperties []string
+	// TimeZone is the time zone of the area covered by the boundaries, defaults to UTC
+	TimeZone *time.Location
+}
+
+// Geocoder resolves coordinates to the administrative units containing them, without calling external services
+type Geocoder struct {
+	counties       []*unit
+	constituencies []*unit
+	wards          []*unit
+	home           *geofence.Hours
+}
+
+// New loads administrative boundaries
+func New(opt *Options) (*Geocoder, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	nameProperties := opt.NameProperties
+	if len(nameProperties) == 0 {
+		nameProperties = []string{"name", "NAME"}
+	}
+
+	var err error
+	g := &Geocoder{}
+
+	for _, level := range []struct {
+		name  string
+		file  string
+		units *[]*unit
+	}{
+		{County, opt.CountyFile, &g.counties},
+		{Constituency, opt.ConstituencyFile, &g.constituencies},
+		{Ward, opt.WardFile, &g.wards},
+	} {
+		if level.file == "" {
+			continue
+		}
+		*level.units, err = loadUnits(level.file, nameProperties)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s boundaries: %v", level.name, err)
+		}
+	}
+
+	g.home, err = geofence.NewHours(homeHoursStart, homeHoursEnd, opt.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// NewFromEnv loads the boundaries in BOUNDARIES_COUNTY_FILE, BOUNDARIES_CONSTITUENCY_FILE and BOUNDARIES_WARD_FILE.
+//
+// BOUNDARIES_NAME_PROPERTIES is a comma separated list of the properties holding unit names and
+// BOUNDARIES_TIME_ZONE the time zone of the area covered. Without files no location is resolved.
+func NewFromEnv() (*Geocoder, error) {
+	opt := &Options{
+		CountyFile:       strings.TrimSpace(os.Getenv("BOUNDARIES_COUNTY_FILE")),
+		ConstituencyFile: strings.TrimSpace(os.Getenv("BOUNDARIES_CONSTITUENCY_FILE")),
+		WardFile:         strings.TrimSpace(os.Getenv("BOUNDARIES_WARD_FILE")),
+	}
+
+	if properties := strings.TrimSpace(os.Getenv("BOUNDARIES_NAME_PROPERTIES")); properties != "" {
+		for _, property := range strings.Split(properties, ",") {
+			opt.NameProperties = append(opt.NameProperties, strings.TrimSpace(property))
+		}
+	}
+
+	timeZone, err := time.LoadLocation(strings.TrimSpace(os.Getenv("BOUNDARIES_TIME_ZONE")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load boundaries time zone: %v", err)
+	}
+	opt.TimeZone = timeZone
+
+	return New(opt)
+}
+
+// Resolve returns the administrative units containing the coordinates
+func (g *Geocoder) Resolve(latitude, longitude float64) *Units {
+	return &Units{
+		County:       find(g.counties, latitude, longitude),
+		Constituency: find(g.constituencies, latitude, longitude),
+		Ward:         find(g.wards, latitude, longitude),
+	}
+}
+
+// AtHome reports whether t is at night in the area of the boundaries, when users are assumed to be at home
+func (g *Geocoder) AtHome(t time.Time) bool {
+	return g.home.Contains(t)
+}
+
+func find(units []*unit, latitude, longitude float64) string {
+	for _, u := range units {
+		if u.contains(latitude, longitude) {
+			return u.name
+		}
+	}
+	return ""
+}
+
+// ring is a closed line of [longitude, latitude] positions, as in GeoJSON
+type ring [][2]float64
+
+func (r ring) contains(latitude, longitude float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		longI, latI := r[i][0], r[i][1]
+		longJ, latJ := r[j][0], r[j][1]
+		if (latI > latitude) != (latJ > latitude) &&
+			longitude < (longJ-longI)*(latitude-latI)/(latJ-latI)+longI {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// polygon is an outer ring with holes
+type polygon []ring
+
+func (p polygon) contains(latitude, longitude float64) bool {
+	if len(p) == 0 || !p[0].contains(latitude, longitude) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.contains(latitude, longitude) {
+			return false
+		}
+	}
+	return true
+}
+
+// unit is an administrative unit made of one or more polygons
+type unit struct {
+	name                             string
+	polygons                         []polygon
+	minLat, maxLat, minLong, maxLong float64
+}
+
+func (u *unit) contains(latitude, longitude float64) bool {
+	if latitude < u.minLat || latitude > u.maxLat || longitude < u.minLong || longitude > u.maxLong {
+		return false
+	}
+	for _, p := range u.polygons {
+		if p.contains(latitude, longitude) {
+			return true
+		}
+	}
+	return false
+}
+
+type featureCollection struct {
+	Features []struct {
+		Properties map[string]interface{} `json:"properties"`
+		Geometry   *struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+func loadUnits(file string, nameProperties []string) ([]*unit, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &featureCollection{}
+	err = json.Unmarshal(data, collection)
+	if err != nil {
+		return nil, fmt.Errorf("malformed feature collection: %v", err)
+	}
+
+	units := make([]*unit, 0, len(collection.Features))
+
+	for i, feature := range collection.Features {
+		u := &unit{
+			minLat:  math.Inf(1),
+			maxLat:  math.Inf(-1),
+			minLong: math.Inf(1),
+			maxLong: math.Inf(-1),
+		}
+
+		for _, property := range nameProperties {
+			if name, ok := feature.Properties[property].(string); ok && strings.TrimSpace(name) != "" {
+				u.name = strings.TrimSpace(name)
+				break
+			}
+		}
+		if u.name == "" {
+			return nil, fmt.Errorf("feature %d has no name in properties %v", i+1, nameProperties)
+		}
+
+		if feature.Geometry == nil {
+			continue
+		}
+
+		switch feature.Geometry.Type {
+		case "Polygon":
+			p := polygon{}
+			err = json.Unmarshal(feature.Geometry.Coordinates, &p)
+			u.polygons = []polygon{p}
+		case "MultiPolygon":
+			err = json.Unmarshal(feature.Geometry.Coordinates, &u.polygons)
+		default:
+			err = fmt.Errorf("unsupported geometry %q", feature.Geometry.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("feature %s: %v", u.name, err)
+		}
+
+		for _, p := range u.polygons {
+			if len(p) == 0 {
+				continue
+			}
+			for _, position := range p[0] {
+				u.minLong, u.maxLong = math.Min(u.minLong, position[0]), math.Max(u.maxLong, position[0])
+				u.minLat, u.maxLat = math.Min(u.minLat, position[1]), math.Max(u.maxLat, position[1])
+			}
+		}
+
+		units = append(units, u)
+	}
+
+	return units, nil
+}
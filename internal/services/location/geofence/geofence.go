@@ -0,0 +1,179 @@
+package geofence
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+)
+
+const (
+	// DefaultCooldown is the minimum time between messages of a rule to a user
+	DefaultCooldown = time.Hour
+	// Messages are stored with the limits of the messages table
+	maxTitleLength   = 30
+	maxMessageLength = 256
+)
+
+// MessageData is passed to message templates of rules
+type MessageData struct {
+	Name         string
+	County       string
+	Category     string
+	Placemark    string
+	Time         string
+	DwellMinutes int
+}
+
+// Rule is a validated geo fence rule
+type Rule struct {
+	*location.GeoFenceRule
+	curfew   *Hours
+	template *template.Template
+}
+
+// Cooldown returns the minimum time between messages of the rule to a user
+func (rule *Rule) Cooldown() time.Duration {
+	if rule.CooldownMinutes == 0 {
+		return DefaultCooldown
+	}
+	return time.Duration(rule.CooldownMinutes) * time.Minute
+}
+
+// Fence is a validated geo fence ready to be matched against locations
+type Fence struct {
+	*location.GeoFence
+	Rules    []*Rule
+	area     *Polygon
+	timeZone *time.Location
+}
+
+// New validates a geo fence
+func New(fencePB *location.GeoFence) (*Fence, error) {
+	var err error
+	switch {
+	case fencePB == nil:
+		err = errors.New("missing geo fence")
+	case strings.TrimSpace(fencePB.Name) == "":
+		err = errors.New("missing geo fence name")
+	case len(fencePB.Rules) == 0:
+		err = errors.New("geo fence must have at least one rule")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fence := &Fence{
+		GeoFence: fencePB,
+		Rules:    make([]*Rule, 0, len(fencePB.Rules)),
+	}
+
+	fence.area, err = NewPolygon(fencePB.Polygon)
+	if err != nil {
+		return nil, fmt.Errorf("geo fence %v", err)
+	}
+
+	fence.timeZone, err = time.LoadLocation(fencePB.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown geo fence time zone %q", fencePB.TimeZone)
+	}
+
+	for i, rulePB := range fencePB.Rules {
+		rule, err := newRule(rulePB, fence.timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i+1, err)
+		}
+		fence.Rules = append(fence.Rules, rule)
+	}
+
+	return fence, nil
+}
+
+func newRule(rulePB *location.GeoFenceRule, timeZone *time.Location) (*Rule, error) {
+	var err error
+	switch {
+	case rulePB == nil:
+		err = errors.New("missing rule")
+	case strings.TrimSpace(rulePB.MessageTemplate) == "":
+		err = errors.New("missing message template")
+	case len(rulePB.Title) > maxTitleLength:
+		err = fmt.Errorf("title must be at most %d characters", maxTitleLength)
+	case rulePB.CooldownMinutes < 0:
+		err = errors.New("cooldown minutes must not be negative")
+	case rulePB.Trigger == location.GeoFenceTrigger_ON_DWELL && rulePB.DwellMinutes <= 0:
+		err = errors.New("dwell rules must have positive dwell minutes")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &Rule{GeoFenceRule: rulePB}
+
+	if rulePB.Trigger == location.GeoFenceTrigger_DURING_CURFEW {
+		rule.curfew, err = NewHours(rulePB.CurfewStart, rulePB.CurfewEnd, timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("malformed curfew: %v", err)
+		}
+	}
+
+	rule.template, err = template.New("message").Option("missingkey=error").Parse(rulePB.MessageTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("malformed message template: %v", err)
+	}
+
+	// Templates that use unknown fields fail now rather than when users are in the geo fence
+	err = rule.template.Execute(&bytes.Buffer{}, &MessageData{})
+	if err != nil {
+		return nil, fmt.Errorf("malformed message template: %v", err)
+	}
+
+	return rule, nil
+}
+
+// Contains reports whether the coordinates are inside the geo fence polygon
+func (fence *Fence) Contains(latitude, longitude float64) bool {
+	return fence.area.Contains(latitude, longitude)
+}
+
+// InCurfew reports whether t is within the curfew hours of the rule in the time zone of the geo fence
+func (fence *Fence) InCurfew(rule *Rule, t time.Time) bool {
+	if rule.curfew == nil {
+		return false
+	}
+	return rule.curfew.Contains(t)
+}
+
+// Message returns the title and message of the rule for a user at the location
+func (fence *Fence) Message(rule *Rule, locationPB *location.Location, dwell time.Duration) (string, string, error) {
+	message := &bytes.Buffer{}
+	err := rule.template.Execute(message, &MessageData{
+		Name:         fence.Name,
+		County:       fence.County,
+		Category:     fence.Category,
+		Placemark:    locationPB.Placemark,
+		Time:         time.Unix(locationPB.Timestamp, 0).In(fence.timeZone).Format("15:04"),
+		DwellMinutes: int(dwell / time.Minute),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	title := rule.Title
+	if title == "" {
+		title = truncate(fence.Name, maxTitleLength)
+	}
+
+	return title, truncate(message.String(), maxMessageLength), nil
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
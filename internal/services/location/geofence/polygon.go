@@ -0,0 +1,119 @@
+package geofence
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+)
+
+const maxPolygonPoints = 1000
+
+// Polygon is a validated area on the map
+type Polygon struct {
+	points                           []*location.GeoPoint
+	minLat, maxLat, minLong, maxLong float64
+}
+
+// NewPolygon validates the points of a polygon
+func NewPolygon(points []*location.GeoPoint) (*Polygon, error) {
+	switch {
+	case len(points) < 3:
+		return nil, errors.New("polygon must have at least 3 points")
+	case len(points) > maxPolygonPoints:
+		return nil, fmt.Errorf("polygon must have at most %d points", maxPolygonPoints)
+	}
+
+	polygon := &Polygon{
+		points:  points,
+		minLat:  math.Inf(1),
+		maxLat:  math.Inf(-1),
+		minLong: math.Inf(1),
+		maxLong: math.Inf(-1),
+	}
+
+	for _, point := range points {
+		lat, long := float64(point.GetLatitude()), float64(point.GetLongitude())
+		if point == nil || math.Abs(lat) > 90 || math.Abs(long) > 180 {
+			return nil, errors.New("polygon has points out of range")
+		}
+		polygon.minLat, polygon.maxLat = math.Min(polygon.minLat, lat), math.Max(polygon.maxLat, lat)
+		polygon.minLong, polygon.maxLong = math.Min(polygon.minLong, long), math.Max(polygon.maxLong, long)
+	}
+
+	return polygon, nil
+}
+
+// Contains reports whether the coordinates are inside the polygon
+func (polygon *Polygon) Contains(latitude, longitude float64) bool {
+	if latitude < polygon.minLat || latitude > polygon.maxLat ||
+		longitude < polygon.minLong || longitude > polygon.maxLong {
+		return false
+	}
+
+	// Ray casting, counts crossings of the polygon edges by a ray from the point
+	inside := false
+	points := polygon.points
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		latI, longI := float64(points[i].Latitude), float64(points[i].Longitude)
+		latJ, longJ := float64(points[j].Latitude), float64(points[j].Longitude)
+		if (latI > latitude) != (latJ > latitude) &&
+			longitude < (longJ-longI)*(latitude-latI)/(latJ-latI)+longI {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// Hours are the same hours of every day in a time zone, such as a curfew
+type Hours struct {
+	// start and end in minutes from midnight
+	start, end int
+	timeZone   *time.Location
+}
+
+// NewHours creates hours from a start and end in HH:MM. Hours that end before they start end on the next day
+func NewHours(start, end string, timeZone *time.Location) (*Hours, error) {
+	var err error
+	hours := &Hours{timeZone: timeZone}
+
+	hours.start, err = parseClock(start)
+	if err != nil {
+		return nil, fmt.Errorf("malformed start: %v", err)
+	}
+	hours.end, err = parseClock(end)
+	if err != nil {
+		return nil, fmt.Errorf("malformed end: %v", err)
+	}
+
+	if hours.timeZone == nil {
+		hours.timeZone = time.UTC
+	}
+
+	return hours, nil
+}
+
+// parseClock returns minutes from midnight of a time in HH:MM
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether t is within the hours
+func (hours *Hours) Contains(t time.Time) bool {
+	local := t.In(hours.timeZone)
+	minute := local.Hour()*60 + local.Minute()
+
+	if hours.start <= hours.end {
+		return minute >= hours.start && minute < hours.end
+	}
+
+	// Hours end on the next day
+	return minute >= hours.start || minute < hours.end
+}
@@ -0,0 +1,627 @@
+package location
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/auth"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/location/geofence"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+	"github.com/go-redis/redis"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// Geo fences changed through other replicas are seen after this interval
+	geoFencesRefreshInterval = time.Minute
+	geoFenceStateExpiration  = 24 * time.Hour
+	// maxGeoFenceAlerts is the number of geo fence messages a user may receive in geoFenceAlertsWindow
+	maxGeoFenceAlerts    = 3
+	geoFenceAlertsWindow = time.Hour
+	// maxVisitorsPageSize is the most users listed in a page of geo fence visitors
+	maxVisitorsPageSize = 1000
+)
+
+// countGeoFenceAlertScript counts a geo fence message of a user, starting the window with the first message
+// in the same step so that the count never outlives its window
+var countGeoFenceAlertScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// geoFenceCache holds the active geo fences evaluated on every location
+type geoFenceCache struct {
+	mu       sync.RWMutex
+	fences   []*geofence.Fence
+	loadedAt time.Time
+}
+
+func getGeoFenceDB(fencePB *location.GeoFence) (*services.GeoFenceModel, error) {
+	polygon, err := json.Marshal(fencePB.Polygon)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json marshal polygon: %v", err)
+	}
+
+	rules, err := json.Marshal(fencePB.Rules)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json marshal rules: %v", err)
+	}
+
+	return &services.GeoFenceModel{
+		Name:     fencePB.Name,
+		County:   fencePB.County,
+		Category: fencePB.Category,
+		TimeZone: fencePB.TimeZone,
+		Polygon:  polygon,
+		Rules:    rules,
+		Active:   fencePB.Active,
+	}, nil
+}
+
+func getGeoFencePB(fenceDB *services.GeoFenceModel) (*location.GeoFence, error) {
+	fencePB := &location.GeoFence{
+		FenceId:   fmt.Sprint(fenceDB.ID),
+		Name:      fenceDB.Name,
+		County:    fenceDB.County,
+		Category:  fenceDB.Category,
+		TimeZone:  fenceDB.TimeZone,
+		Active:    fenceDB.Active,
+		CreatedAt: fenceDB.CreatedAt.Unix(),
+		UpdatedAt: fenceDB.UpdatedAt.Unix(),
+	}
+
+	err := json.Unmarshal(fenceDB.Polygon, &fencePB.Polygon)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json unmarshal polygon: %v", err)
+	}
+
+	err = json.Unmarshal(fenceDB.Rules, &fencePB.Rules)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json unmarshal rules: %v", err)
+	}
+
+	return fencePB, nil
+}
+
+// activeGeoFences returns the active geo fences, reloading them when they are stale
+func (lapi *locationAPIServer) activeGeoFences() ([]*geofence.Fence, error) {
+	lapi.geoFences.mu.RLock()
+	fences, loadedAt := lapi.geoFences.fences, lapi.geoFences.loadedAt
+	lapi.geoFences.mu.RUnlock()
+
+	if time.Since(loadedAt) < geoFencesRefreshInterval {
+		return fences, nil
+	}
+
+	lapi.geoFences.mu.Lock()
+	defer lapi.geoFences.mu.Unlock()
+
+	// Loaded by another request while waiting for the lock
+	if time.Since(lapi.geoFences.loadedAt) < geoFencesRefreshInterval {
+		return lapi.geoFences.fences, nil
+	}
+
+	fencesDB := make([]*services.GeoFenceModel, 0)
+	err := lapi.logsDB.Find(&fencesDB, "active=?", true).Error
+	if err != nil {
+		return nil, err
+	}
+
+	fences = make([]*geofence.Fence, 0, len(fencesDB))
+	for _, fenceDB := range fencesDB {
+		fencePB, err := getGeoFencePB(fenceDB)
+		if err != nil {
+			return nil, err
+		}
+		fence, err := geofence.New(fencePB)
+		if err != nil {
+			lapi.logger.Errorf("skipping invalid geo fence %s: %v", fencePB.FenceId, err)
+			continue
+		}
+		fences = append(fences, fence)
+	}
+
+	lapi.geoFences.fences, lapi.geoFences.loadedAt = fences, time.Now()
+
+	return fences, nil
+}
+
+// invalidateGeoFences reloads geo fences on the next location
+func (lapi *locationAPIServer) invalidateGeoFences() {
+	lapi.geoFences.mu.Lock()
+	lapi.geoFences.loadedAt = time.Time{}
+	lapi.geoFences.mu.Unlock()
+}
+
+func validateGeoFence(fencePB *location.GeoFence) error {
+	_, err := geofence.New(fencePB)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid geo fence: %v", err)
+	}
+	return nil
+}
+
+func (lapi *locationAPIServer) CreateGeoFence(
+	ctx context.Context, createReq *location.CreateGeoFenceRequest,
+) (*location.GeoFence, error) {
+	// Request must not be nil
+	if createReq == nil {
+		return nil, services.NilRequestError("CreateGeoFenceRequest")
+	}
+
+	// Only administrators manage geo fences
+	err := lapi.authorizeGroups(ctx, auth.AdminGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateGeoFence(createReq.GeoFence)
+	if err != nil {
+		return nil, err
+	}
+
+	fenceDB, err := getGeoFenceDB(createReq.GeoFence)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lapi.logsDB.Create(fenceDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create geo fence: %v", err)
+	}
+
+	lapi.invalidateGeoFences()
+
+	return getGeoFencePB(fenceDB)
+}
+
+func (lapi *locationAPIServer) findGeoFence(fenceID string) (*services.GeoFenceModel, error) {
+	id, err := strconv.ParseUint(fenceID, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed fence id %q", fenceID)
+	}
+
+	fenceDB := &services.GeoFenceModel{}
+	err = lapi.logsDB.First(fenceDB, "id=?", id).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "geo fence with id %s not found", fenceID)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get geo fence: %v", err)
+	}
+
+	return fenceDB, nil
+}
+
+func (lapi *locationAPIServer) UpdateGeoFence(
+	ctx context.Context, updateReq *location.UpdateGeoFenceRequest,
+) (*location.GeoFence, error) {
+	// Request must not be nil
+	if updateReq == nil {
+		return nil, services.NilRequestError("UpdateGeoFenceRequest")
+	}
+
+	// Only administrators manage geo fences
+	err := lapi.authorizeGroups(ctx, auth.AdminGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateReq.GetGeoFence().GetFenceId() == "" {
+		return nil, services.MissingFieldError("fence id")
+	}
+
+	err = validateGeoFence(updateReq.GeoFence)
+	if err != nil {
+		return nil, err
+	}
+
+	fenceDB, err := lapi.findGeoFence(updateReq.GeoFence.FenceId)
+	if err != nil {
+		return nil, err
+	}
+
+	updateDB, err := getGeoFenceDB(updateReq.GeoFence)
+	if err != nil {
+		return nil, err
+	}
+
+	// The geo fence is replaced, so zero values are saved too
+	fenceDB.Name, fenceDB.County, fenceDB.Category = updateDB.Name, updateDB.County, updateDB.Category
+	fenceDB.TimeZone, fenceDB.Polygon, fenceDB.Rules = updateDB.TimeZone, updateDB.Polygon, updateDB.Rules
+	fenceDB.Active = updateDB.Active
+
+	err = lapi.logsDB.Save(fenceDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update geo fence: %v", err)
+	}
+
+	lapi.invalidateGeoFences()
+
+	return getGeoFencePB(fenceDB)
+}
+
+func (lapi *locationAPIServer) DeleteGeoFence(
+	ctx context.Context, delReq *location.DeleteGeoFenceRequest,
+) (*empty.Empty, error) {
+	// Request must not be nil
+	if delReq == nil {
+		return nil, services.NilRequestError("DeleteGeoFenceRequest")
+	}
+
+	// Only administrators manage geo fences
+	err := lapi.authorizeGroups(ctx, auth.AdminGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	if delReq.FenceId == "" {
+		return nil, services.MissingFieldError("fence id")
+	}
+
+	fenceDB, err := lapi.findGeoFence(delReq.FenceId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lapi.logsDB.Delete(fenceDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete geo fence: %v", err)
+	}
+
+	lapi.invalidateGeoFences()
+
+	return &empty.Empty{}, nil
+}
+
+func (lapi *locationAPIServer) GetGeoFence(
+	ctx context.Context, getReq *location.GetGeoFenceRequest,
+) (*location.GeoFence, error) {
+	// Request must not be nil
+	if getReq == nil {
+		return nil, services.NilRequestError("GetGeoFenceRequest")
+	}
+
+	// Geo fences announce places such as lockdown zones and testing sites to users
+	err := lapi.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if getReq.FenceId == "" {
+		return nil, services.MissingFieldError("fence id")
+	}
+
+	fenceDB, err := lapi.findGeoFence(getReq.FenceId)
+	if err != nil {
+		return nil, err
+	}
+
+	return getGeoFencePB(fenceDB)
+}
+
+func (lapi *locationAPIServer) ListGeoFences(
+	ctx context.Context, listReq *location.ListGeoFencesRequest,
+) (*location.GeoFences, error) {
+	// Request must not be nil
+	if listReq == nil {
+		return nil, services.NilRequestError("ListGeoFencesRequest")
+	}
+
+	// Authenticate the request
+	err := lapi.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize page
+	pageToken, pageSize := normalizePageSize(listReq.PageToken, listReq.PageSize)
+
+	db := lapi.logsDB.Order("id ASC").Where("id>?", pageToken).Limit(pageSize)
+	if listReq.FilterCounty != "" {
+		db = db.Where("county=?", listReq.FilterCounty)
+	}
+	if listReq.FilterCategory != "" {
+		db = db.Where("category=?", listReq.FilterCategory)
+	}
+	if listReq.ActiveOnly {
+		db = db.Where("active=?", true)
+	}
+
+	fencesDB := make([]*services.GeoFenceModel, 0)
+	err = db.Find(&fencesDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get geo fences from db: %v", err)
+	}
+
+	fencesPB := make([]*location.GeoFence, 0, len(fencesDB))
+	for _, fenceDB := range fencesDB {
+		fencePB, err := getGeoFencePB(fenceDB)
+		if err != nil {
+			return nil, err
+		}
+		fencesPB = append(fencesPB, fencePB)
+		pageToken = int(fenceDB.ID)
+	}
+
+	return &location.GeoFences{
+		GeoFences:     fencesPB,
+		NextPageToken: int32(pageToken),
+	}, nil
+}
+
+func (lapi *locationAPIServer) ListGeoFenceVisitors(
+	ctx context.Context, listReq *location.ListGeoFenceVisitorsRequest,
+) (*location.GeoFenceVisitors, error) {
+	// Request must not be nil
+	if listReq == nil {
+		return nil, services.NilRequestError("ListGeoFenceVisitorsRequest")
+	}
+
+	// Only services may identify users seen in geo fences, on behalf of callers they authorized
+	err := lapi.authorizeGroups(ctx, auth.ServiceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validation
+	if len(listReq.GeoFenceIds) == 0 {
+		return nil, services.MissingFieldError("geo fence ids")
+	}
+
+	pageSize := int(listReq.PageSize)
+	if pageSize <= 0 || pageSize > maxVisitorsPageSize {
+		pageSize = maxVisitorsPageSize
+	}
+
+	// Visits are paged by the pseudonymous ids they are saved against
+	userIDs := make([]string, 0, pageSize)
+	err = lapi.logsDB.Model(&services.GeoFenceVisit{}).
+		Where("fence_id IN(?) AND last_seen_at >= ?", listReq.GeoFenceIds, time.Unix(listReq.SeenSinceTimestamp, 0)).
+		Where("user_id > ?", listReq.PageToken).Order("user_id").Limit(pageSize).
+		Pluck("DISTINCT user_id", &userIDs).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get geo fence visits: %v", err)
+	}
+
+	// Pseudonymous ids are mapped to users only by the pseudonyms database
+	phoneNumbers, err := lapi.pseudonyms.PhoneNumbers(userIDs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get geo fence visitors: %v", err)
+	}
+
+	visitorsPB := &location.GeoFenceVisitors{PhoneNumbers: phoneNumbers}
+	if len(userIDs) == pageSize {
+		visitorsPB.NextPageToken = userIDs[len(userIDs)-1]
+	}
+
+	return visitorsPB, nil
+}
+
+// getGeoFencesKey is the key of the hash of geo fences a user is in, with the time they entered
+func getGeoFencesKey(userID string) string {
+	return fmt.Sprintf("%s:geofences", userID)
+}
+
+// getGeoFenceRuleKey is set while a rule of a geo fence may not send another message to a user
+func getGeoFenceRuleKey(userID, fenceID string, rule int) string {
+	return fmt.Sprintf("%s:geofences:%s:%d", userID, fenceID, rule)
+}
+
+// getGeoFenceAlertsKey counts geo fence messages sent to a user
+func getGeoFenceAlertsKey(userID string) string {
+	return fmt.Sprintf("%s:geofences:alerts", userID)
+}
+
+type geoFenceAlert struct {
+	fence      *geofence.Fence
+	rule       int
+	locationPB *location.Location
+	dwell      time.Duration
+}
+
+// evaluateGeoFences matches time ordered locations of a user against the active geo fences and sends the messages of triggered rules
+func (lapi *locationAPIServer) evaluateGeoFences(
+	ctx context.Context, phoneNumber, userID string, locations []*location.Location,
+) error {
+	fences, err := lapi.activeGeoFences()
+	if err != nil {
+		return fmt.Errorf("failed to get geo fences: %v", err)
+	}
+
+	if len(fences) == 0 || len(locations) == 0 {
+		return nil
+	}
+
+	// Geo fences the user was in, with the time they entered
+	entered := make(map[string]int64)
+	state, err := lapi.eventsDB.HGetAll(ctx, getGeoFencesKey(userID)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to get user geo fences: %v", err)
+	}
+	for fenceID, value := range state {
+		entered[fenceID], _ = strconv.ParseInt(value, 10, 64)
+	}
+
+	var (
+		// The latest alert of every rule
+		alerts = make(map[string]*geoFenceAlert)
+		order  = make([]string, 0)
+		// When the user was last seen in geo fences
+		lastSeen = make(map[string]int64)
+	)
+
+	alert := func(fence *geofence.Fence, rule int, locationPB *location.Location, dwell time.Duration) {
+		key := fmt.Sprintf("%s:%d", fence.FenceId, rule)
+		if _, ok := alerts[key]; !ok {
+			order = append(order, key)
+		}
+		alerts[key] = &geoFenceAlert{fence: fence, rule: rule, locationPB: locationPB, dwell: dwell}
+	}
+
+	for _, locationPB := range locations {
+		inside := make(map[string]bool)
+
+		for _, fence := range fences {
+			if !fence.Contains(float64(locationPB.Latitude), float64(locationPB.Longitude)) {
+				continue
+			}
+			inside[fence.FenceId] = true
+			lastSeen[fence.FenceId] = locationPB.Timestamp
+
+			enteredAt, wasInside := entered[fence.FenceId]
+			if !wasInside {
+				enteredAt = locationPB.Timestamp
+				entered[fence.FenceId] = enteredAt
+			}
+			dwell := time.Duration(locationPB.Timestamp-enteredAt) * time.Second
+
+			for i, rule := range fence.Rules {
+				switch rule.Trigger {
+				case location.GeoFenceTrigger_ON_ENTER:
+					if !wasInside {
+						alert(fence, i, locationPB, dwell)
+					}
+				case location.GeoFenceTrigger_ON_DWELL:
+					if dwell >= time.Duration(rule.DwellMinutes)*time.Minute {
+						alert(fence, i, locationPB, dwell)
+					}
+				case location.GeoFenceTrigger_DURING_CURFEW:
+					if fence.InCurfew(rule, time.Unix(locationPB.Timestamp, 0)) {
+						alert(fence, i, locationPB, dwell)
+					}
+				}
+			}
+		}
+
+		// Leaving a geo fence
+		for fenceID := range entered {
+			if !inside[fenceID] {
+				delete(entered, fenceID)
+			}
+		}
+	}
+
+	pipeliner := lapi.eventsDB.TxPipeline()
+	pipeliner.Del(ctx, getGeoFencesKey(userID))
+	if len(entered) > 0 {
+		values := make([]interface{}, 0, 2*len(entered))
+		for fenceID, enteredAt := range entered {
+			values = append(values, fenceID, enteredAt)
+		}
+		pipeliner.HSet(ctx, getGeoFencesKey(userID), values...)
+		pipeliner.Expire(ctx, getGeoFencesKey(userID), geoFenceStateExpiration)
+	}
+	_, err = pipeliner.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to save user geo fences: %v", err)
+	}
+
+	err = lapi.saveGeoFenceVisits(userID, lastSeen)
+	if err != nil {
+		return fmt.Errorf("failed to save geo fence visits: %v", err)
+	}
+
+	for _, key := range order {
+		err = lapi.sendGeoFenceAlert(ctx, phoneNumber, userID, alerts[key])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveGeoFenceVisits saves when the user was last seen in geo fences, used to target broadcasts
+func (lapi *locationAPIServer) saveGeoFenceVisits(userID string, lastSeen map[string]int64) error {
+	if len(lastSeen) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(lastSeen))
+	values := make([]interface{}, 0, 3*len(lastSeen))
+	for fenceID, timestamp := range lastSeen {
+		placeholders = append(placeholders, "(?, ?, ?)")
+		values = append(values, userID, fenceID, time.Unix(timestamp, 0))
+	}
+
+	// Locations may arrive out of order
+	return lapi.logsDB.Exec(fmt.Sprintf(
+		"INSERT INTO %s (user_id, fence_id, last_seen_at) VALUES %s "+
+			"ON DUPLICATE KEY UPDATE last_seen_at = GREATEST(last_seen_at, VALUES(last_seen_at))",
+		services.GeoFenceVisitsTable, strings.Join(placeholders, ", "),
+	), values...).Error
+}
+
+// sendGeoFenceAlert sends the message of a rule unless the rule or the user is rate limited
+func (lapi *locationAPIServer) sendGeoFenceAlert(
+	ctx context.Context, phoneNumber, userID string, alert *geoFenceAlert,
+) error {
+	rule := alert.fence.Rules[alert.rule]
+
+	ok, err := lapi.eventsDB.SetNX(
+		ctx, getGeoFenceRuleKey(userID, alert.fence.FenceId, alert.rule), alert.locationPB.Timestamp, rule.Cooldown(),
+	).Result()
+	if err != nil {
+		return fmt.Errorf("failed to rate limit geo fence rule: %v", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	count, err := countGeoFenceAlertScript.Run(
+		ctx, lapi.eventsDB, []string{getGeoFenceAlertsKey(userID)}, geoFenceAlertsWindow.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to rate limit geo fence alerts: %v", err)
+	}
+	if count > maxGeoFenceAlerts {
+		return nil
+	}
+
+	title, message, err := alert.fence.Message(rule, alert.locationPB, alert.dwell)
+	if err != nil {
+		return fmt.Errorf("failed to create geo fence message: %v", err)
+	}
+
+	go lapi.sendGeoFenceMessage(phoneNumber, &messaging.Message{
+		UserPhone:    phoneNumber,
+		Title:        title,
+		Notification: message,
+		Timestamp:    time.Now().Unix(),
+		Type:         messaging.MessageType_ALERT,
+		Data: map[string]string{
+			"sender":       "location_api",
+			"geo_fence_id": alert.fence.FenceId,
+			"trigger":      rule.Trigger.String(),
+		},
+	})
+
+	return nil
+}
+
+func (lapi *locationAPIServer) sendGeoFenceMessage(phoneNumber string, messagePB *messaging.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := lapi.messagingClient.SendMessage(ctx, messagePB, grpc.WaitForReady(true))
+	if err != nil {
+		lapi.logger.Errorf("failed to send geo fence message to %s: %v", phoneNumber, err)
+	}
+}
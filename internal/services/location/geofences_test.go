@@ -0,0 +1,271 @@
+package location
+
+import (
+	"context"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+func fakeGeoFence() *location.GeoFence {
+	return &location.GeoFence{
+		Name:     "Gikomba Market",
+		County:   randomdata.State(randomdata.Large),
+		Category: "market",
+		Polygon: []*location.GeoPoint{
+			{Latitude: 50.000, Longitude: 10.000},
+			{Latitude: 50.000, Longitude: 10.010},
+			{Latitude: 50.010, Longitude: 10.010},
+			{Latitude: 50.010, Longitude: 10.000},
+		},
+		Rules: []*location.GeoFenceRule{
+			{
+				Trigger:         location.GeoFenceTrigger_ON_ENTER,
+				MessageTemplate: "You are entering {{.Name}}, wear a mask at all times",
+			},
+			{
+				Trigger:         location.GeoFenceTrigger_ON_DWELL,
+				DwellMinutes:    10,
+				Title:           "Crowded place",
+				MessageTemplate: "You have been in {{.Name}} for {{.DwellMinutes}} minutes",
+			},
+		},
+		Active: true,
+	}
+}
+
+var _ = Describe("Managing geo fences #geofences", func() {
+	var (
+		ctx      context.Context
+		fencePB  *location.GeoFence
+		createFn = func(fencePB *location.GeoFence) (*location.GeoFence, error) {
+			return LocationAPI.CreateGeoFence(context.Background(), &location.CreateGeoFenceRequest{GeoFence: fencePB})
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fencePB = fakeGeoFence()
+	})
+
+	Describe("Creating geo fences with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			createRes, err := LocationAPI.CreateGeoFence(ctx, nil)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when the polygon has less than 3 points", func() {
+			fencePB.Polygon = fencePB.Polygon[:2]
+			createRes, err := createFn(fencePB)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when a message template uses unknown fields", func() {
+			fencePB.Rules[0].MessageTemplate = "Welcome {{.FullName}}"
+			createRes, err := createFn(fencePB)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when curfew hours are malformed", func() {
+			fencePB.Rules[0].Trigger = location.GeoFenceTrigger_DURING_CURFEW
+			fencePB.Rules[0].CurfewStart = "7pm"
+			createRes, err := createFn(fencePB)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when the time zone is unknown", func() {
+			fencePB.TimeZone = "Africa/Atlantis"
+			createRes, err := createFn(fencePB)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+	})
+
+	Describe("Managing geo fences with well-formed requests", func() {
+		It("should create, get, list, update and delete a geo fence", func() {
+			createRes, err := createFn(fencePB)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(createRes.FenceId).ShouldNot(BeEmpty())
+			Expect(createRes.Polygon).Should(HaveLen(4))
+			Expect(createRes.Rules).Should(HaveLen(2))
+
+			getRes, err := LocationAPI.GetGeoFence(ctx, &location.GetGeoFenceRequest{FenceId: createRes.FenceId})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.Name).Should(Equal(fencePB.Name))
+			Expect(getRes.Rules[1].DwellMinutes).Should(BeEquivalentTo(10))
+
+			listRes, err := LocationAPI.ListGeoFences(ctx, &location.ListGeoFencesRequest{
+				FilterCounty: fencePB.County,
+				ActiveOnly:   true,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.GeoFences).ShouldNot(BeEmpty())
+			for _, geoFence := range listRes.GeoFences {
+				Expect(geoFence.County).Should(Equal(fencePB.County))
+			}
+
+			createRes.Active = false
+			createRes.Category = "lockdown_zone"
+			updateRes, err := LocationAPI.UpdateGeoFence(ctx, &location.UpdateGeoFenceRequest{GeoFence: createRes})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(updateRes.Active).Should(BeFalse())
+			Expect(updateRes.Category).Should(Equal("lockdown_zone"))
+
+			_, err = LocationAPI.DeleteGeoFence(ctx, &location.DeleteGeoFenceRequest{FenceId: createRes.FenceId})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			getRes, err = LocationAPI.GetGeoFence(ctx, &location.GetGeoFenceRequest{FenceId: createRes.FenceId})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail to update a geo fence that does not exist", func() {
+			fencePB.FenceId = "999999999"
+			updateRes, err := LocationAPI.UpdateGeoFence(ctx, &location.UpdateGeoFenceRequest{GeoFence: fencePB})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(updateRes).Should(BeNil())
+		})
+	})
+
+	Describe("Evaluating geo fences on incoming locations", func() {
+		var (
+			phoneNumber string
+			fenceID     string
+			start       int64
+		)
+
+		locationAt := func(lat, long float32, minutes int64) *location.Location {
+			locationPB := fakeLocation()
+			locationPB.Latitude, locationPB.Longitude = lat, long
+			locationPB.Timestamp = start + minutes*60
+			return locationPB
+		}
+
+		userID := func() string {
+			return LocationServer.pseudonyms.ID(phoneNumber, time.Now())
+		}
+
+		BeforeEach(func() {
+			phoneNumber = randomdata.PhoneNumber()
+			start = time.Now().Add(-time.Hour).Unix()
+
+			createRes, err := createFn(fencePB)
+			Expect(err).ShouldNot(HaveOccurred())
+			fenceID = createRes.FenceId
+
+			sendRes, err := LocationAPI.SendLocations(ctx, &location.SendLocationsRequest{
+				UserId:   phoneNumber,
+				StatusId: location.Status_NEGATIVE,
+				Locations: []*location.Location{
+					locationAt(50.005, 10.005, 0),
+					locationAt(50.006, 10.005, 15),
+				},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendRes).ShouldNot(BeNil())
+		})
+
+		AfterEach(func() {
+			_, err := LocationAPI.DeleteGeoFence(ctx, &location.DeleteGeoFenceRequest{FenceId: fenceID})
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should remember when the user entered the geo fence", func() {
+			entered, err := LocationServer.eventsDB.HGet(ctx, getGeoFencesKey(userID()), fenceID).Int64()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(entered).Should(Equal(start))
+		})
+
+		It("should save when the user was last seen in the geo fence", func() {
+			visitDB := &services.GeoFenceVisit{}
+			err := LocationServer.logsDB.First(visitDB, "user_id=? AND fence_id=?", userID(), fenceID).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(visitDB.LastSeenAt.Unix()).Should(Equal(start + 15*60))
+		})
+
+		It("should list the user among visitors of the geo fence", func() {
+			listRes, err := LocationAPI.ListGeoFenceVisitors(ctx, &location.ListGeoFenceVisitorsRequest{
+				GeoFenceIds:        []string{fenceID},
+				SeenSinceTimestamp: start,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.PhoneNumbers).Should(Equal([]string{phoneNumber}))
+
+			listRes, err = LocationAPI.ListGeoFenceVisitors(ctx, &location.ListGeoFenceVisitorsRequest{
+				GeoFenceIds:        []string{fenceID},
+				SeenSinceTimestamp: start + 30*60,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.PhoneNumbers).Should(BeEmpty())
+		})
+
+		It("should list visitors of the geo fence a page at a time", func() {
+			listRes, err := LocationAPI.ListGeoFenceVisitors(ctx, &location.ListGeoFenceVisitorsRequest{
+				GeoFenceIds:        []string{fenceID},
+				SeenSinceTimestamp: start,
+				PageSize:           1,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.PhoneNumbers).Should(Equal([]string{phoneNumber}))
+			Expect(listRes.NextPageToken).ShouldNot(BeEmpty())
+
+			listRes, err = LocationAPI.ListGeoFenceVisitors(ctx, &location.ListGeoFenceVisitorsRequest{
+				GeoFenceIds:        []string{fenceID},
+				SeenSinceTimestamp: start,
+				PageSize:           1,
+				PageToken:          listRes.NextPageToken,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.PhoneNumbers).Should(BeEmpty())
+			Expect(listRes.NextPageToken).Should(BeEmpty())
+		})
+
+		It("should fail to list visitors when geo fences are missing", func() {
+			listRes, err := LocationAPI.ListGeoFenceVisitors(ctx, &location.ListGeoFenceVisitorsRequest{})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(listRes).Should(BeNil())
+		})
+
+		It("should rate limit the rules that were triggered", func() {
+			for rule := range fencePB.Rules {
+				exists, err := LocationServer.eventsDB.Exists(ctx, getGeoFenceRuleKey(userID(), fenceID, rule)).Result()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(exists).Should(BeEquivalentTo(1))
+			}
+
+			count, err := LocationServer.eventsDB.Get(ctx, getGeoFenceAlertsKey(userID())).Int()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(count).Should(Equal(2))
+
+			// The count expires with its window
+			ttl, err := LocationServer.eventsDB.PTTL(ctx, getGeoFenceAlertsKey(userID())).Result()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ttl).Should(BeNumerically(">", 0))
+			Expect(ttl).Should(BeNumerically("<=", geoFenceAlertsWindow))
+		})
+
+		It("should forget the geo fence when the user leaves", func() {
+			sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+				UserId:   phoneNumber,
+				StatusId: location.Status_NEGATIVE,
+				Location: locationAt(50.1, 10.005, 45),
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendRes).ShouldNot(BeNil())
+
+			exists, err := LocationServer.eventsDB.HExists(ctx, getGeoFencesKey(userID()), fenceID).Result()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(exists).Should(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,202 @@
+package location
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+var _ = Describe("Getting user trajectory #trajectory", func() {
+	var (
+		getReq *location.GetUserTrajectoryRequest
+		ctx    context.Context
+		start  int64
+	)
+
+	BeforeEach(func() {
+		start = time.Now().Add(-time.Hour).Unix()
+		getReq = &location.GetUserTrajectoryRequest{
+			PhoneNumber:    randomdata.PhoneNumber(),
+			StartTimestamp: start,
+		}
+		ctx = context.Background()
+	})
+
+	Describe("Getting trajectory with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			getReq = nil
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail when phone number is missing", func() {
+			getReq.PhoneNumber = ""
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail when start timestamp is missing", func() {
+			getReq.StartTimestamp = 0
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail when end timestamp is before start timestamp", func() {
+			getReq.EndTimestamp = start - 60
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail when date range is too long", func() {
+			getReq.StartTimestamp = time.Now().Add(-60 * 24 * time.Hour).Unix()
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail when the caller is not a health officer", func() {
+			authorizeGroups := LocationServer.authorizeGroups
+			defer func() {
+				LocationServer.authorizeGroups = authorizeGroups
+			}()
+
+			LocationServer.authorizeGroups = func(context.Context, ...string) error {
+				return status.Error(codes.PermissionDenied, "permission denied")
+			}
+
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.PermissionDenied))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail when the user does not exist", func() {
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(getRes).Should(BeNil())
+		})
+	})
+
+	Describe("Getting trajectory with well-formed request", func() {
+		var phoneNumber string
+
+		sendLocation := func(lat, long float32, timestamp int64, placemark string) {
+			locationPB := fakeLocation()
+			locationPB.Latitude = lat
+			locationPB.Longitude = long
+			locationPB.Timestamp = timestamp
+			locationPB.Placemark = placemark
+
+			sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+				UserId:   phoneNumber,
+				StatusId: location.Status_UNKNOWN,
+				Location: locationPB,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendRes).ShouldNot(BeNil())
+		}
+
+		BeforeEach(func() {
+			phoneNumber = addFakeUser(ctx)
+			getReq.PhoneNumber = phoneNumber
+
+			// Stays 30 minutes at the same place
+			for i := int64(0); i <= 6; i++ {
+				sendLocation(-1.2921, 36.8219, start+i*5*60, "Nairobi CBD")
+			}
+
+			// Moves along a straight line
+			for i := int64(1); i <= 5; i++ {
+				sendLocation(-1.2921+float32(i)*0.001, 36.8219, start+(30+i)*60, "")
+			}
+		})
+
+		It("should return the time ordered trajectory", func() {
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes).ShouldNot(BeNil())
+			Expect(getRes.OriginalPointsCount).Should(BeEquivalentTo(12))
+			Expect(getRes.Points).Should(HaveLen(12))
+			for i := 1; i < len(getRes.Points); i++ {
+				Expect(getRes.Points[i].Timestamp).Should(BeNumerically(">=", getRes.Points[i-1].Timestamp))
+			}
+			Expect(getRes.DistanceMeters).Should(BeNumerically("~", 556, 5))
+		})
+
+		It("should simplify the trajectory", func() {
+			getReq.SimplifyToleranceMeters = 10
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes).ShouldNot(BeNil())
+			Expect(getRes.OriginalPointsCount).Should(BeEquivalentTo(12))
+			Expect(len(getRes.Points)).Should(BeNumerically("<", 12))
+			Expect(getRes.Points[0].Timestamp).Should(Equal(start))
+		})
+
+		It("should detect dwell points", func() {
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.DwellPoints).Should(HaveLen(1))
+			dwellPoint := getRes.DwellPoints[0]
+			Expect(dwellPoint.Placemark).Should(Equal("Nairobi CBD"))
+			Expect(dwellPoint.DurationMinutes).Should(BeEquivalentTo(30))
+			Expect(dwellPoint.PointsCount).Should(BeEquivalentTo(7))
+			Expect(dwellPoint.ArrivalTimestamp).Should(Equal(start))
+		})
+
+		It("should exclude locations outside the date range", func() {
+			getReq.EndTimestamp = start + 10*60
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.Points).Should(HaveLen(3))
+			Expect(getRes.DwellPoints).Should(BeEmpty())
+		})
+
+		It("should export the trajectory as geojson", func() {
+			getReq.ExportFormat = location.TrajectoryExportFormat_GEOJSON
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.ContentType).Should(Equal("application/geo+json"))
+
+			collection := struct {
+				Type     string `json:"type"`
+				Features []struct {
+					Geometry struct {
+						Type string `json:"type"`
+					} `json:"geometry"`
+				} `json:"features"`
+			}{}
+			err = json.Unmarshal(getRes.Data, &collection)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(collection.Type).Should(Equal("FeatureCollection"))
+			Expect(collection.Features).Should(HaveLen(2))
+			Expect(collection.Features[0].Geometry.Type).Should(Equal("LineString"))
+			Expect(collection.Features[1].Geometry.Type).Should(Equal("Point"))
+		})
+
+		It("should export the trajectory as gpx", func() {
+			getReq.ExportFormat = location.TrajectoryExportFormat_GPX
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.ContentType).Should(Equal("application/gpx+xml"))
+
+			doc := struct {
+				Waypoints []struct{} `xml:"wpt"`
+				Points    []struct{} `xml:"trk>trkseg>trkpt"`
+			}{}
+			err = xml.Unmarshal(getRes.Data, &doc)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(doc.Waypoints).Should(HaveLen(1))
+			Expect(doc.Points).Should(HaveLen(12))
+		})
+	})
+})
@@ -0,0 +1,89 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/encryption"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/location/geocoding"
+	"github.com/go-redis/redis"
+)
+
+// Nights are counted until the user sends no location at night for this long
+const homeExpiration = 7 * 24 * time.Hour
+
+// getHomeKey is the key of the hash counting the locations of a user at night by administrative units.
+// It is keyed by the blind index of the phone number, as pseudonymous ids change before nights could be compared.
+func getHomeKey(phoneNumber string) (string, error) {
+	index, err := encryption.BlindIndex(phoneNumber)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("home:%s", index), nil
+}
+
+// updateHome sets the administrative units of the user to those where they were most often at night
+func (lapi *locationAPIServer) updateHome(ctx context.Context, phoneNumber string) error {
+	homeKey, err := getHomeKey(phoneNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get home key: %v", err)
+	}
+
+	counts, err := lapi.eventsDB.HGetAll(ctx, homeKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to get home counts: %v", err)
+	}
+
+	var (
+		home string
+		max  int64
+	)
+	for key, value := range counts {
+		count, _ := strconv.ParseInt(value, 10, 64)
+		if count > max || (count == max && key < home) {
+			home, max = key, count
+		}
+	}
+	if home == "" {
+		return nil
+	}
+
+	units := geocoding.ParseKey(home)
+
+	// Users are only updated when their home changes
+	db := lapi.logsDB.Model(&services.UserModel{}).
+		Where(
+			"phone_number=? AND (county<>? OR constituency<>? OR ward<>? OR home_resolved_at IS NULL)",
+			phoneNumber, units.County, units.Constituency, units.Ward,
+		).
+		Updates(map[string]interface{}{
+			"county":           units.County,
+			"constituency":     units.Constituency,
+			"ward":             units.Ward,
+			"home_resolved_at": time.Now(),
+		})
+	if db.Error != nil {
+		return fmt.Errorf("failed to update user home: %v", db.Error)
+	}
+
+	// The county topic of the user may have changed
+	if db.RowsAffected > 0 {
+		go lapi.syncTopicSubscriptions(phoneNumber)
+	}
+
+	return nil
+}
+
+// updateDeclaredCounty saves the county given by the user, unless it has been resolved from their locations
+func (lapi *locationAPIServer) updateDeclaredCounty(phoneNumber, county string) error {
+	if county == "" {
+		return nil
+	}
+	return lapi.logsDB.Table(services.UsersTable).
+		Where("phone_number=? AND home_resolved_at IS NULL", phoneNumber).
+		Update("county", county).Error
+}
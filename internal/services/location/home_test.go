@@ -0,0 +1,146 @@
+package location
+
+import (
+	"context"
+	"github.com/gidyon/pandemic-api/internal/services/location/geocoding"
+	"github.com/gidyon/pandemic-api/internal/services/locationstore"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// writeBoundaries writes a GeoJSON feature collection with a single unit
+func writeBoundaries(name, geometry string) string {
+	file, err := ioutil.TempFile("", "boundaries-*.geojson")
+	Expect(err).ShouldNot(HaveOccurred())
+	defer file.Close()
+
+	_, err = file.WriteString(`{"type": "FeatureCollection", "features": [{"type": "Feature", ` +
+		`"properties": {"COUNTY_NAM": "` + name + `"}, "geometry": ` + geometry + `}]}`)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	return file.Name()
+}
+
+var _ = Describe("Resolving administrative units of locations #geocoding", func() {
+	var (
+		ctx      context.Context
+		geocoder *geocoding.Geocoder
+		files    []string
+		userPB   *location.User
+		// night is a time when users are at home in UTC
+		night = time.Now().UTC().Truncate(24 * time.Hour).Add(-time.Hour)
+	)
+
+	sendAt := func(lat, long float32, t time.Time) {
+		locationPB := fakeLocation()
+		locationPB.Latitude, locationPB.Longitude = lat, long
+		locationPB.Timestamp = t.Unix()
+		_, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+			UserId:   userPB.PhoneNumber,
+			StatusId: location.Status_NEGATIVE,
+			Location: locationPB,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+
+		files = []string{
+			// A county with a hole
+			writeBoundaries("Nairobi", `{"type": "MultiPolygon", "coordinates": [[`+
+				`[[-60, 60], [-59, 60], [-59, 61], [-60, 61], [-60, 60]], `+
+				`[[-59.6, 60.4], [-59.4, 60.4], [-59.4, 60.6], [-59.6, 60.6], [-59.6, 60.4]]]]}`),
+			writeBoundaries("Westlands", `{"type": "Polygon", "coordinates": `+
+				`[[[-60, 60], [-59.8, 60], [-59.8, 60.2], [-60, 60.2], [-60, 60]]]}`),
+		}
+		geocoder, err = geocoding.New(&geocoding.Options{
+			CountyFile:       files[0],
+			ConstituencyFile: files[1],
+			NameProperties:   []string{"COUNTY_NAM"},
+			TimeZone:         time.UTC,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		LocationServer.geocoder = geocoder
+
+		userPB = fakeUser()
+		_, err = LocationAPI.AddUser(ctx, &location.AddUserRequest{User: userPB})
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		var err error
+		LocationServer.geocoder, err = geocoding.New(nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		for _, file := range files {
+			os.Remove(file)
+		}
+	})
+
+	It("should resolve units of coordinates", func() {
+		units := geocoder.Resolve(60.1, -59.9)
+		Expect(units.County).Should(Equal("Nairobi"))
+		Expect(units.Constituency).Should(Equal("Westlands"))
+		Expect(units.Ward).Should(BeEmpty())
+
+		units = geocoder.Resolve(60.5, -59.5)
+		Expect(units.County).Should(BeEmpty())
+	})
+
+	It("should tag saved locations with their units", func() {
+		sendAt(60.1, -59.9, night)
+
+		locationsDB, err := LocationServer.locations.Find(ctx, &locationstore.Query{
+			UserIDs: []string{LocationServer.pseudonyms.ID(userPB.PhoneNumber, time.Now())},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(locationsDB).Should(HaveLen(1))
+		Expect(locationsDB[0].County).Should(Equal("Nairobi"))
+		Expect(locationsDB[0].Constituency).Should(Equal("Westlands"))
+	})
+
+	It("should set the home of users from where they are at night", func() {
+		sendAt(60.1, -59.9, night)
+
+		getRes, err := LocationAPI.GetUser(ctx, &location.GetUserRequest{PhoneNumber: userPB.PhoneNumber})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getRes.County).Should(Equal("Nairobi"))
+		Expect(getRes.Constituency).Should(Equal("Westlands"))
+
+		// The declared county does not replace the resolved one
+		userPB.County = "Mombasa"
+		_, err = LocationAPI.UpdateUser(ctx, &location.UpdateUserRequest{
+			PhoneNumber: userPB.PhoneNumber,
+			User:        userPB,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		getRes, err = LocationAPI.GetUser(ctx, &location.GetUserRequest{PhoneNumber: userPB.PhoneNumber})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getRes.County).Should(Equal("Nairobi"))
+	})
+
+	It("should set the home of users from where they were most nights", func() {
+		// Pseudonymous ids change every day, nights are still compared
+		sendAt(60.1, -59.9, night.Add(-48*time.Hour))
+		sendAt(60.1, -59.9, night.Add(-24*time.Hour))
+		sendAt(60.8, -59.2, night)
+
+		getRes, err := LocationAPI.GetUser(ctx, &location.GetUserRequest{PhoneNumber: userPB.PhoneNumber})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getRes.County).Should(Equal("Nairobi"))
+		Expect(getRes.Constituency).Should(Equal("Westlands"))
+	})
+
+	It("should not set the home of users from where they are in the day", func() {
+		sendAt(60.1, -59.9, night.Add(-10*time.Hour))
+
+		getRes, err := LocationAPI.GetUser(ctx, &location.GetUserRequest{PhoneNumber: userPB.PhoneNumber})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getRes.County).Should(Equal(userPB.County))
+		Expect(getRes.Constituency).Should(BeEmpty())
+	})
+})
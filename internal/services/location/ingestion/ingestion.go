@@ -0,0 +1,173 @@
+package ingestion
+
+import (
+	"math"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reasons for rejecting a location
+const (
+	ReasonPoorAccuracy      = "poor_accuracy"
+	ReasonTeleport          = "teleport"
+	ReasonDuplicate         = "duplicate"
+	ReasonMockLocation      = "mock_location"
+	ReasonFutureTimestamp   = "future_timestamp"
+	ReasonInvalid           = "invalid_location"
+	ReasonDuplicateSequence = "duplicate_sequence"
+)
+
+// Results of filtering a location
+const (
+	ResultAccepted      = "accepted"
+	ResultLowConfidence = "low_confidence"
+	ResultRejected      = "rejected"
+)
+
+var (
+	pointsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "location",
+		Subsystem: "ingestion",
+		Name:      "points_total",
+		Help:      "Number of locations filtered at ingestion by result",
+	}, []string{"result"})
+
+	rejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "location",
+		Subsystem: "ingestion",
+		Name:      "rejections_total",
+		Help:      "Number of locations rejected at ingestion by reason",
+	}, []string{"reason"})
+)
+
+func init() {
+	prometheus.MustRegister(pointsTotal, rejectionsTotal)
+}
+
+// Options contains thresholds used by the filter, zero values use the defaults
+type Options struct {
+	// MaxAccuracy is the accuracy radius in meters above which locations are rejected, defaults to 100
+	MaxAccuracy float64
+	// LowConfidenceAccuracy is the accuracy radius in meters above which locations are stored
+	// but not used for contact matching and alerts, defaults to 30
+	LowConfidenceAccuracy float64
+	// MaxSpeed is the speed in meters per second above which a jump from the previous location is a teleport, defaults to 70
+	MaxSpeed float64
+	// DuplicateDistance is the distance in meters within which a repeated location is a duplicate, defaults to 1
+	DuplicateDistance float64
+	// DuplicateInterval is the time within which a repeated location is a duplicate, defaults to 1 minute
+	DuplicateInterval time.Duration
+	// MaxClockSkew is how far in the future a location timestamp may be, defaults to 5 minutes
+	MaxClockSkew time.Duration
+}
+
+// Filter drops or down-weights locations that would produce false contacts
+type Filter struct {
+	opt *Options
+}
+
+// NewFilter creates a filter. A nil options uses the default thresholds
+func NewFilter(opt *Options) *Filter {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	o := *opt
+	if o.MaxAccuracy == 0 {
+		o.MaxAccuracy = 100
+	}
+	if o.LowConfidenceAccuracy == 0 {
+		o.LowConfidenceAccuracy = 30
+	}
+	if o.MaxSpeed == 0 {
+		o.MaxSpeed = 70
+	}
+	if o.DuplicateDistance == 0 {
+		o.DuplicateDistance = 1
+	}
+	if o.DuplicateInterval == 0 {
+		o.DuplicateInterval = time.Minute
+	}
+	if o.MaxClockSkew == 0 {
+		o.MaxClockSkew = 5 * time.Minute
+	}
+
+	return &Filter{opt: &o}
+}
+
+// Result is the outcome of filtering a location
+type Result struct {
+	// Accepted is whether the location should be stored
+	Accepted bool
+	// LowConfidence is whether an accepted location should be left out of contact matching and alerts
+	LowConfidence bool
+	// Reason is why the location was rejected
+	Reason string
+}
+
+// String returns the result label used in metrics
+func (res *Result) String() string {
+	switch {
+	case !res.Accepted:
+		return ResultRejected
+	case res.LowConfidence:
+		return ResultLowConfidence
+	default:
+		return ResultAccepted
+	}
+}
+
+func rejected(reason string) *Result {
+	return &Result{Reason: reason}
+}
+
+// Check filters a location against the previous accepted location of the user, which may be nil.
+// Accuracy of zero means the device did not report it and is not filtered.
+func (f *Filter) Check(previous, loc *location.Location, now time.Time) *Result {
+	accuracy := float64(loc.Accuracy)
+
+	switch {
+	case loc.IsMock:
+		return rejected(ReasonMockLocation)
+	case time.Unix(loc.Timestamp, 0).Sub(now) > f.opt.MaxClockSkew:
+		return rejected(ReasonFutureTimestamp)
+	case accuracy > f.opt.MaxAccuracy:
+		return rejected(ReasonPoorAccuracy)
+	}
+
+	if previous != nil {
+		var (
+			distance = conversion.Distance(
+				float64(previous.Latitude), float64(previous.Longitude), float64(loc.Latitude), float64(loc.Longitude),
+			)
+			elapsed = loc.Timestamp - previous.Timestamp
+		)
+
+		if elapsed == 0 || (distance <= f.opt.DuplicateDistance &&
+			elapsed > 0 && time.Duration(elapsed)*time.Second < f.opt.DuplicateInterval) {
+			return rejected(ReasonDuplicate)
+		}
+
+		// Movement that can be explained by the accuracy of both locations is not counted
+		moved := distance - accuracy - float64(previous.Accuracy)
+		if moved > 0 && moved/math.Abs(float64(elapsed)) > f.opt.MaxSpeed {
+			return rejected(ReasonTeleport)
+		}
+	}
+
+	return &Result{
+		Accepted:      true,
+		LowConfidence: accuracy > f.opt.LowConfidenceAccuracy,
+	}
+}
+
+// Record counts the result in metrics
+func Record(res *Result) {
+	pointsTotal.WithLabelValues(res.String()).Inc()
+	if !res.Accepted {
+		rejectionsTotal.WithLabelValues(res.Reason).Inc()
+	}
+}
@@ -0,0 +1,130 @@
+package location
+
+import (
+	"context"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+var _ = Describe("Filtering locations at ingestion #ingestion", func() {
+	var (
+		ctx         context.Context
+		phoneNumber string
+		locationPB  *location.Location
+	)
+
+	send := func(locationPB *location.Location) {
+		sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+			UserId:   phoneNumber,
+			StatusId: location.Status_NEGATIVE,
+			Location: locationPB,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(sendRes).ShouldNot(BeNil())
+	}
+
+	countLocations := func() int {
+		var count int
+		err := LocationServer.logsDB.Model(&services.LocationModel{}).
+			Where("user_id=?", LocationServer.pseudonyms.ID(phoneNumber, time.Now())).Count(&count).Error
+		Expect(err).ShouldNot(HaveOccurred())
+		return count
+	}
+
+	countContactPoints := func() int64 {
+		count, err := LocationServer.eventsDB.SCard(
+			ctx, getUserSetKey(LocationServer.pseudonyms.ID(phoneNumber, time.Now()), time.Now()),
+		).Result()
+		Expect(err).ShouldNot(HaveOccurred())
+		return count
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		phoneNumber = randomdata.PhoneNumber()
+		locationPB = fakeLocation()
+		locationPB.Latitude = -1.2921
+		locationPB.Longitude = 36.8219
+		locationPB.Accuracy = 5
+		locationPB.Timestamp = time.Now().Add(-time.Hour).Unix()
+	})
+
+	It("should fail when coordinates are out of range", func() {
+		locationPB.Latitude = 91
+		sendRes, err := LocationAPI.SendLocation(ctx, &location.SendLocationRequest{
+			UserId:   phoneNumber,
+			StatusId: location.Status_NEGATIVE,
+			Location: locationPB,
+		})
+		Expect(err).Should(HaveOccurred())
+		Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		Expect(sendRes).Should(BeNil())
+	})
+
+	It("should accept an accurate location", func() {
+		send(locationPB)
+		Expect(countLocations()).Should(Equal(1))
+		Expect(countContactPoints()).Should(BeEquivalentTo(1))
+	})
+
+	It("should drop mock locations", func() {
+		locationPB.IsMock = true
+		send(locationPB)
+		Expect(countLocations()).Should(BeZero())
+	})
+
+	It("should drop locations with poor accuracy", func() {
+		locationPB.Accuracy = 500
+		send(locationPB)
+		Expect(countLocations()).Should(BeZero())
+		Expect(countContactPoints()).Should(BeZero())
+	})
+
+	It("should drop locations in the future", func() {
+		locationPB.Timestamp = time.Now().Add(time.Hour).Unix()
+		send(locationPB)
+		Expect(countLocations()).Should(BeZero())
+	})
+
+	It("should keep low confidence locations out of contact matching", func() {
+		locationPB.Accuracy = 50
+		send(locationPB)
+		Expect(countLocations()).Should(Equal(1))
+		Expect(countContactPoints()).Should(BeZero())
+	})
+
+	It("should drop duplicate locations", func() {
+		send(locationPB)
+
+		duplicate := *locationPB
+		duplicate.Timestamp += 10
+		send(&duplicate)
+
+		Expect(countLocations()).Should(Equal(1))
+	})
+
+	It("should drop locations implying impossible speed", func() {
+		send(locationPB)
+
+		// Mombasa is about 440 km from Nairobi
+		teleport := *locationPB
+		teleport.Latitude = -4.0435
+		teleport.Longitude = 39.6682
+		teleport.Timestamp += 60
+		send(&teleport)
+
+		Expect(countLocations()).Should(Equal(1))
+
+		// Travelling a few hundred meters in a minute is possible
+		walk := *locationPB
+		walk.Latitude += 0.001
+		walk.Timestamp += 120
+		send(&walk)
+
+		Expect(countLocations()).Should(Equal(2))
+	})
+})
@@ -0,0 +1,118 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/auth"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/location/trajectory"
+	"github.com/gidyon/pandemic-api/internal/services/locationstore"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultDwellMinutes = 15
+	defaultDwellRadius  = 50 // meters
+	maxTrajectoryRange  = 31 * 24 * time.Hour
+)
+
+func (lapi *locationAPIServer) GetUserTrajectory(
+	ctx context.Context, getReq *location.GetUserTrajectoryRequest,
+) (*location.Trajectory, error) {
+	// Request must not be nil
+	if getReq == nil {
+		return nil, services.NilRequestError("GetUserTrajectoryRequest")
+	}
+
+	// Only health officers can view movement history of users
+	err := lapi.authorizeGroups(ctx, auth.HealthOfficerGroup, auth.AdminGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	if getReq.EndTimestamp == 0 {
+		getReq.EndTimestamp = time.Now().Unix()
+	}
+
+	// Validation
+	switch {
+	case getReq.PhoneNumber == "":
+		err = services.MissingFieldError("phone number")
+	case getReq.StartTimestamp == 0:
+		err = services.MissingFieldError("start timestamp")
+	case getReq.EndTimestamp < getReq.StartTimestamp:
+		err = status.Error(codes.InvalidArgument, "end timestamp must not be before start timestamp")
+	case time.Duration(getReq.EndTimestamp-getReq.StartTimestamp)*time.Second > maxTrajectoryRange:
+		err = status.Errorf(codes.InvalidArgument, "date range must not be longer than %v", maxTrajectoryRange)
+	case getReq.SimplifyToleranceMeters < 0:
+		err = status.Error(codes.InvalidArgument, "simplify tolerance must not be negative")
+	case getReq.DwellMinutes < 0:
+		err = status.Error(codes.InvalidArgument, "dwell minutes must not be negative")
+	case getReq.DwellRadiusMeters < 0:
+		err = status.Error(codes.InvalidArgument, "dwell radius must not be negative")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if getReq.DwellMinutes == 0 {
+		getReq.DwellMinutes = defaultDwellMinutes
+	}
+	if getReq.DwellRadiusMeters == 0 {
+		getReq.DwellRadiusMeters = defaultDwellRadius
+	}
+
+	// User must exist
+	err = lapi.logsDB.Select("id").First(&services.UserModel{}, "phone_number=?", getReq.PhoneNumber).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "user with phone number %s not found", getReq.PhoneNumber)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+
+	// Locations are stored against pseudonymous ids
+	userIDs, err := lapi.pseudonyms.IDs(getReq.PhoneNumber)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	locationsDB, err := lapi.locations.Find(ctx, &locationstore.Query{
+		UserIDs:        userIDs,
+		StartTimestamp: getReq.StartTimestamp,
+		EndTimestamp:   getReq.EndTimestamp,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user locations: %v", err)
+	}
+
+	points := make([]*location.Location, 0, len(locationsDB))
+	for _, locationDB := range locationsDB {
+		locationPB := services.GetLocationPB(locationDB)
+		locationPB.Timestamp = locationDB.Timestamp
+		points = append(points, locationPB)
+	}
+
+	trajectoryPB := &location.Trajectory{
+		PhoneNumber:         getReq.PhoneNumber,
+		StartTimestamp:      getReq.StartTimestamp,
+		EndTimestamp:        getReq.EndTimestamp,
+		DwellPoints:         trajectory.Dwells(points, float64(getReq.DwellRadiusMeters), time.Duration(getReq.DwellMinutes)*time.Minute),
+		Points:              trajectory.Simplify(points, float64(getReq.SimplifyToleranceMeters)),
+		OriginalPointsCount: int32(len(points)),
+		DistanceMeters:      float32(trajectory.Length(points)),
+	}
+
+	err = trajectory.Export(trajectoryPB, getReq.ExportFormat)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to export trajectory: %v", err)
+	}
+
+	return trajectoryPB, nil
+}
@@ -5,11 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gidyon/pandemic-api/internal/auth"
+	"github.com/gidyon/pandemic-api/internal/devicetoken"
+	"github.com/gidyon/pandemic-api/internal/encryption"
+	"github.com/gidyon/pandemic-api/internal/pseudonym"
 	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
+	"github.com/gidyon/pandemic-api/internal/services/location/geocoding"
+	"github.com/gidyon/pandemic-api/internal/services/location/ingestion"
+	"github.com/gidyon/pandemic-api/internal/services/location/throttle"
+	"github.com/gidyon/pandemic-api/internal/services/locationstore"
 	"github.com/gidyon/pandemic-api/pkg/api/messaging"
-	hashids "github.com/speps/go-hashids"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/grpclog"
+	"math"
+	"net/mail"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,19 +35,27 @@ import (
 
 const (
 	timeBoundary   = time.Duration(5 * time.Minute)
-	alertRadius    = 10  // 10 meters
 	socialDistance = 1.5 //meters
-	durationRange  = 5 * time.Minute
+	// Alerts and warnings of users close to cases share one cooldown and daily cap
+	proximityAlerts = "proximity"
 )
 
 type locationAPIServer struct {
 	logsDB          *gorm.DB
 	eventsDB        *redis.Client
 	logger          grpclog.LoggerV2
-	hasher          *hashids.HashID
+	pseudonyms      *pseudonym.Pseudonymizer
+	cells           conversion.CellSystem
+	timeBuckets     *conversion.TimeBuckets
+	filter          *ingestion.Filter
+	locations       locationstore.Store
+	geoFences       geoFenceCache
+	alerts          *throttle.Throttler
+	geocoder        *geocoding.Geocoder
 	messagingClient messaging.MessagingClient
 	realtimeAlerts  bool
 	authorize       func(context.Context, string) error
+	authorizeGroups func(context.Context, ...string) error
 	authenticate    func(context.Context) error
 }
 
@@ -48,11 +65,17 @@ type Options struct {
 	EventsDB        *redis.Client
 	Logger          grpclog.LoggerV2
 	MessagingClient messaging.MessagingClient
-	RealTimeAlerts  bool
-}
-
-func newHasher(salt string) (*hashids.HashID, error) {
-	return nil, nil
+	Pseudonymizer   *pseudonym.Pseudonymizer
+	CellSystem      conversion.CellSystem
+	TimeBuckets     *conversion.TimeBuckets
+	IngestionFilter *ingestion.Filter
+	// LocationStore keeps location history, defaults to the mysql store in LogsDB
+	LocationStore locationstore.Store
+	// AlertThrottler limits alerts sent to users close to cases, defaults to the default limits in EventsDB
+	AlertThrottler *throttle.Throttler
+	// Geocoder resolves the administrative units of locations, defaults to one without boundaries
+	Geocoder       *geocoding.Geocoder
+	RealTimeAlerts bool
 }
 
 // NewLocationTracing creates a new location tracing API
@@ -70,6 +93,8 @@ func NewLocationTracing(ctx context.Context, opt *Options) (location.LocationTra
 		err = errors.New("non-nil grpc logger is required")
 	case opt.MessagingClient == nil:
 		err = errors.New("non-nil messaging client is required")
+	case opt.Pseudonymizer == nil:
+		err = errors.New("non-nil pseudonymizer is required")
 	}
 	if err != nil {
 		return nil, err
@@ -80,23 +105,77 @@ func NewLocationTracing(ctx context.Context, opt *Options) (location.LocationTra
 		eventsDB:        opt.EventsDB,
 		logger:          opt.Logger,
 		messagingClient: opt.MessagingClient,
+		pseudonyms:      opt.Pseudonymizer,
+		cells:           opt.CellSystem,
+		timeBuckets:     opt.TimeBuckets,
+		filter:          opt.IngestionFilter,
+		locations:       opt.LocationStore,
+		alerts:          opt.AlertThrottler,
+		geocoder:        opt.Geocoder,
 		realtimeAlerts:  opt.RealTimeAlerts,
 		authenticate:    auth.AuthenticateRequest,
 		authorize:       auth.AuthenticateUser,
+		authorizeGroups: func(ctx context.Context, groups ...string) error {
+			_, err := auth.AuthenticateGroups(ctx, groups...)
+			return err
+		},
+	}
+
+	if lapi.cells == nil {
+		lapi.cells = conversion.DefaultCellSystem()
+	}
+	if lapi.timeBuckets == nil {
+		lapi.timeBuckets = conversion.DefaultTimeBuckets()
+	}
+	if lapi.filter == nil {
+		lapi.filter = ingestion.NewFilter(nil)
+	}
+	if lapi.alerts == nil {
+		lapi.alerts = throttle.New(lapi.eventsDB, nil)
+	}
+	if lapi.geocoder == nil {
+		lapi.geocoder, err = geocoding.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create geocoder: %v", err)
+		}
+	}
+	if lapi.locations == nil {
+		lapi.locations, err = locationstore.New(ctx, locationstore.MySQL, &locationstore.Options{SQLDB: lapi.logsDB})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create location store: %v", err)
+		}
 	}
 
 	// Automigration
-	err = lapi.logsDB.AutoMigrate(&services.LocationModel{}, &services.UserModel{}).Error
+	err = lapi.logsDB.AutoMigrate(
+		&services.UserModel{}, &services.StatusHistory{}, &services.Consent{}, &services.GeoFenceModel{},
+		&services.RestrictionModel{}, &services.UserDevice{}, &services.GeoFenceVisit{},
+		// Messages and their deliveries are erased with user accounts
+		&services.Message{}, &services.Delivery{}, &services.DeliveryAttempt{},
+	).Error
 	if err != nil {
 		return nil, err
 	}
 
+	err = lapi.locations.Migrate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate location store: %v", err)
+	}
+
+	go lapi.maintainLocations(ctx)
+
 	// Create a full text search index
 	err = services.CreateFullTextIndex(lapi.logsDB, services.UsersTable, "phone_number, full_name")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create full text index: %v", err)
 	}
 
+	// Withdrawn consents are checked in redis for every location
+	err = lapi.loadWithdrawnConsents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load withdrawn consents: %v", err)
+	}
+
 	return lapi, nil
 }
 
@@ -116,10 +195,10 @@ func validateLocation(locationPB *location.Location) error {
 		err = services.MissingFieldError("location longitude")
 	case locationPB.GetLatitude() == 0.0:
 		err = services.MissingFieldError("location latitude")
+	case math.Abs(float64(locationPB.GetLatitude())) > 90 || math.Abs(float64(locationPB.GetLongitude())) > 180:
+		err = status.Error(codes.InvalidArgument, "location coordinates are out of range")
 	case locationPB.Timestamp == 0.0:
 		err = services.MissingFieldError("location timestamp")
-	case locationPB.GeoFenceId == "":
-		err = services.MissingFieldError("location geo fence id")
 	}
 	if err != nil {
 		return err
@@ -127,104 +206,166 @@ func validateLocation(locationPB *location.Location) error {
 	return nil
 }
 
-func getUserSetKeyToday(userID string) string {
-	y, m, d := time.Now().Date()
+const locationsMaintenanceInterval = time.Hour
+
+// maintainLocations prepares location storage for the coming days and drops expired locations until ctx is done
+func (lapi *locationAPIServer) maintainLocations(ctx context.Context) {
+	ticker := time.NewTicker(locationsMaintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			err := lapi.locations.Maintain(ctx, now)
+			if err != nil {
+				lapi.logger.Errorf("failed to maintain location store: %v", err)
+			}
+		}
+	}
+}
+
+func getUserSetKey(userID string, t time.Time) string {
+	y, m, d := t.Date()
 	date := fmt.Sprintf("%d:%d:%d", y, m, d)
 	return fmt.Sprintf("%s:%s", userID, date)
 }
 
-func (lapi *locationAPIServer) validateAndSaveLocation(
-	ctx context.Context, sendReq *location.SendLocationRequest, notifyUser bool,
-) error {
-	locationPB := sendReq.GetLocation()
-	err := validateLocation(locationPB)
-	if err != nil {
-		return err
-	}
+// getLastLocationKey is the key of the last accepted location of a user.
+// It has the prefix of other user keys so that it is deleted together with them.
+func getLastLocationKey(userID string) string {
+	return fmt.Sprintf("%s:last", userID)
+}
 
-	// Update location on server
-	locationPB.TimeId = conversion.GetTimeID(locationPB, durationRange)
+const lastLocationExpiration = 24 * time.Hour
 
-	// Validate user id and status
+func (lapi *locationAPIServer) getLastLocation(ctx context.Context, userID string) (*location.Location, error) {
+	value, err := lapi.eventsDB.Get(ctx, getLastLocationKey(userID)).Result()
 	switch {
-	case sendReq.UserId == "":
-		return services.MissingFieldError("user id")
-	case sendReq.StatusId.String() == "":
-		return services.MissingFieldError("status id")
+	case err == nil:
+	case errors.Is(err, redis.Nil):
+		return nil, nil
+	default:
+		return nil, err
 	}
 
-	// save user log to redis
-	key := getUserSetKeyToday(sendReq.UserId)
-
-	// Add to set
-	_, err = lapi.eventsDB.SAdd(
-		ctx, key, fmt.Sprintf("%s:%s", locationPB.GetGeoFenceId(), locationPB.GetTimeId()),
-	).Result()
+	value, err = encryption.Decrypt(value)
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to add location to set: %v", err)
+		return nil, err
 	}
 
-	if sendReq.StatusId == location.Status_POSITIVE {
-		// Add to blacklist
-		err = lapi.eventsDB.SAdd(
-			ctx, getTimeKey(locationPB.GetTimeId()), getAlertGeoFenceID(locationPB),
-		).Err()
-		if err != nil {
-			return status.Errorf(codes.Internal, "failed to add location to set: %v", err)
-		}
-	} else {
-		if notifyUser {
-			go lapi.sendUserAlert(locationPB, sendReq.UserId)
-		}
+	// latitude,longitude,timestamp,accuracy
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("malformed last location")
 	}
 
-	// Save to database
-	locationDB := services.GetLocationDB(locationPB)
+	latitude, longitude, err := services.ParseCoordinates(parts[0] + "," + parts[1])
+	if err != nil {
+		return nil, err
+	}
 
-	locationDB.UserID = sendReq.UserId
+	timestamp, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed last location timestamp: %v", err)
+	}
 
-	// Add to database
-	err = lapi.logsDB.Create(locationDB).Error
+	accuracy, err := strconv.ParseFloat(parts[3], 32)
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to add location to db: %v", err)
+		return nil, fmt.Errorf("malformed last location accuracy: %v", err)
 	}
 
-	return nil
+	return &location.Location{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Timestamp: timestamp,
+		Accuracy:  float32(accuracy),
+	}, nil
+}
+
+func formatLastLocation(loc *location.Location) (string, error) {
+	return encryption.Encrypt(fmt.Sprintf(
+		"%s,%d,%f", services.FormatCoordinates(loc.Latitude, loc.Longitude), loc.Timestamp, loc.Accuracy,
+	))
 }
 
 func getTimeKey(timeID string) string {
 	return fmt.Sprintf("time:%s", timeID)
 }
 
-func getAlertGeoFenceID(loc *location.Location) string {
-	return conversion.GeoFenceID(loc, alertRadius)
+// throttleAlert returns the alert of a user that was close to cases, nil if the user should not be messaged now
+func (lapi *locationAPIServer) throttleAlert(ctx context.Context, userID string) *throttle.Alert {
+	alert, err := lapi.alerts.Throttle(ctx, userID, proximityAlerts, time.Now())
+	if err != nil {
+		lapi.logger.Errorf("failed to throttle user alert: %v", err)
+		return nil
+	}
+	if !alert.Send {
+		return nil
+	}
+	return alert
+}
+
+func alertData(alert *throttle.Alert) map[string]string {
+	return map[string]string{
+		"sender":       "location_api",
+		"collapse_key": alert.CollapseKey,
+		"alerts_count": strconv.Itoa(alert.Count),
+	}
 }
 
-func (lapi *locationAPIServer) sendUserAlert(loc *location.Location, phoneNumber string) {
+func (lapi *locationAPIServer) sendUserAlert(phoneNumber, userID string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	danger, err := lapi.eventsDB.SIsMember(
-		ctx, getTimeKey(loc.GetTimeId()), getAlertGeoFenceID(loc),
-	).Result()
+	alert := lapi.throttleAlert(ctx, userID)
+	if alert == nil {
+		return
+	}
+
+	// Send message to user
+	_, err := lapi.messagingClient.SendMessage(ctx, &messaging.Message{
+		UserPhone: phoneNumber,
+		Template:  services.TemplateAreaAlert,
+		TemplateVariables: map[string]string{
+			"Count": strconv.Itoa(alert.Count),
+			"Since": strconv.FormatInt(alert.Since.Unix(), 10),
+		},
+		Timestamp: time.Now().Unix(),
+		Type:      messaging.MessageType_ALERT,
+		Data:      alertData(alert),
+	}, grpc.WaitForReady(true))
 	if err != nil {
-		lapi.logger.Errorf("failed to get cases: %v", err)
+		lapi.logger.Errorf("failed to send user message on aerial covid-19 case: %v", err)
 		return
 	}
+}
 
-	if danger {
-		// Send message to user
-		_, err = lapi.messagingClient.SendMessage(ctx, &messaging.Message{
-			UserPhone:    phoneNumber,
-			Title:        "COVID-19 Social Distancing Alert",
-			Notification: "You are currently in an area with a COVID-19 cases. Ensure you maintain social distance",
-			Timestamp:    time.Now().Unix(),
-			Type:         messaging.MessageType_ALERT,
-		}, grpc.WaitForReady(true))
-		if err != nil {
-			lapi.logger.Errorf("failed to send user message on aerial covid-19 case: %v", err)
-			return
-		}
+func (lapi *locationAPIServer) sendUserWarning(phoneNumber, userID string, loc *location.Location) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	alert := lapi.throttleAlert(ctx, userID)
+	if alert == nil {
+		return
+	}
+
+	_, err := lapi.messagingClient.SendMessage(ctx, &messaging.Message{
+		UserPhone: phoneNumber,
+		Template:  services.TemplateExposureWarning,
+		TemplateVariables: map[string]string{
+			"Count":     strconv.Itoa(alert.Count),
+			"Since":     strconv.FormatInt(alert.Since.Unix(), 10),
+			"Placemark": loc.Placemark,
+			"Time":      strconv.FormatInt(loc.Timestamp, 10),
+		},
+		Timestamp: time.Now().Unix(),
+		Type:      messaging.MessageType_WARNING,
+		Data:      alertData(alert),
+	}, grpc.WaitForReady(true))
+	if err != nil {
+		lapi.logger.Errorf("failed to send user message on aerial covid-19 case: %v", err)
 	}
 }
 
@@ -242,17 +383,30 @@ func (lapi *locationAPIServer) SendLocation(
 		return nil, err
 	}
 
-	err = lapi.validateAndSaveLocation(ctx, sendReq, lapi.realtimeAlerts)
+	err = validateLocation(sendReq.GetLocation())
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := lapi.saveLocations(ctx, &location.SendLocationsRequest{
+		UserId:    sendReq.UserId,
+		StatusId:  sendReq.StatusId,
+		Locations: []*location.Location{sendReq.Location},
+	}, 0, lapi.realtimeAlerts)
 	if err != nil {
 		return nil, err
 	}
 
+	if saved.danger != nil {
+		go lapi.sendUserAlert(sendReq.UserId, saved.userID)
+	}
+
 	return &empty.Empty{}, nil
 }
 
 func (lapi *locationAPIServer) SendLocations(
 	ctx context.Context, sendReq *location.SendLocationsRequest,
-) (*empty.Empty, error) {
+) (*location.SendLocationsResponse, error) {
 	// Request must not be nil
 	if sendReq == nil {
 		return nil, services.NilRequestError("SendLocationsRequest")
@@ -264,79 +418,21 @@ func (lapi *locationAPIServer) SendLocations(
 		return nil, err
 	}
 
-	// Validation
-	switch {
-	case sendReq.GetStatusId().String() == "":
-		err = services.MissingFieldError("status")
-	case sendReq.GetUserId() == "":
-		err = services.MissingFieldError("user id")
-	}
+	// Validate and save locations
+	saved, err := lapi.saveLocations(ctx, sendReq, 0, lapi.realtimeAlerts)
 	if err != nil {
 		return nil, err
 	}
 
-	var (
-		shouldNotify    bool
-		approximateTime string
-		placeMark       string
-		maxTimestamp    int64
-	)
-	// Validate and save locations
-	for _, locationPB := range sendReq.Locations {
-		err = lapi.validateAndSaveLocation(ctx, &location.SendLocationRequest{
-			UserId:   sendReq.UserId,
-			StatusId: sendReq.StatusId,
-			Location: locationPB,
-		}, false)
-		if err != nil {
-			lapi.logger.Errorf("error while saving user locations: %v", err)
-			continue
-		}
-
-		// Check if there exist case
-		danger, err := lapi.eventsDB.SIsMember(
-			ctx, getTimeKey(locationPB.GetTimeId()), getAlertGeoFenceID(locationPB),
-		).Result()
-		if err != nil {
-			lapi.logger.Errorf("error checking for regional alerts: %v", err)
-			continue
-		}
-		if danger {
-			if locationPB.Timestamp > maxTimestamp {
-				maxTimestamp = locationPB.Timestamp
-				placeMark = locationPB.Placemark
-				appTime := time.Unix(locationPB.Timestamp, 0)
-				approximateTime = appTime.Format(time.RFC1123)
-			}
-			shouldNotify = true
-		}
-	}
-
-	if shouldNotify && lapi.realtimeAlerts {
-
+	if saved.danger != nil {
 		// Send user a notification
-		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-
-			_, err := lapi.messagingClient.SendMessage(ctx, &messaging.Message{
-				UserPhone: sendReq.UserId,
-				Title:     "COVID-19 Social Distancing Warning",
-				Notification: fmt.Sprintf(
-					"You were in an area (10m diameter) with confirmed COVID-19 cases near %s at around %s. Always ensure you maintain social distance",
-					placeMark, approximateTime,
-				),
-				Timestamp: time.Now().Unix(),
-				Type:      messaging.MessageType_WARNING,
-				Data:      map[string]string{"sender": "location_api"},
-			}, grpc.WaitForReady(true))
-			if err != nil {
-				lapi.logger.Errorf("failed to send user message on aerial covid-19 case: %v", err)
-			}
-		}()
+		go lapi.sendUserWarning(sendReq.UserId, saved.userID, saved.danger)
 	}
 
-	return &empty.Empty{}, nil
+	return &location.SendLocationsResponse{
+		DeviceId:             saved.deviceID,
+		AcknowledgedSequence: saved.acknowledgedSequence,
+	}, nil
 }
 
 const infectedUsers = "infected:users"
@@ -380,9 +476,20 @@ func (lapi *locationAPIServer) UpdateUserStatus(
 	err = tx.Table(services.UsersTable).Where("phone_number=?", updateReq.PhoneNumber).
 		Update("status", int8(updateReq.Status)).Error
 	if err != nil {
+		tx.Rollback()
 		return nil, status.Errorf(codes.Internal, "failed to update user status: %v", err)
 	}
 
+	// Keep history of status changes
+	err = tx.Create(&services.StatusHistory{
+		PhoneNumber: updateReq.PhoneNumber,
+		Status:      int8(updateReq.Status),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, status.Errorf(codes.Internal, "failed to save status history: %v", err)
+	}
+
 	// Add user to list of users with COVID-19
 	if updateReq.GetStatus() == location.Status_POSITIVE {
 		_, err = lapi.eventsDB.LPush(ctx, infectedUsers, updateReq.PhoneNumber).Result()
@@ -397,6 +504,8 @@ func (lapi *locationAPIServer) UpdateUserStatus(
 		return nil, services.FailedToCommitTx(err)
 	}
 
+	go lapi.syncTopicSubscriptions(updateReq.PhoneNumber)
+
 	return &empty.Empty{}, nil
 }
 
@@ -431,12 +540,24 @@ func (lapi *locationAPIServer) UpdateUser(
 	}
 
 	// Update status in database
-	err = lapi.logsDB.Table(services.UsersTable).Omit("status").
+	err = lapi.logsDB.Table(services.UsersTable).Omit("status", "county").
 		Where("phone_number=?", updateReq.PhoneNumber).Updates(userDB).Error
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update user status: %v", err)
 	}
 
+	err = lapi.updateDeclaredCounty(updateReq.PhoneNumber, userDB.County)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update user county: %v", err)
+	}
+
+	err = lapi.saveUserDevice(updateReq.PhoneNumber, userDB.DeviceToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save user device: %v", err)
+	}
+
+	go lapi.syncTopicSubscriptions(updateReq.PhoneNumber)
+
 	return &empty.Empty{}, nil
 }
 
@@ -475,7 +596,10 @@ func (lapi *locationAPIServer) AddUser(
 
 	if alreadyExists {
 		err = lapi.logsDB.Table(services.UsersTable).Where("phone_number=?", addReq.User.PhoneNumber).
-			Omit("traced").Updates(userModel).Error
+			Omit("traced", "county").Updates(userModel).Error
+		if err == nil {
+			err = lapi.updateDeclaredCounty(addReq.User.PhoneNumber, userModel.County)
+		}
 		switch {
 		case err == nil:
 		default:
@@ -493,12 +617,12 @@ func (lapi *locationAPIServer) AddUser(
 
 		// Send user a welcome notification
 		_, err := lapi.messagingClient.SendMessage(ctx, &messaging.Message{
-			UserPhone:    userModel.PhoneNumber,
-			Title:        fmt.Sprintf("Hello %s", userModel.FullName),
-			Notification: "Welcome to KoviTrace application.\nYou can do self-screening assessment, get qualitative information about COVID-19 and most important you will be notified in case you come into close contact with someone who has tested postive for COVID-19.",
-			Timestamp:    time.Now().Unix(),
-			Type:         messaging.MessageType_INFO,
-			Data:         map[string]string{"sender": "location_api"},
+			UserPhone:         userModel.PhoneNumber,
+			Template:          services.TemplateWelcome,
+			TemplateVariables: map[string]string{"FullName": userModel.FullName},
+			Timestamp:         time.Now().Unix(),
+			Type:              messaging.MessageType_INFO,
+			Data:              map[string]string{"sender": "location_api"},
 		}, grpc.WaitForReady(true))
 		if err != nil {
 			lapi.logger.Errorf("failed to send user welcome message: %v", err)
@@ -509,9 +633,25 @@ func (lapi *locationAPIServer) AddUser(
 		return nil, err
 	}
 
+	err = lapi.saveUserDevice(userModel.PhoneNumber, userModel.DeviceToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save user device: %v", err)
+	}
+
+	go lapi.syncTopicSubscriptions(userModel.PhoneNumber)
+
 	return &empty.Empty{}, nil
 }
 
+// saveUserDevice registers the device token given with the user, from clients that do not register devices
+func (lapi *locationAPIServer) saveUserDevice(phoneNumber, deviceToken string) error {
+	if deviceToken == "" || deviceToken == devicetoken.None {
+		return nil
+	}
+	_, err := saveDevice(lapi.logsDB, phoneNumber, &location.Device{DeviceToken: deviceToken})
+	return err
+}
+
 func (lapi *locationAPIServer) GetUser(
 	ctx context.Context, getReq *location.GetUserRequest,
 ) (*location.User, error) {
@@ -675,25 +815,65 @@ func normalizePageSize(pageToken, pageSize int32) (int, int) {
 
 func getUserDB(userPB *location.User) (*services.UserModel, error) {
 	userDB := &services.UserModel{
-		PhoneNumber: userPB.PhoneNumber,
-		FullName:    userPB.FullName,
-		County:      userPB.County,
-		Status:      int8(userPB.Status),
-		DeviceToken: userPB.DeviceToken,
-		Traced:      userPB.Traced,
+		PhoneNumber:       userPB.PhoneNumber,
+		FullName:          userPB.FullName,
+		County:            userPB.County,
+		Status:            int8(userPB.Status),
+		DeviceToken:       userPB.DeviceToken,
+		Traced:            userPB.Traced,
+		Group:             userPB.Group,
+		Email:             userPB.Email,
+		PreferredLanguage: userPB.PreferredLanguage,
+	}
+
+	// Users that did not choose a language are sent messages in the default language
+	if userPB.PreferredLanguage != "" && !services.ValidLanguage(userPB.PreferredLanguage) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown preferred language %q", userPB.PreferredLanguage)
+	}
+
+	if userPB.Email != "" {
+		_, err := mail.ParseAddress(userPB.Email)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "malformed email: %v", err)
+		}
+	}
+
+	channels := make([]string, 0, len(userPB.NotificationChannels))
+	for _, channel := range userPB.NotificationChannels {
+		if _, ok := location.NotificationChannel_name[int32(channel)]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown notification channel %d", channel)
+		}
+		channels = append(channels, strings.ToLower(channel.String()))
 	}
+	userDB.Channels = strings.Join(channels, ",")
+
 	return userDB, nil
 }
 
 func getUserPB(userDB *services.UserModel) (*location.User, error) {
 	userPB := &location.User{
-		PhoneNumber:      userDB.PhoneNumber,
-		FullName:         userDB.FullName,
-		County:           userDB.County,
-		Status:           location.Status(userDB.Status),
-		DeviceToken:      userDB.DeviceToken,
-		Traced:           userDB.Traced,
-		UpdatedTimestamp: userDB.UpdatedAt.Unix(),
+		PhoneNumber:       userDB.PhoneNumber,
+		FullName:          userDB.FullName,
+		County:            userDB.County,
+		Status:            location.Status(userDB.Status),
+		DeviceToken:       userDB.DeviceToken,
+		Traced:            userDB.Traced,
+		UpdatedTimestamp:  userDB.UpdatedAt.Unix(),
+		Group:             userDB.Group,
+		Constituency:      userDB.Constituency,
+		Ward:              userDB.Ward,
+		Email:             userDB.Email,
+		PreferredLanguage: userDB.PreferredLanguage,
+	}
+
+	if userDB.Channels != "" {
+		for _, channel := range strings.Split(userDB.Channels, ",") {
+			userPB.NotificationChannels = append(
+				userPB.NotificationChannels,
+				location.NotificationChannel(location.NotificationChannel_value[strings.ToUpper(channel)]),
+			)
+		}
 	}
+
 	return userPB, nil
 }
@@ -0,0 +1,134 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/locationstore"
+	"time"
+)
+
+var _ = Describe("Storing location history #store", func() {
+	var (
+		ctx    context.Context
+		userID string
+		start  int64
+	)
+
+	fakeLocationsDB := func(count int) []*services.LocationModel {
+		locationsDB := make([]*services.LocationModel, 0, count)
+		for i := 0; i < count; i++ {
+			locationDB := services.GetLocationDB(fakeLocation())
+			locationDB.UserID = userID
+			locationDB.Timestamp = start + int64(i)*60*60
+			dedupKey := fmt.Sprintf("%s:%d", userID, i)
+			locationDB.DedupKey = &dedupKey
+			locationsDB = append(locationsDB, locationDB)
+		}
+		return locationsDB
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		userID = randomdata.RandStringRunes(32)
+		// Spans several daily partitions
+		start = time.Now().Add(-72 * time.Hour).Unix()
+	})
+
+	It("should find locations in a time range in order", func() {
+		err := LocationServer.locations.Insert(ctx, fakeLocationsDB(72))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		locationsDB, err := LocationServer.locations.Find(ctx, &locationstore.Query{UserIDs: []string{userID}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(locationsDB).Should(HaveLen(72))
+		for i := 1; i < len(locationsDB); i++ {
+			Expect(locationsDB[i].Timestamp).Should(BeNumerically(">", locationsDB[i-1].Timestamp))
+		}
+
+		locationsDB, err = LocationServer.locations.Find(ctx, &locationstore.Query{
+			UserIDs:        []string{userID},
+			StartTimestamp: start + 24*60*60,
+			EndTimestamp:   start + 48*60*60,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(locationsDB).Should(HaveLen(25))
+		Expect(locationsDB[0].Coordinates).ShouldNot(BeEmpty())
+	})
+
+	It("should skip locations that were already saved", func() {
+		err := LocationServer.locations.Insert(ctx, fakeLocationsDB(10))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = LocationServer.locations.Insert(ctx, fakeLocationsDB(20))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		locationsDB, err := LocationServer.locations.Find(ctx, &locationstore.Query{UserIDs: []string{userID}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(locationsDB).Should(HaveLen(20))
+	})
+
+	It("should delete locations of users", func() {
+		err := LocationServer.locations.Insert(ctx, fakeLocationsDB(10))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = LocationServer.locations.Delete(ctx, []string{userID})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		locationsDB, err := LocationServer.locations.Find(ctx, &locationstore.Query{UserIDs: []string{userID}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(locationsDB).Should(BeEmpty())
+	})
+
+	It("should partition a locations table created by an earlier version", func() {
+		legacyDB, err := startSchemaDB(LocationServer.logsDB, legacySchema)
+		Expect(err).ShouldNot(HaveOccurred())
+		defer legacyDB.Close()
+
+		// Earlier versions created an unpartitioned table from the model
+		Expect(legacyDB.DropTableIfExists(&services.LocationModel{}).Error).ShouldNot(HaveOccurred())
+		Expect(legacyDB.AutoMigrate(&services.LocationModel{}).Error).ShouldNot(HaveOccurred())
+
+		store, err := locationstore.New(ctx, locationstore.MySQL, &locationstore.Options{SQLDB: legacyDB})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = store.Insert(ctx, fakeLocationsDB(10))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// Unpartitioned tables are not maintained
+		err = store.Maintain(ctx, time.Now())
+		Expect(err).Should(HaveOccurred())
+
+		err = store.Migrate(ctx)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		var partitions int
+		err = legacyDB.Raw(
+			"SELECT COUNT(*) FROM information_schema.PARTITIONS "+
+				"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL",
+			services.LocationsTable,
+		).Row().Scan(&partitions)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(partitions).Should(BeNumerically(">", 1))
+
+		// Locations saved before are kept and still deduplicated
+		err = store.Insert(ctx, fakeLocationsDB(20))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		locationsDB, err := store.Find(ctx, &locationstore.Query{UserIDs: []string{userID}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(locationsDB).Should(HaveLen(20))
+
+		err = store.Maintain(ctx, time.Now().Add(48*time.Hour))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should maintain the store more than once", func() {
+		err := LocationServer.locations.Maintain(ctx, time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = LocationServer.locations.Maintain(ctx, time.Now().Add(48*time.Hour))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+})
@@ -2,13 +2,19 @@ package location
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"github.com/gidyon/micros"
+	"github.com/gidyon/pandemic-api/internal/encryption"
+	"github.com/gidyon/pandemic-api/internal/pseudonym"
 	"github.com/gidyon/pandemic-api/internal/services/location/mocks"
 	"github.com/gidyon/pandemic-api/pkg/api/messaging"
 	"github.com/go-redis/redis"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/stretchr/testify/mock"
+	"io/ioutil"
 	"math/rand"
+	"os"
 	"testing"
 	"time"
 
@@ -31,9 +37,11 @@ var (
 )
 
 const (
-	dbAddress    = "localhost:3306"
-	schema       = "fightcovid19"
-	redisAddress = "localhost:6379"
+	dbAddress        = "localhost:3306"
+	schema           = "fightcovid19"
+	pseudonymsSchema = "fightcovid19_pseudonyms"
+	legacySchema     = "fightcovid19_legacy"
+	redisAddress     = "localhost:6379"
 )
 
 func startDB() (*gorm.DB, error) {
@@ -42,6 +50,18 @@ func startDB() (*gorm.DB, error) {
 	return gorm.Open("mysql", dsn)
 }
 
+// startSchemaDB creates and opens another database on the server of db, such as the pseudonyms database which is
+// separate from the locations database as deployed
+func startSchemaDB(db *gorm.DB, name string) (*gorm.DB, error) {
+	err := db.Exec("CREATE DATABASE IF NOT EXISTS " + name).Error
+	if err != nil {
+		return nil, err
+	}
+	param := "charset=utf8&parseTime=true"
+	dsn := fmt.Sprintf("root:hakty11@tcp(%s)/%s?%s", dbAddress, name, param)
+	return gorm.Open("mysql", dsn)
+}
+
 var _ = BeforeSuite(func() {
 	ctx := context.Background()
 
@@ -59,11 +79,47 @@ var _ = BeforeSuite(func() {
 		Return(&messaging.BroadCastMessageResponse{}, nil)
 	messagingClient.On("SendMessage", mock.Anything, mock.Anything, mock.Anything).
 		Return(&messaging.SendMessageResponse{}, nil)
+	messagingClient.On("SyncTopicSubscriptions", mock.Anything, mock.Anything, mock.Anything).
+		Return(&empty.Empty{}, nil)
+
+	// Encryption with a temporary key file
+	keyFile, err := ioutil.TempFile("", "location-keys-*.json")
+	handleError(err)
+	defer os.Remove(keyFile.Name())
+
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	handleError(err)
+
+	_, err = fmt.Fprintf(keyFile, `{"current": "test", "keys": {"test": %q}}`, base64.StdEncoding.EncodeToString(key))
+	handleError(err)
+	handleError(keyFile.Close())
+
+	keyProvider, err := encryption.NewLocalKeyProvider(keyFile.Name())
+	handleError(err)
+
+	cipher, err := encryption.NewCipher(ctx, &encryption.Options{
+		SQLDB:       db,
+		KeyProvider: keyProvider,
+	})
+	handleError(err)
+
+	encryption.SetDefault(cipher)
+
+	pseudonymsDB, err := startSchemaDB(db, pseudonymsSchema)
+	handleError(err)
+
+	pseudonymizer, err := pseudonym.NewPseudonymizer(ctx, &pseudonym.Options{
+		SQLDB: pseudonymsDB,
+		Key:   []byte("location-test-pseudonym-key"),
+	})
+	handleError(err)
 
 	opt := &Options{
 		LogsDB:          db,
 		EventsDB:        redisDB,
 		MessagingClient: messagingClient,
+		Pseudonymizer:   pseudonymizer,
 		Logger:          micros.NewLogger("location"),
 	}
 
@@ -81,6 +137,10 @@ var _ = BeforeSuite(func() {
 		return nil
 	}
 
+	LocationServer.authorizeGroups = func(context.Context, ...string) error {
+		return nil
+	}
+
 	LocationServer.authenticate = func(context.Context) error {
 		return nil
 	}
@@ -106,6 +166,11 @@ var _ = BeforeSuite(func() {
 	Expect(err).Should(HaveOccurred())
 
 	opt.MessagingClient = messagingClient
+	opt.Pseudonymizer = nil
+	_, err = NewLocationTracing(ctx, opt)
+	Expect(err).Should(HaveOccurred())
+
+	opt.Pseudonymizer = pseudonymizer
 	opt.Logger = nil
 	_, err = NewLocationTracing(ctx, opt)
 	Expect(err).Should(HaveOccurred())
@@ -78,6 +78,246 @@ func (_m *MessagingClientMock) BroadCastMessage(ctx context.Context, in *messagi
 	return r0, r1
 }
 
+// CancelBroadcast provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) CancelBroadcast(ctx context.Context, in *messaging.CancelBroadcastRequest, opts ...grpc.CallOption) (*messaging.Broadcast, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.Broadcast
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.CancelBroadcastRequest, ...grpc.CallOption) *messaging.Broadcast); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.Broadcast)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.CancelBroadcastRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountStaleDeviceTokens provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) CountStaleDeviceTokens(ctx context.Context, in *messaging.CountStaleDeviceTokensRequest, opts ...grpc.CallOption) (*messaging.StaleDeviceTokens, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.StaleDeviceTokens
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.CountStaleDeviceTokensRequest, ...grpc.CallOption) *messaging.StaleDeviceTokens); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.StaleDeviceTokens)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.CountStaleDeviceTokensRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTemplate provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) CreateTemplate(ctx context.Context, in *messaging.CreateTemplateRequest, opts ...grpc.CallOption) (*messaging.MessageTemplate, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.MessageTemplate
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.CreateTemplateRequest, ...grpc.CallOption) *messaging.MessageTemplate); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.MessageTemplate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.CreateTemplateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteSchedule provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) DeleteSchedule(ctx context.Context, in *messaging.ScheduleRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *empty.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.ScheduleRequest, ...grpc.CallOption) *empty.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*empty.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.ScheduleRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTemplate provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) DeleteTemplate(ctx context.Context, in *messaging.TemplateRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *empty.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.TemplateRequest, ...grpc.CallOption) *empty.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*empty.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.TemplateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EstimateAudience provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) EstimateAudience(ctx context.Context, in *messaging.EstimateAudienceRequest, opts ...grpc.CallOption) (*messaging.AudienceEstimate, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.AudienceEstimate
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.EstimateAudienceRequest, ...grpc.CallOption) *messaging.AudienceEstimate); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.AudienceEstimate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.EstimateAudienceRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBroadcast provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) GetBroadcast(ctx context.Context, in *messaging.GetBroadcastRequest, opts ...grpc.CallOption) (*messaging.Broadcast, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.Broadcast
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.GetBroadcastRequest, ...grpc.CallOption) *messaging.Broadcast); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.Broadcast)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.GetBroadcastRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessageDeliveryStatus provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) GetMessageDeliveryStatus(ctx context.Context, in *messaging.GetMessageDeliveryStatusRequest, opts ...grpc.CallOption) (*messaging.MessageDeliveryStatus, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.MessageDeliveryStatus
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.GetMessageDeliveryStatusRequest, ...grpc.CallOption) *messaging.MessageDeliveryStatus); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.MessageDeliveryStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.GetMessageDeliveryStatusRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNewMessagesCount provides a mock function with given fields: ctx, in, opts
 func (_m *MessagingClientMock) GetNewMessagesCount(ctx context.Context, in *messaging.MessageRequest, opts ...grpc.CallOption) (*messaging.NewMessagesCount, error) {
 	_va := make([]interface{}, len(opts))
@@ -108,6 +348,66 @@ func (_m *MessagingClientMock) GetNewMessagesCount(ctx context.Context, in *mess
 	return r0, r1
 }
 
+// GetTemplate provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) GetTemplate(ctx context.Context, in *messaging.TemplateRequest, opts ...grpc.CallOption) (*messaging.MessageTemplate, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.MessageTemplate
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.TemplateRequest, ...grpc.CallOption) *messaging.MessageTemplate); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.MessageTemplate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.TemplateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBroadcasts provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) ListBroadcasts(ctx context.Context, in *messaging.ListBroadcastsRequest, opts ...grpc.CallOption) (*messaging.Broadcasts, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.Broadcasts
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.ListBroadcastsRequest, ...grpc.CallOption) *messaging.Broadcasts); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.Broadcasts)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.ListBroadcastsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListMessages provides a mock function with given fields: ctx, in, opts
 func (_m *MessagingClientMock) ListMessages(ctx context.Context, in *messaging.ListMessagesRequest, opts ...grpc.CallOption) (*messaging.Messages, error) {
 	_va := make([]interface{}, len(opts))
@@ -138,6 +438,96 @@ func (_m *MessagingClientMock) ListMessages(ctx context.Context, in *messaging.L
 	return r0, r1
 }
 
+// ListSchedules provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) ListSchedules(ctx context.Context, in *messaging.ListSchedulesRequest, opts ...grpc.CallOption) (*messaging.Schedules, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.Schedules
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.ListSchedulesRequest, ...grpc.CallOption) *messaging.Schedules); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.Schedules)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.ListSchedulesRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTemplates provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) ListTemplates(ctx context.Context, in *messaging.ListTemplatesRequest, opts ...grpc.CallOption) (*messaging.MessageTemplates, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.MessageTemplates
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.ListTemplatesRequest, ...grpc.CallOption) *messaging.MessageTemplates); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.MessageTemplates)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.ListTemplatesRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PauseSchedule provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) PauseSchedule(ctx context.Context, in *messaging.ScheduleRequest, opts ...grpc.CallOption) (*messaging.Schedule, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.Schedule
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.ScheduleRequest, ...grpc.CallOption) *messaging.Schedule); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.Schedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.ScheduleRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadAll provides a mock function with given fields: ctx, in, opts
 func (_m *MessagingClientMock) ReadAll(ctx context.Context, in *messaging.MessageRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
 	_va := make([]interface{}, len(opts))
@@ -168,6 +558,36 @@ func (_m *MessagingClientMock) ReadAll(ctx context.Context, in *messaging.Messag
 	return r0, r1
 }
 
+// ResumeSchedule provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) ResumeSchedule(ctx context.Context, in *messaging.ScheduleRequest, opts ...grpc.CallOption) (*messaging.Schedule, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.Schedule
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.ScheduleRequest, ...grpc.CallOption) *messaging.Schedule); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.Schedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.ScheduleRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SendMessage provides a mock function with given fields: ctx, in, opts
 func (_m *MessagingClientMock) SendMessage(ctx context.Context, in *messaging.Message, opts ...grpc.CallOption) (*messaging.SendMessageResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -197,3 +617,63 @@ func (_m *MessagingClientMock) SendMessage(ctx context.Context, in *messaging.Me
 
 	return r0, r1
 }
+
+// SyncTopicSubscriptions provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) SyncTopicSubscriptions(ctx context.Context, in *messaging.SyncTopicSubscriptionsRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *empty.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.SyncTopicSubscriptionsRequest, ...grpc.CallOption) *empty.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*empty.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.SyncTopicSubscriptionsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTemplate provides a mock function with given fields: ctx, in, opts
+func (_m *MessagingClientMock) UpdateTemplate(ctx context.Context, in *messaging.UpdateTemplateRequest, opts ...grpc.CallOption) (*messaging.MessageTemplate, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *messaging.MessageTemplate
+	if rf, ok := ret.Get(0).(func(context.Context, *messaging.UpdateTemplateRequest, ...grpc.CallOption) *messaging.MessageTemplate); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*messaging.MessageTemplate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *messaging.UpdateTemplateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
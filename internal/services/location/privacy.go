@@ -0,0 +1,423 @@
+package location
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/locationstore"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userDataExport is everything we hold about a user
+type userDataExport struct {
+	ExportedAt    time.Time            `json:"exported_at"`
+	Profile       *location.User       `json:"profile"`
+	Locations     []*location.Location `json:"locations"`
+	Messages      []*exportedMessage   `json:"messages"`
+	StatusHistory []*exportedStatus    `json:"status_history"`
+	Consents      []*location.Consent  `json:"consents"`
+}
+
+type exportedMessage struct {
+	Title     string          `json:"title"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Seen      bool            `json:"seen"`
+	Type      int8            `json:"type"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+type exportedStatus struct {
+	Status    string `json:"status"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (lapi *locationAPIServer) ExportMyData(
+	ctx context.Context, exportReq *location.ExportMyDataRequest,
+) (*location.ExportMyDataResponse, error) {
+	// Request must not be nil
+	if exportReq == nil {
+		return nil, services.NilRequestError("ExportMyDataRequest")
+	}
+
+	// Validation
+	if exportReq.PhoneNumber == "" {
+		return nil, services.MissingFieldError("phone number")
+	}
+
+	// Only the owner of the data can export it
+	err := lapi.authorize(ctx, exportReq.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := lapi.collectUserData(ctx, exportReq.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := fmt.Sprintf("kovitrace-data-%s", export.ExportedAt.Format("20060102150405"))
+
+	switch exportReq.Format {
+	case location.ExportFormat_ZIP:
+		data, err := zipUserData(export)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create zip archive: %v", err)
+		}
+		return &location.ExportMyDataResponse{
+			FileName:    fileName + ".zip",
+			ContentType: "application/zip",
+			Data:        data,
+		}, nil
+	default:
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to json marshal user data: %v", err)
+		}
+		return &location.ExportMyDataResponse{
+			FileName:    fileName + ".json",
+			ContentType: "application/json",
+			Data:        data,
+		}, nil
+	}
+}
+
+func (lapi *locationAPIServer) collectUserData(ctx context.Context, phoneNumber string) (*userDataExport, error) {
+	// Get user profile
+	userDB := &services.UserModel{}
+	err := lapi.logsDB.First(userDB, "phone_number=?", phoneNumber).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "user with phone number %s not found", phoneNumber)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+
+	userPB, err := getUserPB(userDB)
+	if err != nil {
+		return nil, err
+	}
+
+	// Locations are stored against pseudonymous ids
+	userIDs, err := lapi.pseudonyms.IDs(phoneNumber)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Get user locations
+	locationsDB, err := lapi.locations.Find(ctx, &locationstore.Query{UserIDs: userIDs})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user locations: %v", err)
+	}
+
+	locationsPB := make([]*location.Location, 0, len(locationsDB))
+	for _, locationDB := range locationsDB {
+		locationPB := services.GetLocationPB(locationDB)
+		locationPB.Timestamp = locationDB.Timestamp
+		locationsPB = append(locationsPB, locationPB)
+	}
+
+	// Get user messages
+	messagesDB := make([]*services.Message, 0)
+	err = lapi.logsDB.Order("created_at ASC").Find(&messagesDB, "user_phone=?", phoneNumber).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user messages: %v", err)
+	}
+
+	messages := make([]*exportedMessage, 0, len(messagesDB))
+	for _, messageDB := range messagesDB {
+		messages = append(messages, &exportedMessage{
+			Title:     messageDB.Title,
+			Message:   messageDB.Message,
+			Data:      messageDB.Data,
+			Seen:      messageDB.Seen,
+			Type:      messageDB.Type,
+			Timestamp: messageDB.CreatedAt.Unix(),
+		})
+	}
+
+	// Get status history
+	historyDB := make([]*services.StatusHistory, 0)
+	err = lapi.logsDB.Order("created_at ASC").Find(&historyDB, "phone_number=?", phoneNumber).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user status history: %v", err)
+	}
+
+	history := make([]*exportedStatus, 0, len(historyDB))
+	for _, statusDB := range historyDB {
+		history = append(history, &exportedStatus{
+			Status:    location.Status(statusDB.Status).String(),
+			Timestamp: statusDB.CreatedAt.Unix(),
+		})
+	}
+
+	// Get consents
+	consents, err := lapi.getConsents(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userDataExport{
+		ExportedAt:    time.Now(),
+		Profile:       userPB,
+		Locations:     locationsPB,
+		Messages:      messages,
+		StatusHistory: history,
+		Consents:      consents.Consents,
+	}, nil
+}
+
+func zipUserData(export *userDataExport) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"locations.json", export.Locations},
+		{"messages.json", export.Messages},
+		{"status_history.json", export.StatusHistory},
+		{"consents.json", export.Consents},
+	}
+
+	for _, file := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(file.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := zw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// pseudonymize returns a random identifier replacing the phone number of a deleted user.
+//
+// The identifier is the same for all records of one deletion but is not derived from the phone number,
+// so it cannot be matched to a phone number by hashing candidates.
+func pseudonymize() (string, error) {
+	token := make([]byte, 8)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted-user-%x", token), nil
+}
+
+func (lapi *locationAPIServer) DeleteMyAccount(
+	ctx context.Context, delReq *location.DeleteMyAccountRequest,
+) (*empty.Empty, error) {
+	// Request must not be nil
+	if delReq == nil {
+		return nil, services.NilRequestError("DeleteMyAccountRequest")
+	}
+
+	// Validation
+	if delReq.PhoneNumber == "" {
+		return nil, services.MissingFieldError("phone number")
+	}
+
+	// Only the owner of the account can delete it
+	err := lapi.authorize(ctx, delReq.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	phoneNumber := delReq.PhoneNumber
+
+	// User must exist
+	err = lapi.logsDB.Select("id").First(&services.UserModel{}, "phone_number=?", phoneNumber).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "user with phone number %s not found", phoneNumber)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+
+	userIDs, err := lapi.pseudonyms.IDs(phoneNumber)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Data in other stores or keyed by pseudonymous ids is deleted before the user,
+	// so that a failed request can be retried until the user is deleted
+
+	// Locations may be in a different database
+	err = lapi.locations.Delete(ctx, userIDs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete user locations: %v", err)
+	}
+
+	err = lapi.logsDB.Unscoped().Delete(&services.GeoFenceVisit{}, "user_id IN(?)", userIDs).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete geo fence visits: %v", err)
+	}
+
+	// Remove contact points and blacklist entries
+	err = lapi.deleteUserEvents(ctx, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// A deleted account holds no consent
+	for _, purpose := range consentPurposes {
+		err = lapi.eventsDB.SRem(ctx, getWithdrawnConsentKey(purpose), phoneNumber).Err()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to remove withdrawn consents: %v", err)
+		}
+	}
+
+	// Forget which pseudonymous ids belonged to the user
+	err = lapi.pseudonyms.Delete(phoneNumber)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	pseudonym, err := pseudonymize()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate pseudonym: %v", err)
+	}
+
+	tx := lapi.logsDB.Begin()
+	defer func() {
+		if err := recover(); err != nil {
+			tx.Rollback()
+		}
+	}()
+	if tx.Error != nil {
+		return nil, services.FailedToBeginTx(tx.Error)
+	}
+
+	// Hard delete everything owned by the user
+	for _, model := range []struct {
+		value interface{}
+		query string
+		arg   interface{}
+	}{
+		{&services.UserModel{}, "phone_number=?", phoneNumber},
+		// Attempts are deleted before the deliveries they belong to
+		{&services.DeliveryAttempt{}, "delivery_id IN (?)", tx.Table(services.DeliveriesTable).
+			Select("id").Where("user_phone=?", phoneNumber).QueryExpr()},
+		{&services.Delivery{}, "user_phone=?", phoneNumber},
+		{&services.Message{}, "user_phone=?", phoneNumber},
+		{&services.StatusHistory{}, "phone_number=?", phoneNumber},
+		{&services.Consent{}, "phone_number=?", phoneNumber},
+		{&services.UserDevice{}, "phone_number=?", phoneNumber},
+	} {
+		err = tx.Unscoped().Delete(model.value, model.query, model.arg).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, status.Errorf(codes.Internal, "failed to delete user data: %v", err)
+		}
+	}
+
+	// Contacts alerted about the user keep their alert, but not who the patient was
+	err = tx.Table(services.MessagesTable).
+		Where("JSON_UNQUOTE(JSON_EXTRACT(data, '$.patient_phone'))=?", phoneNumber).
+		UpdateColumn("data", gorm.Expr("JSON_SET(data, '$.patient_phone', ?)", pseudonym)).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, status.Errorf(codes.Internal, "failed to pseudonymize contact alerts: %v", err)
+	}
+
+	// Contact tracing operations are described by the patient name and phone
+	err = tx.Table(services.ContactTracingOperationTable).
+		Where("description LIKE ?", "%- "+phoneNumber).
+		UpdateColumn("description", pseudonym).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, status.Errorf(codes.Internal, "failed to pseudonymize contact tracing operations: %v", err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return nil, services.FailedToCommitTx(err)
+	}
+
+	// Devices of the user are unsubscribed from topics
+	go lapi.syncTopicSubscriptions(phoneNumber)
+
+	return &empty.Empty{}, nil
+}
+
+// deleteUserEvents removes all redis sets and lists entries keyed by the user
+func (lapi *locationAPIServer) deleteUserEvents(ctx context.Context, phoneNumber string) error {
+	err := lapi.deleteUserContactPoints(ctx, phoneNumber)
+	if err != nil {
+		return err
+	}
+
+	err = lapi.eventsDB.LRem(ctx, infectedUsers, 0, phoneNumber).Err()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to remove user from infected users: %v", err)
+	}
+
+	// Nights are counted by phone number rather than pseudonymous id
+	homeKey, err := getHomeKey(phoneNumber)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get home key: %v", err)
+	}
+	err = lapi.eventsDB.Del(ctx, homeKey).Err()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to delete user home counts: %v", err)
+	}
+
+	return nil
+}
+
+// deleteUserContactPoints removes the daily sets of places visited by the user
+func (lapi *locationAPIServer) deleteUserContactPoints(ctx context.Context, phoneNumber string) error {
+	userIDs, err := lapi.pseudonyms.IDs(phoneNumber)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	keys := make([]string, 0)
+	for _, userID := range userIDs {
+		iter := lapi.eventsDB.Scan(ctx, 0, userID+":*", 100).Iterator()
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		if err := iter.Err(); err != nil {
+			return status.Errorf(codes.Internal, "failed to scan user contact points: %v", err)
+		}
+	}
+
+	if len(keys) > 0 {
+		err = lapi.eventsDB.Del(ctx, keys...).Err()
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to delete user contact points: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package restriction
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
+	"github.com/gidyon/pandemic-api/internal/services/location/geofence"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+)
+
+const (
+	// DateLayout is the layout of restriction and report dates
+	DateLayout = "2006-01-02"
+	// MaxReportDays is the maximum number of days in a compliance report
+	MaxReportDays = 31
+	// MovementDistance is how far in meters a user must move during a curfew to not comply.
+	// It is larger than the accuracy of accepted locations so that noise is not counted as movement
+	MovementDistance = 500.0
+)
+
+// Restriction is a validated restriction ready to be evaluated against locations
+type Restriction struct {
+	*location.Restriction
+	curfew    *geofence.Hours
+	zones     []*geofence.Polygon
+	timeZone  *time.Location
+	startDate time.Time
+	// endDate is zero when the restriction has no end
+	endDate time.Time
+	exempt  map[string]bool
+}
+
+// New validates a restriction
+func New(restrictionPB *location.Restriction) (*Restriction, error) {
+	var err error
+	switch {
+	case restrictionPB == nil:
+		err = errors.New("missing restriction")
+	case strings.TrimSpace(restrictionPB.Name) == "":
+		err = errors.New("missing restriction name")
+	case restrictionPB.Type == location.RestrictionType_MOVEMENT && len(restrictionPB.Zones) < 2:
+		err = errors.New("movement restrictions must have at least two zones")
+	case restrictionPB.StartDate == "":
+		err = errors.New("missing start date")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Restriction{
+		Restriction: restrictionPB,
+		zones:       make([]*geofence.Polygon, 0, len(restrictionPB.Zones)),
+		exempt:      make(map[string]bool, len(restrictionPB.ExemptGroups)),
+	}
+
+	r.timeZone, err = time.LoadLocation(restrictionPB.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown restriction time zone %q", restrictionPB.TimeZone)
+	}
+
+	if restrictionPB.Type == location.RestrictionType_CURFEW {
+		r.curfew, err = geofence.NewHours(restrictionPB.CurfewStart, restrictionPB.CurfewEnd, r.timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("malformed curfew: %v", err)
+		}
+	}
+
+	for i, zonePB := range restrictionPB.Zones {
+		if strings.TrimSpace(zonePB.GetName()) == "" {
+			return nil, fmt.Errorf("zone %d: missing zone name", i+1)
+		}
+		zone, err := geofence.NewPolygon(zonePB.Polygon)
+		if err != nil {
+			return nil, fmt.Errorf("zone %d: %v", i+1, err)
+		}
+		r.zones = append(r.zones, zone)
+	}
+
+	r.startDate, err = time.ParseInLocation(DateLayout, restrictionPB.StartDate, r.timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("malformed start date: %v", err)
+	}
+	if restrictionPB.EndDate != "" {
+		r.endDate, err = time.ParseInLocation(DateLayout, restrictionPB.EndDate, r.timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("malformed end date: %v", err)
+		}
+		if r.endDate.Before(r.startDate) {
+			return nil, errors.New("end date must not be before start date")
+		}
+	}
+
+	for _, group := range restrictionPB.ExemptGroups {
+		if strings.TrimSpace(group) == "" {
+			return nil, errors.New("exempt groups must not be empty")
+		}
+		r.exempt[group] = true
+	}
+
+	return r, nil
+}
+
+// Exempt reports whether users of the group are exempted from the restriction
+func (r *Restriction) Exempt(group string) bool {
+	return r.exempt[group]
+}
+
+// Days returns the start of the days between two dates in YYYY-MM-DD, inclusive, on which the restriction applies
+func (r *Restriction) Days(startDate, endDate string) ([]time.Time, error) {
+	start, err := time.ParseInLocation(DateLayout, startDate, r.timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("malformed start date: %v", err)
+	}
+	end, err := time.ParseInLocation(DateLayout, endDate, r.timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("malformed end date: %v", err)
+	}
+
+	switch {
+	case end.Before(start):
+		return nil, errors.New("end date must not be before start date")
+	case end.After(start.AddDate(0, 0, MaxReportDays-1)):
+		return nil, fmt.Errorf("reports must span at most %d days", MaxReportDays)
+	}
+
+	days := make([]time.Time, 0)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if day.Before(r.startDate) || (!r.endDate.IsZero() && day.After(r.endDate)) {
+			continue
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// zone returns the index of the zone containing the location, -1 if it is in none
+func (r *Restriction) zone(locationDB *services.LocationModel) int {
+	for i, zone := range r.zones {
+		if zone.Contains(float64(locationDB.Latitude), float64(locationDB.Longitude)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Compliance is whether a user complied with a restriction on a day
+type Compliance struct {
+	// Observed is whether the user had locations the restriction applies to
+	Observed     bool
+	NonCompliant bool
+	// Crossings is the number of moves between zones of a movement restriction
+	Crossings int
+}
+
+// Evaluate returns the compliance of a user from their locations on a day ordered by timestamp
+func (r *Restriction) Evaluate(locationsDB []*services.LocationModel) *Compliance {
+	if r.Type == location.RestrictionType_MOVEMENT {
+		return r.evaluateMovement(locationsDB)
+	}
+	return r.evaluateCurfew(locationsDB)
+}
+
+// evaluateCurfew finds users that moved away from where they were during curfew hours, outside the allowed zones
+func (r *Restriction) evaluateCurfew(locationsDB []*services.LocationModel) *Compliance {
+	var (
+		compliance = &Compliance{}
+		anchor     *services.LocationModel
+	)
+
+	for _, locationDB := range locationsDB {
+		if !r.curfew.Contains(time.Unix(locationDB.Timestamp, 0)) {
+			continue
+		}
+		compliance.Observed = true
+
+		if r.zone(locationDB) >= 0 {
+			continue
+		}
+
+		if anchor == nil {
+			anchor = locationDB
+			continue
+		}
+
+		distance := conversion.Distance(
+			float64(anchor.Latitude), float64(anchor.Longitude),
+			float64(locationDB.Latitude), float64(locationDB.Longitude),
+		)
+		if distance > MovementDistance {
+			compliance.NonCompliant = true
+			break
+		}
+	}
+
+	return compliance
+}
+
+// evaluateMovement counts moves of a user between zones, locations outside all zones are ignored
+func (r *Restriction) evaluateMovement(locationsDB []*services.LocationModel) *Compliance {
+	var (
+		compliance = &Compliance{}
+		last       = -1
+	)
+
+	for _, locationDB := range locationsDB {
+		zone := r.zone(locationDB)
+		if zone < 0 {
+			continue
+		}
+		compliance.Observed = true
+
+		if last >= 0 && zone != last {
+			compliance.Crossings++
+			compliance.NonCompliant = true
+		}
+		last = zone
+	}
+
+	return compliance
+}
+
+// Tally aggregates the compliance of users on a day
+type Tally struct {
+	observed     int64
+	nonCompliant int64
+	crossings    int64
+}
+
+// Add adds the compliance of a user to the tally
+func (t *Tally) Add(compliance *Compliance) {
+	if !compliance.Observed {
+		return
+	}
+	t.observed++
+	t.crossings += int64(compliance.Crossings)
+	if compliance.NonCompliant {
+		t.nonCompliant++
+	}
+}
+
+// Report returns the compliance of the day, withholding the counts when fewer than minGroupSize users were observed
+func (t *Tally) Report(day time.Time, minGroupSize int) *location.DailyCompliance {
+	dailyPB := &location.DailyCompliance{
+		Date: day.Format(DateLayout),
+	}
+
+	if t.observed < int64(minGroupSize) {
+		dailyPB.Suppressed = true
+		return dailyPB
+	}
+
+	dailyPB.ObservedUsers = t.observed
+	dailyPB.NonCompliantUsers = t.nonCompliant
+	dailyPB.NonComplianceRate = float32(t.nonCompliant) / float32(t.observed)
+	dailyPB.Crossings = t.crossings
+
+	return dailyPB
+}
@@ -0,0 +1,433 @@
+package location
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/auth"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/location/restriction"
+	"github.com/gidyon/pandemic-api/internal/services/locationstore"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// complianceMinGroupSize is the minimum number of users observed on a day for its counts to be reported,
+	// so that reports cannot single out individual users
+	complianceMinGroupSize = 10
+	// complianceBatchSize is the number of users whose locations are loaded at once
+	complianceBatchSize = 200
+)
+
+func getRestrictionDB(restrictionPB *location.Restriction) (*services.RestrictionModel, error) {
+	zones, err := json.Marshal(restrictionPB.Zones)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json marshal zones: %v", err)
+	}
+
+	exemptGroups, err := json.Marshal(restrictionPB.ExemptGroups)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json marshal exempt groups: %v", err)
+	}
+
+	return &services.RestrictionModel{
+		Name:         restrictionPB.Name,
+		Type:         int8(restrictionPB.Type),
+		CurfewStart:  restrictionPB.CurfewStart,
+		CurfewEnd:    restrictionPB.CurfewEnd,
+		TimeZone:     restrictionPB.TimeZone,
+		Zones:        zones,
+		ExemptGroups: exemptGroups,
+		StartDate:    restrictionPB.StartDate,
+		EndDate:      restrictionPB.EndDate,
+		Active:       restrictionPB.Active,
+	}, nil
+}
+
+func getRestrictionPB(restrictionDB *services.RestrictionModel) (*location.Restriction, error) {
+	restrictionPB := &location.Restriction{
+		RestrictionId: fmt.Sprint(restrictionDB.ID),
+		Name:          restrictionDB.Name,
+		Type:          location.RestrictionType(restrictionDB.Type),
+		CurfewStart:   restrictionDB.CurfewStart,
+		CurfewEnd:     restrictionDB.CurfewEnd,
+		TimeZone:      restrictionDB.TimeZone,
+		StartDate:     restrictionDB.StartDate,
+		EndDate:       restrictionDB.EndDate,
+		Active:        restrictionDB.Active,
+		CreatedAt:     restrictionDB.CreatedAt.Unix(),
+		UpdatedAt:     restrictionDB.UpdatedAt.Unix(),
+	}
+
+	err := json.Unmarshal(restrictionDB.Zones, &restrictionPB.Zones)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json unmarshal zones: %v", err)
+	}
+
+	err = json.Unmarshal(restrictionDB.ExemptGroups, &restrictionPB.ExemptGroups)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json unmarshal exempt groups: %v", err)
+	}
+
+	return restrictionPB, nil
+}
+
+func validateRestriction(restrictionPB *location.Restriction) error {
+	_, err := restriction.New(restrictionPB)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid restriction: %v", err)
+	}
+	return nil
+}
+
+func (lapi *locationAPIServer) CreateRestriction(
+	ctx context.Context, createReq *location.CreateRestrictionRequest,
+) (*location.Restriction, error) {
+	// Request must not be nil
+	if createReq == nil {
+		return nil, services.NilRequestError("CreateRestrictionRequest")
+	}
+
+	// Only administrators manage restrictions
+	err := lapi.authorizeGroups(ctx, auth.AdminGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateRestriction(createReq.Restriction)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictionDB, err := getRestrictionDB(createReq.Restriction)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lapi.logsDB.Create(restrictionDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create restriction: %v", err)
+	}
+
+	return getRestrictionPB(restrictionDB)
+}
+
+func (lapi *locationAPIServer) findRestriction(restrictionID string) (*services.RestrictionModel, error) {
+	id, err := strconv.ParseUint(restrictionID, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed restriction id %q", restrictionID)
+	}
+
+	restrictionDB := &services.RestrictionModel{}
+	err = lapi.logsDB.First(restrictionDB, "id=?", id).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "restriction with id %s not found", restrictionID)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get restriction: %v", err)
+	}
+
+	return restrictionDB, nil
+}
+
+func (lapi *locationAPIServer) UpdateRestriction(
+	ctx context.Context, updateReq *location.UpdateRestrictionRequest,
+) (*location.Restriction, error) {
+	// Request must not be nil
+	if updateReq == nil {
+		return nil, services.NilRequestError("UpdateRestrictionRequest")
+	}
+
+	// Only administrators manage restrictions
+	err := lapi.authorizeGroups(ctx, auth.AdminGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateReq.GetRestriction().GetRestrictionId() == "" {
+		return nil, services.MissingFieldError("restriction id")
+	}
+
+	err = validateRestriction(updateReq.Restriction)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictionDB, err := lapi.findRestriction(updateReq.Restriction.RestrictionId)
+	if err != nil {
+		return nil, err
+	}
+
+	updateDB, err := getRestrictionDB(updateReq.Restriction)
+	if err != nil {
+		return nil, err
+	}
+
+	// The restriction is replaced, so zero values are saved too
+	updateDB.Model = restrictionDB.Model
+
+	err = lapi.logsDB.Save(updateDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update restriction: %v", err)
+	}
+
+	return getRestrictionPB(updateDB)
+}
+
+func (lapi *locationAPIServer) DeleteRestriction(
+	ctx context.Context, delReq *location.DeleteRestrictionRequest,
+) (*empty.Empty, error) {
+	// Request must not be nil
+	if delReq == nil {
+		return nil, services.NilRequestError("DeleteRestrictionRequest")
+	}
+
+	// Only administrators manage restrictions
+	err := lapi.authorizeGroups(ctx, auth.AdminGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	if delReq.RestrictionId == "" {
+		return nil, services.MissingFieldError("restriction id")
+	}
+
+	restrictionDB, err := lapi.findRestriction(delReq.RestrictionId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lapi.logsDB.Delete(restrictionDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete restriction: %v", err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (lapi *locationAPIServer) GetRestriction(
+	ctx context.Context, getReq *location.GetRestrictionRequest,
+) (*location.Restriction, error) {
+	// Request must not be nil
+	if getReq == nil {
+		return nil, services.NilRequestError("GetRestrictionRequest")
+	}
+
+	// Restrictions are public so that users can know them
+	err := lapi.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if getReq.RestrictionId == "" {
+		return nil, services.MissingFieldError("restriction id")
+	}
+
+	restrictionDB, err := lapi.findRestriction(getReq.RestrictionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return getRestrictionPB(restrictionDB)
+}
+
+func (lapi *locationAPIServer) ListRestrictions(
+	ctx context.Context, listReq *location.ListRestrictionsRequest,
+) (*location.Restrictions, error) {
+	// Request must not be nil
+	if listReq == nil {
+		return nil, services.NilRequestError("ListRestrictionsRequest")
+	}
+
+	// Authenticate the request
+	err := lapi.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize page
+	pageToken, pageSize := normalizePageSize(listReq.PageToken, listReq.PageSize)
+
+	db := lapi.logsDB.Order("id ASC").Where("id>?", pageToken).Limit(pageSize)
+	if listReq.ActiveOnly {
+		db = db.Where("active=?", true)
+	}
+
+	restrictionsDB := make([]*services.RestrictionModel, 0)
+	err = db.Find(&restrictionsDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get restrictions from db: %v", err)
+	}
+
+	restrictionsPB := make([]*location.Restriction, 0, len(restrictionsDB))
+	for _, restrictionDB := range restrictionsDB {
+		restrictionPB, err := getRestrictionPB(restrictionDB)
+		if err != nil {
+			return nil, err
+		}
+		restrictionsPB = append(restrictionsPB, restrictionPB)
+		pageToken = int(restrictionDB.ID)
+	}
+
+	return &location.Restrictions{
+		Restrictions:  restrictionsPB,
+		NextPageToken: int32(pageToken),
+	}, nil
+}
+
+func (lapi *locationAPIServer) GetComplianceReport(
+	ctx context.Context, getReq *location.GetComplianceReportRequest,
+) (*location.ComplianceReport, error) {
+	// Request must not be nil
+	if getReq == nil {
+		return nil, services.NilRequestError("GetComplianceReportRequest")
+	}
+
+	// Reports are for administrators and health officers
+	err := lapi.authorizeGroups(ctx, auth.AdminGroup, auth.HealthOfficerGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validation
+	switch {
+	case getReq.RestrictionId == "":
+		err = services.MissingFieldError("restriction id")
+	case getReq.StartDate == "":
+		err = services.MissingFieldError("start date")
+	case getReq.EndDate == "":
+		err = services.MissingFieldError("end date")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	restrictionDB, err := lapi.findRestriction(getReq.RestrictionId)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictionPB, err := getRestrictionPB(restrictionDB)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := restriction.New(restrictionPB)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "invalid restriction: %v", err)
+	}
+
+	days, err := r.Days(getReq.StartDate, getReq.EndDate)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid report dates: %v", err)
+	}
+
+	// Phone numbers of users in exempted groups
+	exemptPhones := make([]string, 0)
+	if len(restrictionPB.ExemptGroups) > 0 {
+		err = lapi.logsDB.Model(&services.UserModel{}).Where("`group` IN (?)", restrictionPB.ExemptGroups).
+			Pluck("phone_number", &exemptPhones).Error
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get exempted users: %v", err)
+		}
+	}
+
+	// Users who withdrew consent to analytics are left out like exempted users
+	withdrawnPhones, err := lapi.eventsDB.SMembers(
+		ctx, getWithdrawnConsentKey(location.ConsentPurpose_ANALYTICS),
+	).Result()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get withdrawn consents: %v", err)
+	}
+	excludedPhones := append(exemptPhones, withdrawnPhones...)
+
+	reportPB := &location.ComplianceReport{
+		RestrictionId: restrictionPB.RestrictionId,
+		Type:          restrictionPB.Type,
+		Days:          make([]*location.DailyCompliance, 0, len(days)),
+		MinGroupSize:  complianceMinGroupSize,
+	}
+
+	for _, day := range days {
+		tally, err := lapi.tallyCompliance(ctx, r, day, excludedPhones)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get compliance on %s: %v", day.Format(restriction.DateLayout), err)
+		}
+		reportPB.Days = append(reportPB.Days, tally.Report(day, complianceMinGroupSize))
+	}
+
+	return reportPB, nil
+}
+
+// tallyCompliance evaluates the compliance of every user with locations on the day, leaving out excluded users
+func (lapi *locationAPIServer) tallyCompliance(
+	ctx context.Context, r *restriction.Restriction, day time.Time, excludedPhones []string,
+) (*restriction.Tally, error) {
+	var (
+		tally = &restriction.Tally{}
+		start = day.Unix()
+		end   = day.AddDate(0, 0, 1).Unix() - 1
+	)
+
+	// Pseudonymous ids of excluded users on the dates the day falls on. Locations are keyed by the id
+	// of the date they were recorded on
+	var (
+		dates    = []time.Time{time.Unix(start, 0).UTC(), time.Unix(end, 0).UTC()}
+		excluded = make(map[string]bool, len(dates)*len(excludedPhones))
+	)
+	for _, phoneNumber := range excludedPhones {
+		for _, t := range dates {
+			excluded[lapi.pseudonyms.ID(phoneNumber, t)] = true
+		}
+	}
+
+	userIDs, err := lapi.locations.UserIDs(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	observed := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if !excluded[userID] {
+			observed = append(observed, userID)
+		}
+	}
+
+	for len(observed) > 0 {
+		batch := observed
+		if len(batch) > complianceBatchSize {
+			batch = batch[:complianceBatchSize]
+		}
+		observed = observed[len(batch):]
+
+		locationsDB, err := lapi.locations.Find(ctx, &locationstore.Query{
+			UserIDs:        batch,
+			StartTimestamp: start,
+			EndTimestamp:   end,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Locations are ordered by timestamp, so they remain ordered for every user
+		userLocations := make(map[string][]*services.LocationModel, len(batch))
+		for _, locationDB := range locationsDB {
+			userLocations[locationDB.UserID] = append(userLocations[locationDB.UserID], locationDB)
+		}
+
+		for _, locationsDB := range userLocations {
+			tally.Add(r.Evaluate(locationsDB))
+		}
+	}
+
+	return tally, nil
+}
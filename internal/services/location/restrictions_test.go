@@ -0,0 +1,277 @@
+package location
+
+import (
+	"context"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/location/restriction"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math/rand"
+	"time"
+)
+
+// fakeZone is a square zone with sides of about 1 km
+func fakeZone(name string, lat, long float32) *location.RestrictionZone {
+	return &location.RestrictionZone{
+		Name: name,
+		Polygon: []*location.GeoPoint{
+			{Latitude: lat, Longitude: long},
+			{Latitude: lat, Longitude: long + 0.01},
+			{Latitude: lat + 0.01, Longitude: long + 0.01},
+			{Latitude: lat + 0.01, Longitude: long},
+		},
+	}
+}
+
+func fakeRestriction() *location.Restriction {
+	return &location.Restriction{
+		Name:         "Nairobi Metropolitan",
+		Type:         location.RestrictionType_MOVEMENT,
+		Zones:        []*location.RestrictionZone{fakeZone("Nairobi", 50, 20), fakeZone("Kiambu", 50, 20.02)},
+		ExemptGroups: []string{"HEALTH_WORKER"},
+		StartDate:    "2000-01-01",
+		Active:       true,
+	}
+}
+
+var _ = Describe("Managing restrictions and compliance reports #restrictions", func() {
+	var (
+		ctx           context.Context
+		restrictionPB *location.Restriction
+		createFn      = func(restrictionPB *location.Restriction) (*location.Restriction, error) {
+			return LocationAPI.CreateRestriction(context.Background(), &location.CreateRestrictionRequest{
+				Restriction: restrictionPB,
+			})
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		restrictionPB = fakeRestriction()
+	})
+
+	Describe("Creating restrictions with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			createRes, err := LocationAPI.CreateRestriction(ctx, nil)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when a movement restriction has less than two zones", func() {
+			restrictionPB.Zones = restrictionPB.Zones[:1]
+			createRes, err := createFn(restrictionPB)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when a curfew has no hours", func() {
+			restrictionPB.Type = location.RestrictionType_CURFEW
+			createRes, err := createFn(restrictionPB)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when the end date is before the start date", func() {
+			restrictionPB.EndDate = "1999-12-31"
+			createRes, err := createFn(restrictionPB)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+	})
+
+	Describe("Managing restrictions with well-formed requests", func() {
+		It("should create, get, list, update and delete a restriction", func() {
+			createRes, err := createFn(restrictionPB)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(createRes.RestrictionId).ShouldNot(BeEmpty())
+			Expect(createRes.Zones).Should(HaveLen(2))
+			Expect(createRes.ExemptGroups).Should(Equal(restrictionPB.ExemptGroups))
+
+			getRes, err := LocationAPI.GetRestriction(ctx, &location.GetRestrictionRequest{
+				RestrictionId: createRes.RestrictionId,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.Name).Should(Equal(restrictionPB.Name))
+
+			listRes, err := LocationAPI.ListRestrictions(ctx, &location.ListRestrictionsRequest{ActiveOnly: true})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.Restrictions).ShouldNot(BeEmpty())
+
+			createRes.Type = location.RestrictionType_CURFEW
+			createRes.CurfewStart, createRes.CurfewEnd = "19:00", "05:00"
+			createRes.Active = false
+			updateRes, err := LocationAPI.UpdateRestriction(ctx, &location.UpdateRestrictionRequest{
+				Restriction: createRes,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(updateRes.Type).Should(Equal(location.RestrictionType_CURFEW))
+			Expect(updateRes.Active).Should(BeFalse())
+
+			_, err = LocationAPI.DeleteRestriction(ctx, &location.DeleteRestrictionRequest{
+				RestrictionId: createRes.RestrictionId,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			getRes, err = LocationAPI.GetRestriction(ctx, &location.GetRestrictionRequest{
+				RestrictionId: createRes.RestrictionId,
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(getRes).Should(BeNil())
+		})
+	})
+
+	Describe("Getting compliance reports", func() {
+		var (
+			// day is a random day in the past so that locations of other tests are not in the report
+			day  time.Time
+			date string
+		)
+
+		insertLocations := func(userID string, points ...[2]float32) {
+			locationsDB := make([]*services.LocationModel, 0, len(points))
+			for i, point := range points {
+				locationDB := services.GetLocationDB(fakeLocation())
+				locationDB.UserID = userID
+				locationDB.Latitude, locationDB.Longitude = point[0], point[1]
+				locationDB.Timestamp = day.Add(time.Duration(i+1) * time.Hour).Unix()
+				locationsDB = append(locationsDB, locationDB)
+			}
+			err := LocationServer.locations.Insert(ctx, locationsDB)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			day = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, rand.Intn(3000))
+			date = day.Format(restriction.DateLayout)
+		})
+
+		It("should fail when the report spans too many days", func() {
+			createRes, err := createFn(restrictionPB)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			reportRes, err := LocationAPI.GetComplianceReport(ctx, &location.GetComplianceReportRequest{
+				RestrictionId: createRes.RestrictionId,
+				StartDate:     date,
+				EndDate:       day.AddDate(0, 0, restriction.MaxReportDays).Format(restriction.DateLayout),
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(reportRes).Should(BeNil())
+		})
+
+		It("should count users moving between zones, leaving out exempted users", func() {
+			createRes, err := createFn(restrictionPB)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for i := 0; i < 12; i++ {
+				userID := randomdata.RandStringRunes(32)
+				if i < 4 {
+					insertLocations(userID, [2]float32{50.005, 20.005}, [2]float32{50.005, 20.025}, [2]float32{50.005, 20.005})
+				} else {
+					insertLocations(userID, [2]float32{50.005, 20.005}, [2]float32{50.006, 20.006})
+				}
+			}
+
+			// Exempted users are not in the report
+			userPB := fakeUser()
+			userPB.Group = "HEALTH_WORKER"
+			_, err = LocationAPI.AddUser(ctx, &location.AddUserRequest{User: userPB})
+			Expect(err).ShouldNot(HaveOccurred())
+			insertLocations(LocationServer.pseudonyms.ID(userPB.PhoneNumber, day), [2]float32{50.005, 20.005}, [2]float32{50.005, 20.025})
+
+			reportRes, err := LocationAPI.GetComplianceReport(ctx, &location.GetComplianceReportRequest{
+				RestrictionId: createRes.RestrictionId,
+				StartDate:     date,
+				EndDate:       date,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(reportRes.MinGroupSize).Should(BeEquivalentTo(complianceMinGroupSize))
+			Expect(reportRes.Days).Should(HaveLen(1))
+			Expect(reportRes.Days[0].Date).Should(Equal(date))
+			Expect(reportRes.Days[0].Suppressed).Should(BeFalse())
+			Expect(reportRes.Days[0].ObservedUsers).Should(BeEquivalentTo(12))
+			Expect(reportRes.Days[0].NonCompliantUsers).Should(BeEquivalentTo(4))
+			Expect(reportRes.Days[0].Crossings).Should(BeEquivalentTo(8))
+		})
+
+		It("should leave out users who withdrew consent to analytics", func() {
+			createRes, err := createFn(restrictionPB)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for i := 0; i < 10; i++ {
+				insertLocations(randomdata.RandStringRunes(32), [2]float32{50.005, 20.005}, [2]float32{50.006, 20.006})
+			}
+
+			phoneNumber := addFakeUser(ctx)
+			withdrawRes, err := LocationAPI.WithdrawConsent(ctx, &location.WithdrawConsentRequest{
+				PhoneNumber: phoneNumber,
+				Purposes:    []location.ConsentPurpose{location.ConsentPurpose_ANALYTICS},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(withdrawRes).ShouldNot(BeNil())
+			insertLocations(LocationServer.pseudonyms.ID(phoneNumber, day), [2]float32{50.005, 20.005}, [2]float32{50.005, 20.025})
+
+			reportRes, err := LocationAPI.GetComplianceReport(ctx, &location.GetComplianceReportRequest{
+				RestrictionId: createRes.RestrictionId,
+				StartDate:     date,
+				EndDate:       date,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(reportRes.Days).Should(HaveLen(1))
+			Expect(reportRes.Days[0].ObservedUsers).Should(BeEquivalentTo(10))
+			Expect(reportRes.Days[0].NonCompliantUsers).Should(BeZero())
+		})
+
+		It("should count users moving during a curfew", func() {
+			restrictionPB.Type = location.RestrictionType_CURFEW
+			restrictionPB.CurfewStart, restrictionPB.CurfewEnd = "00:00", "23:59"
+			restrictionPB.Zones = nil
+			createRes, err := createFn(restrictionPB)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for i := 0; i < 10; i++ {
+				userID := randomdata.RandStringRunes(32)
+				if i < 3 {
+					insertLocations(userID, [2]float32{-1.29, 36.82}, [2]float32{-1.31, 36.82})
+				} else {
+					insertLocations(userID, [2]float32{-1.29, 36.82}, [2]float32{-1.2901, 36.8201})
+				}
+			}
+
+			reportRes, err := LocationAPI.GetComplianceReport(ctx, &location.GetComplianceReportRequest{
+				RestrictionId: createRes.RestrictionId,
+				StartDate:     date,
+				EndDate:       day.AddDate(0, 0, 1).Format(restriction.DateLayout),
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(reportRes.Days).Should(HaveLen(2))
+			Expect(reportRes.Days[0].ObservedUsers).Should(BeEquivalentTo(10))
+			Expect(reportRes.Days[0].NonCompliantUsers).Should(BeEquivalentTo(3))
+			Expect(reportRes.Days[0].NonComplianceRate).Should(BeNumerically("~", 0.3, 0.001))
+		})
+
+		It("should withhold counts of days with too few users", func() {
+			createRes, err := createFn(restrictionPB)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for i := 0; i < 3; i++ {
+				insertLocations(randomdata.RandStringRunes(32), [2]float32{50.005, 20.005}, [2]float32{50.005, 20.025})
+			}
+
+			reportRes, err := LocationAPI.GetComplianceReport(ctx, &location.GetComplianceReportRequest{
+				RestrictionId: createRes.RestrictionId,
+				StartDate:     date,
+				EndDate:       date,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(reportRes.Days).Should(HaveLen(1))
+			Expect(reportRes.Days[0].Suppressed).Should(BeTrue())
+			Expect(reportRes.Days[0].ObservedUsers).Should(BeZero())
+			Expect(reportRes.Days[0].NonCompliantUsers).Should(BeZero())
+		})
+	})
+})
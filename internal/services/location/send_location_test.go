@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/encryption"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
 	"github.com/gidyon/pandemic-api/pkg/api/location"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -77,13 +80,6 @@ var _ = Describe("Sending user location #sendloc", func() {
 			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
 			Expect(sendres).Should(BeNil())
 		})
-		It("should fail when location geo fence id is missing", func() {
-			sendReq.Location.GeoFenceId = ""
-			sendres, err := LocationAPI.SendLocation(ctx, sendReq)
-			Expect(err).Should(HaveOccurred())
-			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
-			Expect(sendres).Should(BeNil())
-		})
 		It("should fail when location timestamp is missing", func() {
 			sendReq.Location.Timestamp = 0
 			sendres, err := LocationAPI.SendLocation(ctx, sendReq)
@@ -100,5 +96,75 @@ var _ = Describe("Sending user location #sendloc", func() {
 			Expect(status.Code(err)).Should(Equal(codes.OK))
 			Expect(sendres).ShouldNot(BeNil())
 		})
+		It("should compute the geo fence id on the server", func() {
+			sendReq.Location.GeoFenceId = ""
+			sendres, err := LocationAPI.SendLocation(ctx, sendReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendres).ShouldNot(BeNil())
+			Expect(sendReq.Location.GeoFenceId).Should(Equal(LocationServer.cells.CellID(
+				float64(sendReq.Location.Latitude), float64(sendReq.Location.Longitude),
+			)))
+		})
+		It("should compute the canonical time id on the server", func() {
+			sendReq.Location.Timestamp = time.Date(2020, 5, 13, 10, 4, 59, 0, time.UTC).Unix()
+			sendres, err := LocationAPI.SendLocation(ctx, sendReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendres).ShouldNot(BeNil())
+			Expect(sendReq.Location.TimeId).Should(Equal("1589364000"))
+			Expect(LocationServer.timeBuckets.Adjacent(sendReq.Location.TimeId)).Should(
+				ConsistOf("1589363700", "1589364300"),
+			)
+		})
+		It("should not put locations mirrored across the equator in the same geo fence", func() {
+			north := LocationServer.cells.CellID(0.0001, 36.8219)
+			south := LocationServer.cells.CellID(-0.0001, 36.8219)
+			Expect(north).ShouldNot(Equal(south))
+			Expect(conversion.CellAndNeighbours(LocationServer.cells, north)).Should(ContainElement(south))
+		})
+		It("should store the location against the user pseudonym", func() {
+			sendres, err := LocationAPI.SendLocation(ctx, sendReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendres).ShouldNot(BeNil())
+
+			userID := LocationServer.pseudonyms.ID(sendReq.UserId, time.Now())
+			Expect(userID).ShouldNot(Equal(sendReq.UserId))
+
+			var count int
+			err = LocationServer.logsDB.Model(&services.LocationModel{}).
+				Where("user_id=?", userID).Count(&count).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(count).ShouldNot(BeZero())
+
+			err = LocationServer.logsDB.Model(&services.LocationModel{}).
+				Where("user_id=?", sendReq.UserId).Count(&count).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(count).Should(BeZero())
+
+			phoneNumber, err := LocationServer.pseudonyms.PhoneNumber(userID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(phoneNumber).Should(Equal(sendReq.UserId))
+		})
+		It("should store encrypted coordinates", func() {
+			sendres, err := LocationAPI.SendLocation(ctx, sendReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendres).ShouldNot(BeNil())
+
+			userID := LocationServer.pseudonyms.ID(sendReq.UserId, time.Now())
+
+			var coordinates []string
+			err = LocationServer.logsDB.Model(&services.LocationModel{}).Where("user_id=?", userID).
+				Pluck("coordinates", &coordinates).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(coordinates).ShouldNot(BeEmpty())
+			for _, value := range coordinates {
+				Expect(encryption.IsEncrypted(value)).Should(BeTrue())
+			}
+
+			locationDB := &services.LocationModel{}
+			err = LocationServer.logsDB.Order("id DESC").First(locationDB, "user_id=?", userID).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(locationDB.Latitude).Should(BeNumerically("~", sendReq.Location.Latitude, 0.0001))
+			Expect(locationDB.Longitude).Should(BeNumerically("~", sendReq.Location.Longitude, 0.0001))
+		})
 	})
 })
@@ -3,9 +3,11 @@ package location
 import (
 	"context"
 	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services/location/ingestion"
 	"github.com/gidyon/pandemic-api/pkg/api/location"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 var _ = Describe("Sending locations to the server #sends", func() {
@@ -40,6 +42,14 @@ var _ = Describe("Sending locations to the server #sends", func() {
 			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
 			Expect(sendres).Should(BeNil())
 		})
+		It("should fail when locations are from different devices", func() {
+			sendReq.Locations[0].DeviceId = randomdata.MacAddress()
+			sendReq.Locations[1].DeviceId = randomdata.MacAddress()
+			sendres, err := LocationAPI.SendLocations(ctx, sendReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(sendres).Should(BeNil())
+		})
 	})
 
 	Describe("Sending locations with well-formed request", func() {
@@ -50,4 +60,105 @@ var _ = Describe("Sending locations to the server #sends", func() {
 			Expect(sendres).ShouldNot(BeNil())
 		})
 	})
+
+	Describe("Sending locations with device sequences", func() {
+		var (
+			deviceID string
+			start    int64
+		)
+
+		countLocations := func() int32 {
+			getRes, err := LocationAPI.GetUserTrajectory(ctx, &location.GetUserTrajectoryRequest{
+				PhoneNumber:    sendReq.UserId,
+				StartTimestamp: start,
+				EndTimestamp:   time.Now().Unix(),
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			return getRes.OriginalPointsCount
+		}
+
+		BeforeEach(func() {
+			sendReq.UserId = addFakeUser(ctx)
+			deviceID = randomdata.MacAddress()
+			start = time.Now().Add(-time.Hour).Unix()
+			for i, locationPB := range sendReq.Locations {
+				locationPB.DeviceId = deviceID
+				locationPB.Sequence = int64(i + 1)
+				locationPB.Latitude = -1.2921 + float32(i)*0.001
+				locationPB.Longitude = 36.8219
+				locationPB.Timestamp = start + int64(i)*5*60
+			}
+		})
+
+		It("should acknowledge the highest sequence", func() {
+			sendres, err := LocationAPI.SendLocations(ctx, sendReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendres.DeviceId).Should(Equal(deviceID))
+			Expect(sendres.AcknowledgedSequence).Should(BeEquivalentTo(len(sendReq.Locations)))
+			Expect(countLocations()).Should(BeEquivalentTo(len(sendReq.Locations)))
+		})
+
+		It("should not save retransmitted locations again", func() {
+			sendres, err := LocationAPI.SendLocations(ctx, sendReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendres).ShouldNot(BeNil())
+
+			// Retry with one new location
+			locationPB := fakeLocation()
+			locationPB.DeviceId = deviceID
+			locationPB.Sequence = int64(len(sendReq.Locations) + 1)
+			locationPB.Latitude = -1.2921 + float32(len(sendReq.Locations))*0.001
+			locationPB.Longitude = 36.8219
+			sendReq.Locations = append(sendReq.Locations, locationPB)
+
+			sendres, err = LocationAPI.SendLocations(ctx, sendReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendres.AcknowledgedSequence).Should(BeEquivalentTo(len(sendReq.Locations)))
+			Expect(countLocations()).Should(BeEquivalentTo(len(sendReq.Locations)))
+		})
+
+		It("should key locations of a device that was offline by the day they were recorded on", func() {
+			start = time.Now().Add(-3 * 24 * time.Hour).Unix()
+			for i, locationPB := range sendReq.Locations {
+				locationPB.Timestamp = start + int64(i)*5*60
+			}
+			day := time.Unix(sendReq.Locations[len(sendReq.Locations)-1].Timestamp, 0).UTC()
+
+			_, err := LocationAPI.SendLocations(ctx, sendReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(countLocations()).Should(BeEquivalentTo(len(sendReq.Locations)))
+
+			// Tracing intersects the contact points of the user on each day
+			count, err := LocationServer.eventsDB.SCard(
+				ctx, getUserSetKey(LocationServer.pseudonyms.ID(sendReq.UserId, day), day),
+			).Result()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(count).ShouldNot(BeZero())
+
+			count, err = LocationServer.eventsDB.Exists(
+				ctx, getUserSetKey(LocationServer.pseudonyms.ID(sendReq.UserId, time.Now()), time.Now()),
+			).Result()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(count).Should(BeZero())
+		})
+
+		It("should report retransmitted locations in a stream as duplicates", func() {
+			sendres, err := LocationAPI.SendLocations(ctx, sendReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sendres).ShouldNot(BeNil())
+
+			stream := &fakeLocationsStream{
+				ctx:      ctx,
+				requests: []*location.SendLocationsRequest{sendReq},
+			}
+			err = LocationAPI.StreamLocations(stream)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stream.response.AcceptedCount).Should(BeZero())
+			Expect(stream.response.AcknowledgedSequence).Should(BeEquivalentTo(len(sendReq.Locations)))
+			for _, result := range stream.response.Results {
+				Expect(result.Reason).Should(Equal(ingestion.ReasonDuplicateSequence))
+			}
+			Expect(countLocations()).Should(BeEquivalentTo(len(sendReq.Locations)))
+		})
+	})
 })
@@ -0,0 +1,161 @@
+package location
+
+import (
+	"context"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services/location/ingestion"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"time"
+)
+
+type fakeLocationsStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests []*location.SendLocationsRequest
+	response *location.StreamLocationsResponse
+}
+
+func (stream *fakeLocationsStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *fakeLocationsStream) Recv() (*location.SendLocationsRequest, error) {
+	if len(stream.requests) == 0 {
+		return nil, io.EOF
+	}
+	sendReq := stream.requests[0]
+	stream.requests = stream.requests[1:]
+	return sendReq, nil
+}
+
+func (stream *fakeLocationsStream) SendAndClose(streamRes *location.StreamLocationsResponse) error {
+	stream.response = streamRes
+	return nil
+}
+
+var _ = Describe("Streaming user locations #stream", func() {
+	var (
+		stream      *fakeLocationsStream
+		phoneNumber string
+		start       int64
+	)
+
+	// pathLocations returns locations about 55m and 2 minutes apart
+	pathLocations := func(first, count int) []*location.Location {
+		locations := make([]*location.Location, 0, count)
+		for i := first; i < first+count; i++ {
+			locationPB := fakeLocation()
+			locationPB.Latitude = -1.2921 + float32(i%100)*0.0005
+			locationPB.Longitude = 36.8219 + float32(i/100)*0.0005
+			locationPB.Timestamp = start + int64(i)*2*60
+			locations = append(locations, locationPB)
+		}
+		return locations
+	}
+
+	getTrajectory := func() *location.Trajectory {
+		getRes, err := LocationAPI.GetUserTrajectory(context.Background(), &location.GetUserTrajectoryRequest{
+			PhoneNumber:    phoneNumber,
+			StartTimestamp: start,
+			EndTimestamp:   time.Now().Unix(),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		return getRes
+	}
+
+	BeforeEach(func() {
+		phoneNumber = addFakeUser(context.Background())
+		start = time.Now().Add(-48 * time.Hour).Unix()
+		stream = &fakeLocationsStream{ctx: context.Background()}
+	})
+
+	Describe("Streaming locations with malformed request", func() {
+		It("should fail when user is missing", func() {
+			stream.requests = []*location.SendLocationsRequest{{
+				StatusId:  location.Status_UNKNOWN,
+				Locations: pathLocations(0, 2),
+			}}
+			err := LocationAPI.StreamLocations(stream)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(stream.response).Should(BeNil())
+		})
+		It("should fail when the stream has locations of different users", func() {
+			stream.requests = []*location.SendLocationsRequest{
+				{UserId: phoneNumber, StatusId: location.Status_UNKNOWN, Locations: pathLocations(0, 2)},
+				{UserId: randomdata.PhoneNumber(), StatusId: location.Status_UNKNOWN, Locations: pathLocations(2, 2)},
+			}
+			err := LocationAPI.StreamLocations(stream)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(stream.response).Should(BeNil())
+		})
+	})
+
+	Describe("Streaming locations with well-formed request", func() {
+		It("should report the result of every location", func() {
+			locations := pathLocations(0, 6)
+			locations[1].Latitude = 120
+			locations[3].IsMock = true
+
+			stream.requests = []*location.SendLocationsRequest{
+				{UserId: phoneNumber, StatusId: location.Status_UNKNOWN, Locations: locations[:3]},
+				{UserId: phoneNumber, StatusId: location.Status_UNKNOWN, Locations: locations[3:]},
+			}
+			err := LocationAPI.StreamLocations(stream)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stream.response).ShouldNot(BeNil())
+			Expect(stream.response.AcceptedCount).Should(BeEquivalentTo(4))
+			Expect(stream.response.RejectedCount).Should(BeEquivalentTo(2))
+			Expect(stream.response.Results).Should(HaveLen(6))
+
+			for i, result := range stream.response.Results {
+				Expect(result.Index).Should(BeEquivalentTo(i))
+				switch i {
+				case 1:
+					Expect(result.Accepted).Should(BeFalse())
+					Expect(result.Reason).Should(Equal(ingestion.ReasonInvalid))
+				case 3:
+					Expect(result.Accepted).Should(BeFalse())
+					Expect(result.Reason).Should(Equal(ingestion.ReasonMockLocation))
+				default:
+					Expect(result.Accepted).Should(BeTrue())
+					Expect(result.Reason).Should(BeEmpty())
+				}
+			}
+
+			Expect(getTrajectory().OriginalPointsCount).Should(BeEquivalentTo(4))
+		})
+
+		It("should filter locations received out of order in time order", func() {
+			locations := pathLocations(0, 4)
+			locations[0], locations[3] = locations[3], locations[0]
+
+			stream.requests = []*location.SendLocationsRequest{
+				{UserId: phoneNumber, StatusId: location.Status_UNKNOWN, Locations: locations},
+			}
+			err := LocationAPI.StreamLocations(stream)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stream.response.AcceptedCount).Should(BeEquivalentTo(4))
+			Expect(getTrajectory().OriginalPointsCount).Should(BeEquivalentTo(4))
+		})
+
+		It("should save locations spanning several insert batches", func() {
+			stream.requests = []*location.SendLocationsRequest{
+				{UserId: phoneNumber, StatusId: location.Status_UNKNOWN, Locations: pathLocations(0, 700)},
+				{UserId: phoneNumber, StatusId: location.Status_UNKNOWN, Locations: pathLocations(700, 500)},
+			}
+			err := LocationAPI.StreamLocations(stream)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stream.response.AcceptedCount).Should(BeEquivalentTo(1200))
+			Expect(stream.response.RejectedCount).Should(BeZero())
+			Expect(stream.response.Results[1199].Index).Should(BeEquivalentTo(1199))
+
+			Expect(getTrajectory().OriginalPointsCount).Should(BeEquivalentTo(1200))
+		})
+	})
+})
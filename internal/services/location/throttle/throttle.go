@@ -0,0 +1,202 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services/messaging/templates"
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Decisions on an alert
+const (
+	DecisionSent     = "sent"
+	DecisionMerged   = "merged"
+	DecisionDailyCap = "daily_cap"
+)
+
+var alertsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "location",
+	Subsystem: "alerts",
+	Name:      "alerts_total",
+	Help:      "Number of real-time alerts by kind and throttling decision",
+}, []string{"kind", "decision"})
+
+func init() {
+	prometheus.MustRegister(alertsTotal)
+}
+
+// Options contains limits used by the throttler, zero values use the defaults
+type Options struct {
+	// Cooldown is the minimum time between messages of a kind to a user, defaults to 30 minutes
+	Cooldown time.Duration
+	// DailyCap is the maximum number of messages of a kind a user gets in a day, defaults to 6
+	DailyCap int
+	// MergeWindow is how long alerts are counted into the next message after the first of them, defaults to 2 hours
+	MergeWindow time.Duration
+}
+
+// Throttler limits how often users are alerted, merging alerts that come too soon into the next message.
+//
+// State is kept in redis under keys prefixed with the user id so that it is removed with the user data.
+type Throttler struct {
+	client *redis.Client
+	opt    *Options
+}
+
+// New creates a throttler. A nil options uses the default limits
+func New(client *redis.Client, opt *Options) *Throttler {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	o := *opt
+	if o.Cooldown == 0 {
+		o.Cooldown = 30 * time.Minute
+	}
+	if o.DailyCap == 0 {
+		o.DailyCap = 6
+	}
+	if o.MergeWindow == 0 {
+		o.MergeWindow = 2 * time.Hour
+	}
+	if o.MergeWindow < o.Cooldown {
+		// Alerts suppressed in the cooldown must still be counted in the next message
+		o.MergeWindow = o.Cooldown
+	}
+
+	return &Throttler{client: client, opt: &o}
+}
+
+// NewFromEnv creates a throttler with the limits in ALERT_COOLDOWN_MINUTES, ALERT_DAILY_CAP and ALERT_MERGE_MINUTES.
+// Limits that are not set use the defaults.
+func NewFromEnv(client *redis.Client) (*Throttler, error) {
+	cooldown, err := intFromEnv("ALERT_COOLDOWN_MINUTES")
+	if err != nil {
+		return nil, err
+	}
+	dailyCap, err := intFromEnv("ALERT_DAILY_CAP")
+	if err != nil {
+		return nil, err
+	}
+	mergeWindow, err := intFromEnv("ALERT_MERGE_MINUTES")
+	if err != nil {
+		return nil, err
+	}
+
+	return New(client, &Options{
+		Cooldown:    time.Duration(cooldown) * time.Minute,
+		DailyCap:    dailyCap,
+		MergeWindow: time.Duration(mergeWindow) * time.Minute,
+	}), nil
+}
+
+func intFromEnv(name string) (int, error) {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("%s must be a positive number, got %q", name, value)
+	}
+	return number, nil
+}
+
+// Alert is the outcome of throttling an alert
+type Alert struct {
+	// Send is whether a message should be sent for the alert
+	Send bool
+	// Count is the number of alerts merged into the message, including this one
+	Count int
+	// Since is when the first of the merged alerts happened, in the time zone messages are formatted in
+	Since time.Time
+	// CollapseKey identifies the message of the user, so that a newer message replaces the older one on the device
+	CollapseKey string
+	// Decision is what was decided on the alert, used in metrics
+	Decision string
+}
+
+// throttleScript counts the alert and decides whether to send it, atomically since alerts of a user
+// may be throttled concurrently.
+//
+// KEYS: merged alerts hash, cooldown key, daily count key
+// ARGV: now, cooldown milliseconds, daily cap, merge window milliseconds
+var throttleScript = redis.NewScript(`
+local count = redis.call('HINCRBY', KEYS[1], 'count', 1)
+if count == 1 then
+	redis.call('HSET', KEYS[1], 'since', ARGV[1])
+	redis.call('PEXPIRE', KEYS[1], ARGV[4])
+end
+local since = tonumber(redis.call('HGET', KEYS[1], 'since'))
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	return {0, count, since}
+end
+local daily = tonumber(redis.call('GET', KEYS[3]) or '0')
+if daily >= tonumber(ARGV[3]) then
+	return {-1, count, since}
+end
+redis.call('INCR', KEYS[3])
+redis.call('EXPIRE', KEYS[3], 86400)
+redis.call('SET', KEYS[2], ARGV[1], 'PX', ARGV[2])
+return {1, count, since}
+`)
+
+func getAlertsKey(userID, kind string) string {
+	return fmt.Sprintf("%s:alerts:%s", userID, kind)
+}
+
+func getCooldownKey(userID, kind string) string {
+	return fmt.Sprintf("%s:alerts:%s:cooldown", userID, kind)
+}
+
+func getDailyKey(userID, kind string, now time.Time) string {
+	return fmt.Sprintf("%s:alerts:%s:%s", userID, kind, now.UTC().Format("20060102"))
+}
+
+// Throttle records an alert of a kind to a user and reports whether a message should be sent.
+//
+// Alerts within the cooldown of the last message or past the daily cap are not sent,
+// but are counted in the next message sent within the merge window.
+func (t *Throttler) Throttle(ctx context.Context, userID, kind string, now time.Time) (*Alert, error) {
+	res, err := throttleScript.Run(
+		ctx, t.client,
+		[]string{getAlertsKey(userID, kind), getCooldownKey(userID, kind), getDailyKey(userID, kind, now)},
+		now.Unix(), int64(t.opt.Cooldown/time.Millisecond), t.opt.DailyCap, int64(t.opt.MergeWindow/time.Millisecond),
+	).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to throttle alert: %v", err)
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 3 {
+		return nil, fmt.Errorf("failed to throttle alert: unexpected result %v", res)
+	}
+	decision, _ := values[0].(int64)
+	count, _ := values[1].(int64)
+	since, _ := values[2].(int64)
+
+	alert := &Alert{
+		Count:       int(count),
+		Since:       time.Unix(since, 0).In(templates.TimeZone),
+		CollapseKey: fmt.Sprintf("alerts:%s", kind),
+	}
+
+	switch decision {
+	case 1:
+		alert.Send, alert.Decision = true, DecisionSent
+	case -1:
+		alert.Decision = DecisionDailyCap
+	default:
+		alert.Decision = DecisionMerged
+	}
+
+	alertsTotal.WithLabelValues(kind, alert.Decision).Inc()
+
+	return alert, nil
+}
@@ -0,0 +1,320 @@
+package trajectory
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+)
+
+func distance(p1, p2 *location.Location) float64 {
+	return conversion.Distance(
+		float64(p1.Latitude), float64(p1.Longitude), float64(p2.Latitude), float64(p2.Longitude),
+	)
+}
+
+// Length returns the distance in meters travelled along the points
+func Length(points []*location.Location) float64 {
+	length := 0.0
+	for i := 1; i < len(points); i++ {
+		length += distance(points[i-1], points[i])
+	}
+	return length
+}
+
+// Simplify reduces the number of points in a path with the Douglas-Peucker algorithm.
+//
+// Points that are within tolerance meters of the simplified path are removed. The first and last points are always kept.
+func Simplify(points []*location.Location, tolerance float64) []*location.Location {
+	if tolerance <= 0 || len(points) < 3 {
+		return points
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	// Segments are processed with a stack rather than recursion to handle long paths
+	type segment struct{ start, end int }
+	stack := []segment{{0, len(points) - 1}}
+
+	for len(stack) > 0 {
+		seg := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		maxDistance, index := 0.0, 0
+		for i := seg.start + 1; i < seg.end; i++ {
+			d := perpendicularDistance(points[i], points[seg.start], points[seg.end])
+			if d > maxDistance {
+				maxDistance, index = d, i
+			}
+		}
+
+		if maxDistance > tolerance {
+			keep[index] = true
+			stack = append(stack, segment{seg.start, index}, segment{index, seg.end})
+		}
+	}
+
+	simplified := make([]*location.Location, 0)
+	for i, point := range points {
+		if keep[i] {
+			simplified = append(simplified, point)
+		}
+	}
+
+	return simplified
+}
+
+// perpendicularDistance returns the distance in meters from point to the segment between start and end.
+// Coordinates are projected onto a plane around start, which is accurate enough for paths of a few kilometers.
+func perpendicularDistance(point, start, end *location.Location) float64 {
+	project := func(p *location.Location) (float64, float64) {
+		x := conversion.Distance(float64(start.Latitude), float64(start.Longitude), float64(start.Latitude), float64(p.Longitude))
+		y := conversion.Distance(float64(start.Latitude), float64(start.Longitude), float64(p.Latitude), float64(start.Longitude))
+		if p.Longitude < start.Longitude {
+			x = -x
+		}
+		if p.Latitude < start.Latitude {
+			y = -y
+		}
+		return x, y
+	}
+
+	px, py := project(point)
+	ex, ey := project(end)
+
+	length := ex*ex + ey*ey
+	if length == 0 {
+		return math.Hypot(px, py)
+	}
+
+	// Closest point on the segment
+	t := math.Max(0, math.Min(1, (px*ex+py*ey)/length))
+
+	return math.Hypot(px-t*ex, py-t*ey)
+}
+
+// Dwells returns places where the user stayed within radius meters for at least minDuration.
+//
+// Points must be ordered by timestamp.
+func Dwells(points []*location.Location, radius float64, minDuration time.Duration) []*location.DwellPoint {
+	dwellPoints := make([]*location.DwellPoint, 0)
+
+	for i := 0; i < len(points); {
+		j := i + 1
+		for j < len(points) && distance(points[i], points[j]) <= radius {
+			j++
+		}
+
+		if time.Duration(points[j-1].Timestamp-points[i].Timestamp)*time.Second >= minDuration {
+			dwellPoints = append(dwellPoints, newDwellPoint(points[i:j]))
+			i = j
+			continue
+		}
+
+		i++
+	}
+
+	return dwellPoints
+}
+
+func newDwellPoint(points []*location.Location) *location.DwellPoint {
+	var (
+		latitude, longitude float64
+		placemarks          = make(map[string]int)
+		placemark           string
+	)
+
+	for _, point := range points {
+		latitude += float64(point.Latitude)
+		longitude += float64(point.Longitude)
+		if point.Placemark == "" {
+			continue
+		}
+		placemarks[point.Placemark]++
+		if placemarks[point.Placemark] > placemarks[placemark] {
+			placemark = point.Placemark
+		}
+	}
+
+	arrival, departure := points[0].Timestamp, points[len(points)-1].Timestamp
+
+	return &location.DwellPoint{
+		Latitude:           float32(latitude / float64(len(points))),
+		Longitude:          float32(longitude / float64(len(points))),
+		Placemark:          placemark,
+		ArrivalTimestamp:   arrival,
+		DepartureTimestamp: departure,
+		DurationMinutes:    int32((departure - arrival) / 60),
+		PointsCount:        int32(len(points)),
+	}
+}
+
+// Export encodes the trajectory points and dwell points in the given format and sets the trajectory file
+func Export(trajectoryPB *location.Trajectory, format location.TrajectoryExportFormat) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch format {
+	case location.TrajectoryExportFormat_NO_EXPORT:
+		return nil
+	case location.TrajectoryExportFormat_GEOJSON:
+		data, err = GeoJSON(trajectoryPB)
+		trajectoryPB.FileName = "trajectory.geojson"
+		trajectoryPB.ContentType = "application/geo+json"
+	case location.TrajectoryExportFormat_GPX:
+		data, err = GPX(trajectoryPB)
+		trajectoryPB.FileName = "trajectory.gpx"
+		trajectoryPB.ContentType = "application/gpx+xml"
+	default:
+		return fmt.Errorf("unknown export format %v", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	trajectoryPB.Data = data
+
+	return nil
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   *geoJSONGeometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string            `json:"type"`
+	Features []*geoJSONFeature `json:"features"`
+}
+
+// GeoJSON encodes the trajectory as a feature collection with a line string of the path and a point for every dwell point
+func GeoJSON(trajectoryPB *location.Trajectory) ([]byte, error) {
+	var (
+		coordinates = make([][2]float32, 0, len(trajectoryPB.Points))
+		timestamps  = make([]int64, 0, len(trajectoryPB.Points))
+	)
+
+	for _, point := range trajectoryPB.Points {
+		coordinates = append(coordinates, [2]float32{point.Longitude, point.Latitude})
+		timestamps = append(timestamps, point.Timestamp)
+	}
+
+	features := []*geoJSONFeature{{
+		Type: "Feature",
+		Geometry: &geoJSONGeometry{
+			Type:        "LineString",
+			Coordinates: coordinates,
+		},
+		Properties: map[string]interface{}{
+			"start_timestamp": trajectoryPB.StartTimestamp,
+			"end_timestamp":   trajectoryPB.EndTimestamp,
+			"distance_meters": trajectoryPB.DistanceMeters,
+			"timestamps":      timestamps,
+		},
+	}}
+
+	for _, dwellPoint := range trajectoryPB.DwellPoints {
+		features = append(features, &geoJSONFeature{
+			Type: "Feature",
+			Geometry: &geoJSONGeometry{
+				Type:        "Point",
+				Coordinates: [2]float32{dwellPoint.Longitude, dwellPoint.Latitude},
+			},
+			Properties: map[string]interface{}{
+				"placemark":           dwellPoint.Placemark,
+				"arrival_timestamp":   dwellPoint.ArrivalTimestamp,
+				"departure_timestamp": dwellPoint.DepartureTimestamp,
+				"duration_minutes":    dwellPoint.DurationMinutes,
+			},
+		})
+	}
+
+	return json.Marshal(&geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	})
+}
+
+type gpxPoint struct {
+	Latitude    float32 `xml:"lat,attr"`
+	Longitude   float32 `xml:"lon,attr"`
+	Time        string  `xml:"time,omitempty"`
+	Name        string  `xml:"name,omitempty"`
+	Description string  `xml:"desc,omitempty"`
+}
+
+type gpxSegment struct {
+	Points []*gpxPoint `xml:"trkpt"`
+}
+
+type gpxTrack struct {
+	Name     string        `xml:"name"`
+	Segments []*gpxSegment `xml:"trkseg"`
+}
+
+type gpx struct {
+	XMLName   xml.Name    `xml:"gpx"`
+	Xmlns     string      `xml:"xmlns,attr"`
+	Version   string      `xml:"version,attr"`
+	Creator   string      `xml:"creator,attr"`
+	Waypoints []*gpxPoint `xml:"wpt"`
+	Track     *gpxTrack   `xml:"trk"`
+}
+
+func gpxTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+}
+
+// GPX encodes the trajectory as a GPX 1.1 track with a waypoint for every dwell point
+func GPX(trajectoryPB *location.Trajectory) ([]byte, error) {
+	doc := &gpx{
+		Xmlns:     "http://www.topografix.com/GPX/1/1",
+		Version:   "1.1",
+		Creator:   "pandemic-api",
+		Waypoints: make([]*gpxPoint, 0, len(trajectoryPB.DwellPoints)),
+		Track: &gpxTrack{
+			Name:     "trajectory",
+			Segments: []*gpxSegment{{Points: make([]*gpxPoint, 0, len(trajectoryPB.Points))}},
+		},
+	}
+
+	for _, dwellPoint := range trajectoryPB.DwellPoints {
+		doc.Waypoints = append(doc.Waypoints, &gpxPoint{
+			Latitude:  dwellPoint.Latitude,
+			Longitude: dwellPoint.Longitude,
+			Time:      gpxTime(dwellPoint.ArrivalTimestamp),
+			Name:      dwellPoint.Placemark,
+			Description: fmt.Sprintf(
+				"stayed %d minutes until %s", dwellPoint.DurationMinutes, gpxTime(dwellPoint.DepartureTimestamp),
+			),
+		})
+	}
+
+	for _, point := range trajectoryPB.Points {
+		doc.Track.Segments[0].Points = append(doc.Track.Segments[0].Points, &gpxPoint{
+			Latitude:  point.Latitude,
+			Longitude: point.Longitude,
+			Time:      gpxTime(point.Timestamp),
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
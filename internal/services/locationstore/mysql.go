@@ -0,0 +1,255 @@
+package locationstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+)
+
+const (
+	// maxPartition holds locations beyond the last daily partition
+	maxPartition = "pmax"
+	// dedupIndex is the unique index on the dedup key
+	dedupIndex = "uix_locations_dedup_key"
+)
+
+// mysqlStore keeps locations in a table with daily range partitions on timestamp.
+//
+// Partitions are named after the UTC day they hold, e.g. p20200513. Every unique key of a partitioned
+// table must contain timestamp, so the primary key is (id, timestamp) and the dedup key is (dedup_key, timestamp).
+// Tables created by earlier versions are not partitioned; Migrate rebuilds their keys and partitions them in place.
+type mysqlStore struct {
+	*sqlStore
+}
+
+type partition struct {
+	name string
+	// bound is the timestamp below which locations are in the partition
+	bound int64
+}
+
+func partitionName(day time.Time) string {
+	return "p" + day.UTC().Format("20060102")
+}
+
+// partitionDefinition defines the partition for locations of the UTC day starting at day
+func partitionDefinition(day time.Time) string {
+	return fmt.Sprintf("PARTITION %s VALUES LESS THAN (%d)", partitionName(day), day.AddDate(0, 0, 1).Unix())
+}
+
+// partitionDefinitions defines the partitions of a table being partitioned, the first of which holds all locations before today
+func (s *mysqlStore) partitionDefinitions(now time.Time) string {
+	today := dayStart(now)
+
+	definitions := []string{
+		fmt.Sprintf("PARTITION %s VALUES LESS THAN (%d)", partitionName(today.AddDate(0, 0, -1)), today.Unix()),
+	}
+	for i := 0; i < s.partitionDays; i++ {
+		definitions = append(definitions, partitionDefinition(today.AddDate(0, 0, i)))
+	}
+	definitions = append(definitions, fmt.Sprintf("PARTITION %s VALUES LESS THAN MAXVALUE", maxPartition))
+
+	return strings.Join(definitions, ", ")
+}
+
+func (s *mysqlStore) createTable(now time.Time) error {
+	return s.sqlDB.Exec(fmt.Sprintf("CREATE TABLE `%s` ("+
+		"`id` bigint unsigned NOT NULL AUTO_INCREMENT, "+
+		"`user_id` varchar(50) NOT NULL, "+
+		"`coordinates` varchar(255) NOT NULL, "+
+		"`place_mark` varchar(50) NOT NULL, "+
+		"`geo_fence_id` varchar(50) NOT NULL, "+
+		"`time_id` varchar(50) NOT NULL, "+
+		"`timestamp` bigint(20) NOT NULL, "+
+		"`accuracy` float(10) NOT NULL, "+
+		"`speed` float(10) NOT NULL, "+
+		"`speed_accuracy` float(10) NOT NULL, "+
+		"`county` varchar(50) NOT NULL DEFAULT '', "+
+		"`constituency` varchar(50) NOT NULL DEFAULT '', "+
+		"`ward` varchar(50) NOT NULL DEFAULT '', "+
+		"`dedup_key` varchar(128) NULL, "+
+		"`created_at` datetime NULL, "+
+		"`updated_at` datetime NULL, "+
+		"`deleted_at` datetime NULL, "+
+		"PRIMARY KEY (`id`, `timestamp`), "+
+		"UNIQUE KEY `uix_locations_dedup_key` (`dedup_key`, `timestamp`), "+
+		"KEY `idx_locations_user_timestamp` (`user_id`, `timestamp`), "+
+		"KEY `idx_locations_deleted_at` (`deleted_at`)"+
+		") PARTITION BY RANGE (`timestamp`) (%s)",
+		services.LocationsTable, s.partitionDefinitions(now),
+	)).Error
+}
+
+// partitionTable partitions a locations table created by an earlier version.
+//
+// Its primary and dedup keys are extended with timestamp first, as every unique key of a partitioned table must
+// contain it. Both statements copy the table, so writes to it wait until they finish.
+func (s *mysqlStore) partitionTable(now time.Time) error {
+	keys := "DROP PRIMARY KEY, ADD PRIMARY KEY (`id`, `timestamp`)"
+	if s.sqlDB.Dialect().HasIndex(services.LocationsTable, dedupIndex) {
+		keys += fmt.Sprintf(", DROP INDEX `%s`", dedupIndex)
+	}
+	keys += fmt.Sprintf(", ADD UNIQUE KEY `%s` (`dedup_key`, `timestamp`)", dedupIndex)
+
+	err := s.sqlDB.Exec(fmt.Sprintf("ALTER TABLE `%s` %s", services.LocationsTable, keys)).Error
+	if err != nil {
+		return fmt.Errorf("failed to add timestamp to unique keys: %v", err)
+	}
+
+	err = s.sqlDB.Exec(fmt.Sprintf(
+		"ALTER TABLE `%s` PARTITION BY RANGE (`timestamp`) (%s)", services.LocationsTable, s.partitionDefinitions(now),
+	)).Error
+	if err != nil {
+		return fmt.Errorf("failed to add partitions: %v", err)
+	}
+
+	return nil
+}
+
+func (s *mysqlStore) Migrate(ctx context.Context) error {
+	if !s.sqlDB.HasTable(services.LocationsTable) {
+		err := s.createTable(time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to create locations table: %v", err)
+		}
+	}
+
+	// Adds columns and indexes missing from tables created by earlier versions
+	err := s.sqlDB.AutoMigrate(&services.LocationModel{}).Error
+	if err != nil {
+		return err
+	}
+
+	// Coordinates are encrypted
+	err = services.MigrateLocationCoordinates(s.sqlDB)
+	if err != nil {
+		return fmt.Errorf("failed to migrate location coordinates: %v", err)
+	}
+
+	partitions, err := s.partitions()
+	if err != nil {
+		return fmt.Errorf("failed to get partitions: %v", err)
+	}
+
+	if len(partitions) == 0 {
+		err = s.partitionTable(time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to partition locations table: %v", err)
+		}
+	}
+
+	return s.Maintain(ctx, time.Now())
+}
+
+// partitions returns the partitions of the locations table in order, none if it is not partitioned
+func (s *mysqlStore) partitions() ([]*partition, error) {
+	rows, err := s.sqlDB.Raw(
+		"SELECT PARTITION_NAME, PARTITION_DESCRIPTION FROM information_schema.PARTITIONS "+
+			"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL "+
+			"ORDER BY PARTITION_ORDINAL_POSITION",
+		services.LocationsTable,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	partitions := make([]*partition, 0)
+	for rows.Next() {
+		var name, description string
+		err = rows.Scan(&name, &description)
+		if err != nil {
+			return nil, err
+		}
+
+		p := &partition{name: name, bound: math.MaxInt64}
+		if description != "MAXVALUE" {
+			p.bound, err = strconv.ParseInt(description, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("malformed bound of partition %s: %v", name, err)
+			}
+		}
+		partitions = append(partitions, p)
+	}
+
+	return partitions, rows.Err()
+}
+
+// Maintain creates daily partitions up to partition days ahead and drops partitions past the retention period
+func (s *mysqlStore) Maintain(ctx context.Context, now time.Time) error {
+	partitions, err := s.partitions()
+	if err != nil {
+		return fmt.Errorf("failed to get partitions: %v", err)
+	}
+
+	// Migrate partitions the table before it is maintained
+	if len(partitions) == 0 {
+		return errors.New("locations table is not partitioned")
+	}
+
+	var (
+		hasMax = partitions[len(partitions)-1].bound == math.MaxInt64
+		// next is the first day without a daily partition
+		next = dayStart(now)
+	)
+
+	for _, p := range partitions {
+		if p.bound != math.MaxInt64 {
+			next = dayStart(time.Unix(p.bound, 0))
+		}
+	}
+
+	definitions := make([]string, 0)
+	until := dayStart(now).AddDate(0, 0, s.partitionDays)
+	for day := next; day.Before(until); day = day.AddDate(0, 0, 1) {
+		definitions = append(definitions, partitionDefinition(day))
+	}
+
+	if len(definitions) > 0 {
+		// Locations beyond the daily partitions are moved into the new partitions
+		statement := fmt.Sprintf(
+			"ALTER TABLE `%s` REORGANIZE PARTITION %s INTO (%s, PARTITION %s VALUES LESS THAN MAXVALUE)",
+			services.LocationsTable, partitions[len(partitions)-1].name, strings.Join(definitions, ", "), maxPartition,
+		)
+		if !hasMax {
+			statement = fmt.Sprintf(
+				"ALTER TABLE `%s` ADD PARTITION (%s)", services.LocationsTable, strings.Join(definitions, ", "),
+			)
+		}
+
+		err = s.sqlDB.Exec(statement).Error
+		if err != nil {
+			return fmt.Errorf("failed to add partitions: %v", err)
+		}
+	}
+
+	cutoff := s.retentionCutoff(now)
+	if cutoff == 0 {
+		return nil
+	}
+
+	// Dropping a partition is much cheaper than deleting its rows
+	expired := make([]string, 0)
+	for _, p := range partitions {
+		if p.bound <= cutoff {
+			expired = append(expired, p.name)
+		}
+	}
+
+	if len(expired) > 0 {
+		err = s.sqlDB.Exec(fmt.Sprintf(
+			"ALTER TABLE `%s` DROP PARTITION %s", services.LocationsTable, strings.Join(expired, ", "),
+		)).Error
+		if err != nil {
+			return fmt.Errorf("failed to drop partitions: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package locationstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+)
+
+// postgresStore keeps locations in postgres, as a TimescaleDB hypertable with daily chunks when hypertable is set.
+//
+// Like the mysql store, unique keys contain timestamp since chunks are partitioned on it.
+type postgresStore struct {
+	*sqlStore
+	hypertable bool
+}
+
+func (s *postgresStore) Migrate(ctx context.Context) error {
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (`+
+			`id bigserial NOT NULL, `+
+			`user_id varchar(50) NOT NULL, `+
+			`coordinates varchar(255) NOT NULL, `+
+			`place_mark varchar(50) NOT NULL, `+
+			`geo_fence_id varchar(50) NOT NULL, `+
+			`time_id varchar(50) NOT NULL, `+
+			`"timestamp" bigint NOT NULL, `+
+			`accuracy real NOT NULL, `+
+			`speed real NOT NULL, `+
+			`speed_accuracy real NOT NULL, `+
+			`county varchar(50) NOT NULL DEFAULT '', `+
+			`constituency varchar(50) NOT NULL DEFAULT '', `+
+			`ward varchar(50) NOT NULL DEFAULT '', `+
+			`dedup_key varchar(128) NULL, `+
+			`created_at timestamp with time zone NULL, `+
+			`updated_at timestamp with time zone NULL, `+
+			`deleted_at timestamp with time zone NULL, `+
+			`PRIMARY KEY (id, "timestamp"))`, services.LocationsTable,
+		),
+	}
+
+	if s.hypertable {
+		statements = append(statements,
+			`CREATE EXTENSION IF NOT EXISTS timescaledb`,
+			fmt.Sprintf(
+				`SELECT create_hypertable('%s', 'timestamp', chunk_time_interval => %d, if_not_exists => TRUE)`,
+				services.LocationsTable, int64(24*time.Hour/time.Second),
+			),
+		)
+	}
+
+	// Columns added after the table was first created
+	for _, column := range []string{"county", "constituency", "ward"} {
+		statements = append(statements, fmt.Sprintf(
+			`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s varchar(50) NOT NULL DEFAULT ''`, services.LocationsTable, column,
+		))
+	}
+
+	statements = append(statements,
+		fmt.Sprintf(
+			`CREATE UNIQUE INDEX IF NOT EXISTS uix_locations_dedup_key ON %s (dedup_key, "timestamp")`,
+			services.LocationsTable,
+		),
+		fmt.Sprintf(
+			`CREATE INDEX IF NOT EXISTS idx_locations_user_timestamp ON %s (user_id, "timestamp")`,
+			services.LocationsTable,
+		),
+		fmt.Sprintf(
+			`CREATE INDEX IF NOT EXISTS idx_locations_deleted_at ON %s (deleted_at)`,
+			services.LocationsTable,
+		),
+	)
+
+	for _, statement := range statements {
+		err := s.sqlDB.Exec(statement).Error
+		if err != nil {
+			return fmt.Errorf("failed to migrate locations table: %v", err)
+		}
+	}
+
+	return s.Maintain(ctx, time.Now())
+}
+
+// Maintain drops locations past the retention period. TimescaleDB creates chunks as locations are inserted.
+func (s *postgresStore) Maintain(ctx context.Context, now time.Time) error {
+	cutoff := s.retentionCutoff(now)
+	if cutoff == 0 {
+		return nil
+	}
+
+	var err error
+	if s.hypertable {
+		err = s.sqlDB.Exec(
+			fmt.Sprintf(`SELECT drop_chunks('%s', older_than => ?::bigint)`, services.LocationsTable), cutoff,
+		).Error
+	} else {
+		err = s.sqlDB.Unscoped().Delete(&services.LocationModel{}, `"timestamp" < ?`, cutoff).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to drop expired locations: %v", err)
+	}
+
+	return nil
+}
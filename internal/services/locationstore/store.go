@@ -0,0 +1,285 @@
+package locationstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/jinzhu/gorm"
+
+	// postgres driver for the timescale and postgres stores
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+)
+
+// Supported stores
+const (
+	MySQL     = "mysql"
+	Postgres  = "postgres"
+	Timescale = "timescale"
+)
+
+const (
+	defaultBatchSize     = 500
+	defaultPartitionDays = 7
+)
+
+// Store keeps the location history of users.
+//
+// Locations are partitioned by day on their timestamp, so queries should be bounded in time whenever possible.
+type Store interface {
+	// Migrate creates the locations table and its indexes, or updates a table created by an earlier version
+	Migrate(ctx context.Context) error
+	// Insert saves locations in batches. Locations with the dedup key of a saved location are skipped
+	Insert(ctx context.Context, locationsDB []*services.LocationModel) error
+	// Find returns the locations of users matching the query ordered by timestamp
+	Find(ctx context.Context, query *Query) ([]*services.LocationModel, error)
+	// UserIDs returns the ids of users with locations between the start and end timestamps, inclusive
+	UserIDs(ctx context.Context, startTimestamp, endTimestamp int64) ([]string, error)
+	// Delete permanently deletes all locations of the users
+	Delete(ctx context.Context, userIDs []string) error
+	// Maintain prepares storage for the coming days and drops locations older than the retention period
+	Maintain(ctx context.Context, now time.Time) error
+}
+
+// Query selects locations of users
+type Query struct {
+	UserIDs []string
+	// StartTimestamp is the earliest timestamp to return, zero has no lower bound
+	StartTimestamp int64
+	// EndTimestamp is the latest timestamp to return, zero has no upper bound
+	EndTimestamp int64
+}
+
+// Options contains parameters for New
+type Options struct {
+	// SQLDB holds the locations table, a mysql database for the mysql store and a postgres database otherwise
+	SQLDB *gorm.DB
+	// BatchSize is the number of locations inserted by a single statement, defaults to 500
+	BatchSize int
+	// PartitionDays is the number of days ahead for which mysql partitions are created, defaults to 7
+	PartitionDays int
+	// RetentionDays is the number of days locations are kept for, zero keeps them forever
+	RetentionDays int
+}
+
+// New creates a location store by name
+func New(ctx context.Context, name string, opt *Options) (Store, error) {
+	var err error
+	// Validation
+	switch {
+	case ctx == nil:
+		err = errors.New("non-nil context must not be nil")
+	case opt == nil:
+		err = errors.New("non-nil options is required")
+	case opt.SQLDB == nil:
+		err = errors.New("non-nil sqlDB is required")
+	case opt.BatchSize < 0:
+		err = errors.New("batch size must not be negative")
+	case opt.PartitionDays < 0:
+		err = errors.New("partition days must not be negative")
+	case opt.RetentionDays < 0:
+		err = errors.New("retention days must not be negative")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sqlStore{
+		sqlDB:         opt.SQLDB,
+		batchSize:     opt.BatchSize,
+		partitionDays: opt.PartitionDays,
+		retentionDays: opt.RetentionDays,
+	}
+
+	if s.batchSize == 0 {
+		s.batchSize = defaultBatchSize
+	}
+	if s.partitionDays == 0 {
+		s.partitionDays = defaultPartitionDays
+	}
+
+	switch strings.ToLower(name) {
+	case "", MySQL:
+		s.onConflict = "ON DUPLICATE KEY UPDATE `id` = `id`"
+		return &mysqlStore{s}, nil
+	case Postgres:
+		s.onConflict = "ON CONFLICT DO NOTHING"
+		return &postgresStore{sqlStore: s}, nil
+	case Timescale:
+		s.onConflict = "ON CONFLICT DO NOTHING"
+		return &postgresStore{sqlStore: s, hypertable: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown location store %q", name)
+	}
+}
+
+// DBFromEnv returns the database holding locations.
+//
+// It opens LOCATION_STORE_DSN for the postgres and timescale stores and returns defaultDB for the mysql store.
+func DBFromEnv(defaultDB *gorm.DB) (*gorm.DB, error) {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("LOCATION_STORE"))) {
+	case Postgres, Timescale:
+		dsn := strings.TrimSpace(os.Getenv("LOCATION_STORE_DSN"))
+		if dsn == "" {
+			return nil, errors.New("location store dsn is required")
+		}
+		sqlDB, err := gorm.Open("postgres", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open locations database: %v", err)
+		}
+		return sqlDB, nil
+	default:
+		return defaultDB, nil
+	}
+}
+
+// NewFromEnv creates the location store named by LOCATION_STORE, which defaults to mysql.
+//
+// The retention period in days is read from LOCATION_RETENTION_DAYS.
+func NewFromEnv(ctx context.Context, defaultDB *gorm.DB) (Store, error) {
+	var (
+		retentionDays int
+		err           error
+	)
+
+	sqlDB, err := DBFromEnv(defaultDB)
+	if err != nil {
+		return nil, err
+	}
+
+	if days := strings.TrimSpace(os.Getenv("LOCATION_RETENTION_DAYS")); days != "" {
+		retentionDays, err = strconv.Atoi(days)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse location retention days: %v", err)
+		}
+	}
+
+	return New(ctx, strings.TrimSpace(os.Getenv("LOCATION_STORE")), &Options{
+		SQLDB:         sqlDB,
+		RetentionDays: retentionDays,
+	})
+}
+
+// sqlStore has the queries shared by all stores
+type sqlStore struct {
+	sqlDB         *gorm.DB
+	batchSize     int
+	partitionDays int
+	retentionDays int
+	// onConflict is the clause that skips locations violating the unique dedup key
+	onConflict string
+}
+
+func (s *sqlStore) quote(column string) string {
+	return s.sqlDB.Dialect().Quote(column)
+}
+
+// Insert uses one multi-row statement per batch, as gorm creates a single row per statement
+func (s *sqlStore) Insert(ctx context.Context, locationsDB []*services.LocationModel) error {
+	columns := make([]string, 0, 15)
+	for _, column := range []string{
+		"user_id", "coordinates", "place_mark", "geo_fence_id", "time_id", "timestamp",
+		"accuracy", "speed", "speed_accuracy", "county", "constituency", "ward", "dedup_key",
+		"created_at", "updated_at",
+	} {
+		columns = append(columns, s.quote(column))
+	}
+
+	now := time.Now()
+
+	for start := 0; start < len(locationsDB); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(locationsDB) {
+			end = len(locationsDB)
+		}
+
+		var (
+			placeholders = make([]string, 0, end-start)
+			values       = make([]interface{}, 0, (end-start)*len(columns))
+		)
+
+		for _, locationDB := range locationsDB[start:end] {
+			err := locationDB.BeforeSave()
+			if err != nil {
+				return err
+			}
+
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			values = append(values,
+				locationDB.UserID, locationDB.Coordinates, locationDB.PlaceMark, locationDB.GeoFenceID,
+				locationDB.TimeID, locationDB.Timestamp, locationDB.Accuracy, locationDB.Speed,
+				locationDB.SpeedAccuracy, locationDB.County, locationDB.Constituency, locationDB.Ward,
+				locationDB.DedupKey, now, now,
+			)
+		}
+
+		err := s.sqlDB.Exec(fmt.Sprintf(
+			"INSERT INTO %s (%s) VALUES %s %s",
+			s.quote(services.LocationsTable), strings.Join(columns, ", "), strings.Join(placeholders, ", "), s.onConflict,
+		), values...).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *sqlStore) Find(ctx context.Context, query *Query) ([]*services.LocationModel, error) {
+	locationsDB := make([]*services.LocationModel, 0)
+	if len(query.UserIDs) == 0 {
+		return locationsDB, nil
+	}
+
+	db := s.sqlDB.Where("user_id IN (?)", query.UserIDs)
+	if query.StartTimestamp != 0 {
+		db = db.Where(s.quote("timestamp")+" >= ?", query.StartTimestamp)
+	}
+	if query.EndTimestamp != 0 {
+		db = db.Where(s.quote("timestamp")+" <= ?", query.EndTimestamp)
+	}
+
+	err := db.Order(s.quote("timestamp") + " ASC").Find(&locationsDB).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return locationsDB, nil
+}
+
+func (s *sqlStore) UserIDs(ctx context.Context, startTimestamp, endTimestamp int64) ([]string, error) {
+	userIDs := make([]string, 0)
+	err := s.sqlDB.Model(&services.LocationModel{}).
+		Where(s.quote("timestamp")+" BETWEEN ? AND ?", startTimestamp, endTimestamp).
+		Pluck("DISTINCT user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+func (s *sqlStore) Delete(ctx context.Context, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return s.sqlDB.Unscoped().Delete(&services.LocationModel{}, "user_id IN (?)", userIDs).Error
+}
+
+// dayStart returns the start of the UTC day of t
+func dayStart(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// retentionCutoff returns the timestamp before which locations are dropped, zero when they are kept forever
+func (s *sqlStore) retentionCutoff(now time.Time) int64 {
+	if s.retentionDays == 0 {
+		return 0
+	}
+	return dayStart(now).AddDate(0, 0, -s.retentionDays).Unix()
+}
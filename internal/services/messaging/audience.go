@@ -0,0 +1,438 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/auth"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+const (
+	// maxAudienceDepth is how deep audiences may be nested in any_of and exclude
+	maxAudienceDepth = 3
+	// defaultSeenWindow is how far back users seen in geo fences are looked for
+	defaultSeenWindow = 14 * 24 * time.Hour
+	// audienceVisitorsExpiration is how long users seen in geo fences are kept for audiences never released
+	audienceVisitorsExpiration = 7 * 24 * time.Hour
+)
+
+// visitorsPageSize is how many users seen in geo fences are listed at a time, tests list fewer
+var visitorsPageSize int32 = 1000
+
+// appVersionNumber is the SQL expression of the app version of a device as a number comparable with getAppVersionNumber
+const appVersionNumber = "CAST(SUBSTRING_INDEX(CONCAT(app_version, '.0.0'), '.', 1) AS UNSIGNED) * 1000000 + " +
+	"CAST(SUBSTRING_INDEX(SUBSTRING_INDEX(CONCAT(app_version, '.0.0'), '.', 2), '.', -1) AS UNSIGNED) * 1000 + " +
+	"CAST(SUBSTRING_INDEX(SUBSTRING_INDEX(CONCAT(app_version, '.0.0'), '.', 3), '.', -1) AS UNSIGNED)"
+
+func (s *messagingServer) EstimateAudience(
+	ctx context.Context, estimateReq *messaging.EstimateAudienceRequest,
+) (*messaging.AudienceEstimate, error) {
+	// Request must not be nil
+	if estimateReq == nil {
+		return nil, services.NilRequestError("EstimateAudienceRequest")
+	}
+
+	// Validation
+	if estimateReq.Audience == nil {
+		return nil, services.MissingFieldError("audience")
+	}
+
+	err := s.authorizeAudience(ctx, estimateReq.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	db, release, err := s.audience(ctx, estimateReq.Audience, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	estimate := &messaging.AudienceEstimate{}
+
+	err = db.Count(&estimate.Recipients).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count recipients: %v", err)
+	}
+
+	err = pushRecipients(db).Count(&estimate.PushRecipients).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count push recipients: %v", err)
+	}
+
+	return estimate, nil
+}
+
+// pushRecipientsCondition matches users with a registered device, or the device token they signed up with
+var pushRecipientsCondition = fmt.Sprintf(
+	"phone_number IN (SELECT phone_number FROM %s WHERE deleted_at IS NULL) OR device_token NOT IN(?)",
+	services.UserDevicesTable,
+)
+
+// pushRecipients narrows the query to users reached by push notifications
+func pushRecipients(db *gorm.DB) *gorm.DB {
+	return db.Where(pushRecipientsCondition, []string{"", noDeviceToken})
+}
+
+// otherRecipients narrows the query to users not reached by push notifications
+func otherRecipients(db *gorm.DB) *gorm.DB {
+	return db.Where("NOT ("+pushRecipientsCondition+")", []string{"", noDeviceToken})
+}
+
+// visitorsFunc saves the phone numbers of users seen in any of the geo fences since a time,
+// returning the audience id they are saved with in the audience visitors table
+type visitorsFunc func(fenceIDs []string, seenSince time.Time) (string, error)
+
+// noVisitors is used when audiences are validated without resolving geo fences
+func noVisitors([]string, time.Time) (string, error) {
+	return "", nil
+}
+
+// hasGeoFences reports whether the audience or any audience nested in it targets users seen in geo fences
+func hasGeoFences(audiencePB *messaging.Audience) bool {
+	if audiencePB == nil {
+		return false
+	}
+	if len(audiencePB.GeoFenceIds) > 0 || hasGeoFences(audiencePB.Exclude) {
+		return true
+	}
+	for _, alternativePB := range audiencePB.AnyOf {
+		if hasGeoFences(alternativePB) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeAudience requires callers targeting users seen in geo fences to be health officers or administrators,
+// as the audience reveals who was in the geo fences
+func (s *messagingServer) authorizeAudience(ctx context.Context, audiencePB *messaging.Audience) error {
+	if !hasGeoFences(audiencePB) {
+		return nil
+	}
+	return s.authorizeGroups(ctx, auth.AdminGroup, auth.HealthOfficerGroup)
+}
+
+// geoFenceVisitors returns a visitorsFunc resolving users seen in geo fences through the location service,
+// with a func deleting the users it saved once the audience is no longer queried.
+//
+// Geo fence visits are keyed by pseudonymous ids that only the location service may map to users. The service
+// is called with the identity of the messaging service, as broadcasts are sent after their callers were authorized.
+// Users are listed a page at a time and saved in a table, as busy geo fences have too many to query at once.
+func (s *messagingServer) geoFenceVisitors(ctx context.Context) (visitorsFunc, func()) {
+	audienceIDs := make([]string, 0)
+
+	visitors := func(fenceIDs []string, seenSince time.Time) (string, error) {
+		if s.locationClient == nil {
+			return "", status.Error(codes.FailedPrecondition, "geo fence audiences require the location service")
+		}
+
+		audienceID := uuid.New().String()
+		audienceIDs = append(audienceIDs, audienceID)
+
+		listReq := &location.ListGeoFenceVisitorsRequest{
+			GeoFenceIds:        fenceIDs,
+			SeenSinceTimestamp: seenSince.Unix(),
+			PageSize:           visitorsPageSize,
+		}
+
+		for {
+			token, err := auth.GenServiceToken(ctx, "messaging", time.Now().Add(time.Minute).Unix())
+			if err != nil {
+				return "", status.Errorf(codes.Internal, "failed to generate token: %v", err)
+			}
+
+			listRes, err := s.locationClient.ListGeoFenceVisitors(
+				metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token), listReq, grpc.WaitForReady(true),
+			)
+			if err != nil {
+				return "", status.Errorf(codes.Unavailable, "failed to get users seen in geo fences: %v", err)
+			}
+
+			err = s.saveAudienceVisitors(audienceID, listRes.PhoneNumbers)
+			if err != nil {
+				return "", status.Errorf(codes.Internal, "failed to save users seen in geo fences: %v", err)
+			}
+
+			if listRes.NextPageToken == "" {
+				return audienceID, nil
+			}
+			listReq.PageToken = listRes.NextPageToken
+		}
+	}
+
+	release := func() {
+		if len(audienceIDs) == 0 {
+			return
+		}
+		err := s.sqlDB.Delete(&services.AudienceVisitor{}, "audience_id IN(?)", audienceIDs).Error
+		if err != nil {
+			s.logger.Errorf("failed to delete users seen in geo fences: %v", err)
+		}
+	}
+
+	return visitors, release
+}
+
+// saveAudienceVisitors saves users seen in geo fences in one statement, skipping users saved before
+func (s *messagingServer) saveAudienceVisitors(audienceID string, phoneNumbers []string) error {
+	if len(phoneNumbers) == 0 {
+		return nil
+	}
+
+	var (
+		now          = time.Now()
+		placeholders = make([]string, 0, len(phoneNumbers))
+		values       = make([]interface{}, 0, 3*len(phoneNumbers))
+	)
+
+	for _, phoneNumber := range phoneNumbers {
+		placeholders = append(placeholders, "(?, ?, ?)")
+		values = append(values, audienceID, phoneNumber, now)
+	}
+
+	return s.sqlDB.Exec(fmt.Sprintf(
+		"INSERT IGNORE INTO %s (audience_id, phone_number, created_at) VALUES %s",
+		services.AudienceVisitorsTable, strings.Join(placeholders, ", "),
+	), values...).Error
+}
+
+// deleteStaleAudienceVisitors deletes users saved for audiences of replicas that stopped before releasing them
+func (s *messagingServer) deleteStaleAudienceVisitors(now time.Time) error {
+	return s.sqlDB.Delete(&services.AudienceVisitor{}, "created_at < ?", now.Add(-audienceVisitorsExpiration)).Error
+}
+
+// audience returns the query for users in the audience, geo fence visits are relative to now.
+//
+// The returned func deletes the users seen in geo fences the query depends on, it is called once the query is done.
+func (s *messagingServer) audience(
+	ctx context.Context, audiencePB *messaging.Audience, now time.Time,
+) (*gorm.DB, func(), error) {
+	visitors, release := s.geoFenceVisitors(ctx)
+
+	query, args, err := audienceCondition(audiencePB, now, 1, visitors)
+	if err != nil {
+		release()
+		if _, ok := status.FromError(err); ok {
+			return nil, nil, err
+		}
+		return nil, nil, status.Errorf(codes.InvalidArgument, "malformed audience: %v", err)
+	}
+
+	db := s.sqlDB.Model(&services.UserModel{})
+	if query != "" {
+		db = db.Where(query, args...)
+	}
+
+	return db, release, nil
+}
+
+// audienceCondition returns the SQL condition users in the audience match, empty when every user matches.
+//
+// Errors resolving users seen in geo fences are status errors, other errors are due to a malformed audience.
+func audienceCondition(
+	audiencePB *messaging.Audience, now time.Time, depth int, visitors visitorsFunc,
+) (string, []interface{}, error) {
+	if depth > maxAudienceDepth {
+		return "", nil, fmt.Errorf("audiences are nested more than %d levels deep", maxAudienceDepth)
+	}
+
+	var (
+		conditions = make([]string, 0)
+		args       = make([]interface{}, 0)
+	)
+
+	where := func(condition string, values ...interface{}) {
+		conditions = append(conditions, "("+condition+")")
+		args = append(args, values...)
+	}
+
+	if len(audiencePB.Statuses) > 0 {
+		statuses := make([]int32, 0, len(audiencePB.Statuses))
+		for _, name := range audiencePB.Statuses {
+			value, ok := location.Status_value[strings.ToUpper(name)]
+			if !ok {
+				return "", nil, fmt.Errorf("unknown status %q", name)
+			}
+			statuses = append(statuses, value)
+		}
+		where("status IN(?)", statuses)
+	}
+
+	if len(audiencePB.Counties) > 0 {
+		where("county IN(?)", audiencePB.Counties)
+	}
+
+	if len(audiencePB.Wards) > 0 {
+		where("ward IN(?)", audiencePB.Wards)
+	}
+
+	switch {
+	case len(audiencePB.GeoFenceIds) > 0:
+		seenSince := now.Add(-defaultSeenWindow)
+		if audiencePB.SeenSinceTimestamp != 0 {
+			seenSince = time.Unix(audiencePB.SeenSinceTimestamp, 0)
+		}
+		audienceID, err := visitors(audiencePB.GeoFenceIds, seenSince)
+		if err != nil {
+			return "", nil, err
+		}
+		where(fmt.Sprintf(
+			"phone_number IN (SELECT phone_number FROM %s WHERE audience_id = ?)", services.AudienceVisitorsTable,
+		), audienceID)
+	case audiencePB.SeenSinceTimestamp != 0:
+		return "", nil, fmt.Errorf("seen since timestamp requires geo fence ids")
+	}
+
+	switch audiencePB.Quarantine {
+	case messaging.QuarantineState_QUARANTINE_ANY:
+	case messaging.QuarantineState_QUARANTINED:
+		where("`group` = ?", services.QuarantineGroup)
+	case messaging.QuarantineState_NOT_QUARANTINED:
+		where("`group` <> ?", services.QuarantineGroup)
+	default:
+		return "", nil, fmt.Errorf("unknown quarantine state %d", audiencePB.Quarantine)
+	}
+
+	if len(audiencePB.Languages) > 0 {
+		for _, language := range audiencePB.Languages {
+			if !services.ValidLanguage(language) {
+				return "", nil, fmt.Errorf("unknown language %q", language)
+			}
+		}
+		where("preferred_language IN(?)", audiencePB.Languages)
+	}
+
+	if audiencePB.MinAppVersion != "" {
+		version, err := getAppVersionNumber(audiencePB.MinAppVersion)
+		if err != nil {
+			return "", nil, err
+		}
+		where(fmt.Sprintf(
+			"phone_number IN (SELECT phone_number FROM %s WHERE deleted_at IS NULL AND app_version <> '' AND %s >= ?)",
+			services.UserDevicesTable, appVersionNumber,
+		), version)
+	}
+
+	if len(audiencePB.AnyOf) > 0 {
+		alternatives := make([]string, 0, len(audiencePB.AnyOf))
+		alternativeArgs := make([]interface{}, 0)
+		for _, alternativePB := range audiencePB.AnyOf {
+			if alternativePB == nil {
+				return "", nil, fmt.Errorf("missing audience in any of")
+			}
+			query, values, err := audienceCondition(alternativePB, now, depth+1, visitors)
+			if err != nil {
+				return "", nil, err
+			}
+			if query == "" {
+				// An alternative matching every user matches every user
+				alternatives = nil
+				break
+			}
+			alternatives = append(alternatives, "("+query+")")
+			alternativeArgs = append(alternativeArgs, values...)
+		}
+		if len(alternatives) > 0 {
+			where(strings.Join(alternatives, " OR "), alternativeArgs...)
+		}
+	}
+
+	if audiencePB.Exclude != nil {
+		query, values, err := audienceCondition(audiencePB.Exclude, now, depth+1, visitors)
+		if err != nil {
+			return "", nil, err
+		}
+		if query == "" {
+			return "", nil, fmt.Errorf("audience to exclude matches every user")
+		}
+		where("NOT ("+query+")", values...)
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// getAppVersionNumber converts an app version such as 1.4.2 to a number, versions may have at most three parts below 1000
+func getAppVersionNumber(version string) (int64, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("malformed app version %q", version)
+	}
+
+	var number int64
+	for i := 0; i < 3; i++ {
+		number *= 1000
+		if i >= len(parts) {
+			continue
+		}
+		part, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil || part < 0 || part >= 1000 {
+			return 0, fmt.Errorf("malformed app version %q", version)
+		}
+		number += part
+	}
+
+	return number, nil
+}
+
+// filtersAudience returns the audience of a broadcast sent with filters, counties are given as topics
+func filtersAudience(filters []messaging.BroadCastMessageFilter, topics []string) *messaging.Audience {
+	audiencePB := &messaging.Audience{}
+	for _, filter := range filters {
+		switch filter {
+		case messaging.BroadCastMessageFilter_ALL:
+		case messaging.BroadCastMessageFilter_POSITIVES:
+			audiencePB.Statuses = append(audiencePB.Statuses, location.Status_POSITIVE.String())
+		case messaging.BroadCastMessageFilter_NEGATIVES:
+			audiencePB.Statuses = append(audiencePB.Statuses, location.Status_NEGATIVE.String())
+		case messaging.BroadCastMessageFilter_BY_COUNTY:
+			audiencePB.Counties = topics
+		}
+	}
+	return audiencePB
+}
+
+// broadcastAudience returns the audience a broadcast is sent to
+func broadcastAudience(broadcastPB *messaging.Broadcast) *messaging.Audience {
+	if broadcastPB.Audience != nil {
+		return broadcastPB.Audience
+	}
+	return filtersAudience(broadcastPB.Filters, broadcastPB.Topics)
+}
+
+func marshalAudience(audiencePB *messaging.Audience) ([]byte, error) {
+	if audiencePB == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(audiencePB)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json marshal audience: %v", err)
+	}
+	return data, nil
+}
+
+func unmarshalAudience(data []byte) (*messaging.Audience, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	audiencePB := &messaging.Audience{}
+	err := json.Unmarshal(data, audiencePB)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json unmarshal audience: %v", err)
+	}
+	return audiencePB, nil
+}
@@ -0,0 +1,290 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallinder/go-randomdata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/auth"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+var _ = Describe("Targeting broadcast audiences £audience", func() {
+	var (
+		estimateReq *messaging.EstimateAudienceRequest
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		estimateReq = &messaging.EstimateAudienceRequest{
+			Audience: &messaging.Audience{},
+		}
+		ctx = context.Background()
+	})
+
+	Describe("Estimating audience with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, nil)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(estimateRes).Should(BeNil())
+		})
+		It("should fail when audience is missing", func() {
+			estimateReq.Audience = nil
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, estimateReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(estimateRes).Should(BeNil())
+		})
+		It("should fail when status is unknown", func() {
+			estimateReq.Audience.Statuses = []string{"ISOLATED"}
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, estimateReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(estimateRes).Should(BeNil())
+		})
+		It("should fail when language is unknown", func() {
+			estimateReq.Audience.Languages = []string{"fr"}
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, estimateReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(estimateRes).Should(BeNil())
+		})
+		It("should fail when app version is malformed", func() {
+			estimateReq.Audience.MinAppVersion = "1.x"
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, estimateReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(estimateRes).Should(BeNil())
+		})
+		It("should fail when seen since timestamp is set without geo fences", func() {
+			estimateReq.Audience.SeenSinceTimestamp = time.Now().Add(-time.Hour).Unix()
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, estimateReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(estimateRes).Should(BeNil())
+		})
+		It("should fail when the audience to exclude matches every user", func() {
+			estimateReq.Audience.Exclude = &messaging.Audience{}
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, estimateReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(estimateRes).Should(BeNil())
+		})
+		It("should fail when audiences are nested too deep", func() {
+			audiencePB := estimateReq.Audience
+			for i := 0; i < maxAudienceDepth; i++ {
+				audiencePB.AnyOf = []*messaging.Audience{{Counties: []string{"Nairobi"}}}
+				audiencePB = audiencePB.AnyOf[0]
+			}
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, estimateReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(estimateRes).Should(BeNil())
+		})
+	})
+
+	Describe("Estimating audience with well-formed request", func() {
+		var county, fenceID string
+
+		addUser := func(
+			statusPB location.Status, ward, language, group, appVersion string, seenAgo time.Duration,
+		) {
+			userDB := &services.UserModel{
+				PhoneNumber:       randomPhone(),
+				FullName:          randomdata.FullName(randomdata.RandomGender),
+				County:            county,
+				Ward:              ward,
+				Status:            int8(statusPB),
+				Group:             group,
+				PreferredLanguage: language,
+			}
+			Expect(MessagingServer.sqlDB.Create(userDB).Error).ShouldNot(HaveOccurred())
+
+			if appVersion != "" {
+				err := MessagingServer.sqlDB.Create(&services.UserDevice{
+					PhoneNumber: userDB.PhoneNumber,
+					DeviceToken: randomdata.RandStringRunes(64),
+					AppVersion:  appVersion,
+					LastSeenAt:  time.Now(),
+				}).Error
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+
+			if seenAgo != 0 {
+				if geoFenceVisits[fenceID] == nil {
+					geoFenceVisits[fenceID] = make(map[string]time.Time)
+				}
+				geoFenceVisits[fenceID][userDB.PhoneNumber] = time.Now().Add(-seenAgo)
+			}
+		}
+
+		estimateFn := func(audiencePB *messaging.Audience) *messaging.AudienceEstimate {
+			audiencePB.Counties = []string{county}
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, &messaging.EstimateAudienceRequest{
+				Audience: audiencePB,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			return estimateRes
+		}
+
+		BeforeEach(func() {
+			county = randomdata.RandStringRunes(20)
+			fenceID = randomdata.RandStringRunes(20)
+
+			addUser(location.Status_POSITIVE, "Kileleshwa", services.LanguageSwahili, services.QuarantineGroup, "1.4.2", time.Hour)
+			addUser(location.Status_SUSPECTED, "Kileleshwa", services.LanguageEnglish, "", "1.10.0", 0)
+			addUser(location.Status_RECOVERED, "Kilimani", services.LanguageEnglish, "", "", 20*24*time.Hour)
+			addUser(location.Status_NEGATIVE, "Kilimani", services.LanguageSheng, "", "", 0)
+		})
+
+		It("should count users in the counties and those reached by push notifications", func() {
+			estimateRes := estimateFn(&messaging.Audience{})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(4))
+			Expect(estimateRes.PushRecipients).Should(BeEquivalentTo(2))
+		})
+
+		It("should count users with any of the statuses", func() {
+			estimateRes := estimateFn(&messaging.Audience{Statuses: []string{"SUSPECTED", "RECOVERED"}})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(2))
+		})
+
+		It("should count users in the ward that are not in quarantine", func() {
+			estimateRes := estimateFn(&messaging.Audience{
+				Wards:      []string{"Kileleshwa"},
+				Quarantine: messaging.QuarantineState_NOT_QUARANTINED,
+			})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(1))
+		})
+
+		It("should count users by language", func() {
+			estimateRes := estimateFn(&messaging.Audience{Languages: []string{services.LanguageEnglish}})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(2))
+		})
+
+		It("should compare app versions by number", func() {
+			estimateRes := estimateFn(&messaging.Audience{MinAppVersion: "1.5"})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(1))
+		})
+
+		It("should count users seen in the geo fence", func() {
+			estimateRes := estimateFn(&messaging.Audience{GeoFenceIds: []string{fenceID}})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(1))
+
+			estimateRes = estimateFn(&messaging.Audience{
+				GeoFenceIds:        []string{fenceID},
+				SeenSinceTimestamp: time.Now().Add(-30 * 24 * time.Hour).Unix(),
+			})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(2))
+
+			estimateRes = estimateFn(&messaging.Audience{GeoFenceIds: []string{randomdata.RandStringRunes(20)}})
+			Expect(estimateRes.Recipients).Should(BeZero())
+
+			// The location service is called as a service, not as an administrator
+			Expect(geoFenceVisitorsGroup).Should(Equal(auth.ServiceGroup))
+		})
+
+		It("should count users seen in the geo fence over several pages", func() {
+			pageSize := visitorsPageSize
+			defer func() {
+				visitorsPageSize = pageSize
+			}()
+			visitorsPageSize = 1
+
+			addUser(location.Status_POSITIVE, "Kilimani", services.LanguageEnglish, "", "", 2*time.Hour)
+			addUser(location.Status_NEGATIVE, "Kilimani", services.LanguageEnglish, "", "", 3*time.Hour)
+
+			geoFenceVisitorsPages = 0
+			estimateRes := estimateFn(&messaging.Audience{GeoFenceIds: []string{fenceID}})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(3))
+			Expect(geoFenceVisitorsPages).Should(BeNumerically(">=", 3))
+
+			// Users saved for the audience are deleted once it is counted
+			var count int
+			err := MessagingServer.sqlDB.Model(&services.AudienceVisitor{}).
+				Where("phone_number IN (SELECT phone_number FROM users WHERE county = ?)", county).
+				Count(&count).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(count).Should(BeZero())
+		})
+
+		It("should fail to count users seen in the geo fence when the caller is not a health officer", func() {
+			authorizeGroups := MessagingServer.authorizeGroups
+			defer func() {
+				MessagingServer.authorizeGroups = authorizeGroups
+			}()
+
+			MessagingServer.authorizeGroups = func(context.Context, ...string) error {
+				return status.Error(codes.PermissionDenied, "permission denied")
+			}
+
+			estimateRes, err := MessagingAPI.EstimateAudience(ctx, &messaging.EstimateAudienceRequest{
+				Audience: &messaging.Audience{
+					AnyOf: []*messaging.Audience{{Counties: []string{"Nairobi"}}, {GeoFenceIds: []string{fenceID}}},
+				},
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.PermissionDenied))
+			Expect(estimateRes).Should(BeNil())
+
+			// Audiences without geo fences reveal no visits
+			estimateRes, err = MessagingAPI.EstimateAudience(ctx, &messaging.EstimateAudienceRequest{
+				Audience: &messaging.Audience{Counties: []string{"Nairobi"}},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(estimateRes).ShouldNot(BeNil())
+
+			sendRes, err := MessagingAPI.BroadCastMessage(ctx, &messaging.BroadCastMessageRequest{
+				Title:    "Visitors",
+				Message:  randomdata.Paragraph(),
+				Payload:  map[string]string{},
+				Audience: &messaging.Audience{GeoFenceIds: []string{fenceID}},
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.PermissionDenied))
+			Expect(sendRes).Should(BeNil())
+		})
+
+		It("should count users matching any of the audiences", func() {
+			estimateRes := estimateFn(&messaging.Audience{
+				AnyOf: []*messaging.Audience{
+					{Statuses: []string{"POSITIVE"}},
+					{Wards: []string{"Kilimani"}},
+				},
+			})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(3))
+		})
+
+		It("should leave out users matching the audience to exclude", func() {
+			estimateRes := estimateFn(&messaging.Audience{
+				Exclude: &messaging.Audience{Quarantine: messaging.QuarantineState_QUARANTINED},
+			})
+			Expect(estimateRes.Recipients).Should(BeEquivalentTo(3))
+		})
+
+		It("should broadcast to the audience", func() {
+			broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, &messaging.BroadCastMessageRequest{
+				Title:   randomdata.Paragraph()[:10],
+				Message: randomdata.Paragraph(),
+				Payload: map[string]string{"topic": "testing"},
+				Audience: &messaging.Audience{
+					Counties: []string{county},
+					Statuses: []string{"SUSPECTED"},
+				},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			getRes, err := MessagingAPI.GetBroadcast(ctx, &messaging.GetBroadcastRequest{
+				BroadcastMessageId: broadCastRes.BroadcastMessageId,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.Recipients).Should(BeEquivalentTo(1))
+			Expect(getRes.Audience.Statuses).Should(Equal([]string{"SUSPECTED"}))
+		})
+	})
+})
@@ -0,0 +1,900 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/appleboy/go-fcm"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/devicetoken"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+// broadcastPageSize is how many users a broadcast is sent to at a time
+const broadcastPageSize = 1000
+
+const (
+	// broadcastLease is how long a running broadcast is left to the replica sending it before another replica resumes it
+	broadcastLease = 2 * time.Minute
+	// resumeInterval is how often broadcasts that are no longer sent by any replica are looked for
+	resumeInterval = time.Minute
+)
+
+var broadcastStatuses = map[string]messaging.BroadcastStatus{
+	services.BroadcastPending:   messaging.BroadcastStatus_BROADCAST_PENDING,
+	services.BroadcastRunning:   messaging.BroadcastStatus_BROADCAST_RUNNING,
+	services.BroadcastCompleted: messaging.BroadcastStatus_BROADCAST_COMPLETED,
+	services.BroadcastCancelled: messaging.BroadcastStatus_BROADCAST_CANCELLED,
+	services.BroadcastFailed:    messaging.BroadcastStatus_BROADCAST_FAILED,
+}
+
+func (s *messagingServer) BroadCastMessage(
+	ctx context.Context, req *messaging.BroadCastMessageRequest,
+) (*messaging.BroadCastMessageResponse, error) {
+	// Request must not be nil
+	if req == nil {
+		return nil, services.NilRequestError("BroadCastMessageRequest")
+	}
+
+	// Validation
+	var err error
+	switch {
+	case req.Title == "":
+		err = services.MissingFieldError("title")
+	case req.Message == "":
+		err = services.MissingFieldError("message")
+	case req.Payload == nil:
+		err = services.MissingFieldError("payload")
+	case req.Audience == nil && len(req.Topics) == 0:
+		err = services.MissingFieldError("topics")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Audience != nil {
+		_, _, err = audienceCondition(req.Audience, time.Now(), 1, noVisitors)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "malformed audience: %v", err)
+		}
+	}
+
+	err = s.authorizeAudience(ctx, req.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scheduled broadcasts are sent by the scheduler
+	if req.Schedule != nil {
+		return s.scheduleBroadcast(req)
+	}
+
+	broadcastDB, err := getBroadcastDB(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.prepareBroadcast(ctx, broadcastDB)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sqlDB.Create(broadcastDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save broadcast: %v", err)
+	}
+
+	go s.runBroadcast(context.Background(), broadcastDB.BroadcastID)
+
+	return &messaging.BroadCastMessageResponse{
+		BroadcastMessageId: broadcastDB.BroadcastID,
+	}, nil
+}
+
+func (s *messagingServer) GetBroadcast(
+	ctx context.Context, getReq *messaging.GetBroadcastRequest,
+) (*messaging.Broadcast, error) {
+	// Request must not be nil
+	if getReq == nil {
+		return nil, services.NilRequestError("GetBroadcastRequest")
+	}
+
+	// Validation
+	if getReq.BroadcastMessageId == "" {
+		return nil, services.MissingFieldError("broadcast message id")
+	}
+
+	return s.getBroadcast(getReq.BroadcastMessageId)
+}
+
+func (s *messagingServer) ListBroadcasts(
+	ctx context.Context, listReq *messaging.ListBroadcastsRequest,
+) (*messaging.Broadcasts, error) {
+	// Request must not be nil
+	if listReq == nil {
+		return nil, services.NilRequestError("ListBroadcastsRequest")
+	}
+
+	// Normalize page
+	pageNumber, pageSize := normalizePage(listReq.GetPageToken(), listReq.GetPageSize())
+	offset := pageNumber*pageSize - pageSize
+
+	db := s.sqlDB.Order("id DESC").Offset(offset).Limit(pageSize)
+
+	if len(listReq.FilterStatus) > 0 {
+		statuses := make([]string, 0, len(listReq.FilterStatus))
+		for name, broadcastStatus := range broadcastStatuses {
+			for _, filterStatus := range listReq.FilterStatus {
+				if broadcastStatus == filterStatus {
+					statuses = append(statuses, name)
+				}
+			}
+		}
+		db = db.Where("status IN(?)", statuses)
+	}
+
+	broadcastsDB := make([]*services.Broadcast, 0, pageSize)
+	err := db.Find(&broadcastsDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get broadcasts: %v", err)
+	}
+
+	broadcastsPB := make([]*messaging.Broadcast, 0, len(broadcastsDB))
+	for _, broadcastDB := range broadcastsDB {
+		broadcastPB, err := getBroadcastPB(broadcastDB)
+		if err != nil {
+			return nil, err
+		}
+		broadcastsPB = append(broadcastsPB, broadcastPB)
+	}
+
+	broadcastsRes := &messaging.Broadcasts{
+		Broadcasts: broadcastsPB,
+	}
+	if len(broadcastsDB) == pageSize {
+		broadcastsRes.NextPageToken = int32(pageNumber + 1)
+	}
+
+	return broadcastsRes, nil
+}
+
+func (s *messagingServer) CancelBroadcast(
+	ctx context.Context, cancelReq *messaging.CancelBroadcastRequest,
+) (*messaging.Broadcast, error) {
+	// Request must not be nil
+	if cancelReq == nil {
+		return nil, services.NilRequestError("CancelBroadcastRequest")
+	}
+
+	// Validation
+	if cancelReq.BroadcastMessageId == "" {
+		return nil, services.MissingFieldError("broadcast message id")
+	}
+
+	// The broadcast stops before the next page of users
+	db := s.sqlDB.Model(&services.Broadcast{}).
+		Where("broadcast_id = ? AND status IN(?)", cancelReq.BroadcastMessageId, []string{
+			services.BroadcastPending, services.BroadcastRunning,
+		}).
+		Updates(map[string]interface{}{
+			"status":      services.BroadcastCancelled,
+			"finished_at": time.Now(),
+		})
+	if db.Error != nil {
+		return nil, status.Errorf(codes.Internal, "failed to cancel broadcast: %v", db.Error)
+	}
+
+	broadcastPB, err := s.getBroadcast(cancelReq.BroadcastMessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	if db.RowsAffected == 0 && broadcastPB.Status != messaging.BroadcastStatus_BROADCAST_CANCELLED {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, "broadcast with id %s has already finished", cancelReq.BroadcastMessageId,
+		)
+	}
+
+	return broadcastPB, nil
+}
+
+func (s *messagingServer) getBroadcast(broadcastID string) (*messaging.Broadcast, error) {
+	broadcastDB := &services.Broadcast{}
+	err := s.sqlDB.First(broadcastDB, "broadcast_id=?", broadcastID).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "broadcast with id %s not found", broadcastID)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get broadcast: %v", err)
+	}
+
+	broadcastPB, err := getBroadcastPB(broadcastDB)
+	if err != nil {
+		return nil, err
+	}
+
+	batchesDB := make([]*services.BroadcastBatch, 0)
+	err = s.sqlDB.Order("id").Find(&batchesDB, "broadcast_id=?", broadcastID).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get broadcast batches: %v", err)
+	}
+
+	broadcastPB.Batches = make([]*messaging.BroadcastBatch, 0, len(batchesDB))
+	for _, batchDB := range batchesDB {
+		broadcastPB.Batches = append(broadcastPB.Batches, &messaging.BroadcastBatch{
+			Tokens:       int32(batchDB.Tokens),
+			Success:      int32(batchDB.Success),
+			Failure:      int32(batchDB.Failure),
+			CanonicalIds: int32(batchDB.CanonicalIDs),
+			MulticastId:  batchDB.MulticastID,
+			Error:        batchDB.Error,
+			Timestamp:    batchDB.CreatedAt.Unix(),
+		})
+	}
+
+	return broadcastPB, nil
+}
+
+// prepareBroadcast assigns a pending broadcast its id and counts its recipients
+func (s *messagingServer) prepareBroadcast(ctx context.Context, broadcastDB *services.Broadcast) error {
+	broadcastPB, err := getBroadcastPB(broadcastDB)
+	if err != nil {
+		return err
+	}
+
+	// Broadcast message id
+	broadcastDB.BroadcastID = uuid.New().String()
+	broadcastDB.Status = services.BroadcastPending
+
+	db, release, err := s.audience(ctx, broadcastAudience(broadcastPB), time.Now())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	err = db.Count(&broadcastDB.Recipients).Error
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to count broadcast recipients: %v", err)
+	}
+
+	return nil
+}
+
+// runBroadcast sends a pending broadcast, recording why it failed
+func (s *messagingServer) runBroadcast(ctx context.Context, broadcastID string) {
+	err := s.sendBroadcast(ctx, broadcastID)
+	if err == nil {
+		return
+	}
+
+	s.logger.Errorf("failed to send broadcast %s: %v", broadcastID, err)
+
+	err = s.sqlDB.Model(&services.Broadcast{}).
+		Where("broadcast_id = ? AND status = ?", broadcastID, services.BroadcastRunning).
+		Updates(map[string]interface{}{
+			"status":      services.BroadcastFailed,
+			"last_error":  truncate(err.Error(), 256),
+			"finished_at": time.Now(),
+		}).Error
+	if err != nil {
+		s.logger.Errorf("failed to update broadcast %s: %v", broadcastID, err)
+	}
+}
+
+// resumeWorker resumes broadcasts that are no longer sent by any replica until the context is cancelled
+func (s *messagingServer) resumeWorker(ctx context.Context) {
+	ticker := time.NewTicker(resumeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			err := s.resumeBroadcasts(ctx, now)
+			if err != nil {
+				s.logger.Errorf("failed to resume broadcasts: %v", err)
+			}
+			err = s.deleteStaleAudienceVisitors(now)
+			if err != nil {
+				s.logger.Errorf("failed to delete stale users seen in geo fences: %v", err)
+			}
+		}
+	}
+}
+
+// resumeBroadcasts resumes pending broadcasts that were never started and running broadcasts whose lease expired,
+// such as those of a replica that restarted. They continue after the last user they were sent to.
+func (s *messagingServer) resumeBroadcasts(ctx context.Context, now time.Time) error {
+	broadcastsDB := make([]*services.Broadcast, 0)
+	err := s.sqlDB.Select("broadcast_id").
+		Where("(status = ? AND created_at < ?) OR (status = ? AND (lease_until IS NULL OR lease_until < ?))",
+			services.BroadcastPending, now.Add(-broadcastLease), services.BroadcastRunning, now).
+		Order("id").Find(&broadcastsDB).Error
+	if err != nil {
+		return err
+	}
+
+	for _, broadcastDB := range broadcastsDB {
+		go s.runBroadcast(ctx, broadcastDB.BroadcastID)
+	}
+
+	return nil
+}
+
+// claimBroadcast takes the lease of a pending broadcast or of a running broadcast whose lease expired,
+// returning when the lease ends or nil when the broadcast is sent by another replica
+func (s *messagingServer) claimBroadcast(broadcastID string) (*time.Time, error) {
+	var (
+		now = time.Now()
+		// Lease ends are compared by the second they are saved with
+		leaseEnd = now.Add(broadcastLease).Truncate(time.Second)
+	)
+
+	db := s.sqlDB.Model(&services.Broadcast{}).
+		Where("broadcast_id = ? AND (status = ? OR (status = ? AND (lease_until IS NULL OR lease_until < ?)))",
+			broadcastID, services.BroadcastPending, services.BroadcastRunning, now).
+		Updates(map[string]interface{}{
+			"status":      services.BroadcastRunning,
+			"started_at":  gorm.Expr("COALESCE(started_at, ?)", now),
+			"lease_until": leaseEnd,
+		})
+	if db.Error != nil {
+		return nil, db.Error
+	}
+	if db.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &leaseEnd, nil
+}
+
+// renewBroadcastLease extends the lease of a running broadcast once half of it has passed.
+// It returns nil when the broadcast was cancelled or resumed by another replica.
+func (s *messagingServer) renewBroadcastLease(broadcastID uint, leaseEnd *time.Time) (*time.Time, error) {
+	currentDB := &services.Broadcast{}
+	err := s.sqlDB.Select("status, lease_until").First(currentDB, "id=?", broadcastID).Error
+	if err != nil {
+		return nil, err
+	}
+	if currentDB.Status != services.BroadcastRunning || currentDB.LeaseUntil == nil || !currentDB.LeaseUntil.Equal(*leaseEnd) {
+		return nil, nil
+	}
+
+	if time.Until(*leaseEnd) > broadcastLease/2 {
+		return leaseEnd, nil
+	}
+
+	nextLeaseEnd := time.Now().Add(broadcastLease).Truncate(time.Second)
+
+	db := s.sqlDB.Model(&services.Broadcast{}).
+		Where("id = ? AND status = ? AND lease_until = ?", broadcastID, services.BroadcastRunning, *leaseEnd).
+		UpdateColumn("lease_until", nextLeaseEnd)
+	if db.Error != nil {
+		return nil, db.Error
+	}
+	if db.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &nextLeaseEnd, nil
+}
+
+func (s *messagingServer) sendBroadcast(ctx context.Context, broadcastID string) error {
+	// Only one worker sends a broadcast
+	leaseEnd, err := s.claimBroadcast(broadcastID)
+	if err != nil {
+		return err
+	}
+	if leaseEnd == nil {
+		return nil
+	}
+
+	broadcastDB := &services.Broadcast{}
+	err = s.sqlDB.First(broadcastDB, "broadcast_id=?", broadcastID).Error
+	if err != nil {
+		return err
+	}
+
+	broadcastPB, err := getBroadcastPB(broadcastDB)
+	if err != nil {
+		return err
+	}
+
+	// Geo fence visits are relative to when the broadcast was created, as when its recipients were counted
+	audiencePB := broadcastAudience(broadcastPB)
+	audienceDB, release, err := s.audience(ctx, audiencePB, broadcastDB.CreatedAt)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// FCM payload
+	payload := map[string]interface{}{}
+	for key, value := range broadcastPB.Payload {
+		payload[key] = value
+	}
+
+	notification := &Notification{
+		Title: broadcastDB.Title,
+		Body:  broadcastDB.Message,
+		Data:  payload,
+	}
+
+	// Resumed broadcasts were pushed to their topic before
+	topic := broadcastDB.Topic
+	if topic == "" {
+		topic, err = s.broadcastTopic(audiencePB, audienceDB)
+		if err != nil {
+			return err
+		}
+		if topic != "" {
+			err = s.sendTopicBroadcast(ctx, broadcastDB, notification, topic, audienceDB)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if topic != "" {
+		// Users without push notifications are still sent the broadcast one by one
+		audienceDB = otherRecipients(audienceDB)
+	}
+
+	for {
+		// Cancelled broadcasts and those resumed by another replica stop before the next page
+		leaseEnd, err = s.renewBroadcastLease(broadcastDB.ID, leaseEnd)
+		if err != nil {
+			return err
+		}
+		if leaseEnd == nil {
+			return nil
+		}
+
+		usersDB := make([]*services.UserModel, 0, broadcastPageSize)
+		err = audienceDB.Select("id, "+recipientColumns).
+			Where("id > ?", broadcastDB.LastUserID).Order("id").Limit(broadcastPageSize).
+			Find(&usersDB).Error
+		if err != nil {
+			return fmt.Errorf("failed to get broadcast recipients: %v", err)
+		}
+
+		if len(usersDB) > 0 {
+			leaseEnd, err = s.sendBroadcastPage(ctx, broadcastDB, notification, usersDB, leaseEnd)
+			if err != nil {
+				return err
+			}
+			if leaseEnd == nil {
+				return nil
+			}
+			broadcastDB.LastUserID = usersDB[len(usersDB)-1].ID
+		}
+
+		if len(usersDB) < broadcastPageSize {
+			break
+		}
+	}
+
+	return s.sqlDB.Model(&services.Broadcast{}).
+		Where("id = ? AND status = ?", broadcastDB.ID, services.BroadcastRunning).
+		Updates(map[string]interface{}{
+			"status":      services.BroadcastCompleted,
+			"finished_at": time.Now(),
+		}).Error
+}
+
+// broadcastResult is the outcome of sending a broadcast to a user
+type broadcastResult struct {
+	recipient *Recipient
+	channel   string
+	attempts  []*services.DeliveryAttempt
+	err       error
+}
+
+// sendBroadcastPage sends the broadcast to a page of users and saves their messages with the progress of the broadcast.
+//
+// The lease of the broadcast is renewed while users are sent the broadcast one by one. It returns when the lease ends,
+// or nil when the broadcast was cancelled or resumed by another replica, in which case no more users are sent it.
+func (s *messagingServer) sendBroadcastPage(
+	ctx context.Context,
+	broadcastDB *services.Broadcast,
+	notification *Notification,
+	usersDB []*services.UserModel,
+	leaseEnd *time.Time,
+) (*time.Time, error) {
+	phoneNumbers := make([]string, 0, len(usersDB))
+	for _, userDB := range usersDB {
+		phoneNumbers = append(phoneNumbers, userDB.PhoneNumber)
+	}
+
+	userDevices, err := s.getDeviceTokens(phoneNumbers...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user devices: %v", err)
+	}
+
+	// Progress is saved only while the lease last held is not taken by another replica
+	heldLeaseEnd := *leaseEnd
+
+	holdLease := func() (bool, error) {
+		if leaseEnd == nil {
+			return false, nil
+		}
+		nextLeaseEnd, err := s.renewBroadcastLease(broadcastDB.ID, leaseEnd)
+		if err != nil {
+			return false, fmt.Errorf("failed to renew broadcast lease: %v", err)
+		}
+		leaseEnd = nextLeaseEnd
+		if leaseEnd == nil {
+			return false, nil
+		}
+		heldLeaseEnd = *leaseEnd
+		return true, nil
+	}
+
+	var (
+		results = make([]*broadcastResult, 0, len(usersDB))
+		// Users notified through push notifications are sent multicast messages
+		pushRecipients = make([]*Recipient, 0, len(usersDB))
+		deviceTokens   = make([]string, 0, len(usersDB))
+		batchesDB      = make([]*services.BroadcastBatch, 0)
+	)
+
+	for _, userDB := range usersDB {
+		recipient := getRecipient(userDB, userDevices[userDB.PhoneNumber])
+		if s.firstChannel(recipient) == ChannelPush {
+			pushRecipients = append(pushRecipients, recipient)
+			deviceTokens = append(deviceTokens, recipient.DeviceTokens...)
+			continue
+		}
+
+		held, err := holdLease()
+		if err != nil {
+			return nil, err
+		}
+		if !held {
+			break
+		}
+
+		channel, attempts, err := s.deliver(ctx, recipient, notification)
+		if err != nil {
+			s.logger.Errorf("failed to send broadcast message to %s: %v", recipient.PhoneNumber, err)
+		}
+		results = append(results, &broadcastResult{recipient, channel, attempts, err})
+	}
+
+	held, err := holdLease()
+	if err != nil {
+		return nil, err
+	}
+
+	if held && len(deviceTokens) > 0 {
+		// Send message to devices
+		fcmResults := multicast(s.fcmClient, &fcm.Message{
+			Data: notification.Data,
+			Notification: &fcm.Notification{
+				Title: notification.Title,
+				Body:  notification.Body,
+			},
+		}, deviceTokens, func(tokens []string, res *fcm.Response, err error) {
+			batchDB := &services.BroadcastBatch{
+				BroadcastID: broadcastDB.BroadcastID,
+				Tokens:      len(tokens),
+			}
+			if res != nil {
+				batchDB.Success = res.Success
+				batchDB.Failure = res.Failure
+				batchDB.CanonicalIDs = res.CanonicalIDs
+				batchDB.MulticastID = res.MulticastID
+			}
+			if err != nil {
+				s.logger.Errorf("failed to send broadcast %s to devices: %v", broadcastDB.BroadcastID, err)
+				batchDB.Failure = len(tokens)
+				batchDB.Error = truncate(err.Error(), 256)
+			}
+			batchesDB = append(batchesDB, batchDB)
+		})
+
+		// Invalid tokens are cleared so that they are not sent to again
+		s.updateDeviceTokens(devicetoken.FromResponse(deviceTokens, &fcm.Response{Results: fcmResults}))
+
+		// Users whose push notification failed on all their devices fall back to their other channels
+		first := 0
+		for _, recipient := range pushRecipients {
+			last := first + len(recipient.DeviceTokens)
+			receipt, errDeliver := getDevicesReceipt(recipient.DeviceTokens, fcmResults[first:last])
+			first = last
+
+			channel := ChannelPush
+			attempts := []*services.DeliveryAttempt{getAttempt(ChannelPush, receipt, errDeliver)}
+
+			held, err := holdLease()
+			if err != nil {
+				return nil, err
+			}
+
+			if errDeliver != nil && held {
+				var fallbackAttempts []*services.DeliveryAttempt
+				channel, fallbackAttempts, errDeliver = s.deliver(ctx, recipient, notification, ChannelPush)
+				if errDeliver != nil {
+					s.logger.Errorf("failed to send broadcast message to %s: %v", recipient.PhoneNumber, errDeliver)
+				}
+				attempts = append(attempts, fallbackAttempts...)
+			}
+
+			results = append(results, &broadcastResult{recipient, channel, attempts, errDeliver})
+		}
+	}
+
+	// Start transaction
+	tx := s.sqlDB.Begin()
+	defer func() {
+		if err := recover(); err != nil {
+			tx.Rollback()
+		}
+	}()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	// Messages not delivered can still be read in the app or through USSD
+	err = saveBroadcastResults(tx, broadcastDB, results)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to save broadcast messages: %v", err)
+	}
+
+	for _, batchDB := range batchesDB {
+		err = tx.Create(batchDB).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to save broadcast batch: %v", err)
+		}
+	}
+
+	delivered := 0
+	for _, result := range results {
+		if result.err == nil {
+			delivered++
+		}
+	}
+
+	// A replica that resumed the broadcast sends the page again, the progress of this replica is discarded
+	db := tx.Model(&services.Broadcast{}).
+		Where("id = ? AND lease_until = ?", broadcastDB.ID, heldLeaseEnd).
+		Updates(map[string]interface{}{
+			"processed":    gorm.Expr("processed + ?", len(results)),
+			"delivered":    gorm.Expr("delivered + ?", delivered),
+			"failed":       gorm.Expr("failed + ?", len(results)-delivered),
+			"last_user_id": usersDB[len(usersDB)-1].ID,
+		})
+	if db.Error != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update broadcast progress: %v", db.Error)
+	}
+	if db.RowsAffected == 0 {
+		tx.Rollback()
+		s.logger.Warningf("broadcast %s was resumed by another replica", broadcastDB.BroadcastID)
+		return nil, nil
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return nil, err
+	}
+
+	return leaseEnd, nil
+}
+
+// saveBroadcastResults saves the message of each user with its delivery and delivery attempts using bulk inserts.
+// Broadcasts are not retried, deliveries are saved sent or failed.
+func saveBroadcastResults(tx *gorm.DB, broadcastDB *services.Broadcast, results []*broadcastResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	rows := make([][]interface{}, 0, len(results))
+	phoneNumbers := make([]string, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, []interface{}{
+			result.recipient.PhoneNumber, broadcastDB.Title, broadcastDB.Message, broadcastDB.Payload,
+			result.err == nil, broadcastDB.Type, result.channel, broadcastDB.BroadcastID, now, now,
+		})
+		phoneNumbers = append(phoneNumbers, result.recipient.PhoneNumber)
+	}
+
+	err := bulkInsert(tx, services.MessagesTable, []string{
+		"user_phone", "title", "message", "data", "sent", "type", "channel", "broadcast_id", "created_at", "updated_at",
+	}, rows)
+	if err != nil {
+		return err
+	}
+
+	messagesDB := make([]*services.Message, 0, len(results))
+	err = tx.Select("id, user_phone").
+		Find(&messagesDB, "broadcast_id = ? AND user_phone IN(?)", broadcastDB.BroadcastID, phoneNumbers).Error
+	if err != nil {
+		return err
+	}
+
+	messageIDs := make(map[string]uint, len(messagesDB))
+	for _, messageDB := range messagesDB {
+		messageIDs[messageDB.UserPhone] = messageDB.ID
+	}
+
+	rows = rows[:0]
+	ids := make([]uint, 0, len(results))
+	for _, result := range results {
+		var (
+			messageID      = messageIDs[result.recipient.PhoneNumber]
+			deliveryStatus = services.DeliverySent
+			lastError      string
+		)
+		if result.err != nil {
+			deliveryStatus = services.DeliveryFailed
+			lastError = truncate(result.err.Error(), 256)
+		}
+		rows = append(rows, []interface{}{
+			messageID, result.recipient.PhoneNumber, deliveryStatus, now, 1, result.channel, lastError, now, now,
+		})
+		ids = append(ids, messageID)
+	}
+
+	err = bulkInsert(tx, services.DeliveriesTable, []string{
+		"message_id", "user_phone", "status", "next_attempt_at", "attempts", "channel", "last_error", "created_at",
+		"updated_at",
+	}, rows)
+	if err != nil {
+		return err
+	}
+
+	deliveriesDB := make([]*services.Delivery, 0, len(results))
+	err = tx.Select("id, message_id").Find(&deliveriesDB, "message_id IN(?)", ids).Error
+	if err != nil {
+		return err
+	}
+
+	deliveryIDs := make(map[uint]uint, len(deliveriesDB))
+	for _, deliveryDB := range deliveriesDB {
+		deliveryIDs[deliveryDB.MessageID] = deliveryDB.ID
+	}
+
+	rows = rows[:0]
+	for _, result := range results {
+		deliveryID := deliveryIDs[messageIDs[result.recipient.PhoneNumber]]
+		for _, attempt := range result.attempts {
+			rows = append(rows, []interface{}{
+				deliveryID, attempt.Channel, attempt.Delivered, attempt.ProviderMessageID, attempt.ErrorCode,
+				attempt.Error, attempt.CanonicalRegistrationID, now, now,
+			})
+		}
+	}
+
+	return bulkInsert(tx, services.DeliveryAttemptsTable, []string{
+		"delivery_id", "channel", "delivered", "provider_message_id", "error_code", "error",
+		"canonical_registration_id", "created_at", "updated_at",
+	}, rows)
+}
+
+// bulkInsert inserts rows with one statement
+func bulkInsert(tx *gorm.DB, table string, columns []string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var (
+		placeholder  = "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+		placeholders = make([]string, 0, len(rows))
+		values       = make([]interface{}, 0, len(rows)*len(columns))
+	)
+
+	for _, row := range rows {
+		placeholders = append(placeholders, placeholder)
+		values = append(values, row...)
+	}
+
+	return tx.Exec(fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES %s", table, strings.Join(columns, ", "), strings.Join(placeholders, ", "),
+	), values...).Error
+}
+
+func getBroadcastDB(req *messaging.BroadCastMessageRequest) (*services.Broadcast, error) {
+	broadcastDB := &services.Broadcast{
+		Title:   req.Title,
+		Message: req.Message,
+		Type:    int8(req.Type),
+	}
+
+	filters := make([]string, 0, len(req.Filters))
+	for _, filter := range req.Filters {
+		if _, ok := messaging.BroadCastMessageFilter_name[int32(filter)]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown broadcast filter %d", filter)
+		}
+		filters = append(filters, filter.String())
+	}
+	broadcastDB.Filters = strings.Join(filters, ",")
+
+	var err error
+	broadcastDB.Payload, err = json.Marshal(req.Payload)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json marshal payload: %v", err)
+	}
+
+	broadcastDB.Topics, err = json.Marshal(req.Topics)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json marshal topics: %v", err)
+	}
+
+	broadcastDB.Audience, err = marshalAudience(req.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	return broadcastDB, nil
+}
+
+func getBroadcastPB(broadcastDB *services.Broadcast) (*messaging.Broadcast, error) {
+	broadcastPB := &messaging.Broadcast{
+		BroadcastMessageId: broadcastDB.BroadcastID,
+		Title:              broadcastDB.Title,
+		Message:            broadcastDB.Message,
+		Type:               messaging.MessageType(broadcastDB.Type),
+		Status:             broadcastStatuses[broadcastDB.Status],
+		Recipients:         broadcastDB.Recipients,
+		Processed:          broadcastDB.Processed,
+		Delivered:          broadcastDB.Delivered,
+		Failed:             broadcastDB.Failed,
+		LastError:          broadcastDB.LastError,
+		CreatedTimestamp:   broadcastDB.CreatedAt.Unix(),
+		ScheduleId:         broadcastDB.ScheduleID,
+		Topic:              broadcastDB.Topic,
+	}
+
+	if broadcastDB.StartedAt != nil {
+		broadcastPB.StartedTimestamp = broadcastDB.StartedAt.Unix()
+	}
+	if broadcastDB.FinishedAt != nil {
+		broadcastPB.FinishedTimestamp = broadcastDB.FinishedAt.Unix()
+	}
+
+	if broadcastDB.Filters != "" {
+		for _, filter := range strings.Split(broadcastDB.Filters, ",") {
+			broadcastPB.Filters = append(
+				broadcastPB.Filters, messaging.BroadCastMessageFilter(messaging.BroadCastMessageFilter_value[filter]),
+			)
+		}
+	}
+
+	if len(broadcastDB.Payload) != 0 {
+		err := json.Unmarshal(broadcastDB.Payload, &broadcastPB.Payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to json unmarshal payload: %v", err)
+		}
+	}
+
+	if len(broadcastDB.Topics) != 0 {
+		err := json.Unmarshal(broadcastDB.Topics, &broadcastPB.Topics)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to json unmarshal topics: %v", err)
+		}
+	}
+
+	var err error
+	broadcastPB.Audience, err = unmarshalAudience(broadcastDB.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	return broadcastPB, nil
+}
@@ -5,9 +5,11 @@ import (
 	"time"
 
 	"github.com/Pallinder/go-randomdata"
+	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/gidyon/pandemic-api/internal/services"
 	"github.com/gidyon/pandemic-api/pkg/api/messaging"
 )
 
@@ -75,21 +77,218 @@ var _ = Describe("Broadcasting a message to many users £broadcast", func() {
 	})
 
 	Describe("Broadcasting message with a well formed request", func() {
-		var broadCastID string
+		var (
+			county  string
+			userIDs []uint
+		)
 
-		It("should succeed in broadcasting user message", func() {
+		getBroadcast := func(broadcastID string) *messaging.Broadcast {
+			getRes, err := MessagingAPI.GetBroadcast(ctx, &messaging.GetBroadcastRequest{BroadcastMessageId: broadcastID})
+			Expect(err).ShouldNot(HaveOccurred())
+			return getRes
+		}
+
+		BeforeEach(func() {
+			county = randomdata.RandStringRunes(20)
+			userIDs = make([]uint, 0, 3)
+			for i := 0; i < 3; i++ {
+				userDB := &services.UserModel{
+					PhoneNumber: randomPhone(),
+					FullName:    randomdata.FullName(randomdata.RandomGender),
+					County:      county,
+					DeviceToken: randomdata.MacAddress(),
+				}
+				Expect(MessagingServer.sqlDB.Create(userDB).Error).ShouldNot(HaveOccurred())
+				userIDs = append(userIDs, userDB.ID)
+			}
+			broadCastReq.Filters = []messaging.BroadCastMessageFilter{messaging.BroadCastMessageFilter_BY_COUNTY}
+			broadCastReq.Topics = []string{county}
+		})
+
+		It("should send the broadcast to users matching the filters and track its progress", func() {
 			broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, broadCastReq)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(status.Code(err)).Should(Equal(codes.OK))
 			Expect(broadCastRes).ShouldNot(BeNil())
 			Expect(broadCastRes.BroadcastMessageId).ShouldNot(BeZero())
-			broadCastID = broadCastRes.BroadcastMessageId
+
+			broadcastID := broadCastRes.BroadcastMessageId
+
+			Eventually(func() messaging.BroadcastStatus {
+				return getBroadcast(broadcastID).Status
+			}).Should(Equal(messaging.BroadcastStatus_BROADCAST_COMPLETED))
+
+			broadcastPB := getBroadcast(broadcastID)
+			Expect(broadcastPB.Recipients).Should(BeEquivalentTo(3))
+			Expect(broadcastPB.Processed).Should(BeEquivalentTo(3))
+			Expect(broadcastPB.Delivered).Should(BeEquivalentTo(3))
+			Expect(broadcastPB.Failed).Should(BeZero())
+			Expect(broadcastPB.Topics).Should(Equal([]string{county}))
+			Expect(broadcastPB.Payload).Should(Equal(broadCastReq.Payload))
+			Expect(broadcastPB.Batches).Should(HaveLen(1))
+			Expect(broadcastPB.Batches[0].Tokens).Should(BeEquivalentTo(3))
+
+			// Every user gets the message
+			var messages int
+			err = MessagingServer.sqlDB.Model(&services.Message{}).Where("broadcast_id=?", broadcastID).
+				Count(&messages).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(messages).Should(Equal(3))
+
+			listRes, err := MessagingAPI.ListBroadcasts(ctx, &messaging.ListBroadcastsRequest{
+				FilterStatus: []messaging.BroadcastStatus{messaging.BroadcastStatus_BROADCAST_COMPLETED},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.Broadcasts).ShouldNot(BeEmpty())
+			Expect(listRes.Broadcasts[0].BroadcastMessageId).Should(Equal(broadcastID))
+
+			// Finished broadcasts cannot be cancelled
+			cancelRes, err := MessagingAPI.CancelBroadcast(ctx, &messaging.CancelBroadcastRequest{
+				BroadcastMessageId: broadcastID,
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.FailedPrecondition))
+			Expect(cancelRes).Should(BeNil())
+		})
+
+		It("should not send a broadcast that was cancelled", func() {
+			broadcastDB, err := getBroadcastDB(broadCastReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			broadcastDB.BroadcastID = uuid.New().String()
+			broadcastDB.Status = services.BroadcastPending
+			Expect(MessagingServer.sqlDB.Create(broadcastDB).Error).ShouldNot(HaveOccurred())
+
+			cancelRes, err := MessagingAPI.CancelBroadcast(ctx, &messaging.CancelBroadcastRequest{
+				BroadcastMessageId: broadcastDB.BroadcastID,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cancelRes.Status).Should(Equal(messaging.BroadcastStatus_BROADCAST_CANCELLED))
+
+			MessagingServer.runBroadcast(ctx, broadcastDB.BroadcastID)
+
+			broadcastPB := getBroadcast(broadcastDB.BroadcastID)
+			Expect(broadcastPB.Status).Should(Equal(messaging.BroadcastStatus_BROADCAST_CANCELLED))
+			Expect(broadcastPB.Processed).Should(BeZero())
+		})
+
+		It("should resume a broadcast whose lease expired after the last user it was sent to", func() {
+			var (
+				now        = time.Now()
+				leaseEnded = now.Add(-time.Minute)
+				leaseEnds  = now.Add(time.Hour)
+			)
+
+			broadcastDB, err := getBroadcastDB(broadCastReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			broadcastDB.BroadcastID = uuid.New().String()
+			broadcastDB.Status = services.BroadcastRunning
+			broadcastDB.StartedAt = &leaseEnded
+			broadcastDB.LeaseUntil = &leaseEnded
+			broadcastDB.LastUserID = userIDs[0]
+			Expect(MessagingServer.sqlDB.Create(broadcastDB).Error).ShouldNot(HaveOccurred())
+
+			// Broadcasts with a lease are still being sent
+			sendingDB, err := getBroadcastDB(broadCastReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			sendingDB.BroadcastID = uuid.New().String()
+			sendingDB.Status = services.BroadcastRunning
+			sendingDB.LeaseUntil = &leaseEnds
+			Expect(MessagingServer.sqlDB.Create(sendingDB).Error).ShouldNot(HaveOccurred())
+
+			err = MessagingServer.resumeBroadcasts(ctx, now)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Eventually(func() messaging.BroadcastStatus {
+				return getBroadcast(broadcastDB.BroadcastID).Status
+			}).Should(Equal(messaging.BroadcastStatus_BROADCAST_COMPLETED))
+
+			broadcastPB := getBroadcast(broadcastDB.BroadcastID)
+			Expect(broadcastPB.Processed).Should(BeEquivalentTo(2))
+
+			// Users sent the broadcast before it stopped are not sent it again
+			var messages int
+			err = MessagingServer.sqlDB.Model(&services.Message{}).Where("broadcast_id=?", broadcastDB.BroadcastID).
+				Count(&messages).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(messages).Should(Equal(2))
+
+			Consistently(func() messaging.BroadcastStatus {
+				return getBroadcast(sendingDB.BroadcastID).Status
+			}).Should(Equal(messaging.BroadcastStatus_BROADCAST_RUNNING))
 		})
 
-		Describe("Testing  broadCastMessage method", func() {
-			It("should execute successfully", func() {
-				MessagingServer.broadCastMessage(broadCastReq, broadCastID)
+		It("should not save the progress of a page once another replica resumed the broadcast", func() {
+			var (
+				now             = time.Now().Truncate(time.Second)
+				leaseEnd        = now.Add(time.Minute)
+				resumedLeaseEnd = now.Add(2 * time.Minute)
+			)
+
+			broadcastDB, err := getBroadcastDB(broadCastReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			broadcastDB.BroadcastID = uuid.New().String()
+			broadcastDB.Status = services.BroadcastRunning
+			broadcastDB.LeaseUntil = &resumedLeaseEnd
+			Expect(MessagingServer.sqlDB.Create(broadcastDB).Error).ShouldNot(HaveOccurred())
+
+			usersDB := make([]*services.UserModel, 0, len(userIDs))
+			err = MessagingServer.sqlDB.Select("id, "+recipientColumns).Where("id IN(?)", userIDs).Order("id").
+				Find(&usersDB).Error
+			Expect(err).ShouldNot(HaveOccurred())
+
+			nextLeaseEnd, err := MessagingServer.sendBroadcastPage(ctx, broadcastDB, &Notification{
+				Title: broadcastDB.Title,
+				Body:  broadcastDB.Message,
+			}, usersDB, &leaseEnd)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(nextLeaseEnd).Should(BeNil())
+
+			broadcastPB := getBroadcast(broadcastDB.BroadcastID)
+			Expect(broadcastPB.Status).Should(Equal(messaging.BroadcastStatus_BROADCAST_RUNNING))
+			Expect(broadcastPB.Processed).Should(BeZero())
+
+			var messages int
+			err = MessagingServer.sqlDB.Model(&services.Message{}).Where("broadcast_id=?", broadcastDB.BroadcastID).
+				Count(&messages).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(messages).Should(BeZero())
+		})
+	})
+
+	Describe("Managing broadcasts with malformed request", func() {
+		It("should fail to get a broadcast when the request is nil", func() {
+			getRes, err := MessagingAPI.GetBroadcast(ctx, nil)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail to get a broadcast that does not exist", func() {
+			getRes, err := MessagingAPI.GetBroadcast(ctx, &messaging.GetBroadcastRequest{
+				BroadcastMessageId: uuid.New().String(),
 			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail to list broadcasts when the request is nil", func() {
+			listRes, err := MessagingAPI.ListBroadcasts(ctx, nil)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(listRes).Should(BeNil())
+		})
+		It("should fail to cancel a broadcast when broadcast id is missing", func() {
+			cancelRes, err := MessagingAPI.CancelBroadcast(ctx, &messaging.CancelBroadcastRequest{})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(cancelRes).Should(BeNil())
+		})
+		It("should fail to cancel a broadcast that does not exist", func() {
+			cancelRes, err := MessagingAPI.CancelBroadcast(ctx, &messaging.CancelBroadcastRequest{
+				BroadcastMessageId: uuid.New().String(),
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(cancelRes).Should(BeNil())
 		})
 	})
 })
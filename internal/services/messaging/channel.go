@@ -0,0 +1,176 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gidyon/pandemic-api/internal/devicetoken"
+	"github.com/gidyon/pandemic-api/internal/services"
+)
+
+// Notification channels. USSD sessions can only be started by users, so users of feature phones are sent SMS
+// and read unread messages in the USSD menu served by NewUSSDHandler.
+const (
+	ChannelPush  = "push"
+	ChannelSMS   = "sms"
+	ChannelEmail = "email"
+)
+
+// defaultChannels is the order channels are tried in for users without preferences
+var defaultChannels = []string{ChannelPush, ChannelSMS, ChannelEmail}
+
+// Channel delivers notifications to users
+type Channel interface {
+	// Name returns the name of the channel
+	Name() string
+	// Reaches reports whether the channel has an address for the recipient
+	Reaches(recipient *Recipient) bool
+	// Send delivers the notification to the recipient, returning what the provider reported even when it fails
+	Send(ctx context.Context, recipient *Recipient, notification *Notification) (*Receipt, error)
+}
+
+// Receipt is what a provider reported about a notification
+type Receipt struct {
+	// ProviderMessageID identifies the notification with the provider
+	ProviderMessageID string
+	// ErrorCode is the error code of the provider, such as NotRegistered for FCM
+	ErrorCode string
+	// CanonicalRegistrationID is the token FCM reports the device is registered with when it differs from the one used
+	CanonicalRegistrationID string
+	// TokenUpdates are changes of device tokens of the recipient reported by FCM
+	TokenUpdates []*devicetoken.Update
+}
+
+// getAttempt returns the attempt to deliver a notification through a channel
+func getAttempt(channel string, receipt *Receipt, err error) *services.DeliveryAttempt {
+	attempt := &services.DeliveryAttempt{
+		Channel:   channel,
+		Delivered: err == nil,
+	}
+	if receipt != nil {
+		attempt.ProviderMessageID = truncate(receipt.ProviderMessageID, 100)
+		attempt.ErrorCode = truncate(receipt.ErrorCode, 30)
+		attempt.CanonicalRegistrationID = truncate(receipt.CanonicalRegistrationID, 256)
+	}
+	if err != nil {
+		attempt.Error = truncate(err.Error(), 256)
+	}
+	return attempt
+}
+
+// Recipient is a user receiving a notification
+type Recipient struct {
+	PhoneNumber string
+	FullName    string
+	// DeviceTokens are the tokens of devices of the user, most recently seen first
+	DeviceTokens []string
+	Email        string
+	// Channels are the names of channels preferred by the user in order, empty for the default order
+	Channels []string
+}
+
+// getRecipient returns the recipient for the user and their registered devices.
+// Users who have not registered devices are notified on the device token saved with the user.
+func getRecipient(userDB *services.UserModel, deviceTokens []string) *Recipient {
+	recipient := &Recipient{
+		PhoneNumber:  userDB.PhoneNumber,
+		FullName:     userDB.FullName,
+		DeviceTokens: deviceTokens,
+		Email:        userDB.Email,
+	}
+	if len(deviceTokens) == 0 && userDB.DeviceToken != "" && userDB.DeviceToken != noDeviceToken {
+		recipient.DeviceTokens = []string{userDB.DeviceToken}
+	}
+	if userDB.Channels != "" {
+		recipient.Channels = strings.Split(userDB.Channels, ",")
+	}
+	return recipient
+}
+
+// Notification is the content of a notification
+type Notification struct {
+	Title string
+	Body  string
+	Data  map[string]interface{}
+	// CollapseKey groups notifications that replace each other on devices
+	CollapseKey string
+}
+
+// Text returns the notification as plain text, for channels without a separate title
+func (n *Notification) Text() string {
+	return fmt.Sprintf("%s: %s", n.Title, n.Body)
+}
+
+// channel returns the channel with the name
+func (s *messagingServer) channel(name string) (Channel, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	channel, ok := s.channels[name]
+	return channel, ok
+}
+
+// setChannel adds or replaces a channel, returning the channel replaced
+func (s *messagingServer) setChannel(channel Channel) Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	replaced := s.channels[channel.Name()]
+	s.channels[channel.Name()] = channel
+	return replaced
+}
+
+// deliver sends the notification through the first channel that succeeds, in the order preferred by the recipient.
+// It returns the name of the channel used and the attempts made through each channel.
+func (s *messagingServer) deliver(
+	ctx context.Context, recipient *Recipient, notification *Notification, skip ...string,
+) (string, []*services.DeliveryAttempt, error) {
+	channels := recipient.Channels
+	if len(channels) == 0 {
+		channels = defaultChannels
+	}
+
+	attempts := make([]*services.DeliveryAttempt, 0, len(channels))
+	errs := make([]string, 0, len(channels))
+
+channels:
+	for _, name := range channels {
+		for _, skipped := range skip {
+			if name == skipped {
+				continue channels
+			}
+		}
+
+		channel, ok := s.channel(name)
+		if !ok || !channel.Reaches(recipient) {
+			continue
+		}
+
+		receipt, err := channel.Send(ctx, recipient, notification)
+		attempts = append(attempts, getAttempt(name, receipt, err))
+		if receipt != nil {
+			// Invalid tokens are cleared so that they are not sent to again
+			s.updateDeviceTokens(receipt.TokenUpdates)
+		}
+		if err != nil {
+			s.logger.Warningf("failed to notify %s through %s: %v", recipient.PhoneNumber, name, err)
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		return name, attempts, nil
+	}
+
+	if len(errs) == 0 {
+		return "", attempts, errors.New("no channel reaches the user")
+	}
+
+	return "", attempts, errors.New(strings.Join(errs, "; "))
+}
+
+func truncate(str string, max int) string {
+	if len(str) > max {
+		return str[:max]
+	}
+	return str
+}
@@ -0,0 +1,155 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/appleboy/go-fcm"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/messaging/mocks"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeGateway is a local SMS gateway recording the phone numbers messages are sent to
+type fakeGateway struct {
+	mu sync.Mutex
+	to []string
+}
+
+func (g *fakeGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if r.Header.Get("apiKey") == "" {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	g.to = append(g.to, r.FormValue("to"))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (g *fakeGateway) received(phoneNumber string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, to := range g.to {
+		if to == phoneNumber {
+			return true
+		}
+	}
+	return false
+}
+
+var _ = Describe("Sending messages through channels preferred by users £channels", func() {
+	var (
+		ctx       context.Context
+		gateway   *fakeGateway
+		server    *httptest.Server
+		pushFails bool
+		replaced  []Channel
+	)
+
+	addUser := func(deviceToken string, channels string) *services.UserModel {
+		userDB := &services.UserModel{
+			PhoneNumber: randomPhone(),
+			FullName:    randomdata.FullName(randomdata.RandomGender),
+			County:      randomdata.State(randomdata.Large),
+			DeviceToken: deviceToken,
+			Channels:    channels,
+		}
+		err := MessagingServer.sqlDB.Create(userDB).Error
+		Expect(err).ShouldNot(HaveOccurred())
+		return userDB
+	}
+
+	sendFn := func(phoneNumber string) (*messaging.SendMessageResponse, error) {
+		msg := fakeMessage()
+		msg.UserPhone = phoneNumber
+		return MessagingAPI.SendMessage(ctx, msg)
+	}
+
+	// channelOf returns the channel the last message of the user was delivered through
+	channelOf := func(phoneNumber string) func() string {
+		return func() string {
+			messageDB := &services.Message{}
+			err := MessagingServer.sqlDB.Order("id DESC").First(messageDB, "user_phone=?", phoneNumber).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			return messageDB.Channel
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		gateway = &fakeGateway{}
+		server = httptest.NewServer(gateway)
+		pushFails = false
+
+		fcmClient := &mocks.FCMClientMock{}
+		fcmClient.On("SendWithRetry", mock.Anything, 5).Return(
+			func(*fcm.Message, int) *fcm.Response { return &fcm.Response{} },
+			func(*fcm.Message, int) error {
+				if pushFails {
+					return errors.New("fcm unavailable")
+				}
+				return nil
+			},
+		)
+
+		smsChannel, err := NewSMSChannel(&SMSOptions{
+			URL:      server.URL,
+			Username: "sandbox",
+			APIKey:   randomdata.RandStringRunes(32),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		replaced = []Channel{
+			MessagingServer.setChannel(&pushChannel{client: fcmClient}),
+			MessagingServer.setChannel(smsChannel),
+		}
+	})
+
+	AfterEach(func() {
+		for _, channel := range replaced {
+			if channel != nil {
+				MessagingServer.setChannel(channel)
+			}
+		}
+		server.Close()
+	})
+
+	It("should send push notifications to users with a device", func() {
+		userDB := addUser(randomdata.MacAddress(), "")
+		_, err := sendFn(userDB.PhoneNumber)
+		Expect(err).ShouldNot(HaveOccurred())
+		Eventually(channelOf(userDB.PhoneNumber)).Should(Equal(ChannelPush))
+		Expect(gateway.received(userDB.PhoneNumber)).Should(BeFalse())
+	})
+
+	It("should send SMS to users without a device", func() {
+		userDB := addUser(noDeviceToken, "")
+		_, err := sendFn(userDB.PhoneNumber)
+		Expect(err).ShouldNot(HaveOccurred())
+		Eventually(channelOf(userDB.PhoneNumber)).Should(Equal(ChannelSMS))
+		Expect(gateway.received(userDB.PhoneNumber)).Should(BeTrue())
+	})
+
+	It("should fall back to SMS when the push notification fails", func() {
+		pushFails = true
+		userDB := addUser(randomdata.MacAddress(), "")
+		_, err := sendFn(userDB.PhoneNumber)
+		Expect(err).ShouldNot(HaveOccurred())
+		Eventually(channelOf(userDB.PhoneNumber)).Should(Equal(ChannelSMS))
+	})
+
+	It("should use the channels preferred by the user", func() {
+		userDB := addUser(randomdata.MacAddress(), "sms,push")
+		_, err := sendFn(userDB.PhoneNumber)
+		Expect(err).ShouldNot(HaveOccurred())
+		Eventually(channelOf(userDB.PhoneNumber)).Should(Equal(ChannelSMS))
+		Expect(gateway.received(userDB.PhoneNumber)).Should(BeTrue())
+	})
+})
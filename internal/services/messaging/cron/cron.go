@@ -0,0 +1,177 @@
+// Package cron parses standard five field cron expressions and computes when they run next
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// When either day field is *, days must match both fields, otherwise days match either field
+	anyDay bool
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse parses a cron expression with the fields minute, hour, day of month, month and day of week.
+// A field is *, a value, a range such as 1-5, a step such as */15 or 8-18/2, or a comma separated list of these.
+// Sunday is either 0 or 7.
+func Parse(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron expression %q has %d fields, expected %d", expr, len(parts), len(fields))
+	}
+
+	sets := make([]uint64, 0, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+
+	schedule := &Schedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		anyDay: strings.HasPrefix(parts[2], "*") || strings.HasPrefix(parts[4], "*"),
+	}
+
+	// Sunday is 0 in time.Weekday
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow = schedule.dow&^(1<<7) | 1
+	}
+
+	return schedule, nil
+}
+
+func parseField(expr string, f field) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(expr, ",") {
+		var (
+			rangeExpr = item
+			step      = 1
+			lo, hi    = f.min, f.max
+			err       error
+		)
+
+		if i := strings.Index(item, "/"); i >= 0 {
+			rangeExpr = item[:i]
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, item)
+			}
+		}
+
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			lo, err = f.value(bounds[0])
+			if err != nil {
+				return 0, err
+			}
+			hi, err = f.value(bounds[1])
+			if err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range in %s field %q", f.name, item)
+			}
+		default:
+			lo, err = f.value(rangeExpr)
+			if err != nil {
+				return 0, err
+			}
+			// A value with a step such as 5/15 runs from the value to the end of the range
+			if step == 1 {
+				hi = lo
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func (f field) value(str string) (int, error) {
+	v, err := strconv.Atoi(str)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s field value %q is not between %d and %d", f.name, str, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first time after t that the schedule runs, in the location of t.
+// It returns the zero time when the schedule never runs, such as on February 30.
+//
+// Wall clock times skipped by a daylight saving change run as much later as clocks moved forward,
+// and times repeated by a change run once, at their first occurrence.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+
+	// Wall clock times are walked in UTC, where each of them occurs once
+	wall := wallClock(t).Add(time.Minute)
+
+	// Schedules that do not run within five years never run
+	end := wall.AddDate(5, 0, 0)
+
+	for wall.Before(end) {
+		switch {
+		case s.month&(1<<uint(wall.Month())) == 0:
+			wall = time.Date(wall.Year(), wall.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchDay(wall):
+			wall = time.Date(wall.Year(), wall.Month(), wall.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<uint(wall.Hour())) == 0:
+			wall = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour()+1, 0, 0, 0, time.UTC)
+		case s.minute&(1<<uint(wall.Minute())) == 0:
+			wall = wall.Add(time.Minute)
+		default:
+			next := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, loc)
+			if skipped := wall.Sub(wallClock(next)); skipped > 0 {
+				next = next.Add(skipped)
+			}
+			// The first occurrence of a repeated time may have passed already
+			if next.After(t) {
+				return next
+			}
+			wall = wall.Add(time.Minute)
+		}
+	}
+
+	return time.Time{}
+}
+
+// wallClock returns the wall clock time of t to the minute, in UTC
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,143 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"1-x * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Parse(expr); err == nil {
+				t.Fatalf("Parse(%q) succeeded, want an error", expr)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		expr string
+		zone string
+		// from and want are RFC 3339 times, from is taken in the zone
+		from string
+		want string
+	}{
+		// Minutes and hours
+		{"every minute", "* * * * *", "UTC", "2021-06-01T10:07:30Z", "2021-06-01T10:08:00Z"},
+		{"strictly after", "0 9 * * *", "UTC", "2021-06-01T09:00:00Z", "2021-06-02T09:00:00Z"},
+		{"seconds are dropped", "0 9 * * *", "UTC", "2021-06-01T08:59:59Z", "2021-06-01T09:00:00Z"},
+		{"step", "*/15 * * * *", "UTC", "2021-06-01T10:07:00Z", "2021-06-01T10:15:00Z"},
+		{"step from a value", "5/15 * * * *", "UTC", "2021-06-01T10:21:00Z", "2021-06-01T10:35:00Z"},
+		{"step from a value wraps to the next hour", "5/15 * * * *", "UTC", "2021-06-01T10:50:00Z", "2021-06-01T11:05:00Z"},
+		{"step in a range", "0 8-18/4 * * *", "UTC", "2021-06-01T12:30:00Z", "2021-06-01T16:00:00Z"},
+		{"list", "0 7,19 * * *", "UTC", "2021-06-01T08:00:00Z", "2021-06-01T19:00:00Z"},
+		{"next day", "30 6 * * *", "UTC", "2021-06-01T23:59:00Z", "2021-06-02T06:30:00Z"},
+		{"next year", "0 0 1 1 *", "UTC", "2021-06-01T00:00:00Z", "2022-01-01T00:00:00Z"},
+
+		// Days of the week, 2021-06-04 is a Friday
+		{"week days", "0 9 * * 1-5", "UTC", "2021-06-04T10:00:00Z", "2021-06-07T09:00:00Z"},
+		{"sunday as 0", "0 8 * * 0", "UTC", "2021-06-05T12:00:00Z", "2021-06-06T08:00:00Z"},
+		{"sunday as 7", "0 8 * * 7", "UTC", "2021-06-05T12:00:00Z", "2021-06-06T08:00:00Z"},
+		{"range ending on sunday as 7", "0 8 * * 6-7", "UTC", "2021-06-06T12:00:00Z", "2021-06-12T08:00:00Z"},
+
+		// Days of the month and of the week match either field, unless one of them starts with *
+		{"day of month or of week matches the day of week", "0 0 13 * 5", "UTC", "2021-06-01T00:00:00Z", "2021-06-04T00:00:00Z"},
+		{"day of month or of week matches the day of month", "0 0 13 * 5", "UTC", "2021-06-11T12:00:00Z", "2021-06-13T00:00:00Z"},
+		{"any day of month matches the day of week", "0 0 * * 5", "UTC", "2021-06-05T00:00:00Z", "2021-06-11T00:00:00Z"},
+		{"any day of week matches the day of month", "0 0 13 * *", "UTC", "2021-06-01T00:00:00Z", "2021-06-13T00:00:00Z"},
+		{"day of month step and day of week match both", "0 0 */2 * 5", "UTC", "2021-06-01T00:00:00Z", "2021-06-11T00:00:00Z"},
+		// Days of the week */5 are Sunday and Friday, 2021-07-04 is a Sunday
+		{"day of month and day of week step match both", "0 0 4 * */5", "UTC", "2021-06-05T00:00:00Z", "2021-07-04T00:00:00Z"},
+
+		// Days that do not exist in every month
+		{"31st skips shorter months", "0 0 31 * *", "UTC", "2021-06-01T00:00:00Z", "2021-07-31T00:00:00Z"},
+		{"29th of February in a leap year", "0 0 29 2 *", "UTC", "2021-03-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+
+		// Time zones without daylight saving
+		{"nairobi", "0 9 * * *", "Africa/Nairobi", "2021-06-01T09:00:00+03:00", "2021-06-02T09:00:00+03:00"},
+		{"nairobi from another zone", "0 9 * * *", "Africa/Nairobi", "2021-06-01T05:00:00Z", "2021-06-01T09:00:00+03:00"},
+		{"nairobi at midnight", "0 0 1 * *", "Africa/Nairobi", "2021-12-31T21:00:00Z", "2022-02-01T00:00:00+03:00"},
+
+		// Clocks spring forward from 02:00 to 03:00
+		{"new york time skipped", "30 2 * * *", "America/New_York", "2021-03-13T12:00:00-05:00", "2021-03-14T03:30:00-04:00"},
+		{"new york after the skipped time", "30 2 * * *", "America/New_York", "2021-03-14T03:30:00-04:00", "2021-03-15T02:30:00-04:00"},
+		{"new york hourly across the gap", "0 * * * *", "America/New_York", "2021-03-14T01:30:00-05:00", "2021-03-14T03:00:00-04:00"},
+		// Clocks spring forward from 01:00 to 02:00
+		{"london time skipped", "30 1 * * *", "Europe/London", "2021-03-27T12:00:00Z", "2021-03-28T02:30:00+01:00"},
+
+		// Clocks fall back from 02:00 to 01:00, repeated times run once
+		{"new york repeated time", "30 1 * * *", "America/New_York", "2021-11-07T00:00:00-04:00", "2021-11-07T01:30:00-04:00"},
+		{"new york repeated time runs once", "30 1 * * *", "America/New_York", "2021-11-07T01:30:00-04:00", "2021-11-08T01:30:00-05:00"},
+		{"new york from the repeated hour", "30 1 * * *", "America/New_York", "2021-11-07T01:10:00-05:00", "2021-11-08T01:30:00-05:00"},
+		{"new york hourly across the repeated hour", "0 * * * *", "America/New_York", "2021-11-07T01:00:00-04:00", "2021-11-07T02:00:00-05:00"},
+
+		// Southern hemisphere, clocks spring forward from 02:00 to 03:00 in October
+		{"sydney time skipped", "15 2 * * *", "Australia/Sydney", "2021-10-02T12:00:00+10:00", "2021-10-03T03:15:00+11:00"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			schedule, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.expr, err)
+			}
+
+			from, err := time.Parse(time.RFC3339, tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := time.Parse(time.RFC3339, tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			next := schedule.Next(from.In(loc))
+			if !next.Equal(want) {
+				t.Fatalf("Next(%s) = %s, want %s", from.In(loc), next, want.In(loc))
+			}
+			if next.Location() != loc {
+				t.Fatalf("Next returned a time in %s, want %s", next.Location(), loc)
+			}
+		})
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	for _, expr := range []string{
+		"0 0 30 2 *",
+		"0 0 31 4 *",
+		"0 0 31 2,4,6,9,11 *",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			schedule, err := Parse(expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", expr, err)
+			}
+			if next := schedule.Next(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)); !next.IsZero() {
+				t.Fatalf("Next = %s, want the zero time", next)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+var deliveryStatuses = map[string]messaging.DeliveryStatus{
+	services.DeliveryPending: messaging.DeliveryStatus_PENDING,
+	services.DeliverySent:    messaging.DeliveryStatus_DELIVERED,
+	services.DeliveryFailed:  messaging.DeliveryStatus_FAILED,
+}
+
+func (s *messagingServer) GetMessageDeliveryStatus(
+	ctx context.Context, getReq *messaging.GetMessageDeliveryStatusRequest,
+) (*messaging.MessageDeliveryStatus, error) {
+	// Request must not be nil
+	if getReq == nil {
+		return nil, services.NilRequestError("GetMessageDeliveryStatusRequest")
+	}
+
+	// Validation
+	if getReq.MessageId == "" && getReq.BroadcastMessageId == "" {
+		return nil, services.MissingFieldError("message id or broadcast message id")
+	}
+
+	if getReq.BroadcastMessageId != "" {
+		return s.getBroadcastDeliveryStatus(getReq.BroadcastMessageId)
+	}
+
+	messageID, err := strconv.ParseUint(getReq.MessageId, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed message id: %v", err)
+	}
+
+	messageDB := &services.Message{}
+	err = s.sqlDB.Select("id, channel").First(messageDB, "id=?", messageID).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "message with id %s not found", getReq.MessageId)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get message: %v", err)
+	}
+
+	statusPB := &messaging.MessageDeliveryStatus{
+		MessageId: getReq.MessageId,
+		Status:    messaging.DeliveryStatus_NOT_TRACKED,
+		Channel:   messageDB.Channel,
+	}
+
+	deliveryDB := &services.Delivery{}
+	err = s.sqlDB.First(deliveryDB, "message_id=?", messageID).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return statusPB, nil
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get message delivery: %v", err)
+	}
+
+	statusPB.Status = deliveryStatuses[deliveryDB.Status]
+	statusPB.Channel = deliveryDB.Channel
+	statusPB.Attempts = int32(deliveryDB.Attempts)
+	statusPB.LastError = deliveryDB.LastError
+
+	attemptsDB := make([]*services.DeliveryAttempt, 0)
+	err = s.sqlDB.Order("id").Find(&attemptsDB, "delivery_id=?", deliveryDB.ID).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get message delivery attempts: %v", err)
+	}
+
+	statusPB.DeliveryAttempts = make([]*messaging.DeliveryAttempt, 0, len(attemptsDB))
+	for _, attemptDB := range attemptsDB {
+		statusPB.DeliveryAttempts = append(statusPB.DeliveryAttempts, &messaging.DeliveryAttempt{
+			Channel:                 attemptDB.Channel,
+			Delivered:               attemptDB.Delivered,
+			ProviderMessageId:       attemptDB.ProviderMessageID,
+			ErrorCode:               attemptDB.ErrorCode,
+			Error:                   attemptDB.Error,
+			CanonicalRegistrationId: attemptDB.CanonicalRegistrationID,
+			Timestamp:               attemptDB.CreatedAt.Unix(),
+		})
+	}
+
+	return statusPB, nil
+}
+
+// getBroadcastDeliveryStatus counts the users a broadcast was delivered to
+func (s *messagingServer) getBroadcastDeliveryStatus(broadcastID string) (*messaging.MessageDeliveryStatus, error) {
+	statusPB := &messaging.MessageDeliveryStatus{
+		BroadcastMessageId: broadcastID,
+	}
+
+	err := s.sqlDB.Model(&services.Message{}).Where("broadcast_id = ?", broadcastID).
+		Count(&statusPB.Recipients).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count broadcast messages: %v", err)
+	}
+
+	if statusPB.Recipients == 0 {
+		return nil, status.Errorf(codes.NotFound, "broadcast with id %s not found", broadcastID)
+	}
+
+	rows, err := s.sqlDB.Table(services.DeliveriesTable).
+		Select("deliveries.status, COUNT(*)").
+		Joins("JOIN messages ON messages.id = deliveries.message_id").
+		Where("messages.broadcast_id = ?", broadcastID).
+		Group("deliveries.status").Rows()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count broadcast deliveries: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			deliveryStatus string
+			count          int64
+		)
+		err = rows.Scan(&deliveryStatus, &count)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read broadcast deliveries: %v", err)
+		}
+		switch deliveryStatus {
+		case services.DeliveryPending:
+			statusPB.Pending = count
+		case services.DeliverySent:
+			statusPB.Delivered = count
+		case services.DeliveryFailed:
+			statusPB.Failed = count
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read broadcast deliveries: %v", err)
+	}
+
+	return statusPB, nil
+}
+
+// setDeliveryStatus sets the delivery status of messages
+func (s *messagingServer) setDeliveryStatus(messagesPB []*messaging.Message) error {
+	if len(messagesPB) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(messagesPB))
+	for _, messagePB := range messagesPB {
+		ids = append(ids, messagePB.MessageId)
+	}
+
+	deliveriesDB := make([]*services.Delivery, 0, len(ids))
+	err := s.sqlDB.Select("message_id, status, attempts").Find(&deliveriesDB, "message_id IN(?)", ids).Error
+	if err != nil {
+		return err
+	}
+
+	deliveries := make(map[string]*services.Delivery, len(deliveriesDB))
+	for _, deliveryDB := range deliveriesDB {
+		deliveries[strconv.FormatUint(uint64(deliveryDB.MessageID), 10)] = deliveryDB
+	}
+
+	for _, messagePB := range messagesPB {
+		deliveryDB, ok := deliveries[messagePB.MessageId]
+		if !ok {
+			continue
+		}
+		messagePB.DeliveryStatus = deliveryStatuses[deliveryDB.Status]
+		messagePB.DeliveryAttempts = int32(deliveryDB.Attempts)
+	}
+
+	return nil
+}
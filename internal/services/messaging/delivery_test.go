@@ -0,0 +1,168 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/appleboy/go-fcm"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/messaging/mocks"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("Getting delivery status of messages £delivery", func() {
+	var (
+		ctx      context.Context
+		getReq   *messaging.GetMessageDeliveryStatusRequest
+		result   fcm.Result
+		replaced Channel
+	)
+
+	addUser := func(county string) *services.UserModel {
+		userDB := &services.UserModel{
+			PhoneNumber: randomPhone(),
+			FullName:    randomdata.FullName(randomdata.RandomGender),
+			County:      county,
+			DeviceToken: randomdata.MacAddress(),
+		}
+		Expect(MessagingServer.sqlDB.Create(userDB).Error).ShouldNot(HaveOccurred())
+		return userDB
+	}
+
+	sendFn := func(phoneNumber string) string {
+		msg := fakeMessage()
+		msg.UserPhone = phoneNumber
+		sendRes, err := MessagingAPI.SendMessage(ctx, msg)
+		Expect(err).ShouldNot(HaveOccurred())
+		return sendRes.MessageId
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		getReq = &messaging.GetMessageDeliveryStatusRequest{}
+		result = fcm.Result{MessageID: "0:" + randomdata.RandStringRunes(16)}
+
+		fcmClient := &mocks.FCMClientMock{}
+		fcmClient.On("SendWithRetry", mock.Anything, 5).Return(
+			func(*fcm.Message, int) *fcm.Response {
+				return &fcm.Response{Results: []fcm.Result{result}}
+			},
+			nil,
+		)
+		replaced = MessagingServer.setChannel(&pushChannel{client: fcmClient})
+	})
+
+	AfterEach(func() {
+		MessagingServer.setChannel(replaced)
+	})
+
+	Describe("Getting delivery status with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, nil)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail when message id and broadcast id are missing", func() {
+			getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail when message id is malformed", func() {
+			getReq.MessageId = randomdata.SillyName()
+			getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail when the broadcast does not exist", func() {
+			getReq.BroadcastMessageId = uuid.New().String()
+			getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, getReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(getRes).Should(BeNil())
+		})
+	})
+
+	Describe("Getting delivery status with well-formed request", func() {
+		It("should record the FCM message id and canonical registration id", func() {
+			result.RegistrationID = randomdata.MacAddress()
+			getReq.MessageId = sendFn(addUser(randomdata.State(randomdata.Large)).PhoneNumber)
+
+			Eventually(func() messaging.DeliveryStatus {
+				getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, getReq)
+				Expect(err).ShouldNot(HaveOccurred())
+				return getRes.Status
+			}).Should(Equal(messaging.DeliveryStatus_DELIVERED))
+
+			getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.Channel).Should(Equal(ChannelPush))
+			Expect(getRes.Attempts).Should(BeEquivalentTo(1))
+			Expect(getRes.DeliveryAttempts).Should(HaveLen(1))
+			Expect(getRes.DeliveryAttempts[0].ProviderMessageId).Should(Equal(result.MessageID))
+			Expect(getRes.DeliveryAttempts[0].CanonicalRegistrationId).Should(Equal(result.RegistrationID))
+		})
+
+		It("should record devices that are not registered", func() {
+			result = fcm.Result{Error: fcm.ErrNotRegistered}
+			userDB := addUser(randomdata.State(randomdata.Large))
+			getReq.MessageId = sendFn(userDB.PhoneNumber)
+
+			Eventually(func() int32 {
+				getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, getReq)
+				Expect(err).ShouldNot(HaveOccurred())
+				return getRes.Attempts
+			}).Should(BeNumerically(">=", 1))
+
+			getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.Status).ShouldNot(Equal(messaging.DeliveryStatus_DELIVERED))
+			Expect(getRes.DeliveryAttempts[0].Delivered).Should(BeFalse())
+			Expect(getRes.DeliveryAttempts[0].ErrorCode).Should(Equal("NotRegistered"))
+
+			// Delivery status is in the messages of the user
+			listRes, err := MessagingAPI.ListMessages(ctx, &messaging.ListMessagesRequest{PhoneNumber: userDB.PhoneNumber})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.Messages).Should(HaveLen(1))
+			Expect(listRes.Messages[0].DeliveryAttempts).Should(BeNumerically(">=", 1))
+		})
+
+		It("should count users that received a broadcast", func() {
+			county := randomdata.RandStringRunes(20)
+			for i := 0; i < 3; i++ {
+				addUser(county)
+			}
+
+			broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, &messaging.BroadCastMessageRequest{
+				Title:   randomdata.Paragraph()[:10],
+				Message: randomdata.Paragraph()[:100],
+				Filters: []messaging.BroadCastMessageFilter{messaging.BroadCastMessageFilter_BY_COUNTY},
+				Topics:  []string{county},
+				Payload: map[string]string{"time": time.Now().String()},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Eventually(func() messaging.BroadcastStatus {
+				getRes, err := MessagingAPI.GetBroadcast(ctx, &messaging.GetBroadcastRequest{
+					BroadcastMessageId: broadCastRes.BroadcastMessageId,
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				return getRes.Status
+			}).Should(Equal(messaging.BroadcastStatus_BROADCAST_COMPLETED))
+
+			getReq.BroadcastMessageId = broadCastRes.BroadcastMessageId
+			getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.Recipients).Should(BeEquivalentTo(3))
+			Expect(getRes.Delivered).Should(BeEquivalentTo(3))
+			Expect(getRes.Failed).Should(BeZero())
+		})
+	})
+})
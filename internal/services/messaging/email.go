@@ -0,0 +1,115 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// EmailOptions contains parameters for NewEmailChannel
+type EmailOptions struct {
+	Host string
+	// Port defaults to 587
+	Port int
+	// Username and Password authenticate with the server, no authentication is done without a username
+	Username string
+	Password string
+	From     string
+}
+
+// emailChannel sends emails through a SMTP server
+type emailChannel struct {
+	opt  *EmailOptions
+	addr string
+	auth smtp.Auth
+}
+
+// NewEmailChannel creates a channel sending emails through a SMTP server
+func NewEmailChannel(opt *EmailOptions) (Channel, error) {
+	var err error
+	switch {
+	case opt == nil:
+		err = errors.New("email options are required")
+	case opt.Host == "":
+		err = errors.New("smtp host is required")
+	case opt.From == "":
+		err = errors.New("email sender is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Port == 0 {
+		opt.Port = 587
+	}
+
+	c := &emailChannel{
+		opt:  opt,
+		addr: net.JoinHostPort(opt.Host, strconv.Itoa(opt.Port)),
+	}
+	if opt.Username != "" {
+		c.auth = smtp.PlainAuth("", opt.Username, opt.Password, opt.Host)
+	}
+
+	return c, nil
+}
+
+// NewEmailChannelFromEnv creates an email channel from SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and
+// SMTP_FROM. It returns nil when no server is configured.
+func NewEmailChannelFromEnv() (Channel, error) {
+	host := strings.TrimSpace(os.Getenv("SMTP_HOST"))
+	if host == "" {
+		return nil, nil
+	}
+
+	opt := &EmailOptions{
+		Host:     host,
+		Username: strings.TrimSpace(os.Getenv("SMTP_USERNAME")),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     strings.TrimSpace(os.Getenv("SMTP_FROM")),
+	}
+
+	if port := strings.TrimSpace(os.Getenv("SMTP_PORT")); port != "" {
+		var err error
+		opt.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("malformed SMTP_PORT: %v", err)
+		}
+	}
+
+	return NewEmailChannel(opt)
+}
+
+func (*emailChannel) Name() string {
+	return ChannelEmail
+}
+
+func (*emailChannel) Reaches(recipient *Recipient) bool {
+	return recipient.Email != ""
+}
+
+func (c *emailChannel) Send(ctx context.Context, recipient *Recipient, notification *Notification) (*Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Headers must not contain line breaks
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(notification.Title)
+
+	msg := strings.Join([]string{
+		"From: " + c.opt.From,
+		"To: " + recipient.Email,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		notification.Body,
+	}, "\r\n")
+
+	return &Receipt{}, smtp.SendMail(c.addr, c.auth, c.opt.From, []string{recipient.Email}, []byte(msg))
+}
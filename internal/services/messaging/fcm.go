@@ -2,8 +2,11 @@ package messaging
 
 import (
 	"context"
+	"errors"
 
 	"github.com/appleboy/go-fcm"
+
+	"github.com/gidyon/pandemic-api/internal/devicetoken"
 )
 
 type fcmClient interface {
@@ -11,3 +14,136 @@ type fcmClient interface {
 	SendWithRetry(msg *fcm.Message, retryAttempts int) (*fcm.Response, error)
 	Send(msg *fcm.Message) (*fcm.Response, error)
 }
+
+// noDeviceToken is the device token of users without a smartphone, such as those imported from files
+const noDeviceToken = devicetoken.None
+
+// pushChannel sends push notifications through firebase cloud messaging
+type pushChannel struct {
+	client fcmClient
+}
+
+func (*pushChannel) Name() string {
+	return ChannelPush
+}
+
+func (*pushChannel) Reaches(recipient *Recipient) bool {
+	return len(recipient.DeviceTokens) > 0
+}
+
+// Send pushes the notification to every device of the recipient, it is delivered when any device received it
+func (c *pushChannel) Send(ctx context.Context, recipient *Recipient, notification *Notification) (*Receipt, error) {
+	msg := &fcm.Message{
+		Data: notification.Data,
+		// Messages with the same collapse key replace each other on the device
+		CollapseKey: notification.CollapseKey,
+		Notification: &fcm.Notification{
+			Title: notification.Title,
+			Body:  notification.Body,
+		},
+	}
+	if len(recipient.DeviceTokens) == 1 {
+		msg.To = recipient.DeviceTokens[0]
+	} else {
+		msg.RegistrationIDs = recipient.DeviceTokens
+	}
+
+	res, err := c.client.SendWithRetry(msg, 5)
+	switch {
+	case err != nil:
+		return nil, err
+	case res == nil:
+		return &Receipt{}, nil
+	case len(res.Results) > 0:
+		return getDevicesReceipt(recipient.DeviceTokens, res.Results)
+	case res.Failure > 0:
+		return &Receipt{}, errors.New("push notification not delivered")
+	}
+	return &Receipt{}, nil
+}
+
+// fcmErrorCodes are the error codes of errors returned by FCM for a registration id
+var fcmErrorCodes = map[error]string{
+	fcm.ErrMissingRegistration: "MissingRegistration",
+	fcm.ErrInvalidRegistration: "InvalidRegistration",
+	fcm.ErrNotRegistered:       "NotRegistered",
+	fcm.ErrMismatchSenderID:    "MismatchSenderId",
+	fcm.ErrUnavailable:         "Unavailable",
+}
+
+// getReceipt returns the receipt of a message sent to a registration id
+func getReceipt(result fcm.Result) (*Receipt, error) {
+	receipt := &Receipt{
+		ProviderMessageID:       result.MessageID,
+		CanonicalRegistrationID: result.RegistrationID,
+	}
+	if result.Error != nil {
+		receipt.ErrorCode = fcmErrorCodes[result.Error]
+		return receipt, result.Error
+	}
+	return receipt, nil
+}
+
+// getDevicesReceipt returns the receipt of a message sent to devices of a user from the results of their tokens.
+// The receipt is of the first device that received the message, or of the last device when none received it.
+func getDevicesReceipt(tokens []string, results []fcm.Result) (*Receipt, error) {
+	var (
+		receipt = &Receipt{}
+		err     error
+		updates = make([]*devicetoken.Update, 0)
+	)
+
+	for i, result := range results {
+		if i >= len(tokens) {
+			break
+		}
+		if update := devicetoken.FromResult(tokens[i], result); update != nil {
+			updates = append(updates, update)
+		}
+		if i == 0 || err != nil {
+			receipt, err = getReceipt(result)
+		}
+	}
+
+	receipt.TokenUpdates = updates
+
+	return receipt, err
+}
+
+// maxRegistrationIDs is the most device tokens FCM accepts in a multicast message
+const maxRegistrationIDs = 1000
+
+// multicast sends the message to the tokens in batches FCM accepts, returning a result for each token.
+// Tokens of a batch that could not be sent get the error of the batch. onBatch is called after each batch is sent.
+func multicast(
+	client fcmClient, msg *fcm.Message, tokens []string, onBatch func(tokens []string, res *fcm.Response, err error),
+) []fcm.Result {
+	results := make([]fcm.Result, 0, len(tokens))
+
+	for start := 0; start < len(tokens); start += maxRegistrationIDs {
+		end := start + maxRegistrationIDs
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+
+		batch := *msg
+		batch.RegistrationIDs = tokens[start:end]
+
+		batchResults := make([]fcm.Result, end-start)
+		res, err := client.SendWithRetry(&batch, 5)
+		switch {
+		case err != nil:
+			for i := range batchResults {
+				batchResults[i].Error = err
+			}
+		case res != nil:
+			copy(batchResults, res.Results)
+		}
+
+		onBatch(batch.RegistrationIDs, res, err)
+
+		results = append(results, batchResults...)
+	}
+
+	return results
+}
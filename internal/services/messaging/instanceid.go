@@ -0,0 +1,169 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDeviceNotRegistered is the error of a device token FCM does not know, such as one of an uninstalled app
+var ErrDeviceNotRegistered = errors.New("device not registered")
+
+// maxTopicBatchSize is the maximum number of devices subscribed to a topic in a single request
+const maxTopicBatchSize = 1000
+
+// TopicManager subscribes devices to FCM topics and unsubscribes them
+type TopicManager interface {
+	// Subscribe adds the devices to the topic, returning the error of every device that was not added
+	Subscribe(ctx context.Context, topic string, deviceTokens []string) (map[string]error, error)
+	// Unsubscribe removes the devices from the topic, returning the error of every device that was not removed
+	Unsubscribe(ctx context.Context, topic string, deviceTokens []string) (map[string]error, error)
+}
+
+// InstanceIDOptions contains parameters for NewInstanceIDTopicManager
+type InstanceIDOptions struct {
+	// URL is the endpoint of the Instance ID service, defaults to https://iid.googleapis.com
+	URL        string
+	ServerKey  string
+	HTTPClient *http.Client
+}
+
+// instanceIDTopicManager manages topics through the batch APIs of the Instance ID service
+type instanceIDTopicManager struct {
+	opt *InstanceIDOptions
+}
+
+// NewInstanceIDTopicManager creates a topic manager using the Instance ID batch APIs
+func NewInstanceIDTopicManager(opt *InstanceIDOptions) (TopicManager, error) {
+	var err error
+	switch {
+	case opt == nil:
+		err = errors.New("instance id options are required")
+	case opt.ServerKey == "":
+		err = errors.New("fcm server key is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.URL == "" {
+		opt.URL = "https://iid.googleapis.com"
+	}
+	if opt.HTTPClient == nil {
+		opt.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &instanceIDTopicManager{opt: opt}, nil
+}
+
+// NewInstanceIDTopicManagerFromEnv creates a topic manager from FCM_SERVER_KEY and INSTANCE_ID_URL when
+// FCM_TOPICS_ENABLED is true. It returns nil otherwise.
+func NewInstanceIDTopicManagerFromEnv() (TopicManager, error) {
+	enabled, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("FCM_TOPICS_ENABLED")))
+	if !enabled {
+		return nil, nil
+	}
+	return NewInstanceIDTopicManager(&InstanceIDOptions{
+		URL:       strings.TrimSpace(os.Getenv("INSTANCE_ID_URL")),
+		ServerKey: strings.TrimSpace(os.Getenv("FCM_SERVER_KEY")),
+	})
+}
+
+func (m *instanceIDTopicManager) Subscribe(
+	ctx context.Context, topic string, deviceTokens []string,
+) (map[string]error, error) {
+	return m.batch(ctx, "batchAdd", topic, deviceTokens)
+}
+
+func (m *instanceIDTopicManager) Unsubscribe(
+	ctx context.Context, topic string, deviceTokens []string,
+) (map[string]error, error) {
+	return m.batch(ctx, "batchRemove", topic, deviceTokens)
+}
+
+type instanceIDRequest struct {
+	To                 string   `json:"to"`
+	RegistrationTokens []string `json:"registration_tokens"`
+}
+
+type instanceIDResponse struct {
+	Results []struct {
+		Error string `json:"error"`
+	} `json:"results"`
+}
+
+func (m *instanceIDTopicManager) batch(
+	ctx context.Context, operation, topic string, deviceTokens []string,
+) (map[string]error, error) {
+	failed := make(map[string]error)
+
+	for first := 0; first < len(deviceTokens); first += maxTopicBatchSize {
+		last := first + maxTopicBatchSize
+		if last > len(deviceTokens) {
+			last = len(deviceTokens)
+		}
+		tokens := deviceTokens[first:last]
+
+		data, err := json.Marshal(&instanceIDRequest{
+			To:                 "/topics/" + topic,
+			RegistrationTokens: tokens,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequest(
+			http.MethodPost, strings.TrimSuffix(m.opt.URL, "/")+"/iid/v1:"+operation, bytes.NewReader(data),
+		)
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "key="+m.opt.ServerKey)
+
+		res, err := m.opt.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1<<20))
+		res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return nil, fmt.Errorf(
+				"instance id service responded with %s: %s", res.Status, truncate(strings.TrimSpace(string(body)), 128),
+			)
+		}
+
+		batchRes := &instanceIDResponse{}
+		err = json.Unmarshal(body, batchRes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to json unmarshal instance id response: %v", err)
+		}
+
+		// Results are in the order of the tokens
+		for i, result := range batchRes.Results {
+			if i >= len(tokens) || result.Error == "" {
+				continue
+			}
+			switch result.Error {
+			case "NOT_FOUND", "INVALID_ARGUMENT":
+				failed[tokens[i]] = ErrDeviceNotRegistered
+			default:
+				failed[tokens[i]] = errors.New(result.Error)
+			}
+		}
+	}
+
+	return failed, nil
+}
@@ -5,40 +5,66 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/gidyon/pandemic-api/pkg/api/location"
 	"io"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
-	"github.com/google/uuid"
-
 	"google.golang.org/grpc/grpclog"
 
 	"github.com/golang/protobuf/ptypes/empty"
 
+	"github.com/go-redis/redis"
+	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 
-	"github.com/appleboy/go-fcm"
+	"github.com/gidyon/pandemic-api/internal/auth"
 	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
 	"github.com/gidyon/pandemic-api/pkg/api/messaging"
 )
 
 var emptyMsg = &empty.Empty{}
 
 type messagingServer struct {
-	failedSend chan *fcmErrFDetails
-	sqlDB      *gorm.DB
-	fcmClient  fcmClient
-	logger     grpclog.LoggerV2
+	failedSend       chan *fcmErrFDetails
+	sqlDB            *gorm.DB
+	redisDB          *redis.Client
+	fcmClient        fcmClient
+	topicManager     TopicManager
+	locationClient   location.LocationTracingAPIClient
+	mu               sync.RWMutex // guards channels
+	channels         map[string]Channel
+	wake             chan struct{}
+	dispatchInterval time.Duration
+	maxAttempts      int
+	scheduleInterval time.Duration
+	replicaID        string
+	logger           grpclog.LoggerV2
+	authorizeGroups  func(context.Context, ...string) error
 }
 
 // Options contains options passed while calling NewMessagingServer
 type Options struct {
-	SQLDB     *gorm.DB
-	FCMClient fcmClient
-	Logger    grpclog.LoggerV2
+	SQLDB *gorm.DB
+	// RedisClient elects the replica that sends scheduled broadcasts
+	RedisClient *redis.Client
+	FCMClient   fcmClient
+	// TopicManager subscribes devices to FCM topics broadcasts are pushed to, topics are not used when nil
+	TopicManager TopicManager
+	// LocationClient resolves users seen in geo fences, audiences with geo fences are rejected when nil
+	LocationClient location.LocationTracingAPIClient
+	// Channels are channels used besides push notifications, such as SMS and email
+	Channels []Channel
+	// DispatchInterval is how often pending deliveries are looked for, defaults to 5 seconds
+	DispatchInterval time.Duration
+	// MaxAttempts is how many times delivery of a message is attempted before giving up, defaults to 5
+	MaxAttempts int
+	// ScheduleInterval is how often scheduled broadcasts that are due are looked for, defaults to 30 seconds
+	ScheduleInterval time.Duration
+	Logger           grpclog.LoggerV2
 }
 
 type fcmErrFDetails struct {
@@ -56,6 +82,8 @@ func NewMessagingServer(
 	switch {
 	case opt.SQLDB == nil:
 		err = errors.New("active sqlDB is required")
+	case opt.RedisClient == nil:
+		err = errors.New("non-nil redis is required")
 	case opt.FCMClient == nil:
 		err = errors.New("fcm client is required")
 	case opt.Logger == nil:
@@ -66,18 +94,69 @@ func NewMessagingServer(
 	}
 
 	ms := &messagingServer{
-		failedSend: make(chan *fcmErrFDetails, 0),
-		sqlDB:      opt.SQLDB,
-		fcmClient:  opt.FCMClient,
-		logger:     opt.Logger,
+		failedSend:       make(chan *fcmErrFDetails, 0),
+		sqlDB:            opt.SQLDB,
+		redisDB:          opt.RedisClient,
+		fcmClient:        opt.FCMClient,
+		topicManager:     opt.TopicManager,
+		locationClient:   opt.LocationClient,
+		channels:         make(map[string]Channel, len(opt.Channels)+1),
+		wake:             make(chan struct{}, 1),
+		dispatchInterval: opt.DispatchInterval,
+		maxAttempts:      opt.MaxAttempts,
+		scheduleInterval: opt.ScheduleInterval,
+		replicaID:        uuid.New().String(),
+		logger:           opt.Logger,
+		authorizeGroups: func(ctx context.Context, groups ...string) error {
+			_, err := auth.AuthenticateGroups(ctx, groups...)
+			return err
+		},
+	}
+
+	if ms.dispatchInterval <= 0 {
+		ms.dispatchInterval = defaultDispatchInterval
+	}
+	if ms.maxAttempts <= 0 {
+		ms.maxAttempts = defaultMaxAttempts
+	}
+	if ms.scheduleInterval <= 0 {
+		ms.scheduleInterval = defaultScheduleInterval
+	}
+
+	push := &pushChannel{client: opt.FCMClient}
+	ms.channels[push.Name()] = push
+	for _, channel := range opt.Channels {
+		if channel == nil {
+			continue
+		}
+		ms.channels[channel.Name()] = channel
 	}
 
 	// Auto migration
-	err = ms.sqlDB.AutoMigrate(&services.Message{}, &services.UserModel{}).Error
+	err = ms.sqlDB.AutoMigrate(
+		&services.Message{}, &services.UserModel{}, &services.UserDevice{}, &services.Delivery{},
+		&services.DeliveryAttempt{}, &services.Broadcast{}, &services.BroadcastBatch{},
+		&services.BroadcastSchedule{}, &services.MessageTemplate{}, &services.TopicSubscription{},
+		&services.AudienceVisitor{},
+	).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to automigrate: %v", err)
 	}
 
+	err = ms.saveDefaultTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to save default templates: %v", err)
+	}
+
+	// Delivers messages saved in the outbox
+	go ms.dispatchWorker(ctx)
+
+	// Sends scheduled broadcasts
+	go ms.scheduleWorker(ctx)
+
+	// Resumes broadcasts of replicas that stopped
+	go ms.resumeWorker(ctx)
+
 	return ms, nil
 }
 
@@ -123,17 +202,24 @@ func (s *messagingServer) alertContact(
 		return status.Errorf(codes.Internal, "failed to json marshal message: %v", err)
 	}
 
+	language, err := s.getLanguage(contactData.UserPhone)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get user language: %v", err)
+	}
+
+	title, message, err := s.renderTemplate(
+		services.TemplateContactAlert, language, contactAlertVariables(contactData),
+	)
+	if err != nil {
+		return err
+	}
+
 	messageModel := &services.Message{
 		UserPhone: contactData.UserPhone,
-		Title:     "COVID-19 Alert!",
-		Message: fmt.Sprintf(
-			"Hello %s, you have been in contact %d times with a person who has now tested positive for COVID-19",
-			contactData.FullName,
-			contactData.Count,
-		),
-		Sent: true,
-		Type: int8(messaging.MessageType_ALERT),
-		Data: data,
+		Title:     title,
+		Message:   message,
+		Type:      int8(messaging.MessageType_ALERT),
+		Data:      data,
 	}
 
 	// Start a transaction
@@ -155,18 +241,11 @@ func (s *messagingServer) alertContact(
 		return status.Errorf(codes.Internal, "failed to save message: %v", err)
 	}
 
-	// Send message and notification
-	_, err = s.fcmClient.SendWithRetry(&fcm.Message{
-		To:   contactData.DeviceToken,
-		Data: messageData,
-		Notification: &fcm.Notification{
-			Title: messageModel.Title,
-			Body:  messageModel.Message,
-		},
-	}, 5)
+	// Message is sent by the dispatcher after the transaction commits
+	err = enqueue(tx, messageModel, contactData.DeviceToken)
 	if err != nil {
 		tx.Rollback()
-		return status.Errorf(codes.Internal, "failed to send message to user: %v", err)
+		return status.Errorf(codes.Internal, "failed to save message delivery: %v", err)
 	}
 
 	// Commit transaction
@@ -176,153 +255,9 @@ func (s *messagingServer) alertContact(
 		return services.FailedToCommitTx(err)
 	}
 
-	return nil
-}
-
-func (s *messagingServer) BroadCastMessage(
-	ctx context.Context, req *messaging.BroadCastMessageRequest,
-) (*messaging.BroadCastMessageResponse, error) {
-	// Request must not be nil
-	if req == nil {
-		return nil, services.NilRequestError("BroadCastMessageRequest")
-	}
-
-	// Validation
-	var err error
-	switch {
-	case req.Title == "":
-		err = services.MissingFieldError("title")
-	case req.Message == "":
-		err = services.MissingFieldError("message")
-	case req.Payload == nil:
-		err = services.MissingFieldError("payload")
-	case len(req.Topics) == 0:
-		err = services.MissingFieldError("topics")
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	// Broadcast message id
-	messageID := uuid.New().String()
-
-	go s.broadCastMessage(req, messageID)
-
-	return &messaging.BroadCastMessageResponse{
-		BroadcastMessageId: messageID,
-	}, nil
-}
-
-func (s *messagingServer) broadCastMessage(
-	req *messaging.BroadCastMessageRequest, messageID string,
-) {
-	// Start transaction
-	tx := s.sqlDB.Begin()
-	defer func() {
-		if err := recover(); err != nil {
-			tx.Rollback()
-		}
-	}()
-	err := tx.Error
-	if err != nil {
-		s.logger.Errorf("failed to start broadcast transaction: %v", err)
-		return
-	}
-
-	for _, filter := range req.Filters {
-		switch filter {
-		case messaging.BroadCastMessageFilter_ALL:
-		case messaging.BroadCastMessageFilter_POSITIVES:
-			tx = tx.Where("status = ?", location.Status_POSITIVE)
-		case messaging.BroadCastMessageFilter_NEGATIVES:
-			tx = tx.Where("status = ?", location.Status_NEGATIVE)
-		case messaging.BroadCastMessageFilter_BY_COUNTY:
-			if len(req.GetTopics()) > 0 {
-				tx = tx.Where("county IN(?)", req.GetTopics())
-			}
-		}
-	}
-
-	// Message payload
-	bs, err := json.Marshal(req.Payload)
-	if err != nil {
-		tx.Rollback()
-		s.logger.Errorf("failed to marshal payload: %v", err)
-		return
-	}
-
-	// FCM payload
-	payload := map[string]interface{}{}
-	for key, value := range req.Payload {
-		payload[key] = value
-	}
-
-	condition := true
-	limit := 1000
-	offset := 0
-
-	usersDB := make([]*services.UserModel, 0)
-
-	for condition {
-		deviceTokens := make([]string, 0, limit)
-
-		// Get device tokens
-		err = tx.Offset(offset).Limit(limit).Select("device_token, phone_number").
-			Find(&usersDB).Error
-		if err != nil {
-			tx.Rollback()
-			s.logger.Errorf("failed to get device token: %v", err)
-			return
-		}
-
-		if len(usersDB) < limit {
-			condition = false
-		}
-
-		for _, userDB := range usersDB {
-			deviceTokens = append(deviceTokens, userDB.DeviceToken)
-
-			// Save user message
-			userMsg := &services.Message{
-				UserPhone: userDB.PhoneNumber,
-				Title:     req.Title,
-				Message:   req.Message,
-				Data:      bs,
-				Sent:      true,
-				Type:      int8(req.Type),
-			}
-
-			err = tx.Create(userMsg).Error
-			if err != nil {
-				s.logger.Errorf("failed to save user broadcast message: %v", err)
-				continue
-			}
-		}
-
-		// Send message to devices
-		_, err = s.fcmClient.SendWithRetry(&fcm.Message{
-			RegistrationIDs: deviceTokens,
-			Data:            payload,
-			Notification: &fcm.Notification{
-				Title: req.Title,
-				Body:  req.Message,
-			},
-		}, 5)
-		if err != nil {
-			tx.Rollback()
-			s.logger.Errorf("failed to send users message and notifications: %v", err)
-			return
-		}
+	s.wakeDispatcher()
 
-		// Commit the transaction
-		err = tx.Commit().Error
-		if err != nil {
-			tx.Rollback()
-			return
-		}
-
-		offset += len(usersDB)
-	}
+	return nil
 }
 
 func (s *messagingServer) SendMessage(
@@ -338,9 +273,9 @@ func (s *messagingServer) SendMessage(
 	switch {
 	case msg.UserPhone == "":
 		err = services.MissingFieldError("user phone")
-	case msg.Title == "":
+	case msg.Title == "" && msg.Template == "":
 		err = services.MissingFieldError("title")
-	case msg.Notification == "":
+	case msg.Notification == "" && msg.Template == "":
 		err = services.MissingFieldError("notification")
 	case msg.Data == nil:
 		err = services.MissingFieldError("data")
@@ -349,10 +284,8 @@ func (s *messagingServer) SendMessage(
 		return nil, err
 	}
 
-	// Get user device token
-	userDB := &services.UserModel{}
-	err = s.sqlDB.Table(services.UsersTable).Select("device_token").
-		First(userDB, "phone_number=?", msg.UserPhone).Error
+	// Get user addresses
+	_, err = s.getRecipient(msg.UserPhone)
 	switch {
 	case err == nil:
 	case errors.Is(err, gorm.ErrRecordNotFound):
@@ -361,6 +294,18 @@ func (s *messagingServer) SendMessage(
 		return nil, status.Errorf(codes.NotFound, "error happened: %v", err)
 	}
 
+	// Message is written in the language of the user
+	if msg.Template != "" {
+		language, err := s.getLanguage(msg.UserPhone)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get user language: %v", err)
+		}
+		msg.Title, msg.Notification, err = s.renderTemplate(msg.Template, language, msg.TemplateVariables)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Start transaction
 	tx := s.sqlDB.Begin()
 	defer func() {
@@ -379,7 +324,7 @@ func (s *messagingServer) SendMessage(
 		return nil, err
 	}
 
-	userMsg.Sent = true
+	userMsg.Sent = false
 
 	err = tx.Create(userMsg).Error
 	if err != nil {
@@ -387,23 +332,11 @@ func (s *messagingServer) SendMessage(
 		return nil, status.Errorf(codes.Internal, "failed to save user message: %v", err)
 	}
 
-	data := map[string]interface{}{}
-	for key, value := range msg.Data {
-		data[key] = value
-	}
-
-	// Send notification and message
-	_, err = s.fcmClient.SendWithRetry(&fcm.Message{
-		To:   userDB.DeviceToken,
-		Data: data,
-		Notification: &fcm.Notification{
-			Title: msg.Title,
-			Body:  msg.Notification,
-		},
-	}, 5)
+	// Message is sent by the dispatcher after the transaction commits
+	err = enqueue(tx, userMsg, "")
 	if err != nil {
 		tx.Rollback()
-		return nil, status.Errorf(codes.Internal, "failed to send message to user: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to save message delivery: %v", err)
 	}
 
 	err = tx.Commit().Error
@@ -412,11 +345,64 @@ func (s *messagingServer) SendMessage(
 		return nil, services.FailedToCommitTx(err)
 	}
 
+	s.wakeDispatcher()
+
 	return &messaging.SendMessageResponse{
 		MessageId: fmt.Sprint(userMsg.ID),
 	}, nil
 }
 
+// recipientColumns are the columns of users needed to notify them
+const recipientColumns = "phone_number, full_name, device_token, email, channels"
+
+func (s *messagingServer) getRecipient(phoneNumber string) (*Recipient, error) {
+	userDB := &services.UserModel{}
+	err := s.sqlDB.Table(services.UsersTable).Select(recipientColumns).
+		First(userDB, "phone_number=?", phoneNumber).Error
+	if err != nil {
+		return nil, err
+	}
+
+	userDevices, err := s.getDeviceTokens(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return getRecipient(userDB, userDevices[phoneNumber]), nil
+}
+
+// getDeviceTokens returns tokens of devices registered to the users, most recently seen first.
+// Devices are removed when unregistered or when FCM reports their token invalid, so every device is active.
+func (s *messagingServer) getDeviceTokens(phoneNumbers ...string) (map[string][]string, error) {
+	devicesDB := make([]*services.UserDevice, 0, len(phoneNumbers))
+	err := s.sqlDB.Select("phone_number, device_token").Order("last_seen_at DESC").
+		Find(&devicesDB, "phone_number IN(?)", phoneNumbers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	deviceTokens := make(map[string][]string, len(phoneNumbers))
+	for _, deviceDB := range devicesDB {
+		deviceTokens[deviceDB.PhoneNumber] = append(deviceTokens[deviceDB.PhoneNumber], deviceDB.DeviceToken)
+	}
+
+	return deviceTokens, nil
+}
+
+// firstChannel returns the first channel that reaches the recipient in their order of preference
+func (s *messagingServer) firstChannel(recipient *Recipient) string {
+	channels := recipient.Channels
+	if len(channels) == 0 {
+		channels = defaultChannels
+	}
+	for _, name := range channels {
+		if channel, ok := s.channel(name); ok && channel.Reaches(recipient) {
+			return name
+		}
+	}
+	return ""
+}
+
 func (s *messagingServer) ListMessages(
 	ctx context.Context, listReq *messaging.ListMessagesRequest,
 ) (*messaging.Messages, error) {
@@ -466,6 +452,11 @@ func (s *messagingServer) ListMessages(
 		messagesPB = append(messagesPB, messagePB)
 	}
 
+	err = s.setDeliveryStatus(messagesPB)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get message deliveries: %v", err)
+	}
+
 	return &messaging.Messages{
 		Messages: messagesPB,
 	}, nil
@@ -528,6 +519,7 @@ func getMessagePB(messageDB *services.Message) (*messaging.Message, error) {
 		Sent:         messageDB.Sent,
 		Seen:         messageDB.Seen,
 		Type:         messaging.MessageType(messageDB.Type),
+		Channel:      messageDB.Channel,
 	}
 
 	if len(messageDB.Data) != 0 {
@@ -3,17 +3,26 @@ package messaging
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gidyon/micros"
 
+	"github.com/go-redis/redis"
+
 	"github.com/appleboy/go-fcm"
 
+	"github.com/gidyon/pandemic-api/internal/auth"
 	"github.com/gidyon/pandemic-api/internal/services/messaging/mocks"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/gidyon/pandemic-api/pkg/api/location"
 	"github.com/gidyon/pandemic-api/pkg/api/messaging"
 	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/onsi/ginkgo"
@@ -28,11 +37,27 @@ func TestMessaging(t *testing.T) {
 var (
 	MessagingServer *messagingServer
 	MessagingAPI    messaging.MessagingServer
+	// geoFenceVisits is when users were last seen in geo fences, by fence id and phone number
+	geoFenceVisits = make(map[string]map[string]time.Time)
+	// geoFenceVisitorsGroup is the group of the token geo fence visitors were last listed with
+	geoFenceVisitorsGroup string
+	// geoFenceVisitorsPages is how many pages of geo fence visitors were listed
+	geoFenceVisitorsPages int
 )
 
+// tokenFromOutgoingContext returns the bearer token sent with a call
+func tokenFromOutgoingContext(ctx context.Context) string {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		return strings.TrimPrefix(value, "Bearer ")
+	}
+	return ""
+}
+
 const (
-	dbAddress = "192.168.100.10:3306"
-	schema    = "fightcovid19"
+	dbAddress    = "192.168.100.10:3306"
+	schema       = "fightcovid19"
+	redisAddress = "localhost:6379"
 )
 
 func startDB() (*gorm.DB, error) {
@@ -56,10 +81,66 @@ var _ = BeforeSuite(func() {
 	fcmClient.On("SendWithContext", mock.Anything, mock.Anything).
 		Return(&fcm.Response{}, nil)
 
+	// Devices with tokens starting with unregistered are unknown to FCM
+	topicResults := func(ctx context.Context, topic string, deviceTokens []string) map[string]error {
+		failed := make(map[string]error)
+		for _, deviceToken := range deviceTokens {
+			if strings.HasPrefix(deviceToken, "unregistered") {
+				failed[deviceToken] = ErrDeviceNotRegistered
+			}
+		}
+		return failed
+	}
+
+	topicManager := &mocks.TopicManagerMock{}
+	topicManager.On("Subscribe", mock.Anything, mock.Anything, mock.Anything).
+		Return(topicResults, nil)
+	topicManager.On("Unsubscribe", mock.Anything, mock.Anything, mock.Anything).
+		Return(topicResults, nil)
+
+	// Users seen in geo fences are resolved by the location service
+	locationClient := &mocks.LocationClientMock{}
+	locationClient.On("ListGeoFenceVisitors", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(
+			ctx context.Context, listReq *location.ListGeoFenceVisitorsRequest, opts ...grpc.CallOption,
+		) *location.GeoFenceVisitors {
+			// Remembers the group the messaging service called with
+			geoFenceVisitorsGroup = ""
+			if claims, err := auth.ParseToken(tokenFromOutgoingContext(ctx)); err == nil {
+				geoFenceVisitorsGroup = claims.Group
+			}
+
+			// Pages through visitors ordered by phone number, the last one listed is the page token
+			phoneNumbers := make([]string, 0)
+			for _, fenceID := range listReq.GeoFenceIds {
+				for phoneNumber, lastSeen := range geoFenceVisits[fenceID] {
+					if lastSeen.Unix() >= listReq.SeenSinceTimestamp && phoneNumber > listReq.PageToken {
+						phoneNumbers = append(phoneNumbers, phoneNumber)
+					}
+				}
+			}
+			sort.Strings(phoneNumbers)
+
+			visitorsPB := &location.GeoFenceVisitors{PhoneNumbers: phoneNumbers}
+			if listReq.PageSize > 0 && len(phoneNumbers) > int(listReq.PageSize) {
+				visitorsPB.PhoneNumbers = phoneNumbers[:listReq.PageSize]
+				visitorsPB.NextPageToken = phoneNumbers[listReq.PageSize-1]
+			}
+			geoFenceVisitorsPages++
+			return visitorsPB
+		}, nil)
+
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddress,
+	})
+
 	opt := &Options{
-		SQLDB:     db,
-		FCMClient: fcmClient,
-		Logger:    micros.NewLogger("messaging"),
+		SQLDB:          db,
+		RedisClient:    redisDB,
+		FCMClient:      fcmClient,
+		TopicManager:   topicManager,
+		LocationClient: locationClient,
+		Logger:         micros.NewLogger("messaging"),
 	}
 
 	// Create messaging server
@@ -70,12 +151,21 @@ var _ = BeforeSuite(func() {
 	MessagingServer, ok = MessagingAPI.(*messagingServer)
 	Expect(ok).Should(BeTrue())
 
+	MessagingServer.authorizeGroups = func(context.Context, ...string) error {
+		return nil
+	}
+
 	// Pasing incorrect payload
 	opt.SQLDB = nil
 	_, err = NewMessagingServer(ctx, opt)
 	Expect(err).Should(HaveOccurred())
 
 	opt.SQLDB = db
+	opt.RedisClient = nil
+	_, err = NewMessagingServer(ctx, opt)
+	Expect(err).Should(HaveOccurred())
+
+	opt.RedisClient = redisDB
 	opt.FCMClient = nil
 	_, err = NewMessagingServer(ctx, opt)
 	Expect(err).Should(HaveOccurred())
@@ -1,6 +1,20 @@
 package messaging
 
+import (
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+)
+
 // FCMClientMock is mock for fcmClient
 type FCMClientMock interface {
 	fcmClient
 }
+
+// TopicManagerMock is mock for TopicManager
+type TopicManagerMock interface {
+	TopicManager
+}
+
+// LocationClientMock creates a mock for location API
+type LocationClientMock interface {
+	location.LocationTracingAPIClient
+}
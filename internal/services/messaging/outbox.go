@@ -0,0 +1,238 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+)
+
+const (
+	defaultDispatchInterval = 5 * time.Second
+	defaultMaxAttempts      = 5
+	dispatchBatchSize       = 100
+	// deliveryLease is how long a claimed delivery is hidden from other dispatchers
+	deliveryLease = time.Minute
+	// retryBackoff is the wait after the first failed attempt, doubled after each attempt up to maxRetryBackoff
+	retryBackoff    = 30 * time.Second
+	maxRetryBackoff = time.Hour
+)
+
+// enqueue saves the delivery of a message in the transaction that saves the message.
+// The message is delivered by the dispatcher once the transaction is committed.
+func enqueue(tx *gorm.DB, messageDB *services.Message, deviceToken string) error {
+	return tx.Create(&services.Delivery{
+		MessageID:     messageDB.ID,
+		UserPhone:     messageDB.UserPhone,
+		DeviceToken:   deviceToken,
+		Status:        services.DeliveryPending,
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// wakeDispatcher makes the dispatcher look for pending deliveries without waiting for the next interval
+func (s *messagingServer) wakeDispatcher() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchWorker delivers pending messages until the context is cancelled
+func (s *messagingServer) dispatchWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.dispatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		for {
+			n, err := s.dispatch(ctx)
+			if err != nil {
+				s.logger.Errorf("failed to dispatch messages: %v", err)
+			}
+			if err != nil || n < dispatchBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// dispatch delivers a batch of pending messages that are due, returning how many were claimed
+func (s *messagingServer) dispatch(ctx context.Context) (int, error) {
+	deliveriesDB := make([]*services.Delivery, 0, dispatchBatchSize)
+
+	err := s.sqlDB.Where("status = ? AND next_attempt_at <= ?", services.DeliveryPending, time.Now()).
+		Order("next_attempt_at").Limit(dispatchBatchSize).Find(&deliveriesDB).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, deliveryDB := range deliveriesDB {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		// Other replicas may have claimed the delivery
+		claimed, err := s.claim(deliveryDB)
+		if err != nil {
+			return 0, err
+		}
+		if !claimed {
+			continue
+		}
+
+		err = s.attemptDelivery(ctx, deliveryDB)
+		if err != nil {
+			s.logger.Errorf("failed to record delivery of message %d: %v", deliveryDB.MessageID, err)
+		}
+	}
+
+	return len(deliveriesDB), nil
+}
+
+// claim hides the delivery from other dispatchers for the lease, it reports false when the delivery has been claimed
+func (s *messagingServer) claim(deliveryDB *services.Delivery) (bool, error) {
+	leaseEnd := time.Now().Add(deliveryLease)
+
+	db := s.sqlDB.Model(&services.Delivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", deliveryDB.ID, services.DeliveryPending, deliveryDB.NextAttemptAt).
+		Update("next_attempt_at", leaseEnd)
+	if db.Error != nil {
+		return false, db.Error
+	}
+
+	deliveryDB.NextAttemptAt = leaseEnd
+
+	return db.RowsAffected == 1, nil
+}
+
+func (s *messagingServer) attemptDelivery(ctx context.Context, deliveryDB *services.Delivery) error {
+	messageDB := &services.Message{}
+	err := s.sqlDB.First(messageDB, "id=?", deliveryDB.MessageID).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Message deleted with the user
+		return s.sqlDB.Model(deliveryDB).Updates(map[string]interface{}{
+			"status":     services.DeliveryFailed,
+			"last_error": "message not found",
+		}).Error
+	default:
+		return err
+	}
+
+	recipient, err := s.getRecipient(deliveryDB.UserPhone)
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		recipient = &Recipient{PhoneNumber: deliveryDB.UserPhone}
+	default:
+		return err
+	}
+	if deliveryDB.DeviceToken != "" && deliveryDB.DeviceToken != noDeviceToken {
+		recipient.DeviceTokens = []string{deliveryDB.DeviceToken}
+	}
+
+	notification := &Notification{
+		Title: messageDB.Title,
+		Body:  messageDB.Message,
+		Data:  map[string]interface{}{},
+	}
+	if len(messageDB.Data) != 0 {
+		data := make(map[string]string)
+		err = json.Unmarshal(messageDB.Data, &data)
+		if err != nil {
+			return err
+		}
+		for key, value := range data {
+			notification.Data[key] = value
+		}
+		notification.CollapseKey = data["collapse_key"]
+	}
+
+	channel, attempts, errDeliver := s.deliver(ctx, recipient, notification)
+
+	updates := map[string]interface{}{
+		"attempts": deliveryDB.Attempts + 1,
+	}
+
+	// Retries must not use an invalid token given with the message
+	for _, attempt := range attempts {
+		if update := tokenUpdate(deliveryDB.DeviceToken, attempt); update != nil && update.Invalid {
+			updates["device_token"] = ""
+		}
+	}
+
+	switch {
+	case errDeliver == nil:
+		updates["status"] = services.DeliverySent
+		updates["channel"] = channel
+		updates["last_error"] = ""
+	case deliveryDB.Attempts+1 >= s.maxAttempts:
+		updates["status"] = services.DeliveryFailed
+		updates["last_error"] = truncate(errDeliver.Error(), 256)
+	default:
+		updates["next_attempt_at"] = time.Now().Add(backoff(deliveryDB.Attempts + 1))
+		updates["last_error"] = truncate(errDeliver.Error(), 256)
+	}
+
+	tx := s.sqlDB.Begin()
+	defer func() {
+		if err := recover(); err != nil {
+			tx.Rollback()
+		}
+	}()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	for _, attempt := range attempts {
+		attempt.DeliveryID = deliveryDB.ID
+		err = tx.Create(attempt).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Model(deliveryDB).Updates(updates).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if errDeliver == nil {
+		err = tx.Model(messageDB).Updates(map[string]interface{}{
+			"sent":    true,
+			"channel": channel,
+		}).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// backoff returns the wait before the next attempt after the given number of attempts
+func backoff(attempts int) time.Duration {
+	wait := retryBackoff
+	for i := 1; i < attempts && wait < maxRetryBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxRetryBackoff {
+		wait = maxRetryBackoff
+	}
+	return wait
+}
@@ -0,0 +1,119 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+// failingChannel is a SMS channel that never delivers
+type failingChannel struct {
+	smsChannel
+}
+
+func (*failingChannel) Send(context.Context, *Recipient, *Notification) (*Receipt, error) {
+	return &Receipt{ErrorCode: "500"}, errors.New("sms gateway unavailable")
+}
+
+var _ = Describe("Delivering messages saved in the outbox £outbox", func() {
+	var (
+		ctx      context.Context
+		replaced Channel
+	)
+
+	deliveryOf := func(messageID string) *services.Delivery {
+		id, err := strconv.Atoi(messageID)
+		Expect(err).ShouldNot(HaveOccurred())
+		deliveryDB := &services.Delivery{}
+		err = MessagingServer.sqlDB.First(deliveryDB, "message_id=?", id).Error
+		Expect(err).ShouldNot(HaveOccurred())
+		return deliveryDB
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		replaced = MessagingServer.setChannel(&failingChannel{})
+	})
+
+	AfterEach(func() {
+		if replaced != nil {
+			MessagingServer.setChannel(replaced)
+		}
+	})
+
+	It("should save the message before it is delivered and mark it sent after", func() {
+		userDB := &services.UserModel{
+			PhoneNumber: randomPhone(),
+			FullName:    randomdata.FullName(randomdata.RandomGender),
+			County:      randomdata.State(randomdata.Large),
+			DeviceToken: randomdata.MacAddress(),
+		}
+		Expect(MessagingServer.sqlDB.Create(userDB).Error).ShouldNot(HaveOccurred())
+
+		msg := fakeMessage()
+		msg.UserPhone = userDB.PhoneNumber
+		sendRes, err := MessagingAPI.SendMessage(ctx, msg)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Eventually(func() string {
+			return deliveryOf(sendRes.MessageId).Status
+		}).Should(Equal(services.DeliverySent))
+
+		deliveryDB := deliveryOf(sendRes.MessageId)
+		Expect(deliveryDB.Attempts).Should(Equal(1))
+		Expect(deliveryDB.Channel).Should(Equal(ChannelPush))
+
+		messageDB := &services.Message{}
+		err = MessagingServer.sqlDB.First(messageDB, "id=?", deliveryDB.MessageID).Error
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(messageDB.Sent).Should(BeTrue())
+	})
+
+	It("should keep the message and retry later when no channel delivers it", func() {
+		userDB := &services.UserModel{
+			PhoneNumber: randomPhone(),
+			FullName:    randomdata.FullName(randomdata.RandomGender),
+			County:      randomdata.State(randomdata.Large),
+			DeviceToken: noDeviceToken,
+		}
+		Expect(MessagingServer.sqlDB.Create(userDB).Error).ShouldNot(HaveOccurred())
+
+		msg := fakeMessage()
+		msg.UserPhone = userDB.PhoneNumber
+		sendRes, err := MessagingAPI.SendMessage(ctx, msg)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Eventually(func() int {
+			return deliveryOf(sendRes.MessageId).Attempts
+		}).Should(Equal(1))
+
+		deliveryDB := deliveryOf(sendRes.MessageId)
+		Expect(deliveryDB.Status).Should(Equal(services.DeliveryPending))
+		Expect(deliveryDB.LastError).ShouldNot(BeEmpty())
+		Expect(deliveryDB.NextAttemptAt).Should(BeTemporally(">", time.Now()))
+
+		attemptsDB := make([]*services.DeliveryAttempt, 0)
+		err = MessagingServer.sqlDB.Find(&attemptsDB, "delivery_id=?", deliveryDB.ID).Error
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(attemptsDB).Should(HaveLen(1))
+		Expect(attemptsDB[0].Channel).Should(Equal(ChannelSMS))
+		Expect(attemptsDB[0].Delivered).Should(BeFalse())
+
+		// The message can still be read by the user
+		listRes, err := MessagingAPI.ListMessages(ctx, &messaging.ListMessagesRequest{PhoneNumber: userDB.PhoneNumber})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(listRes.Messages).Should(HaveLen(1))
+		Expect(listRes.Messages[0].Sent).Should(BeFalse())
+	})
+
+	It("should back off exponentially up to an hour", func() {
+		Expect(backoff(1)).Should(Equal(retryBackoff))
+		Expect(backoff(2)).Should(Equal(2 * retryBackoff))
+		Expect(backoff(100)).Should(Equal(maxRetryBackoff))
+	})
+})
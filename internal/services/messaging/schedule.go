@@ -0,0 +1,477 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/messaging/cron"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+const (
+	defaultScheduleInterval = 30 * time.Second
+	defaultTimezone         = "Africa/Nairobi"
+	// schedulerLeaderKey holds the id of the replica sending scheduled broadcasts
+	schedulerLeaderKey = "messaging:scheduler:leader"
+	schedulesBatchSize = 100
+)
+
+// extendLeaseScript extends the leader lease only if it is still held by the replica
+var extendLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// scheduleBroadcast saves a broadcast to be sent by the scheduler
+func (s *messagingServer) scheduleBroadcast(
+	req *messaging.BroadCastMessageRequest,
+) (*messaging.BroadCastMessageResponse, error) {
+	scheduleDB, err := getScheduleDB(req)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleDB.ScheduleID = uuid.New().String()
+
+	scheduleDB.NextRunAt, err = nextRun(scheduleDB, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schedule: %v", err)
+	}
+	if scheduleDB.NextRunAt == nil {
+		return nil, status.Error(codes.InvalidArgument, "schedule ends before it runs")
+	}
+
+	err = s.sqlDB.Create(scheduleDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save schedule: %v", err)
+	}
+
+	return &messaging.BroadCastMessageResponse{
+		ScheduleId: scheduleDB.ScheduleID,
+	}, nil
+}
+
+func (s *messagingServer) ListSchedules(
+	ctx context.Context, listReq *messaging.ListSchedulesRequest,
+) (*messaging.Schedules, error) {
+	// Request must not be nil
+	if listReq == nil {
+		return nil, services.NilRequestError("ListSchedulesRequest")
+	}
+
+	// Normalize page
+	pageNumber, pageSize := normalizePage(listReq.GetPageToken(), listReq.GetPageSize())
+	offset := pageNumber*pageSize - pageSize
+
+	schedulesDB := make([]*services.BroadcastSchedule, 0, pageSize)
+	err := s.sqlDB.Order("id DESC").Offset(offset).Limit(pageSize).Find(&schedulesDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get schedules: %v", err)
+	}
+
+	schedulesPB := make([]*messaging.Schedule, 0, len(schedulesDB))
+	for _, scheduleDB := range schedulesDB {
+		schedulePB, err := getSchedulePB(scheduleDB)
+		if err != nil {
+			return nil, err
+		}
+		schedulesPB = append(schedulesPB, schedulePB)
+	}
+
+	schedulesRes := &messaging.Schedules{
+		Schedules: schedulesPB,
+	}
+	if len(schedulesDB) == pageSize {
+		schedulesRes.NextPageToken = int32(pageNumber + 1)
+	}
+
+	return schedulesRes, nil
+}
+
+func (s *messagingServer) PauseSchedule(
+	ctx context.Context, pauseReq *messaging.ScheduleRequest,
+) (*messaging.Schedule, error) {
+	// Request must not be nil
+	if pauseReq == nil {
+		return nil, services.NilRequestError("ScheduleRequest")
+	}
+
+	// Validation
+	if pauseReq.ScheduleId == "" {
+		return nil, services.MissingFieldError("schedule id")
+	}
+
+	scheduleDB, err := s.getSchedule(pauseReq.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sqlDB.Model(scheduleDB).Update("paused", true).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to pause schedule: %v", err)
+	}
+
+	return getSchedulePB(scheduleDB)
+}
+
+func (s *messagingServer) ResumeSchedule(
+	ctx context.Context, resumeReq *messaging.ScheduleRequest,
+) (*messaging.Schedule, error) {
+	// Request must not be nil
+	if resumeReq == nil {
+		return nil, services.NilRequestError("ScheduleRequest")
+	}
+
+	// Validation
+	if resumeReq.ScheduleId == "" {
+		return nil, services.MissingFieldError("schedule id")
+	}
+
+	scheduleDB, err := s.getSchedule(resumeReq.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !scheduleDB.Paused {
+		return getSchedulePB(scheduleDB)
+	}
+
+	// Runs missed while the schedule was paused are skipped
+	nextRunAt, err := nextRun(scheduleDB, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get next run of schedule: %v", err)
+	}
+
+	err = s.sqlDB.Model(scheduleDB).Updates(map[string]interface{}{
+		"paused":      false,
+		"next_run_at": nextRunAt,
+	}).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to resume schedule: %v", err)
+	}
+
+	return getSchedulePB(scheduleDB)
+}
+
+func (s *messagingServer) DeleteSchedule(
+	ctx context.Context, delReq *messaging.ScheduleRequest,
+) (*empty.Empty, error) {
+	// Request must not be nil
+	if delReq == nil {
+		return nil, services.NilRequestError("ScheduleRequest")
+	}
+
+	// Validation
+	if delReq.ScheduleId == "" {
+		return nil, services.MissingFieldError("schedule id")
+	}
+
+	db := s.sqlDB.Delete(&services.BroadcastSchedule{}, "schedule_id=?", delReq.ScheduleId)
+	switch {
+	case db.Error != nil:
+		return nil, status.Errorf(codes.Internal, "failed to delete schedule: %v", db.Error)
+	case db.RowsAffected == 0:
+		return nil, status.Errorf(codes.NotFound, "schedule with id %s not found", delReq.ScheduleId)
+	}
+
+	return emptyMsg, nil
+}
+
+func (s *messagingServer) getSchedule(scheduleID string) (*services.BroadcastSchedule, error) {
+	scheduleDB := &services.BroadcastSchedule{}
+	err := s.sqlDB.First(scheduleDB, "schedule_id=?", scheduleID).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "schedule with id %s not found", scheduleID)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get schedule: %v", err)
+	}
+	return scheduleDB, nil
+}
+
+// scheduleWorker sends scheduled broadcasts that are due while the replica is the scheduler leader
+func (s *messagingServer) scheduleWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		leader, err := s.leadScheduler(ctx)
+		if err != nil {
+			s.logger.Errorf("failed to elect scheduler leader: %v", err)
+			continue
+		}
+		if !leader {
+			continue
+		}
+
+		err = s.runSchedules(ctx)
+		if err != nil {
+			s.logger.Errorf("failed to run schedules: %v", err)
+		}
+	}
+}
+
+// leadScheduler acquires or extends the leader lease, reporting whether the replica is the scheduler leader.
+// The lease outlives a few intervals so that another replica takes over only when the leader stops.
+func (s *messagingServer) leadScheduler(ctx context.Context) (bool, error) {
+	lease := 3 * s.scheduleInterval
+
+	ok, err := s.redisDB.SetNX(ctx, schedulerLeaderKey, s.replicaID, lease).Result()
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return true, nil
+	}
+
+	extended, err := extendLeaseScript.Run(
+		ctx, s.redisDB, []string{schedulerLeaderKey}, s.replicaID, lease.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return false, err
+	}
+
+	return extended == 1, nil
+}
+
+// runSchedules starts a broadcast for each schedule that is due
+func (s *messagingServer) runSchedules(ctx context.Context) error {
+	now := time.Now()
+
+	schedulesDB := make([]*services.BroadcastSchedule, 0, schedulesBatchSize)
+	err := s.sqlDB.Where("paused = ? AND next_run_at <= ?", false, now).
+		Order("next_run_at").Limit(schedulesBatchSize).Find(&schedulesDB).Error
+	if err != nil {
+		return err
+	}
+
+	for _, scheduleDB := range schedulesDB {
+		broadcastID, err := s.runSchedule(ctx, scheduleDB, now)
+		if err != nil {
+			s.logger.Errorf("failed to run schedule %s: %v", scheduleDB.ScheduleID, err)
+			continue
+		}
+		if broadcastID != "" {
+			go s.runBroadcast(ctx, broadcastID)
+		}
+	}
+
+	return nil
+}
+
+// runSchedule saves the broadcast of a schedule that is due and sets when the schedule runs next.
+// It returns an empty broadcast id when the schedule was run by another replica.
+func (s *messagingServer) runSchedule(
+	ctx context.Context, scheduleDB *services.BroadcastSchedule, now time.Time,
+) (string, error) {
+	// Runs missed while the service was down are skipped
+	nextRunAt, err := nextRun(scheduleDB, now)
+	if err != nil {
+		return "", err
+	}
+
+	broadcastDB := getScheduledBroadcastDB(scheduleDB)
+
+	err = s.prepareBroadcast(ctx, broadcastDB)
+	if err != nil {
+		return "", err
+	}
+
+	// Start transaction
+	tx := s.sqlDB.Begin()
+	defer func() {
+		if err := recover(); err != nil {
+			tx.Rollback()
+		}
+	}()
+	if tx.Error != nil {
+		return "", tx.Error
+	}
+
+	// A replica that was leader before may have run the schedule already
+	db := tx.Model(&services.BroadcastSchedule{}).
+		Where("id = ? AND next_run_at = ?", scheduleDB.ID, scheduleDB.NextRunAt).
+		Updates(map[string]interface{}{
+			"next_run_at":       nextRunAt,
+			"last_run_at":       now,
+			"last_broadcast_id": broadcastDB.BroadcastID,
+			"runs":              gorm.Expr("runs + 1"),
+		})
+	switch {
+	case db.Error != nil:
+		tx.Rollback()
+		return "", db.Error
+	case db.RowsAffected == 0:
+		tx.Rollback()
+		return "", nil
+	}
+
+	err = tx.Create(broadcastDB).Error
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return "", err
+	}
+
+	return broadcastDB.BroadcastID, nil
+}
+
+// nextRun returns when the schedule runs after the given time, or nil when it does not run again
+func nextRun(scheduleDB *services.BroadcastSchedule, after time.Time) (*time.Time, error) {
+	if scheduleDB.CronExpression == "" {
+		if scheduleDB.SendAt != nil && scheduleDB.SendAt.After(after) {
+			return scheduleDB.SendAt, nil
+		}
+		return nil, nil
+	}
+
+	schedule, err := cron.Parse(scheduleDB.CronExpression)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(scheduleDB.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recurring broadcasts start at the send at time
+	if scheduleDB.SendAt != nil && scheduleDB.SendAt.After(after) {
+		after = scheduleDB.SendAt.Add(-time.Second)
+	}
+
+	next := schedule.Next(after.In(loc))
+	if next.IsZero() || scheduleDB.EndAt != nil && next.After(*scheduleDB.EndAt) {
+		return nil, nil
+	}
+
+	return &next, nil
+}
+
+func getScheduleDB(req *messaging.BroadCastMessageRequest) (*services.BroadcastSchedule, error) {
+	schedulePB := req.Schedule
+
+	// Validation
+	var err error
+	switch {
+	case schedulePB.SendAtTimestamp == 0 && schedulePB.CronExpression == "":
+		err = services.MissingFieldError("schedule send at timestamp or cron expression")
+	case schedulePB.SendAtTimestamp != 0 && schedulePB.SendAtTimestamp <= time.Now().Unix():
+		err = status.Error(codes.InvalidArgument, "schedule send at timestamp must be in the future")
+	case schedulePB.EndTimestamp != 0 && schedulePB.CronExpression == "":
+		err = status.Error(codes.InvalidArgument, "schedule end timestamp requires a cron expression")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastDB, err := getBroadcastDB(req)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleDB := &services.BroadcastSchedule{
+		Title:          broadcastDB.Title,
+		Message:        broadcastDB.Message,
+		Type:           broadcastDB.Type,
+		Payload:        broadcastDB.Payload,
+		Filters:        broadcastDB.Filters,
+		Topics:         broadcastDB.Topics,
+		Audience:       broadcastDB.Audience,
+		CronExpression: schedulePB.CronExpression,
+		Timezone:       schedulePB.Timezone,
+	}
+
+	if scheduleDB.Timezone == "" {
+		scheduleDB.Timezone = defaultTimezone
+	}
+
+	if schedulePB.SendAtTimestamp != 0 {
+		sendAt := time.Unix(schedulePB.SendAtTimestamp, 0)
+		scheduleDB.SendAt = &sendAt
+	}
+
+	if schedulePB.EndTimestamp != 0 {
+		endAt := time.Unix(schedulePB.EndTimestamp, 0)
+		scheduleDB.EndAt = &endAt
+	}
+
+	return scheduleDB, nil
+}
+
+// getScheduledBroadcastDB returns the broadcast sent on a run of the schedule
+func getScheduledBroadcastDB(scheduleDB *services.BroadcastSchedule) *services.Broadcast {
+	return &services.Broadcast{
+		Title:      scheduleDB.Title,
+		Message:    scheduleDB.Message,
+		Type:       scheduleDB.Type,
+		Payload:    scheduleDB.Payload,
+		Filters:    scheduleDB.Filters,
+		Topics:     scheduleDB.Topics,
+		Audience:   scheduleDB.Audience,
+		ScheduleID: scheduleDB.ScheduleID,
+	}
+}
+
+func getSchedulePB(scheduleDB *services.BroadcastSchedule) (*messaging.Schedule, error) {
+	broadcastPB, err := getBroadcastPB(getScheduledBroadcastDB(scheduleDB))
+	if err != nil {
+		return nil, err
+	}
+
+	schedulePB := &messaging.Schedule{
+		ScheduleId:             scheduleDB.ScheduleID,
+		Title:                  broadcastPB.Title,
+		Message:                broadcastPB.Message,
+		Type:                   broadcastPB.Type,
+		Filters:                broadcastPB.Filters,
+		Topics:                 broadcastPB.Topics,
+		Payload:                broadcastPB.Payload,
+		Audience:               broadcastPB.Audience,
+		Paused:                 scheduleDB.Paused,
+		LastBroadcastMessageId: scheduleDB.LastBroadcastID,
+		Runs:                   scheduleDB.Runs,
+		CreatedTimestamp:       scheduleDB.CreatedAt.Unix(),
+		Schedule: &messaging.BroadcastSchedule{
+			CronExpression: scheduleDB.CronExpression,
+			Timezone:       scheduleDB.Timezone,
+		},
+	}
+
+	if scheduleDB.SendAt != nil {
+		schedulePB.Schedule.SendAtTimestamp = scheduleDB.SendAt.Unix()
+	}
+	if scheduleDB.EndAt != nil {
+		schedulePB.Schedule.EndTimestamp = scheduleDB.EndAt.Unix()
+	}
+	if scheduleDB.NextRunAt != nil {
+		schedulePB.NextRunTimestamp = scheduleDB.NextRunAt.Unix()
+	}
+	if scheduleDB.LastRunAt != nil {
+		schedulePB.LastRunTimestamp = scheduleDB.LastRunAt.Unix()
+	}
+
+	return schedulePB, nil
+}
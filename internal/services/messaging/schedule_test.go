@@ -0,0 +1,235 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+var _ = Describe("Scheduling broadcasts £schedule", func() {
+	var (
+		broadCastReq *messaging.BroadCastMessageRequest
+		scheduleReq  *messaging.ScheduleRequest
+		ctx          context.Context
+	)
+
+	BeforeEach(func() {
+		broadCastReq = &messaging.BroadCastMessageRequest{
+			Title:   randomdata.Paragraph()[:10],
+			Message: randomdata.Paragraph(),
+			Filters: []messaging.BroadCastMessageFilter{messaging.BroadCastMessageFilter_BY_COUNTY},
+			Topics:  []string{randomdata.RandStringRunes(20)},
+			Payload: map[string]string{"topic": "curfew"},
+			Schedule: &messaging.BroadcastSchedule{
+				CronExpression: "0 21 * * *",
+				Timezone:       "Africa/Nairobi",
+			},
+		}
+		scheduleReq = &messaging.ScheduleRequest{
+			ScheduleId: uuid.New().String(),
+		}
+		ctx = context.Background()
+	})
+
+	Describe("Scheduling broadcast with malformed request", func() {
+		It("should fail when send at timestamp and cron expression are missing", func() {
+			broadCastReq.Schedule.CronExpression = ""
+			broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, broadCastReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(broadCastRes).Should(BeNil())
+		})
+		It("should fail when send at timestamp is in the past", func() {
+			broadCastReq.Schedule.SendAtTimestamp = time.Now().Add(-time.Hour).Unix()
+			broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, broadCastReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(broadCastRes).Should(BeNil())
+		})
+		It("should fail when cron expression is invalid", func() {
+			broadCastReq.Schedule.CronExpression = "0 25 * * *"
+			broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, broadCastReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(broadCastRes).Should(BeNil())
+		})
+		It("should fail when timezone is unknown", func() {
+			broadCastReq.Schedule.Timezone = "Africa/Atlantis"
+			broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, broadCastReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(broadCastRes).Should(BeNil())
+		})
+		It("should fail when schedule ends before it runs", func() {
+			broadCastReq.Schedule.SendAtTimestamp = time.Now().Add(48 * time.Hour).Unix()
+			broadCastReq.Schedule.EndTimestamp = time.Now().Add(24 * time.Hour).Unix()
+			broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, broadCastReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(broadCastRes).Should(BeNil())
+		})
+	})
+
+	Describe("Managing schedules with malformed request", func() {
+		It("should fail to list schedules when the request is nil", func() {
+			listRes, err := MessagingAPI.ListSchedules(ctx, nil)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(listRes).Should(BeNil())
+		})
+		It("should fail to pause schedule when schedule id is missing", func() {
+			scheduleReq.ScheduleId = ""
+			pauseRes, err := MessagingAPI.PauseSchedule(ctx, scheduleReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(pauseRes).Should(BeNil())
+		})
+		It("should fail to resume schedule when schedule does not exist", func() {
+			resumeRes, err := MessagingAPI.ResumeSchedule(ctx, scheduleReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(resumeRes).Should(BeNil())
+		})
+		It("should fail to delete schedule when schedule does not exist", func() {
+			delRes, err := MessagingAPI.DeleteSchedule(ctx, scheduleReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(delRes).Should(BeNil())
+		})
+	})
+
+	Describe("Scheduling broadcast with well-formed request", func() {
+		var scheduleID string
+
+		BeforeEach(func() {
+			broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, broadCastReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(broadCastRes.BroadcastMessageId).Should(BeEmpty())
+			Expect(broadCastRes.ScheduleId).ShouldNot(BeEmpty())
+			scheduleID = broadCastRes.ScheduleId
+			scheduleReq.ScheduleId = scheduleID
+		})
+
+		It("should list the schedule with its next run", func() {
+			listRes, err := MessagingAPI.ListSchedules(ctx, &messaging.ListSchedulesRequest{PageSize: 1})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.Schedules).Should(HaveLen(1))
+
+			schedulePB := listRes.Schedules[0]
+			Expect(schedulePB.ScheduleId).Should(Equal(scheduleID))
+			Expect(schedulePB.Schedule.CronExpression).Should(Equal("0 21 * * *"))
+			Expect(schedulePB.Paused).Should(BeFalse())
+
+			nextRun := time.Unix(schedulePB.NextRunTimestamp, 0).In(time.FixedZone("EAT", 3*60*60))
+			Expect(nextRun).Should(BeTemporally(">", time.Now()))
+			Expect(nextRun.Hour()).Should(Equal(21))
+			Expect(nextRun.Minute()).Should(BeZero())
+		})
+
+		It("should pause, resume and delete the schedule", func() {
+			pauseRes, err := MessagingAPI.PauseSchedule(ctx, scheduleReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pauseRes.Paused).Should(BeTrue())
+
+			resumeRes, err := MessagingAPI.ResumeSchedule(ctx, scheduleReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(resumeRes.Paused).Should(BeFalse())
+			Expect(resumeRes.NextRunTimestamp).Should(BeNumerically(">", time.Now().Unix()))
+
+			_, err = MessagingAPI.DeleteSchedule(ctx, scheduleReq)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = MessagingAPI.PauseSchedule(ctx, scheduleReq)
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+
+		It("should send a broadcast when the schedule is due and skip runs already made", func() {
+			// Paused so that the scheduler does not run it meanwhile
+			_, err := MessagingAPI.PauseSchedule(ctx, scheduleReq)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			dueAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+			err = MessagingServer.sqlDB.Model(&services.BroadcastSchedule{}).
+				Where("schedule_id=?", scheduleID).Update("next_run_at", dueAt).Error
+			Expect(err).ShouldNot(HaveOccurred())
+
+			scheduleDB, err := MessagingServer.getSchedule(scheduleID)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			broadcastID, err := MessagingServer.runSchedule(ctx, scheduleDB, time.Now())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(broadcastID).ShouldNot(BeEmpty())
+
+			getRes, err := MessagingAPI.GetBroadcast(ctx, &messaging.GetBroadcastRequest{BroadcastMessageId: broadcastID})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(getRes.ScheduleId).Should(Equal(scheduleID))
+			Expect(getRes.Title).Should(Equal(broadCastReq.Title))
+
+			scheduleDB, err = MessagingServer.getSchedule(scheduleID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(scheduleDB.Runs).Should(BeEquivalentTo(1))
+			Expect(scheduleDB.LastBroadcastID).Should(Equal(broadcastID))
+			Expect(*scheduleDB.NextRunAt).Should(BeTemporally(">", time.Now()))
+
+			// The run was made by another replica
+			scheduleDB.NextRunAt = &dueAt
+			broadcastID, err = MessagingServer.runSchedule(ctx, scheduleDB, time.Now())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(broadcastID).Should(BeEmpty())
+		})
+	})
+
+	Describe("Electing the scheduler leader", func() {
+		var replicas []*messagingServer
+
+		BeforeEach(func() {
+			replicas = make([]*messagingServer, 0, 2)
+			for i := 0; i < 2; i++ {
+				replicas = append(replicas, &messagingServer{
+					redisDB:          MessagingServer.redisDB,
+					scheduleInterval: time.Second,
+					replicaID:        uuid.New().String(),
+				})
+			}
+			Expect(MessagingServer.redisDB.Del(ctx, schedulerLeaderKey).Err()).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(MessagingServer.redisDB.Del(ctx, schedulerLeaderKey).Err()).ShouldNot(HaveOccurred())
+		})
+
+		It("should have only one leader at a time", func() {
+			leader, err := replicas[0].leadScheduler(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(leader).Should(BeTrue())
+
+			leader, err = replicas[1].leadScheduler(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(leader).Should(BeFalse())
+
+			// The leader extends its lease
+			leader, err = replicas[0].leadScheduler(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(leader).Should(BeTrue())
+		})
+
+		It("should elect another leader when the lease of the leader expires", func() {
+			leader, err := replicas[0].leadScheduler(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(leader).Should(BeTrue())
+
+			Eventually(func() bool {
+				leader, err := replicas[1].leadScheduler(ctx)
+				Expect(err).ShouldNot(HaveOccurred())
+				return leader
+			}, 5*time.Second, 500*time.Millisecond).Should(BeTrue())
+		})
+	})
+})
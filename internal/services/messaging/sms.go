@@ -0,0 +1,135 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMSOptions contains parameters for NewSMSChannel
+type SMSOptions struct {
+	// URL is the endpoint of the gateway accepting form encoded messages, as Africa's Talking messaging API
+	URL      string
+	Username string
+	APIKey   string
+	// SenderID is the short code or alphanumeric sender of messages, the gateway default when empty
+	SenderID   string
+	HTTPClient *http.Client
+}
+
+// smsChannel sends text messages through an HTTP SMS gateway
+type smsChannel struct {
+	opt *SMSOptions
+}
+
+// NewSMSChannel creates a channel sending SMS through an HTTP gateway
+func NewSMSChannel(opt *SMSOptions) (Channel, error) {
+	var err error
+	switch {
+	case opt == nil:
+		err = errors.New("sms options are required")
+	case opt.URL == "":
+		err = errors.New("sms gateway url is required")
+	case opt.APIKey == "":
+		err = errors.New("sms gateway api key is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.HTTPClient == nil {
+		opt.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &smsChannel{opt: opt}, nil
+}
+
+// NewSMSChannelFromEnv creates a SMS channel from SMS_GATEWAY_URL, SMS_GATEWAY_USERNAME, SMS_GATEWAY_API_KEY and
+// SMS_SENDER_ID. It returns nil when no gateway is configured.
+func NewSMSChannelFromEnv() (Channel, error) {
+	gatewayURL := strings.TrimSpace(os.Getenv("SMS_GATEWAY_URL"))
+	if gatewayURL == "" {
+		return nil, nil
+	}
+	return NewSMSChannel(&SMSOptions{
+		URL:      gatewayURL,
+		Username: strings.TrimSpace(os.Getenv("SMS_GATEWAY_USERNAME")),
+		APIKey:   strings.TrimSpace(os.Getenv("SMS_GATEWAY_API_KEY")),
+		SenderID: strings.TrimSpace(os.Getenv("SMS_SENDER_ID")),
+	})
+}
+
+func (*smsChannel) Name() string {
+	return ChannelSMS
+}
+
+func (*smsChannel) Reaches(recipient *Recipient) bool {
+	return recipient.PhoneNumber != ""
+}
+
+// smsResponse is the response of the gateway, only read when it is in the format of Africa's Talking
+type smsResponse struct {
+	SMSMessageData struct {
+		Recipients []struct {
+			MessageID  string `json:"messageId"`
+			Status     string `json:"status"`
+			StatusCode int    `json:"statusCode"`
+		}
+	}
+}
+
+func (c *smsChannel) Send(ctx context.Context, recipient *Recipient, notification *Notification) (*Receipt, error) {
+	form := url.Values{}
+	form.Set("username", c.opt.Username)
+	form.Set("to", recipient.PhoneNumber)
+	form.Set("message", notification.Text())
+	if c.opt.SenderID != "" {
+		form.Set("from", c.opt.SenderID)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.opt.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("apiKey", c.opt.APIKey)
+
+	res, err := c.opt.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 4096))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &Receipt{ErrorCode: strconv.Itoa(res.StatusCode)},
+			fmt.Errorf("sms gateway responded with %s: %s", res.Status, truncate(strings.TrimSpace(string(body)), 128))
+	}
+
+	receipt := &Receipt{}
+
+	smsRes := &smsResponse{}
+	if json.Unmarshal(body, smsRes) == nil && len(smsRes.SMSMessageData.Recipients) > 0 {
+		result := smsRes.SMSMessageData.Recipients[0]
+		receipt.ProviderMessageID = result.MessageID
+		// Status codes from 100 to 102 are accepted messages
+		if result.StatusCode != 0 && (result.StatusCode < 100 || result.StatusCode > 102) {
+			receipt.ErrorCode = result.Status
+			return receipt, fmt.Errorf("sms gateway rejected message: %s", result.Status)
+		}
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,399 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/messaging/templates"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+// defaultTemplates are the English templates of messages sent by the services.
+// They are saved when missing, messages in other languages fall back to them.
+// Titles fit in the 30 characters of the messages table.
+var defaultTemplates = []*messaging.MessageTemplate{
+	{
+		Name:     services.TemplateWelcome,
+		Language: services.LanguageEnglish,
+		Title:    "Hello {{.FullName}}",
+		Body: "Welcome to KoviTrace application.\nYou can do self-screening assessment, get qualitative information " +
+			"about COVID-19 and most important you will be notified in case you come into close contact with someone " +
+			"who has tested postive for COVID-19.",
+		Variables: []*messaging.TemplateVariable{
+			{Name: "FullName", Type: messaging.TemplateVariableType_STRING},
+		},
+	},
+	{
+		Name:     services.TemplateContactAlert,
+		Language: services.LanguageEnglish,
+		Title:    "COVID-19 Alert!",
+		Body: "Hello {{.FullName}}, you have been in contact {{.Count}} times with a person who has now tested " +
+			"positive for COVID-19",
+		Variables: []*messaging.TemplateVariable{
+			{Name: "FullName", Type: messaging.TemplateVariableType_STRING},
+			{Name: "Count", Type: messaging.TemplateVariableType_NUMBER},
+			{Name: "PatientPhone", Type: messaging.TemplateVariableType_STRING},
+			{Name: "ContactTime", Type: messaging.TemplateVariableType_STRING},
+		},
+	},
+	{
+		Name:     services.TemplateAreaAlert,
+		Language: services.LanguageEnglish,
+		Title:    "COVID-19 Distancing Alert",
+		Body: "{{if gt .Count 1}}You have been in {{.Count}} areas with COVID-19 cases since " +
+			"{{.Since.Format \"3:04PM\"}}. Ensure you maintain social distance{{else}}You are currently in an area " +
+			"with a COVID-19 cases. Ensure you maintain social distance{{end}}",
+		Variables: []*messaging.TemplateVariable{
+			{Name: "Count", Type: messaging.TemplateVariableType_NUMBER},
+			{Name: "Since", Type: messaging.TemplateVariableType_TIMESTAMP},
+		},
+	},
+	{
+		Name:     services.TemplateExposureWarning,
+		Language: services.LanguageEnglish,
+		Title:    "COVID-19 Distancing Warning",
+		Body: "{{if gt .Count 1}}You were close to confirmed COVID-19 cases {{.Count}} times since " +
+			"{{.Since.Format \"3:04PM\"}}, most recently near {{.Placemark}}. Always ensure you maintain social " +
+			"distance{{else}}You were close to confirmed COVID-19 cases near {{.Placemark}} at around " +
+			"{{.Time.Format \"Mon, 02 Jan 2006 15:04:05 MST\"}}. Always ensure you maintain social distance{{end}}",
+		Variables: []*messaging.TemplateVariable{
+			{Name: "Count", Type: messaging.TemplateVariableType_NUMBER},
+			{Name: "Since", Type: messaging.TemplateVariableType_TIMESTAMP},
+			{Name: "Placemark", Type: messaging.TemplateVariableType_STRING},
+			{Name: "Time", Type: messaging.TemplateVariableType_TIMESTAMP},
+		},
+	},
+}
+
+func isDefaultTemplate(name, language string) bool {
+	for _, templatePB := range defaultTemplates {
+		if templatePB.Name == name && templatePB.Language == language {
+			return true
+		}
+	}
+	return false
+}
+
+// saveDefaultTemplates saves default templates that are missing, keeping the wording of those already saved
+func (s *messagingServer) saveDefaultTemplates() error {
+	for _, templatePB := range defaultTemplates {
+		templateDB, err := getTemplateDB(templatePB)
+		if err != nil {
+			return err
+		}
+
+		err = s.sqlDB.Where(&services.MessageTemplate{
+			Name:     templateDB.Name,
+			Language: templateDB.Language,
+		}).Attrs(templateDB).FirstOrCreate(&services.MessageTemplate{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *messagingServer) CreateTemplate(
+	ctx context.Context, createReq *messaging.CreateTemplateRequest,
+) (*messaging.MessageTemplate, error) {
+	// Request must not be nil
+	if createReq == nil {
+		return nil, services.NilRequestError("CreateTemplateRequest")
+	}
+
+	templateDB, err := getTemplateDB(createReq.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sqlDB.Select("id").First(
+		&services.MessageTemplate{}, "name = ? AND language = ?", templateDB.Name, templateDB.Language,
+	).Error
+	switch {
+	case err == nil:
+		return nil, status.Errorf(
+			codes.AlreadyExists, "template %s already exists in language %s", templateDB.Name, templateDB.Language,
+		)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get template: %v", err)
+	}
+
+	err = s.sqlDB.Create(templateDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save template: %v", err)
+	}
+
+	return getTemplatePB(templateDB)
+}
+
+func (s *messagingServer) UpdateTemplate(
+	ctx context.Context, updateReq *messaging.UpdateTemplateRequest,
+) (*messaging.MessageTemplate, error) {
+	// Request must not be nil
+	if updateReq == nil {
+		return nil, services.NilRequestError("UpdateTemplateRequest")
+	}
+
+	templateDB, err := getTemplateDB(updateReq.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	savedDB, err := s.getTemplate(templateDB.Name, templateDB.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sqlDB.Model(savedDB).Updates(map[string]interface{}{
+		"title":     templateDB.Title,
+		"body":      templateDB.Body,
+		"variables": templateDB.Variables,
+	}).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update template: %v", err)
+	}
+
+	return getTemplatePB(savedDB)
+}
+
+func (s *messagingServer) GetTemplate(
+	ctx context.Context, getReq *messaging.TemplateRequest,
+) (*messaging.MessageTemplate, error) {
+	// Request must not be nil
+	if getReq == nil {
+		return nil, services.NilRequestError("TemplateRequest")
+	}
+
+	// Validation
+	var err error
+	switch {
+	case getReq.Name == "":
+		err = services.MissingFieldError("template name")
+	case getReq.Language == "":
+		err = services.MissingFieldError("template language")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	templateDB, err := s.getTemplate(getReq.Name, getReq.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	return getTemplatePB(templateDB)
+}
+
+func (s *messagingServer) ListTemplates(
+	ctx context.Context, listReq *messaging.ListTemplatesRequest,
+) (*messaging.MessageTemplates, error) {
+	// Request must not be nil
+	if listReq == nil {
+		return nil, services.NilRequestError("ListTemplatesRequest")
+	}
+
+	// Normalize page
+	pageNumber, pageSize := normalizePage(listReq.GetPageToken(), listReq.GetPageSize())
+	offset := pageNumber*pageSize - pageSize
+
+	db := s.sqlDB.Order("name, language").Offset(offset).Limit(pageSize)
+	if listReq.FilterName != "" {
+		db = db.Where("name = ?", listReq.FilterName)
+	}
+	if listReq.FilterLanguage != "" {
+		db = db.Where("language = ?", listReq.FilterLanguage)
+	}
+
+	templatesDB := make([]*services.MessageTemplate, 0, pageSize)
+	err := db.Find(&templatesDB).Error
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get templates: %v", err)
+	}
+
+	templatesPB := make([]*messaging.MessageTemplate, 0, len(templatesDB))
+	for _, templateDB := range templatesDB {
+		templatePB, err := getTemplatePB(templateDB)
+		if err != nil {
+			return nil, err
+		}
+		templatesPB = append(templatesPB, templatePB)
+	}
+
+	templatesRes := &messaging.MessageTemplates{
+		Templates: templatesPB,
+	}
+	if len(templatesDB) == pageSize {
+		templatesRes.NextPageToken = int32(pageNumber + 1)
+	}
+
+	return templatesRes, nil
+}
+
+func (s *messagingServer) DeleteTemplate(
+	ctx context.Context, delReq *messaging.TemplateRequest,
+) (*empty.Empty, error) {
+	// Request must not be nil
+	if delReq == nil {
+		return nil, services.NilRequestError("TemplateRequest")
+	}
+
+	// Validation
+	var err error
+	switch {
+	case delReq.Name == "":
+		err = services.MissingFieldError("template name")
+	case delReq.Language == "":
+		err = services.MissingFieldError("template language")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages of the services fall back to the default templates
+	if isDefaultTemplate(delReq.Name, delReq.Language) {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, "template %s in language %s is a default template", delReq.Name, delReq.Language,
+		)
+	}
+
+	db := s.sqlDB.Unscoped().Delete(
+		&services.MessageTemplate{}, "name = ? AND language = ?", delReq.Name, delReq.Language,
+	)
+	switch {
+	case db.Error != nil:
+		return nil, status.Errorf(codes.Internal, "failed to delete template: %v", db.Error)
+	case db.RowsAffected == 0:
+		return nil, status.Errorf(codes.NotFound, "template %s not found in language %s", delReq.Name, delReq.Language)
+	}
+
+	return emptyMsg, nil
+}
+
+func (s *messagingServer) getTemplate(name, language string) (*services.MessageTemplate, error) {
+	templateDB := &services.MessageTemplate{}
+	err := s.sqlDB.First(templateDB, "name = ? AND language = ?", name, language).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "template %s not found in language %s", name, language)
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get template: %v", err)
+	}
+	return templateDB, nil
+}
+
+// getLanguage returns the language messages are sent to the user in
+func (s *messagingServer) getLanguage(phoneNumber string) (string, error) {
+	userDB := &services.UserModel{}
+	err := s.sqlDB.Table(services.UsersTable).Select("preferred_language").
+		First(userDB, "phone_number=?", phoneNumber).Error
+	switch {
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return services.LanguageEnglish, nil
+	default:
+		return "", err
+	}
+	if !services.ValidLanguage(userDB.PreferredLanguage) {
+		return services.LanguageEnglish, nil
+	}
+	return userDB.PreferredLanguage, nil
+}
+
+// renderTemplate returns the title and message of the template in the language,
+// or in English when the template has no variant in the language
+func (s *messagingServer) renderTemplate(name, language string, values map[string]string) (string, string, error) {
+	templatesDB := make([]*services.MessageTemplate, 0, 2)
+	err := s.sqlDB.Find(
+		&templatesDB, "name = ? AND language IN(?)", name, []string{language, services.LanguageEnglish},
+	).Error
+	if err != nil {
+		return "", "", status.Errorf(codes.Internal, "failed to get template: %v", err)
+	}
+
+	var templateDB *services.MessageTemplate
+	for _, variantDB := range templatesDB {
+		if templateDB == nil || variantDB.Language == language {
+			templateDB = variantDB
+		}
+	}
+	if templateDB == nil {
+		return "", "", status.Errorf(codes.NotFound, "template %s not found", name)
+	}
+
+	templatePB, err := getTemplatePB(templateDB)
+	if err != nil {
+		return "", "", err
+	}
+
+	t, err := templates.New(templatePB)
+	if err != nil {
+		return "", "", status.Errorf(codes.Internal, "failed to parse template %s: %v", name, err)
+	}
+
+	title, message, err := t.Render(values)
+	if err != nil {
+		return "", "", status.Errorf(codes.InvalidArgument, "failed to render template %s: %v", name, err)
+	}
+
+	return title, message, nil
+}
+
+func getTemplateDB(templatePB *messaging.MessageTemplate) (*services.MessageTemplate, error) {
+	_, err := templates.New(templatePB)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	templateDB := &services.MessageTemplate{
+		Name:     templatePB.Name,
+		Language: templatePB.Language,
+		Title:    templatePB.Title,
+		Body:     templatePB.Body,
+	}
+
+	templateDB.Variables, err = json.Marshal(templatePB.Variables)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to json marshal template variables: %v", err)
+	}
+
+	return templateDB, nil
+}
+
+func getTemplatePB(templateDB *services.MessageTemplate) (*messaging.MessageTemplate, error) {
+	templatePB := &messaging.MessageTemplate{
+		Name:             templateDB.Name,
+		Language:         templateDB.Language,
+		Title:            templateDB.Title,
+		Body:             templateDB.Body,
+		UpdatedTimestamp: templateDB.UpdatedAt.Unix(),
+	}
+
+	if len(templateDB.Variables) != 0 {
+		err := json.Unmarshal(templateDB.Variables, &templatePB.Variables)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to json unmarshal template variables: %v", err)
+		}
+	}
+
+	return templatePB, nil
+}
+
+// contactAlertVariables are the variables of the contact alert template for the contact
+func contactAlertVariables(contactData *messaging.ContactData) map[string]string {
+	return map[string]string{
+		"FullName":     contactData.FullName,
+		"Count":        fmt.Sprint(contactData.Count),
+		"PatientPhone": contactData.PatientPhone,
+		"ContactTime":  contactData.ContactTime,
+	}
+}
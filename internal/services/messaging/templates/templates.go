@@ -0,0 +1,164 @@
+// Package templates renders messages from templates with typed variables
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+// Messages are stored with the limits of the messages table
+const (
+	maxTitleLength   = 30
+	maxMessageLength = 256
+)
+
+// TimeZone is the time zone timestamp variables are formatted in
+var TimeZone = time.FixedZone("EAT", 3*60*60)
+
+// Variables are accessed as fields in templates e.g {{.FullName}}
+var variableName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Template is a validated message template
+type Template struct {
+	*messaging.MessageTemplate
+	title *template.Template
+	body  *template.Template
+}
+
+// New validates a message template
+func New(templatePB *messaging.MessageTemplate) (*Template, error) {
+	var err error
+	switch {
+	case templatePB == nil:
+		err = errors.New("missing template")
+	case strings.TrimSpace(templatePB.Name) == "":
+		err = errors.New("missing template name")
+	case !services.ValidLanguage(templatePB.Language):
+		err = fmt.Errorf("unknown template language %q", templatePB.Language)
+	case strings.TrimSpace(templatePB.Title) == "":
+		err = errors.New("missing template title")
+	case strings.TrimSpace(templatePB.Body) == "":
+		err = errors.New("missing template body")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(templatePB.Variables))
+	for _, variable := range templatePB.Variables {
+		switch {
+		case variable == nil:
+			err = errors.New("missing template variable")
+		case !variableName.MatchString(variable.Name):
+			err = fmt.Errorf("malformed template variable name %q", variable.Name)
+		case names[variable.Name]:
+			err = fmt.Errorf("template variable %s is declared more than once", variable.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := messaging.TemplateVariableType_name[int32(variable.Type)]; !ok {
+			return nil, fmt.Errorf("unknown type of template variable %s", variable.Name)
+		}
+		names[variable.Name] = true
+	}
+
+	t := &Template{MessageTemplate: templatePB}
+
+	t.title, err = template.New("title").Option("missingkey=error").Parse(templatePB.Title)
+	if err != nil {
+		return nil, fmt.Errorf("malformed template title: %v", err)
+	}
+
+	t.body, err = template.New("body").Option("missingkey=error").Parse(templatePB.Body)
+	if err != nil {
+		return nil, fmt.Errorf("malformed template body: %v", err)
+	}
+
+	// Templates that use undeclared variables fail now rather than when messages are sent
+	_, _, err = t.render(t.zero())
+	if err != nil {
+		return nil, fmt.Errorf("malformed template: %v", err)
+	}
+
+	return t, nil
+}
+
+// Render returns the title and message of the template with the values of its variables
+func (t *Template) Render(values map[string]string) (string, string, error) {
+	data := make(map[string]interface{}, len(t.Variables))
+	for _, variable := range t.Variables {
+		value, ok := values[variable.Name]
+		if !ok {
+			return "", "", fmt.Errorf("missing value of template variable %s", variable.Name)
+		}
+
+		switch variable.Type {
+		case messaging.TemplateVariableType_STRING:
+			data[variable.Name] = value
+		case messaging.TemplateVariableType_NUMBER:
+			number, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return "", "", fmt.Errorf("template variable %s must be a number", variable.Name)
+			}
+			data[variable.Name] = number
+		case messaging.TemplateVariableType_TIMESTAMP:
+			timestamp, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return "", "", fmt.Errorf("template variable %s must be a unix timestamp", variable.Name)
+			}
+			data[variable.Name] = time.Unix(timestamp, 0).In(TimeZone)
+		}
+	}
+
+	return t.render(data)
+}
+
+func (t *Template) render(data map[string]interface{}) (string, string, error) {
+	title := &bytes.Buffer{}
+	err := t.title.Execute(title, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	body := &bytes.Buffer{}
+	err = t.body.Execute(body, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	return truncate(title.String(), maxTitleLength), truncate(body.String(), maxMessageLength), nil
+}
+
+// zero returns the zero values of the variables of the template
+func (t *Template) zero() map[string]interface{} {
+	data := make(map[string]interface{}, len(t.Variables))
+	for _, variable := range t.Variables {
+		switch variable.Type {
+		case messaging.TemplateVariableType_STRING:
+			data[variable.Name] = ""
+		case messaging.TemplateVariableType_NUMBER:
+			data[variable.Name] = int64(0)
+		case messaging.TemplateVariableType_TIMESTAMP:
+			data[variable.Name] = time.Time{}.In(TimeZone)
+		}
+	}
+	return data
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
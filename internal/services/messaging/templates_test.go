@@ -0,0 +1,220 @@
+package messaging
+
+import (
+	"context"
+
+	"github.com/Pallinder/go-randomdata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+func fakeTemplate(language string) *messaging.MessageTemplate {
+	return &messaging.MessageTemplate{
+		Name:     randomdata.RandStringRunes(20),
+		Language: language,
+		Title:    "Curfew",
+		Body:     "Hello {{.FullName}}, curfew starts at {{.Start.Format \"15:04\"}} in {{.Count}} counties",
+		Variables: []*messaging.TemplateVariable{
+			{Name: "FullName", Type: messaging.TemplateVariableType_STRING},
+			{Name: "Start", Type: messaging.TemplateVariableType_TIMESTAMP},
+			{Name: "Count", Type: messaging.TemplateVariableType_NUMBER},
+		},
+	}
+}
+
+var _ = Describe("Managing message templates £templates", func() {
+	var (
+		createReq *messaging.CreateTemplateRequest
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		createReq = &messaging.CreateTemplateRequest{
+			Template: fakeTemplate(services.LanguageEnglish),
+		}
+		ctx = context.Background()
+	})
+
+	Describe("Creating template with malformed request", func() {
+		It("should fail when the request is nil", func() {
+			createRes, err := MessagingAPI.CreateTemplate(ctx, nil)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when template is missing", func() {
+			createReq.Template = nil
+			createRes, err := MessagingAPI.CreateTemplate(ctx, createReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when language is unknown", func() {
+			createReq.Template.Language = "fr"
+			createRes, err := MessagingAPI.CreateTemplate(ctx, createReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when template uses an undeclared variable", func() {
+			createReq.Template.Body = "Hello {{.Placemark}}"
+			createRes, err := MessagingAPI.CreateTemplate(ctx, createReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+		It("should fail when variable name is malformed", func() {
+			createReq.Template.Variables[0].Name = "full name"
+			createRes, err := MessagingAPI.CreateTemplate(ctx, createReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(createRes).Should(BeNil())
+		})
+	})
+
+	Describe("Getting and deleting templates with malformed request", func() {
+		It("should fail to get template when language is missing", func() {
+			getRes, err := MessagingAPI.GetTemplate(ctx, &messaging.TemplateRequest{Name: services.TemplateWelcome})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail to get template that does not exist", func() {
+			getRes, err := MessagingAPI.GetTemplate(ctx, &messaging.TemplateRequest{
+				Name:     randomdata.RandStringRunes(20),
+				Language: services.LanguageEnglish,
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(getRes).Should(BeNil())
+		})
+		It("should fail to delete a default template", func() {
+			delRes, err := MessagingAPI.DeleteTemplate(ctx, &messaging.TemplateRequest{
+				Name:     services.TemplateWelcome,
+				Language: services.LanguageEnglish,
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.FailedPrecondition))
+			Expect(delRes).Should(BeNil())
+		})
+	})
+
+	It("should save the default templates", func() {
+		getRes, err := MessagingAPI.GetTemplate(ctx, &messaging.TemplateRequest{
+			Name:     services.TemplateWelcome,
+			Language: services.LanguageEnglish,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getRes.Variables).Should(HaveLen(1))
+	})
+
+	Describe("Sending messages from templates", func() {
+		var (
+			name     string
+			variants map[string]*messaging.MessageTemplate
+		)
+
+		sendFn := func(language string, variables map[string]string) (*services.Message, error) {
+			userDB := &services.UserModel{
+				PhoneNumber:       randomPhone(),
+				FullName:          randomdata.FullName(randomdata.RandomGender),
+				County:            randomdata.State(randomdata.Large),
+				PreferredLanguage: language,
+			}
+			Expect(MessagingServer.sqlDB.Create(userDB).Error).ShouldNot(HaveOccurred())
+
+			msg := fakeMessage()
+			msg.UserPhone = userDB.PhoneNumber
+			msg.Title = ""
+			msg.Notification = ""
+			msg.Template = name
+			msg.TemplateVariables = variables
+			_, err := MessagingAPI.SendMessage(ctx, msg)
+			if err != nil {
+				return nil, err
+			}
+
+			messageDB := &services.Message{}
+			err = MessagingServer.sqlDB.First(messageDB, "user_phone=?", userDB.PhoneNumber).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			return messageDB, nil
+		}
+
+		BeforeEach(func() {
+			english := createReq.Template
+			name = english.Name
+
+			swahili := fakeTemplate(services.LanguageSwahili)
+			swahili.Name = name
+			swahili.Title = "Kafyu"
+			swahili.Body = "Habari {{.FullName}}, kafyu inaanza saa {{.Start.Format \"15:04\"}} katika kaunti {{.Count}}"
+
+			variants = map[string]*messaging.MessageTemplate{
+				services.LanguageEnglish: english,
+				services.LanguageSwahili: swahili,
+			}
+			for _, templatePB := range variants {
+				_, err := MessagingAPI.CreateTemplate(ctx, &messaging.CreateTemplateRequest{Template: templatePB})
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should fail to create a template that exists", func() {
+			_, err := MessagingAPI.CreateTemplate(ctx, createReq)
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.AlreadyExists))
+		})
+
+		It("should list the language variants of the template", func() {
+			listRes, err := MessagingAPI.ListTemplates(ctx, &messaging.ListTemplatesRequest{FilterName: name})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listRes.Templates).Should(HaveLen(2))
+		})
+
+		It("should render the message in the language of the user", func() {
+			variables := map[string]string{"FullName": "Wanjiku", "Start": "1586880000", "Count": "47"}
+
+			messageDB, err := sendFn(services.LanguageSwahili, variables)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(messageDB.Title).Should(Equal("Kafyu"))
+			Expect(messageDB.Message).Should(Equal("Habari Wanjiku, kafyu inaanza saa 19:00 katika kaunti 47"))
+
+			// Templates without a variant in the language fall back to English
+			messageDB, err = sendFn(services.LanguageSheng, variables)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(messageDB.Title).Should(Equal("Curfew"))
+			Expect(messageDB.Message).Should(Equal("Hello Wanjiku, curfew starts at 19:00 in 47 counties"))
+		})
+
+		It("should fail to send a message when a variable has a value of the wrong type", func() {
+			_, err := sendFn(services.LanguageEnglish, map[string]string{
+				"FullName": "Wanjiku", "Start": "1586880000", "Count": "many",
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		})
+
+		It("should update and delete the template", func() {
+			updated := variants[services.LanguageSwahili]
+			updated.Body = "Kafyu inaanza saa {{.Start.Format \"15:04\"}}"
+			updateRes, err := MessagingAPI.UpdateTemplate(ctx, &messaging.UpdateTemplateRequest{Template: updated})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(updateRes.Body).Should(Equal(updated.Body))
+
+			_, err = MessagingAPI.DeleteTemplate(ctx, &messaging.TemplateRequest{
+				Name:     name,
+				Language: services.LanguageSwahili,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = MessagingAPI.GetTemplate(ctx, &messaging.TemplateRequest{
+				Name:     name,
+				Language: services.LanguageSwahili,
+			})
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+	})
+})
@@ -0,0 +1,82 @@
+package messaging
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/devicetoken"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+// tokenUpdate returns the change of the device token reported by an attempt to push to it, nil when it did not change
+func tokenUpdate(token string, attempt *services.DeliveryAttempt) *devicetoken.Update {
+	if attempt.Channel != ChannelPush || token == "" {
+		return nil
+	}
+	for err, code := range fcmErrorCodes {
+		if code == attempt.ErrorCode && devicetoken.Invalid(err) {
+			return &devicetoken.Update{Token: token, Invalid: true}
+		}
+	}
+	if attempt.CanonicalRegistrationID != "" && attempt.CanonicalRegistrationID != token {
+		return &devicetoken.Update{Token: token, Canonical: attempt.CanonicalRegistrationID}
+	}
+	return nil
+}
+
+// updateDeviceTokens clears invalid device tokens of users and replaces tokens with their canonical tokens
+func (s *messagingServer) updateDeviceTokens(updates []*devicetoken.Update) {
+	if len(updates) == 0 {
+		return
+	}
+	err := devicetoken.Apply(s.sqlDB, updates)
+	if err != nil {
+		s.logger.Errorf("failed to update device tokens: %v", err)
+	}
+}
+
+func (s *messagingServer) CountStaleDeviceTokens(
+	ctx context.Context, countReq *messaging.CountStaleDeviceTokensRequest,
+) (*messaging.StaleDeviceTokens, error) {
+	// Request must not be nil
+	if countReq == nil {
+		return nil, services.NilRequestError("CountStaleDeviceTokensRequest")
+	}
+
+	db := s.sqlDB.Table(services.UsersTable).
+		Select("county, COUNT(*), SUM(CASE WHEN device_token = ? AND device_token_invalid_at IS NOT NULL THEN 1 ELSE 0 END)", devicetoken.None).
+		Where("deleted_at IS NULL")
+	if len(countReq.Counties) > 0 {
+		db = db.Where("county IN(?)", countReq.Counties)
+	}
+
+	rows, err := db.Group("county").Order("county").Rows()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count stale device tokens: %v", err)
+	}
+	defer rows.Close()
+
+	countsPB := &messaging.StaleDeviceTokens{
+		Counties: make([]*messaging.CountyDeviceTokens, 0),
+	}
+
+	for rows.Next() {
+		countyPB := &messaging.CountyDeviceTokens{}
+		err = rows.Scan(&countyPB.County, &countyPB.Users, &countyPB.StaleTokens)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read stale device tokens: %v", err)
+		}
+		countsPB.Counties = append(countsPB.Counties, countyPB)
+		countsPB.StaleTokens += countyPB.StaleTokens
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read stale device tokens: %v", err)
+	}
+
+	return countsPB, nil
+}
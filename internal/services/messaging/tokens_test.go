@@ -0,0 +1,185 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/appleboy/go-fcm"
+	"github.com/gidyon/pandemic-api/internal/devicetoken"
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/messaging/mocks"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("Cleaning up invalid device tokens £tokens", func() {
+	var (
+		ctx      context.Context
+		county   string
+		userDB   *services.UserModel
+		result   fcm.Result
+		replaced Channel
+	)
+
+	userOf := func(phoneNumber string) func() *services.UserModel {
+		return func() *services.UserModel {
+			userDB := &services.UserModel{}
+			err := MessagingServer.sqlDB.First(userDB, "phone_number=?", phoneNumber).Error
+			Expect(err).ShouldNot(HaveOccurred())
+			return userDB
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		county = randomdata.RandStringRunes(20)
+		result = fcm.Result{}
+
+		userDB = &services.UserModel{
+			PhoneNumber: randomPhone(),
+			FullName:    randomdata.FullName(randomdata.RandomGender),
+			County:      county,
+			DeviceToken: randomdata.MacAddress(),
+		}
+		Expect(MessagingServer.sqlDB.Create(userDB).Error).ShouldNot(HaveOccurred())
+
+		fcmClient := &mocks.FCMClientMock{}
+		fcmClient.On("SendWithRetry", mock.Anything, 5).Return(
+			func(*fcm.Message, int) *fcm.Response {
+				return &fcm.Response{Results: []fcm.Result{result}}
+			},
+			nil,
+		)
+		replaced = MessagingServer.setChannel(&pushChannel{client: fcmClient})
+	})
+
+	AfterEach(func() {
+		MessagingServer.setChannel(replaced)
+	})
+
+	sendFn := func() {
+		msg := fakeMessage()
+		msg.UserPhone = userDB.PhoneNumber
+		_, err := MessagingAPI.SendMessage(ctx, msg)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	It("should clear tokens that are not registered and count them", func() {
+		result.Error = fcm.ErrNotRegistered
+		sendFn()
+
+		Eventually(func() string {
+			return userOf(userDB.PhoneNumber)().DeviceToken
+		}).Should(Equal(devicetoken.None))
+		Expect(userOf(userDB.PhoneNumber)().DeviceTokenInvalidAt).ShouldNot(BeNil())
+
+		countRes, err := MessagingAPI.CountStaleDeviceTokens(ctx, &messaging.CountStaleDeviceTokensRequest{
+			Counties: []string{county},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(countRes.Counties).Should(HaveLen(1))
+		Expect(countRes.Counties[0].County).Should(Equal(county))
+		Expect(countRes.Counties[0].Users).Should(BeEquivalentTo(1))
+		Expect(countRes.Counties[0].StaleTokens).Should(BeEquivalentTo(1))
+		Expect(countRes.StaleTokens).Should(BeEquivalentTo(1))
+	})
+
+	It("should replace tokens with their canonical tokens", func() {
+		result.RegistrationID = randomdata.MacAddress()
+		sendFn()
+
+		Eventually(func() string {
+			return userOf(userDB.PhoneNumber)().DeviceToken
+		}).Should(Equal(result.RegistrationID))
+	})
+
+	It("should fail to count stale tokens when the request is nil", func() {
+		countRes, err := MessagingAPI.CountStaleDeviceTokens(ctx, nil)
+		Expect(err).Should(HaveOccurred())
+		Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		Expect(countRes).Should(BeNil())
+	})
+})
+
+var _ = Describe("Pushing messages to every device of a user £devices", func() {
+	var (
+		ctx      context.Context
+		userDB   *services.UserModel
+		tokens   []string
+		sent     chan *fcm.Message
+		replaced Channel
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		tokens = []string{randomdata.MacAddress(), randomdata.MacAddress()}
+		sent = make(chan *fcm.Message, 1)
+
+		userDB = &services.UserModel{
+			PhoneNumber: randomPhone(),
+			FullName:    randomdata.FullName(randomdata.RandomGender),
+			County:      randomdata.State(randomdata.Large),
+			DeviceToken: tokens[0],
+		}
+		Expect(MessagingServer.sqlDB.Create(userDB).Error).ShouldNot(HaveOccurred())
+
+		for i, token := range tokens {
+			err := MessagingServer.sqlDB.Create(&services.UserDevice{
+				PhoneNumber: userDB.PhoneNumber,
+				DeviceToken: token,
+				LastSeenAt:  time.Now().Add(-time.Duration(i) * time.Hour),
+			}).Error
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		// The first device has uninstalled the app
+		fcmClient := &mocks.FCMClientMock{}
+		fcmClient.On("SendWithRetry", mock.Anything, 5).Return(
+			func(msg *fcm.Message, _ int) *fcm.Response {
+				sent <- msg
+				return &fcm.Response{Results: []fcm.Result{
+					{Error: fcm.ErrNotRegistered}, {MessageID: "0:" + randomdata.RandStringRunes(16)},
+				}}
+			},
+			nil,
+		)
+		replaced = MessagingServer.setChannel(&pushChannel{client: fcmClient})
+	})
+
+	AfterEach(func() {
+		MessagingServer.setChannel(replaced)
+	})
+
+	It("should deliver to the remaining devices and remove the invalid one", func() {
+		msg := fakeMessage()
+		msg.UserPhone = userDB.PhoneNumber
+		sendRes, err := MessagingAPI.SendMessage(ctx, msg)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		var fcmMsg *fcm.Message
+		Eventually(sent).Should(Receive(&fcmMsg))
+		Expect(fcmMsg.RegistrationIDs).Should(Equal(tokens))
+
+		getReq := &messaging.GetMessageDeliveryStatusRequest{MessageId: sendRes.MessageId}
+		Eventually(func() messaging.DeliveryStatus {
+			getRes, err := MessagingAPI.GetMessageDeliveryStatus(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			return getRes.Status
+		}).Should(Equal(messaging.DeliveryStatus_DELIVERED))
+
+		devicesDB := make([]*services.UserDevice, 0)
+		err = MessagingServer.sqlDB.Find(&devicesDB, "phone_number=?", userDB.PhoneNumber).Error
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(devicesDB).Should(HaveLen(1))
+		Expect(devicesDB[0].DeviceToken).Should(Equal(tokens[1]))
+
+		// The user token falls back to the remaining device
+		savedDB := &services.UserModel{}
+		err = MessagingServer.sqlDB.First(savedDB, "phone_number=?", userDB.PhoneNumber).Error
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(savedDB.DeviceToken).Should(Equal(tokens[1]))
+	})
+})
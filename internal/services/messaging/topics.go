@@ -0,0 +1,389 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/appleboy/go-fcm"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+// Prefixes of the FCM topics devices are subscribed to
+const (
+	countyTopicPrefix   = "county_"
+	statusTopicPrefix   = "status_"
+	languageTopicPrefix = "language_"
+)
+
+// Characters not allowed in topic names are replaced
+var topicCharacters = regexp.MustCompile(`[^a-z0-9_.~-]+`)
+
+func countyTopic(county string) string {
+	return countyTopicPrefix + topicCharacters.ReplaceAllString(strings.ToLower(strings.TrimSpace(county)), "-")
+}
+
+func statusTopic(userStatus location.Status) string {
+	return statusTopicPrefix + strings.ToLower(userStatus.String())
+}
+
+func languageTopic(language string) string {
+	return languageTopicPrefix + language
+}
+
+// userTopics returns the topics of the county, status and language of a user
+func userTopics(userDB *services.UserModel) []string {
+	topics := make([]string, 0, 3)
+	if userDB.County != "" {
+		topics = append(topics, countyTopic(userDB.County))
+	}
+	topics = append(topics, statusTopic(location.Status(userDB.Status)))
+	if userDB.PreferredLanguage != "" {
+		topics = append(topics, languageTopic(userDB.PreferredLanguage))
+	} else {
+		topics = append(topics, languageTopic(services.LanguageEnglish))
+	}
+	return topics
+}
+
+// audienceTopic returns the topic of an audience of a single county, status or language, empty for other audiences
+func audienceTopic(audiencePB *messaging.Audience) string {
+	topics := make([]string, 0, 1)
+
+	switch len(audiencePB.Counties) {
+	case 0:
+	case 1:
+		topics = append(topics, countyTopic(audiencePB.Counties[0]))
+	default:
+		return ""
+	}
+
+	switch len(audiencePB.Statuses) {
+	case 0:
+	case 1:
+		value, ok := location.Status_value[strings.ToUpper(audiencePB.Statuses[0])]
+		if !ok {
+			return ""
+		}
+		topics = append(topics, statusTopic(location.Status(value)))
+	default:
+		return ""
+	}
+
+	switch len(audiencePB.Languages) {
+	case 0:
+	case 1:
+		topics = append(topics, languageTopic(audiencePB.Languages[0]))
+	default:
+		return ""
+	}
+
+	// Any other field narrows the audience to part of the topic
+	rest := proto.Clone(audiencePB).(*messaging.Audience)
+	rest.Counties, rest.Statuses, rest.Languages = nil, nil, nil
+
+	if len(topics) != 1 || !proto.Equal(rest, &messaging.Audience{}) {
+		return ""
+	}
+
+	return topics[0]
+}
+
+func (s *messagingServer) SyncTopicSubscriptions(
+	ctx context.Context, syncReq *messaging.SyncTopicSubscriptionsRequest,
+) (*empty.Empty, error) {
+	// Request must not be nil
+	if syncReq == nil {
+		return nil, services.NilRequestError("SyncTopicSubscriptionsRequest")
+	}
+
+	// Devices are not subscribed to topics unless topics are enabled
+	if s.topicManager == nil {
+		return emptyMsg, nil
+	}
+
+	if syncReq.PhoneNumber == "" {
+		go s.syncAllTopicSubscriptions(context.Background())
+		return emptyMsg, nil
+	}
+
+	err := s.syncTopicSubscriptions(ctx, syncReq.PhoneNumber)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sync topic subscriptions: %v", err)
+	}
+
+	return emptyMsg, nil
+}
+
+// syncAllTopicSubscriptions syncs the topic subscriptions of every user, such as those registered before topics were enabled
+func (s *messagingServer) syncAllTopicSubscriptions(ctx context.Context) {
+	var lastUserID uint
+	for {
+		usersDB := make([]*services.UserModel, 0, broadcastPageSize)
+		err := s.sqlDB.Select("id, phone_number").Where("id > ?", lastUserID).Order("id").
+			Limit(broadcastPageSize).Find(&usersDB).Error
+		if err != nil {
+			s.logger.Errorf("failed to get users to sync topic subscriptions: %v", err)
+			return
+		}
+
+		for _, userDB := range usersDB {
+			if ctx.Err() != nil {
+				return
+			}
+			err = s.syncTopicSubscriptions(ctx, userDB.PhoneNumber)
+			if err != nil {
+				s.logger.Errorf("failed to sync topic subscriptions of %s: %v", userDB.PhoneNumber, err)
+			}
+		}
+
+		if len(usersDB) < broadcastPageSize {
+			return
+		}
+		lastUserID = usersDB[len(usersDB)-1].ID
+	}
+}
+
+// syncTopicSubscriptions subscribes the devices of a user to the topics of the user and unsubscribes them from other topics
+func (s *messagingServer) syncTopicSubscriptions(ctx context.Context, phoneNumber string) error {
+	var (
+		topics = make(map[string]bool)
+		tokens = make(map[string]bool)
+	)
+
+	// Devices of deleted users are unsubscribed from every topic
+	userDB := &services.UserModel{}
+	err := s.sqlDB.Select("county, status, preferred_language, "+recipientColumns).
+		First(userDB, "phone_number=?", phoneNumber).Error
+	switch {
+	case err == nil:
+		for _, topic := range userTopics(userDB) {
+			topics[topic] = true
+		}
+
+		userDevices, err := s.getDeviceTokens(phoneNumber)
+		if err != nil {
+			return fmt.Errorf("failed to get user devices: %v", err)
+		}
+		for _, deviceToken := range getRecipient(userDB, userDevices[phoneNumber]).DeviceTokens {
+			tokens[deviceToken] = true
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	default:
+		return fmt.Errorf("failed to get user: %v", err)
+	}
+
+	subscriptionsDB := make([]*services.TopicSubscription, 0)
+	err = s.sqlDB.Find(&subscriptionsDB, "phone_number=?", phoneNumber).Error
+	if err != nil {
+		return fmt.Errorf("failed to get topic subscriptions: %v", err)
+	}
+
+	var (
+		subscribed  = make(map[string]bool)
+		subscribe   = make(map[string][]string)
+		unsubscribe = make(map[string][]string)
+	)
+
+	for _, subscriptionDB := range subscriptionsDB {
+		if topics[subscriptionDB.Topic] && tokens[subscriptionDB.DeviceToken] {
+			subscribed[subscriptionDB.Topic+":"+subscriptionDB.DeviceToken] = true
+			continue
+		}
+		unsubscribe[subscriptionDB.Topic] = append(unsubscribe[subscriptionDB.Topic], subscriptionDB.DeviceToken)
+	}
+
+	for topic := range topics {
+		for deviceToken := range tokens {
+			if !subscribed[topic+":"+deviceToken] {
+				subscribe[topic] = append(subscribe[topic], deviceToken)
+			}
+		}
+	}
+
+	for _, topic := range sortedKeys(unsubscribe) {
+		deviceTokens := unsubscribe[topic]
+		failed, err := s.topicManager.Unsubscribe(ctx, topic, deviceTokens)
+		if err != nil {
+			return fmt.Errorf("failed to unsubscribe devices from topic %s: %v", topic, err)
+		}
+
+		// Devices FCM does not know are not subscribed to any topic
+		removed := make([]string, 0, len(deviceTokens))
+		for _, deviceToken := range deviceTokens {
+			if err := failed[deviceToken]; err == nil || errors.Is(err, ErrDeviceNotRegistered) {
+				removed = append(removed, deviceToken)
+			}
+		}
+		if len(removed) == 0 {
+			continue
+		}
+
+		err = s.sqlDB.Delete(
+			&services.TopicSubscription{}, "topic=? AND device_token IN(?)", topic, removed,
+		).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete topic subscriptions: %v", err)
+		}
+	}
+
+	for _, topic := range sortedKeys(subscribe) {
+		deviceTokens := subscribe[topic]
+		failed, err := s.topicManager.Subscribe(ctx, topic, deviceTokens)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe devices to topic %s: %v", topic, err)
+		}
+
+		for _, deviceToken := range deviceTokens {
+			if failed[deviceToken] != nil {
+				continue
+			}
+			// A device may have moved from another user
+			err = s.sqlDB.Set("gorm:insert_option", "ON DUPLICATE KEY UPDATE phone_number = VALUES(phone_number)").
+				Create(&services.TopicSubscription{
+					DeviceToken: deviceToken,
+					Topic:       topic,
+					PhoneNumber: phoneNumber,
+				}).Error
+			if err != nil {
+				return fmt.Errorf("failed to save topic subscription: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// broadcastTopic returns the topic a broadcast to the audience is pushed to, empty when it is pushed to devices.
+// Broadcasts are only pushed to a topic once every user in the audience with push notifications is subscribed to it.
+func (s *messagingServer) broadcastTopic(audiencePB *messaging.Audience, audienceDB *gorm.DB) (string, error) {
+	if s.topicManager == nil {
+		return "", nil
+	}
+
+	topic := audienceTopic(audiencePB)
+	if topic == "" {
+		return "", nil
+	}
+
+	var recipients, subscribers int64
+
+	err := pushRecipients(audienceDB).Count(&recipients).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to count push recipients: %v", err)
+	}
+
+	err = pushRecipients(audienceDB).Where(fmt.Sprintf(
+		"phone_number IN (SELECT phone_number FROM %s WHERE topic = ?)", services.TopicSubscriptionsTable,
+	), topic).Count(&subscribers).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to count topic subscribers: %v", err)
+	}
+
+	if recipients == 0 || subscribers < recipients {
+		return "", nil
+	}
+
+	return topic, nil
+}
+
+// sendTopicBroadcast pushes the broadcast to the topic and saves the messages of the users it was pushed to
+func (s *messagingServer) sendTopicBroadcast(
+	ctx context.Context, broadcastDB *services.Broadcast, notification *Notification, topic string, audienceDB *gorm.DB,
+) error {
+	res, err := s.fcmClient.SendWithRetry(&fcm.Message{
+		To:   "/topics/" + topic,
+		Data: notification.Data,
+		Notification: &fcm.Notification{
+			Title: notification.Title,
+			Body:  notification.Body,
+		},
+	}, 5)
+	if err == nil && res != nil && res.Error != nil {
+		err = res.Error
+	}
+
+	batchDB := &services.BroadcastBatch{
+		BroadcastID: broadcastDB.BroadcastID,
+	}
+	if res != nil {
+		batchDB.MulticastID = res.MessageID
+	}
+
+	if err != nil {
+		batchDB.Error = truncate(err.Error(), 256)
+		if errSave := s.sqlDB.Create(batchDB).Error; errSave != nil {
+			s.logger.Errorf("failed to save broadcast batch: %v", errSave)
+		}
+		return fmt.Errorf("failed to push broadcast to topic %s: %v", topic, err)
+	}
+
+	// Start transaction
+	tx := s.sqlDB.Begin()
+	defer func() {
+		if err := recover(); err != nil {
+			tx.Rollback()
+		}
+	}()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	err = tx.Create(batchDB).Error
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to save broadcast batch: %v", err)
+	}
+
+	// Messages of users the broadcast was pushed to are saved at once
+	now := time.Now()
+	db := tx.Exec(
+		fmt.Sprintf(
+			"INSERT INTO %s (user_phone, title, message, data, sent, type, channel, broadcast_id, created_at, updated_at) ?",
+			services.MessagesTable,
+		),
+		pushRecipients(audienceDB).Select(
+			"phone_number, ?, ?, ?, ?, ?, ?, ?, ?, ?",
+			// Byte slices would be expanded as lists
+			broadcastDB.Title, broadcastDB.Message, string(broadcastDB.Payload), true, broadcastDB.Type, ChannelPush,
+			broadcastDB.BroadcastID, now, now,
+		).QueryExpr(),
+	)
+	if db.Error != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to save broadcast messages: %v", db.Error)
+	}
+
+	err = tx.Model(broadcastDB).Updates(map[string]interface{}{
+		"topic":     topic,
+		"processed": gorm.Expr("processed + ?", db.RowsAffected),
+		"delivered": gorm.Expr("delivered + ?", db.RowsAffected),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update broadcast progress: %v", err)
+	}
+
+	return tx.Commit().Error
+}
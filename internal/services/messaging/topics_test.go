@@ -0,0 +1,145 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallinder/go-randomdata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/pkg/api/location"
+	"github.com/gidyon/pandemic-api/pkg/api/messaging"
+)
+
+var _ = Describe("Managing FCM topic subscriptions £topics", func() {
+	var (
+		userDB *services.UserModel
+		ctx    context.Context
+	)
+
+	addDevice := func(deviceToken string) {
+		err := MessagingServer.sqlDB.Create(&services.UserDevice{
+			PhoneNumber: userDB.PhoneNumber,
+			DeviceToken: deviceToken,
+			LastSeenAt:  time.Now(),
+		}).Error
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	syncFn := func() []string {
+		_, err := MessagingAPI.SyncTopicSubscriptions(ctx, &messaging.SyncTopicSubscriptionsRequest{
+			PhoneNumber: userDB.PhoneNumber,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		subscriptionsDB := make([]*services.TopicSubscription, 0)
+		err = MessagingServer.sqlDB.Order("topic").
+			Find(&subscriptionsDB, "phone_number=?", userDB.PhoneNumber).Error
+		Expect(err).ShouldNot(HaveOccurred())
+
+		topics := make([]string, 0, len(subscriptionsDB))
+		for _, subscriptionDB := range subscriptionsDB {
+			topics = append(topics, subscriptionDB.Topic)
+		}
+		return topics
+	}
+
+	BeforeEach(func() {
+		userDB = &services.UserModel{
+			PhoneNumber:       randomPhone(),
+			FullName:          randomdata.FullName(randomdata.RandomGender),
+			County:            "Murang'a " + randomdata.RandStringRunes(10),
+			Status:            int8(location.Status_POSITIVE),
+			PreferredLanguage: services.LanguageSwahili,
+		}
+		Expect(MessagingServer.sqlDB.Create(userDB).Error).ShouldNot(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	It("should fail to sync topic subscriptions when the request is nil", func() {
+		syncRes, err := MessagingAPI.SyncTopicSubscriptions(ctx, nil)
+		Expect(err).Should(HaveOccurred())
+		Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		Expect(syncRes).Should(BeNil())
+	})
+
+	It("should map audiences of a single county, status or language to a topic", func() {
+		Expect(audienceTopic(&messaging.Audience{Counties: []string{"Nairobi"}})).Should(Equal("county_nairobi"))
+		Expect(audienceTopic(&messaging.Audience{Statuses: []string{"suspected"}})).Should(Equal("status_suspected"))
+		Expect(audienceTopic(&messaging.Audience{Languages: []string{"sw"}})).Should(Equal("language_sw"))
+
+		Expect(audienceTopic(&messaging.Audience{})).Should(BeEmpty())
+		Expect(audienceTopic(&messaging.Audience{Counties: []string{"Nairobi", "Kiambu"}})).Should(BeEmpty())
+		Expect(audienceTopic(&messaging.Audience{
+			Counties: []string{"Nairobi"}, Statuses: []string{"POSITIVE"},
+		})).Should(BeEmpty())
+		Expect(audienceTopic(&messaging.Audience{
+			Counties: []string{"Nairobi"}, Wards: []string{"Kilimani"},
+		})).Should(BeEmpty())
+	})
+
+	It("should subscribe devices of the user to the topics of the user and keep them in sync", func() {
+		addDevice(randomdata.RandStringRunes(64))
+		// Not saved since FCM does not know the device
+		addDevice("unregistered" + randomdata.RandStringRunes(52))
+
+		Expect(syncFn()).Should(Equal([]string{
+			countyTopic(userDB.County), "language_sw", "status_positive",
+		}))
+
+		err := MessagingServer.sqlDB.Model(userDB).Update("status", int8(location.Status_RECOVERED)).Error
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(syncFn()).Should(Equal([]string{
+			countyTopic(userDB.County), "language_sw", "status_recovered",
+		}))
+
+		// Devices of deleted users are unsubscribed
+		err = MessagingServer.sqlDB.Unscoped().Delete(userDB).Error
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(syncFn()).Should(BeEmpty())
+	})
+
+	It("should push a broadcast to the topic of its audience once every device is subscribed", func() {
+		addDevice(randomdata.RandStringRunes(64))
+		Expect(syncFn()).Should(HaveLen(3))
+
+		// Users without push notifications are sent the broadcast one by one
+		err := MessagingServer.sqlDB.Create(&services.UserModel{
+			PhoneNumber: randomPhone(),
+			FullName:    randomdata.FullName(randomdata.RandomGender),
+			County:      userDB.County,
+		}).Error
+		Expect(err).ShouldNot(HaveOccurred())
+
+		broadCastRes, err := MessagingAPI.BroadCastMessage(ctx, &messaging.BroadCastMessageRequest{
+			Title:    randomdata.Paragraph()[:10],
+			Message:  randomdata.Paragraph(),
+			Payload:  map[string]string{"topic": "testing"},
+			Audience: &messaging.Audience{Counties: []string{userDB.County}},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		getReq := &messaging.GetBroadcastRequest{BroadcastMessageId: broadCastRes.BroadcastMessageId}
+		Eventually(func() messaging.BroadcastStatus {
+			getRes, err := MessagingAPI.GetBroadcast(ctx, getReq)
+			Expect(err).ShouldNot(HaveOccurred())
+			return getRes.Status
+		}, 5*time.Second, 100*time.Millisecond).Should(Equal(messaging.BroadcastStatus_BROADCAST_COMPLETED))
+
+		getRes, err := MessagingAPI.GetBroadcast(ctx, getReq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getRes.Topic).Should(Equal(countyTopic(userDB.County)))
+		Expect(getRes.Processed).Should(BeEquivalentTo(2))
+		Expect(getRes.Batches).Should(HaveLen(1))
+
+		messageDB := &services.Message{}
+		err = MessagingServer.sqlDB.First(messageDB, "user_phone=? AND broadcast_id=?",
+			userDB.PhoneNumber, broadCastRes.BroadcastMessageId).Error
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(messageDB.Channel).Should(Equal(ChannelPush))
+	})
+})
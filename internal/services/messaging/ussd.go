@@ -0,0 +1,188 @@
+package messaging
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc/grpclog"
+)
+
+const (
+	// ussdMenuSize is how many unread messages are listed in the menu, so that their titles fit in a screen
+	ussdMenuSize = 4
+	// ussdMaxLength is how many characters fit in a USSD screen
+	ussdMaxLength = 182
+)
+
+// USSDOptions contains parameters for NewUSSDHandler
+type USSDOptions struct {
+	SQLDB *gorm.DB
+	// Secret is sent by the gateway in the key query parameter of the callback url
+	Secret string
+	// ServiceCode is the code users dial, such as *384*19#, sessions for other codes are ended when set
+	ServiceCode string
+	Logger      grpclog.LoggerV2
+}
+
+// ussdHandler lets users of feature phones read unread messages in USSD sessions
+type ussdHandler struct {
+	opt *USSDOptions
+}
+
+// NewUSSDHandler creates a handler of USSD gateway callbacks, as Africa's Talking USSD API, showing users a menu
+// of their unread messages. Messages read in the menu are marked as seen.
+func NewUSSDHandler(opt *USSDOptions) (http.Handler, error) {
+	var err error
+	switch {
+	case opt == nil:
+		err = errors.New("ussd options are required")
+	case opt.SQLDB == nil:
+		err = errors.New("active sqlDB is required")
+	case opt.Secret == "":
+		err = errors.New("ussd callback secret is required")
+	case opt.Logger == nil:
+		err = errors.New("logger is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ussdHandler{opt: opt}, nil
+}
+
+// NewUSSDHandlerFromEnv creates a USSD handler from USSD_CALLBACK_SECRET and USSD_SERVICE_CODE. It returns nil
+// when no callback secret is configured.
+func NewUSSDHandlerFromEnv(sqlDB *gorm.DB, logger grpclog.LoggerV2) (http.Handler, error) {
+	secret := strings.TrimSpace(os.Getenv("USSD_CALLBACK_SECRET"))
+	if secret == "" {
+		return nil, nil
+	}
+	return NewUSSDHandler(&USSDOptions{
+		SQLDB:       sqlDB,
+		Secret:      secret,
+		ServiceCode: strings.TrimSpace(os.Getenv("USSD_SERVICE_CODE")),
+		Logger:      logger,
+	})
+}
+
+func (h *ussdHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	if subtle.ConstantTimeCompare([]byte(key), []byte(h.opt.Secret)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, "malformed request", http.StatusBadRequest)
+		return
+	}
+
+	phoneNumber := strings.TrimSpace(r.PostForm.Get("phoneNumber"))
+	if phoneNumber == "" {
+		http.Error(w, "missing phone number", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if h.opt.ServiceCode != "" && r.PostForm.Get("serviceCode") != h.opt.ServiceCode {
+		fmt.Fprint(w, "END Unknown service")
+		return
+	}
+
+	screen, err := h.screen(phoneNumber, r.PostForm.Get("text"))
+	if err != nil {
+		h.opt.Logger.Errorf("failed to show ussd menu: %v", err)
+		fmt.Fprint(w, "END Service unavailable, please try again later")
+		return
+	}
+
+	fmt.Fprint(w, screen)
+}
+
+// screen returns the screen shown for the choices made in the session. The gateway sends every choice made
+// separated by *, only the last one is read since every screen offers going back to the menu.
+func (h *ussdHandler) screen(phoneNumber, text string) (string, error) {
+	messages, err := h.unreadMessages(phoneNumber)
+	if err != nil {
+		return "", err
+	}
+
+	choice := ""
+	if text != "" {
+		choices := strings.Split(text, "*")
+		choice = strings.TrimSpace(choices[len(choices)-1])
+	}
+
+	switch choice {
+	case "", "0":
+		return ussdMenu(messages, ""), nil
+	case "00":
+		return "END Goodbye", nil
+	}
+
+	n, err := strconv.Atoi(choice)
+	if err != nil || n < 1 || n > len(messages) {
+		return ussdMenu(messages, "Invalid choice\n"), nil
+	}
+	messageDB := messages[n-1]
+
+	err = h.opt.SQLDB.Model(messageDB).Update("seen", true).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to mark message as read: %v", err)
+	}
+
+	return "CON " + shorten(messageDB.Title+"\n"+messageDB.Message, ussdMaxLength-len("CON \n0. Back")) +
+		"\n0. Back", nil
+}
+
+// unreadMessages returns the oldest unread messages of the user, so that messages arriving during a session
+// do not change the numbers of those listed
+func (h *ussdHandler) unreadMessages(phoneNumber string) ([]*services.Message, error) {
+	messagesDB := make([]*services.Message, 0, ussdMenuSize)
+	err := h.opt.SQLDB.Order("id ASC").Limit(ussdMenuSize).
+		Find(&messagesDB, "user_phone = ? AND seen = ?", phoneNumber, false).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread messages: %v", err)
+	}
+	return messagesDB, nil
+}
+
+// ussdMenu lists the titles of unread messages
+func ussdMenu(messages []*services.Message, notice string) string {
+	if len(messages) == 0 {
+		return "END " + notice + "You have no unread messages"
+	}
+
+	var b strings.Builder
+	b.WriteString("CON " + notice + "Unread messages\n")
+	for i, messageDB := range messages {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, messageDB.Title)
+	}
+	b.WriteString("00. Exit")
+
+	return shorten(b.String(), ussdMaxLength)
+}
+
+// shorten shortens text to at most n characters, ending it with ... when shortened. Unlike truncate it counts
+// characters rather than bytes, which is how USSD screens are measured.
+func shorten(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,111 @@
+package messaging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/gidyon/micros"
+	"github.com/gidyon/pandemic-api/internal/services"
+)
+
+var _ = Describe("Reading unread messages through USSD #ussd", func() {
+	var (
+		handler     http.Handler
+		phoneNumber string
+	)
+
+	addMessage := func(title, message string, seen bool) *services.Message {
+		messageDB := &services.Message{
+			UserPhone: phoneNumber,
+			Title:     title,
+			Message:   message,
+			Sent:      true,
+			Seen:      seen,
+		}
+		Expect(MessagingServer.sqlDB.Create(messageDB).Error).ShouldNot(HaveOccurred())
+		return messageDB
+	}
+
+	// dial sends a callback of the gateway with the choices made in the session
+	dial := func(key, text string) *httptest.ResponseRecorder {
+		form := url.Values{
+			"sessionId":   {"ATUid_1"},
+			"serviceCode": {"*384*19#"},
+			"phoneNumber": {phoneNumber},
+			"text":        {text},
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/messaging/ussd?key="+key, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	BeforeEach(func() {
+		var err error
+		handler, err = NewUSSDHandler(&USSDOptions{
+			SQLDB:       MessagingServer.sqlDB,
+			Secret:      "ussd-secret",
+			ServiceCode: "*384*19#",
+			Logger:      micros.NewLogger("messaging"),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		phoneNumber = randomPhone()
+	})
+
+	It("should fail to create the handler without a secret", func() {
+		_, err := NewUSSDHandler(&USSDOptions{SQLDB: MessagingServer.sqlDB, Logger: micros.NewLogger("messaging")})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should reject callbacks without the secret", func() {
+		addMessage("Curfew", "Curfew starts at 7pm", false)
+		res := dial("wrong", "")
+		Expect(res.Code).Should(Equal(http.StatusUnauthorized))
+		Expect(res.Body.String()).ShouldNot(ContainSubstring("Curfew"))
+	})
+
+	It("should end the session when there are no unread messages", func() {
+		addMessage("Curfew", "Curfew starts at 7pm", true)
+		res := dial("ussd-secret", "")
+		Expect(res.Code).Should(Equal(http.StatusOK))
+		Expect(res.Body.String()).Should(Equal("END You have no unread messages"))
+	})
+
+	It("should list unread messages and mark those read as seen", func() {
+		first := addMessage("Curfew", "Curfew starts at 7pm", false)
+		addMessage("Old", "Already read", true)
+		second := addMessage("Testing", "Free testing at Nyeri county hospital", false)
+
+		res := dial("ussd-secret", "")
+		Expect(res.Body.String()).Should(Equal("CON Unread messages\n1. Curfew\n2. Testing\n00. Exit"))
+
+		res = dial("ussd-secret", "2")
+		Expect(res.Body.String()).Should(Equal("CON Testing\nFree testing at Nyeri county hospital\n0. Back"))
+
+		messageDB := &services.Message{}
+		Expect(MessagingServer.sqlDB.First(messageDB, second.ID).Error).ShouldNot(HaveOccurred())
+		Expect(messageDB.Seen).Should(BeTrue())
+		Expect(MessagingServer.sqlDB.First(messageDB, first.ID).Error).ShouldNot(HaveOccurred())
+		Expect(messageDB.Seen).Should(BeFalse())
+
+		// Going back lists the messages still unread
+		res = dial("ussd-secret", "2*0")
+		Expect(res.Body.String()).Should(Equal("CON Unread messages\n1. Curfew\n00. Exit"))
+
+		res = dial("ussd-secret", "2*0*5")
+		Expect(res.Body.String()).Should(HavePrefix("CON Invalid choice\n"))
+
+		res = dial("ussd-secret", "2*0*00")
+		Expect(res.Body.String()).Should(Equal("END Goodbye"))
+	})
+
+	It("should shorten messages to fit in a screen", func() {
+		addMessage("Guidelines", strings.Repeat("Wash your hands. ", 15), false)
+		res := dial("ussd-secret", "1")
+		Expect(len([]rune(res.Body.String()))).Should(BeNumerically("<=", ussdMaxLength))
+		Expect(res.Body.String()).Should(HaveSuffix("...\n0. Back"))
+	})
+})
@@ -1,8 +1,14 @@
 package services
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/jinzhu/gorm"
 
+	"github.com/gidyon/pandemic-api/internal/encryption"
 	"github.com/gidyon/pandemic-api/pkg/api/location"
 )
 
@@ -11,16 +17,23 @@ const LocationsTable = "locations"
 
 // LocationModel is a geographic location
 type LocationModel struct {
-	UserID        string  `gorm:"type:varchar(50);not null"`
-	Latitude      float32 `gorm:"type:float(10);not null"`
-	Longitude     float32 `gorm:"type:float(10);not null"`
+	UserID        string  `gorm:"index:idx_locations_user_timestamp;type:varchar(50);not null"`
+	Latitude      float32 `gorm:"-"`
+	Longitude     float32 `gorm:"-"`
+	Coordinates   string  `gorm:"type:varchar(255);not null"`
 	PlaceMark     string  `gorm:"type:varchar(50);not null"`
 	GeoFenceID    string  `gorm:"type:varchar(50);not null"`
 	TimeID        string  `gorm:"type:varchar(50);not null"`
-	Timestamp     int64   `gorm:"type:bigint(20);not null"`
+	Timestamp     int64   `gorm:"index:idx_locations_user_timestamp;type:bigint(20);not null"`
 	Accuracy      float32 `gorm:"type:float(10);not null"`
 	Speed         float32 `gorm:"type:float(10);not null"`
 	SpeedAccuracy float32 `gorm:"type:float(10);not null"`
+	// Administrative units containing the location, empty when they are not known
+	County       string `gorm:"type:varchar(50);not null;default:''"`
+	Constituency string `gorm:"type:varchar(50);not null;default:''"`
+	Ward         string `gorm:"type:varchar(50);not null;default:''"`
+	// DedupKey is the blind index of the device id and sequence, nil for locations sent without them
+	DedupKey *string `gorm:"type:varchar(128);unique_index"`
 	gorm.Model
 }
 
@@ -29,6 +42,67 @@ func (*LocationModel) TableName() string {
 	return LocationsTable
 }
 
+// BeforeSave encrypts the location coordinates
+func (locationDB *LocationModel) BeforeSave() error {
+	coordinates, err := encryption.Encrypt(FormatCoordinates(locationDB.Latitude, locationDB.Longitude))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt location coordinates: %v", err)
+	}
+	locationDB.Coordinates = coordinates
+	return nil
+}
+
+// AfterFind decrypts the location coordinates
+func (locationDB *LocationModel) AfterFind() error {
+	coordinates, err := encryption.Decrypt(locationDB.Coordinates)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt location coordinates: %v", err)
+	}
+	if coordinates == "" {
+		return nil
+	}
+	locationDB.Latitude, locationDB.Longitude, err = ParseCoordinates(coordinates)
+	return err
+}
+
+// FormatCoordinates formats latitude and longitude as they are stored
+func FormatCoordinates(latitude, longitude float32) string {
+	return fmt.Sprintf("%f,%f", latitude, longitude)
+}
+
+// ParseCoordinates parses coordinates formatted with FormatCoordinates
+func ParseCoordinates(coordinates string) (float32, float32, error) {
+	parts := strings.Split(coordinates, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed coordinates %q", coordinates)
+	}
+	latitude, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed latitude: %v", err)
+	}
+	longitude, err := strconv.ParseFloat(parts[1], 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed longitude: %v", err)
+	}
+	return float32(latitude), float32(longitude), nil
+}
+
+// MigrateLocationCoordinates allows inserting locations into tables created before coordinates were encrypted.
+//
+// The old latitude and longitude columns are kept until the key rotation command has encrypted them.
+func MigrateLocationCoordinates(db *gorm.DB) error {
+	for _, column := range []string{"latitude", "longitude"} {
+		if !db.Dialect().HasColumn(LocationsTable, column) {
+			continue
+		}
+		err := db.Model(&LocationModel{}).ModifyColumn(column, "float(10) NULL").Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetLocationDB creates location model from given location proto
 func GetLocationDB(locationPB *location.Location) *LocationModel {
 	return &LocationModel{
@@ -70,6 +144,18 @@ type UserModel struct {
 	Status      int8   `gorm:"type:tinyint(1);default:0"`
 	DeviceToken string `gorm:"type:varchar(256);not null;default:'NA'"`
 	Traced      bool   `gorm:"type:tinyint(1);default:0"`
+	Group       string `gorm:"index;type:varchar(50);not null;default:''"`
+	// Constituency and Ward are resolved from where the user spends nights, as is County once HomeResolvedAt is set
+	Constituency   string `gorm:"type:varchar(50);not null;default:''"`
+	Ward           string `gorm:"type:varchar(50);not null;default:''"`
+	HomeResolvedAt *time.Time
+	// DeviceTokenInvalidAt is when FCM reported the device token invalid and it was cleared
+	DeviceTokenInvalidAt *time.Time
+	Email                string `gorm:"type:varchar(100);not null;default:''"`
+	// Channels is a comma separated list of notification channels in order of preference, empty for the default order
+	Channels string `gorm:"type:varchar(50);not null;default:''"`
+	// PreferredLanguage is the language messages are sent to the user in
+	PreferredLanguage string `gorm:"type:varchar(10);not null;default:'en'"`
 	gorm.Model
 }
 
@@ -78,6 +164,173 @@ func (*UserModel) TableName() string {
 	return UsersTable
 }
 
+// QuarantineGroup is the group of users in quarantine
+const QuarantineGroup = "QUARANTINE"
+
+// Languages messages are sent in
+const (
+	LanguageEnglish = "en"
+	LanguageSwahili = "sw"
+	LanguageSheng   = "sheng"
+)
+
+// ValidLanguage reports whether messages can be sent in the language
+func ValidLanguage(language string) bool {
+	switch language {
+	case LanguageEnglish, LanguageSwahili, LanguageSheng:
+		return true
+	}
+	return false
+}
+
+// UserDevicesTable is table containing devices users receive push notifications on
+const UserDevicesTable = "user_devices"
+
+// UserDevice is a device of a user. Devices are hard deleted when unregistered or when FCM reports them invalid.
+type UserDevice struct {
+	PhoneNumber string    `gorm:"index;type:varchar(15);not null"`
+	DeviceToken string    `gorm:"unique_index;type:varchar(256);not null"`
+	Platform    int8      `gorm:"type:tinyint(1);default:0"`
+	AppVersion  string    `gorm:"type:varchar(20);not null;default:''"`
+	LastSeenAt  time.Time `gorm:"not null"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*UserDevice) TableName() string {
+	return UserDevicesTable
+}
+
+// TopicSubscriptionsTable is table containing FCM topics devices are subscribed to
+const TopicSubscriptionsTable = "topic_subscriptions"
+
+// TopicSubscription is a FCM topic a device of a user is subscribed to
+type TopicSubscription struct {
+	DeviceToken string `gorm:"primary_key;type:varchar(256)"`
+	Topic       string `gorm:"primary_key;type:varchar(100)"`
+	PhoneNumber string `gorm:"index;type:varchar(15);not null"`
+	CreatedAt   time.Time
+}
+
+// TableName returns the name of the table
+func (*TopicSubscription) TableName() string {
+	return TopicSubscriptionsTable
+}
+
+// StatusHistoryTable is table containing changes of user status
+const StatusHistoryTable = "status_history"
+
+// StatusHistory is a record of a change in user status
+type StatusHistory struct {
+	PhoneNumber string `gorm:"index;type:varchar(15);not null"`
+	Status      int8   `gorm:"type:tinyint(1);default:0"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*StatusHistory) TableName() string {
+	return StatusHistoryTable
+}
+
+// ConsentsTable is table containing user consents
+const ConsentsTable = "consents"
+
+// Consent is a user consent for a single purpose
+type Consent struct {
+	PhoneNumber          string `gorm:"unique_index:user_purpose;type:varchar(15);not null"`
+	Purpose              int8   `gorm:"unique_index:user_purpose;type:tinyint(1);not null"`
+	Granted              bool   `gorm:"type:tinyint(1);default:0"`
+	PrivacyNoticeVersion string `gorm:"type:varchar(20);not null"`
+	GrantedAt            *time.Time
+	WithdrawnAt          *time.Time
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*Consent) TableName() string {
+	return ConsentsTable
+}
+
+// WithdrawnConsents is a sub query for phone numbers of users who withdrew consent for the purpose
+func WithdrawnConsents(db *gorm.DB, purpose location.ConsentPurpose) *gorm.SqlExpr {
+	return db.Model(&Consent{}).Select("phone_number").
+		Where("purpose=? AND granted=?", int8(purpose), false).SubQuery()
+}
+
+// UserPseudonymsTable is table mapping pseudonymous user ids to phone numbers
+const UserPseudonymsTable = "user_pseudonyms"
+
+// UserPseudonym maps a pseudonymous user id used in location data to a phone number
+type UserPseudonym struct {
+	Pseudonym   string `gorm:"primary_key;type:varchar(64)"`
+	PhoneNumber string `gorm:"index;type:varchar(15);not null"`
+	Epoch       int64  `gorm:"type:bigint(20);not null"`
+	CreatedAt   time.Time
+}
+
+// TableName returns the name of the table
+func (*UserPseudonym) TableName() string {
+	return UserPseudonymsTable
+}
+
+// GeoFencesTable is table containing geo fences defined by administrators
+const GeoFencesTable = "geo_fences"
+
+// GeoFenceModel is an area with rules for alerting users in it
+type GeoFenceModel struct {
+	Name     string `gorm:"type:varchar(100);not null"`
+	County   string `gorm:"index:geo_fence_query;type:varchar(50);not null"`
+	Category string `gorm:"index:geo_fence_query;type:varchar(50);not null"`
+	TimeZone string `gorm:"type:varchar(50);not null"`
+	Polygon  []byte `gorm:"type:json;not null"`
+	Rules    []byte `gorm:"type:json;not null"`
+	Active   bool   `gorm:"type:tinyint(1);default:0"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*GeoFenceModel) TableName() string {
+	return GeoFencesTable
+}
+
+// GeoFenceVisitsTable is table containing when users were last seen in geo fences
+const GeoFenceVisitsTable = "geo_fence_visits"
+
+// GeoFenceVisit is when a pseudonymous user was last seen in a geo fence
+type GeoFenceVisit struct {
+	UserID     string    `gorm:"primary_key;type:varchar(64)"`
+	FenceID    string    `gorm:"primary_key;type:varchar(50)"`
+	LastSeenAt time.Time `gorm:"index;not null"`
+}
+
+// TableName returns the name of the table
+func (*GeoFenceVisit) TableName() string {
+	return GeoFenceVisitsTable
+}
+
+// RestrictionsTable is table containing curfews and movement restrictions
+const RestrictionsTable = "restrictions"
+
+// RestrictionModel is a curfew or a restriction on movement between zones
+type RestrictionModel struct {
+	Name         string `gorm:"type:varchar(100);not null"`
+	Type         int8   `gorm:"type:tinyint(1);default:0"`
+	CurfewStart  string `gorm:"type:varchar(5);not null"`
+	CurfewEnd    string `gorm:"type:varchar(5);not null"`
+	TimeZone     string `gorm:"type:varchar(50);not null"`
+	Zones        []byte `gorm:"type:json;not null"`
+	ExemptGroups []byte `gorm:"type:json;not null"`
+	StartDate    string `gorm:"type:varchar(10);not null"`
+	EndDate      string `gorm:"type:varchar(10);not null"`
+	Active       bool   `gorm:"type:tinyint(1);default:0"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*RestrictionModel) TableName() string {
+	return RestrictionsTable
+}
+
 // MessagesTable is messages table
 const MessagesTable = "messages"
 
@@ -90,6 +343,9 @@ type Message struct {
 	Sent      bool   `gorm:"type:tinyint(1);default:0"`
 	Seen      bool   `gorm:"type:tinyint(1);default:0"`
 	Type      int8   `gorm:"type:tinyint(1);default:0"`
+	Channel   string `gorm:"type:varchar(10);not null;default:''"`
+	// BroadcastID is the id of the broadcast the message was sent in
+	BroadcastID string `gorm:"index;type:varchar(36);not null;default:''"`
 	gorm.Model
 }
 
@@ -98,6 +354,205 @@ func (*Message) TableName() string {
 	return MessagesTable
 }
 
+// DeliveriesTable is table of messages waiting to be delivered or delivered
+const DeliveriesTable = "deliveries"
+
+// Delivery statuses
+const (
+	DeliveryPending = "PENDING"
+	DeliverySent    = "SENT"
+	DeliveryFailed  = "FAILED"
+)
+
+// Delivery is the delivery of a message to a user, written in the same transaction as the message
+type Delivery struct {
+	MessageID uint   `gorm:"unique_index;not null"`
+	UserPhone string `gorm:"type:varchar(15);not null"`
+	// DeviceToken overrides the device token of the user when set
+	DeviceToken   string    `gorm:"type:varchar(256);not null;default:''"`
+	Status        string    `gorm:"index:status_next_attempt;type:varchar(10);not null"`
+	NextAttemptAt time.Time `gorm:"index:status_next_attempt;not null"`
+	Attempts      int       `gorm:"type:int(11);not null;default:0"`
+	// Channel is the channel the message was delivered through
+	Channel   string `gorm:"type:varchar(10);not null;default:''"`
+	LastError string `gorm:"type:varchar(256);not null;default:''"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*Delivery) TableName() string {
+	return DeliveriesTable
+}
+
+// DeliveryAttemptsTable is table of attempts to deliver messages through each channel
+const DeliveryAttemptsTable = "delivery_attempts"
+
+// DeliveryAttempt is an attempt to deliver a message through a channel
+type DeliveryAttempt struct {
+	DeliveryID uint   `gorm:"index;not null"`
+	Channel    string `gorm:"type:varchar(10);not null"`
+	Delivered  bool   `gorm:"type:tinyint(1);default:0"`
+	// ProviderMessageID identifies the message with the provider, such as the FCM message id
+	ProviderMessageID string `gorm:"type:varchar(100);not null;default:''"`
+	// ErrorCode is the error code of the provider, such as NotRegistered for FCM
+	ErrorCode string `gorm:"type:varchar(30);not null;default:''"`
+	Error     string `gorm:"type:varchar(256);not null;default:''"`
+	// CanonicalRegistrationID is the token FCM reports the device is registered with when it differs from the one used
+	CanonicalRegistrationID string `gorm:"type:varchar(256);not null;default:''"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*DeliveryAttempt) TableName() string {
+	return DeliveryAttemptsTable
+}
+
+// BroadcastsTable is table of messages broadcasted to many users
+const BroadcastsTable = "broadcasts"
+
+// Broadcast statuses
+const (
+	BroadcastPending   = "PENDING"
+	BroadcastRunning   = "RUNNING"
+	BroadcastCompleted = "COMPLETED"
+	BroadcastCancelled = "CANCELLED"
+	BroadcastFailed    = "FAILED"
+)
+
+// Broadcast is a job sending a message to the users matching its filters, in pages of users ordered by id
+type Broadcast struct {
+	BroadcastID string `gorm:"unique_index;type:varchar(36);not null"`
+	Title       string `gorm:"type:varchar(30);not null"`
+	Message     string `gorm:"type:varchar(256);not null"`
+	Type        int8   `gorm:"type:tinyint(1);default:0"`
+	Payload     []byte `gorm:"type:json"`
+	// Filters is a comma separated list of filter names, Topics is a json array of counties
+	Filters string `gorm:"type:varchar(50);not null;default:''"`
+	Topics  []byte `gorm:"type:json"`
+	Status  string `gorm:"index;type:varchar(10);not null"`
+	// Recipients is how many users matched the filters when the broadcast was created
+	Recipients int64 `gorm:"not null;default:0"`
+	Processed  int64 `gorm:"not null;default:0"`
+	Delivered  int64 `gorm:"not null;default:0"`
+	Failed     int64 `gorm:"not null;default:0"`
+	// LastUserID is the id of the last user the broadcast was sent to
+	LastUserID uint   `gorm:"not null;default:0"`
+	LastError  string `gorm:"type:varchar(256);not null;default:''"`
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	// LeaseUntil is when a running broadcast is resumed by another replica unless the replica sending it renews the lease
+	LeaseUntil *time.Time
+	// ScheduleID is the id of the schedule the broadcast was sent for
+	ScheduleID string `gorm:"index;type:varchar(36);not null;default:''"`
+	// Audience is the json audience expression, nil for broadcasts sent by filters
+	Audience []byte `gorm:"type:json"`
+	// Topic is the FCM topic the broadcast was pushed to, empty when it was pushed to devices
+	Topic string `gorm:"type:varchar(100);not null;default:''"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*Broadcast) TableName() string {
+	return BroadcastsTable
+}
+
+// MessageTemplatesTable is table of templates messages are rendered from
+const MessageTemplatesTable = "message_templates"
+
+// Templates of messages sent by the services
+const (
+	TemplateWelcome         = "welcome"
+	TemplateContactAlert    = "contact_alert"
+	TemplateAreaAlert       = "area_alert"
+	TemplateExposureWarning = "exposure_warning"
+)
+
+// MessageTemplate is the wording of a message in a language. Templates are hard deleted.
+type MessageTemplate struct {
+	Name     string `gorm:"unique_index:idx_template_language;type:varchar(50);not null"`
+	Language string `gorm:"unique_index:idx_template_language;type:varchar(10);not null"`
+	Title    string `gorm:"type:varchar(100);not null"`
+	Body     string `gorm:"type:text;not null"`
+	// Variables is a json array of the variables the template is rendered with
+	Variables []byte `gorm:"type:json"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*MessageTemplate) TableName() string {
+	return MessageTemplatesTable
+}
+
+// BroadcastSchedulesTable is table of broadcasts sent at a later time or repeatedly
+const BroadcastSchedulesTable = "broadcast_schedules"
+
+// BroadcastSchedule is a broadcast sent once at a later time or repeatedly on a cron schedule.
+// Every run of the schedule creates a Broadcast.
+type BroadcastSchedule struct {
+	ScheduleID string `gorm:"unique_index;type:varchar(36);not null"`
+	Title      string `gorm:"type:varchar(30);not null"`
+	Message    string `gorm:"type:varchar(256);not null"`
+	Type       int8   `gorm:"type:tinyint(1);default:0"`
+	Payload    []byte `gorm:"type:json"`
+	Filters    string `gorm:"type:varchar(50);not null;default:''"`
+	Topics     []byte `gorm:"type:json"`
+	// SendAt is when a one-off broadcast is sent, or when a recurring broadcast starts
+	SendAt         *time.Time
+	CronExpression string `gorm:"type:varchar(100);not null;default:''"`
+	Timezone       string `gorm:"type:varchar(50);not null"`
+	EndAt          *time.Time
+	// NextRunAt is nil once the schedule has ended
+	NextRunAt       *time.Time `gorm:"index"`
+	Paused          bool       `gorm:"not null;default:false"`
+	Runs            int64      `gorm:"not null;default:0"`
+	LastRunAt       *time.Time
+	LastBroadcastID string `gorm:"type:varchar(36);not null;default:''"`
+	Audience        []byte `gorm:"type:json"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*BroadcastSchedule) TableName() string {
+	return BroadcastSchedulesTable
+}
+
+// BroadcastBatchesTable is table of multicast messages sent for broadcasts
+const BroadcastBatchesTable = "broadcast_batches"
+
+// BroadcastBatch is what FCM reported for a multicast message sent to a batch of device tokens of a broadcast
+type BroadcastBatch struct {
+	BroadcastID  string `gorm:"index;type:varchar(36);not null"`
+	Tokens       int    `gorm:"type:int(11);not null;default:0"`
+	Success      int    `gorm:"type:int(11);not null;default:0"`
+	Failure      int    `gorm:"type:int(11);not null;default:0"`
+	CanonicalIDs int    `gorm:"type:int(11);not null;default:0"`
+	// MulticastID identifies the multicast message with FCM
+	MulticastID int64  `gorm:"not null;default:0"`
+	Error       string `gorm:"type:varchar(256);not null;default:''"`
+	gorm.Model
+}
+
+// TableName returns the name of the table
+func (*BroadcastBatch) TableName() string {
+	return BroadcastBatchesTable
+}
+
+// AudienceVisitorsTable is table of users seen in geo fences, saved while broadcasts to them are estimated or sent
+const AudienceVisitorsTable = "audience_visitors"
+
+// AudienceVisitor is a user seen in the geo fences of an audience
+type AudienceVisitor struct {
+	// AudienceID identifies the geo fences and time users were seen since, for a single estimate or broadcast
+	AudienceID  string    `gorm:"primary_key;type:varchar(36)"`
+	PhoneNumber string    `gorm:"primary_key;type:varchar(15)"`
+	CreatedAt   time.Time `gorm:"index"`
+}
+
+// TableName returns the name of the table
+func (*AudienceVisitor) TableName() string {
+	return AudienceVisitorsTable
+}
+
 // int64 id = 1;
 //     string county = 2;
 //     string description = 3;
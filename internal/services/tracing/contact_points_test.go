@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
+	"time"
+)
+
+var _ = Describe("Expanding patient contact points to neighbouring cells #contactpoints", func() {
+	var (
+		ctx         context.Context
+		phoneNumber string
+		today       time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		phoneNumber = randomdata.PhoneNumber()
+		today = time.Now()
+	})
+
+	It("should add contact points in neighbouring cells and adjacent time windows", func() {
+		var (
+			userID = TracingServer.pseudonyms.ID(phoneNumber, today)
+			cellID = TracingServer.cells.CellID(-0.0001, 36.8219)
+			timeID = TracingServer.timeBuckets.TimeID(today.Unix())
+		)
+
+		err := TracingServer.redisDB.SAdd(
+			ctx, getUserSetKey(userID, &today), conversion.ContactPoint(cellID, timeID),
+		).Err()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = TracingServer.expandContactPoints(ctx, phoneNumber, today, today)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		contactPoints, err := TracingServer.redisDB.SMembers(ctx, getContactPointsKey(userID, &today)).Result()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(contactPoints).Should(HaveLen(27))
+		Expect(contactPoints).Should(ContainElement(conversion.ContactPoint(cellID, timeID)))
+
+		// A user across the equator is in a neighbouring cell
+		northCellID := TracingServer.cells.CellID(0.0001, 36.8219)
+		Expect(contactPoints).Should(ContainElement(conversion.ContactPoint(northCellID, timeID)))
+
+		// A user two minutes later may be in the next time window
+		nextTimeID := TracingServer.timeBuckets.TimeID(today.Add(TracingServer.timeBuckets.Width()).Unix())
+		Expect(contactPoints).Should(ContainElement(conversion.ContactPoint(cellID, nextTimeID)))
+
+		ttl, err := TracingServer.redisDB.TTL(ctx, getContactPointsKey(userID, &today)).Result()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ttl).Should(BeNumerically(">", 0))
+	})
+
+	It("should include contact points of the previous day in adjacent time windows", func() {
+		var (
+			yesterday  = today.Add(-24 * time.Hour)
+			y, m, d    = today.Date()
+			midnight   = time.Date(y, m, d, 0, 0, 0, 0, today.Location())
+			cellID     = TracingServer.cells.CellID(-1.2921, 36.8219)
+			lateTimeID = TracingServer.timeBuckets.TimeID(midnight.Add(-time.Second).Unix())
+		)
+
+		err := TracingServer.redisDB.SAdd(
+			ctx,
+			getUserSetKey(TracingServer.pseudonyms.ID(phoneNumber, yesterday), &yesterday),
+			conversion.ContactPoint(cellID, lateTimeID),
+		).Err()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = TracingServer.expandContactPoints(ctx, phoneNumber, today, today)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		userID := TracingServer.pseudonyms.ID(phoneNumber, today)
+		contactPoints, err := TracingServer.redisDB.SMembers(ctx, getContactPointsKey(userID, &today)).Result()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(contactPoints).Should(ContainElement(conversion.ContactPoint(
+			cellID, TracingServer.timeBuckets.TimeID(midnight.Unix()),
+		)))
+	})
+
+	It("should not create a set when the patient has no contact points", func() {
+		userID := TracingServer.pseudonyms.ID(phoneNumber, today)
+
+		err := TracingServer.expandContactPoints(ctx, phoneNumber, today, today)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		exists, err := TracingServer.redisDB.Exists(ctx, getContactPointsKey(userID, &today)).Result()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(exists).Should(BeZero())
+	})
+})
@@ -8,7 +8,6 @@ import (
 	"github.com/gidyon/pandemic-api/pkg/api/location"
 	"github.com/gidyon/pandemic-api/pkg/api/messaging"
 	"io"
-	"strings"
 	"sync"
 	"time"
 
@@ -22,7 +21,9 @@ import (
 
 	"google.golang.org/grpc/status"
 
+	"github.com/gidyon/pandemic-api/internal/pseudonym"
 	"github.com/gidyon/pandemic-api/internal/services"
+	"github.com/gidyon/pandemic-api/internal/services/location/conversion"
 	"github.com/go-redis/redis"
 
 	"github.com/jinzhu/gorm"
@@ -35,6 +36,9 @@ type tracingAPIServer struct {
 	sqlDB                *gorm.DB
 	redisDB              *redis.Client
 	messagingClient      messaging.MessagingClient
+	pseudonyms           *pseudonym.Pseudonymizer
+	cells                conversion.CellSystem
+	timeBuckets          *conversion.TimeBuckets
 }
 
 // Options contains options for creating tracing API
@@ -42,6 +46,9 @@ type Options struct {
 	SQLDB           *gorm.DB
 	RedisClient     *redis.Client
 	MessagingClient messaging.MessagingClient
+	Pseudonymizer   *pseudonym.Pseudonymizer
+	CellSystem      conversion.CellSystem
+	TimeBuckets     *conversion.TimeBuckets
 	Logger          grpclog.LoggerV2
 }
 
@@ -58,6 +65,8 @@ func NewContactTracingAPI(
 		err = errors.New("non-nil redis is required")
 	case opt.MessagingClient == nil:
 		err = errors.New("non-nil messaging client is required")
+	case opt.Pseudonymizer == nil:
+		err = errors.New("non-nil pseudonymizer is required")
 	case opt.Logger == nil:
 		err = errors.New("non-nil logger is required")
 	}
@@ -71,11 +80,23 @@ func NewContactTracingAPI(
 		sqlDB:                opt.SQLDB,
 		redisDB:              opt.RedisClient,
 		messagingClient:      opt.MessagingClient,
+		pseudonyms:           opt.Pseudonymizer,
+		cells:                opt.CellSystem,
+		timeBuckets:          opt.TimeBuckets,
 		logger:               opt.Logger,
 	}
 
+	if ms.cells == nil {
+		ms.cells = conversion.DefaultCellSystem()
+	}
+	if ms.timeBuckets == nil {
+		ms.timeBuckets = conversion.DefaultTimeBuckets()
+	}
+
 	// Automigration
-	err = ms.sqlDB.AutoMigrate(&services.ContactTracingOperation{}).Error
+	err = ms.sqlDB.AutoMigrate(
+		&services.ContactTracingOperation{}, &services.StatusHistory{}, &services.Consent{},
+	).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to automigrate: %v", err)
 	}
@@ -89,6 +110,74 @@ func getUserSetKey(userID string, t *time.Time) string {
 	return fmt.Sprintf("%s:%s", userID, date)
 }
 
+// getContactPointsKey is the key of a temporary set with the contact points of a patient and their neighbouring cells
+func getContactPointsKey(userID string, t *time.Time) string {
+	return "contacts:" + getUserSetKey(userID, t)
+}
+
+const contactPointsExpiration = 24 * time.Hour
+
+// expandContactPoints saves the contact points of the patient for each day together with neighbouring
+// cells and adjacent time windows, so that users who were close by are matched by intersecting sets.
+//
+// Adjacent windows may fall on the previous or next day, so contact points of those days are included too.
+func (t *tracingAPIServer) expandContactPoints(
+	ctx context.Context, phoneNumber string, sinceDate, todayDate time.Time,
+) error {
+	const day = 24 * time.Hour
+
+	// Expanded contact points of each day from the day before since date to the day after today
+	expanded := make([][]interface{}, 0)
+	for date := sinceDate.Add(-day); date.Unix() <= todayDate.Add(day).Unix(); date = date.Add(day) {
+		contactPoints, err := t.redisDB.SMembers(ctx, getUserSetKey(t.pseudonyms.ID(phoneNumber, date), &date)).Result()
+		if err != nil {
+			return err
+		}
+
+		members := make([]interface{}, 0, len(contactPoints)*27)
+		for _, contactPoint := range contactPoints {
+			cellID, timeID, ok := conversion.ParseContactPoint(contactPoint)
+			if !ok {
+				continue
+			}
+			for _, tID := range t.timeBuckets.TimeAndAdjacent(timeID) {
+				for _, cID := range conversion.CellAndNeighbours(t.cells, cellID) {
+					members = append(members, conversion.ContactPoint(cID, tID))
+				}
+			}
+		}
+
+		expanded = append(expanded, members)
+	}
+
+	i := 1
+	for since := sinceDate; since.Unix() <= todayDate.Unix(); since = since.Add(day) {
+		members := make([]interface{}, 0, len(expanded[i-1])+len(expanded[i])+len(expanded[i+1]))
+		members = append(members, expanded[i-1]...)
+		members = append(members, expanded[i]...)
+		members = append(members, expanded[i+1]...)
+		i++
+
+		if len(members) == 0 {
+			continue
+		}
+
+		key := getContactPointsKey(t.pseudonyms.ID(phoneNumber, since), &since)
+
+		pipeliner := t.redisDB.TxPipeline()
+		pipeliner.Del(ctx, key)
+		pipeliner.SAdd(ctx, key, members...)
+		pipeliner.Expire(ctx, key, contactPointsExpiration)
+
+		_, err := pipeliner.Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 type dayContact struct {
 	UserPhone    string
 	PatientPhone string
@@ -134,6 +223,19 @@ func (t *tracingAPIServer) TraceUserLocations(
 		return nil, status.Error(codes.FailedPrecondition, "user must be infected with COVID-19")
 	}
 
+	// User must not have withdrawn consent for tracing
+	err = t.sqlDB.Select("id").First(&services.Consent{},
+		"phone_number=? AND purpose=? AND granted=?",
+		userDB.PhoneNumber, int8(location.ConsentPurpose_TRACING), false,
+	).Error
+	switch {
+	case err == nil:
+		return nil, status.Error(codes.FailedPrecondition, "user has withdrawn consent for location tracing")
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	default:
+		return nil, status.Errorf(codes.Internal, "failed to get user consent: %v", err)
+	}
+
 	// Get user logs for the last n days
 	sinceDate, err := time.Parse("2006-01-02", traceReq.SinceDate)
 	if err != nil {
@@ -194,6 +296,7 @@ func (t *tracingAPIServer) traceUserWorker(
 	}
 
 	var (
+		ctx       = context.Background()
 		todayDate = time.Now()
 		condition = true
 		offset    = 0
@@ -204,11 +307,21 @@ func (t *tracingAPIServer) traceUserWorker(
 		complete  = true
 	)
 
+	// Contact points in neighbouring cells are matched too
+	err = t.expandContactPoints(ctx, userDB.PhoneNumber, *sinceDate, todayDate)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to expand patient contact points: %v", err)
+		t.logger.Error(errMsg)
+		t.failLongRunningOperation(longrunningID, errMsg)
+		return
+	}
+
 	for condition {
 		usersDB = make([]*services.UserModel, 0, limit)
 
-		// Only those whose current status is not known
-		db := t.sqlDB.Limit(limit).Offset(offset).Where("status=?", int8(location.Status_UNKNOWN))
+		// Only those whose current status is not known and who have not withdrawn consent
+		db := t.sqlDB.Limit(limit).Offset(offset).Where("status=?", int8(location.Status_UNKNOWN)).
+			Where("phone_number NOT IN (?)", services.WithdrawnConsents(t.sqlDB, location.ConsentPurpose_TRACING))
 
 		if len(traceCounties) > 0 {
 			db = db.Where("county IN(?)", traceCounties)
@@ -256,9 +369,11 @@ func (t *tracingAPIServer) traceUserWorker(
 				resChan := make(chan *redis.StringSliceCmd, days)
 
 				for since.Unix() <= todayDate.Unix() {
-					// Get union of contact points
+					// Get union of contact points, sets are keyed by the users pseudonymous id of the day
 					resChan <- pipeliner.SInter(
-						getUserSetKey(suspect.PhoneNumber, &since), getUserSetKey(userDB.PhoneNumber, &since),
+						ctx,
+						getUserSetKey(t.pseudonyms.ID(suspect.PhoneNumber, since), &since),
+						getContactPointsKey(t.pseudonyms.ID(userDB.PhoneNumber, since), &since),
 					)
 
 					since = since.Add(time.Hour * 24)
@@ -266,7 +381,7 @@ func (t *tracingAPIServer) traceUserWorker(
 
 				close(resChan)
 
-				_, err := pipeliner.Exec()
+				_, err := pipeliner.Exec(ctx)
 				if err != nil {
 					mu.Lock()
 					complete = false
@@ -292,8 +407,8 @@ func (t *tracingAPIServer) traceUserWorker(
 
 					// Range individual contact points
 					if len(contacts) > 0 {
-						contactPointData := strings.Split(contacts[0], ":")
-						if len(contactPointData) != 3 {
+						_, timeID, ok := conversion.ParseContactPoint(contacts[0])
+						if !ok {
 							mu.Lock()
 							complete = false
 							mu.Unlock()
@@ -303,7 +418,18 @@ func (t *tracingAPIServer) traceUserWorker(
 							return
 						}
 
-						contactData.ContactTime = contactPointData[2]
+						contactTime, err := t.timeBuckets.Time(timeID)
+						if err != nil {
+							mu.Lock()
+							complete = false
+							mu.Unlock()
+							errMsg := fmt.Sprintf("failed to parse contact time: %v", err)
+							t.logger.Error(errMsg)
+							t.failLongRunningOperation(longrunningID, errMsg)
+							return
+						}
+
+						contactData.ContactTime = contactTime.Format(time.RFC1123)
 						contactData.Count = int32(len(contacts))
 					}
 				}
@@ -322,6 +448,15 @@ func (t *tracingAPIServer) traceUserWorker(
 						return
 					}
 
+					// Keep history of status changes
+					err = t.sqlDB.Create(&services.StatusHistory{
+						PhoneNumber: suspect.PhoneNumber,
+						Status:      int8(location.Status_SUSPECTED),
+					}).Error
+					if err != nil {
+						t.logger.Errorf("failed to save status history: %v", err)
+					}
+
 					// Send contact data to messaging server
 					err = messagingStream.Send(contactData)
 					switch {
@@ -455,7 +590,8 @@ func (t *tracingAPIServer) TraceUsersLocations(
 	for condition {
 		usersDB = make([]*services.UserModel, 0, limit)
 
-		db := t.sqlDB.Select("status, id, full_name, phone_number, county")
+		db := t.sqlDB.Select("status, id, full_name, phone_number, county").
+			Where("phone_number NOT IN (?)", services.WithdrawnConsents(t.sqlDB, location.ConsentPurpose_TRACING))
 		if len(traceReq.Counties) > 0 {
 			db = db.Where("county IN(?)", traceReq.Counties)
 		}
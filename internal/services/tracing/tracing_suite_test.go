@@ -11,6 +11,7 @@ import (
 	"github.com/go-redis/redis"
 
 	"github.com/gidyon/micros"
+	"github.com/gidyon/pandemic-api/internal/pseudonym"
 	"github.com/gidyon/pandemic-api/internal/services/tracing/mocks"
 	"github.com/jinzhu/gorm"
 	"github.com/onsi/ginkgo"
@@ -71,10 +72,17 @@ var _ = BeforeSuite(func() {
 	messagingClient.On("Send", mock.Anything, mock.Anything).
 		Return(nil)
 
+	pseudonymizer, err := pseudonym.NewPseudonymizer(ctx, &pseudonym.Options{
+		SQLDB: db,
+		Key:   []byte("tracing-test-pseudonym-key"),
+	})
+	handleError(err)
+
 	opt := &Options{
 		SQLDB:           db,
 		RedisClient:     redisDB,
 		MessagingClient: messagingClient,
+		Pseudonymizer:   pseudonymizer,
 		Logger:          micros.NewLogger("messaging"),
 	}
 
@@ -101,6 +109,11 @@ var _ = BeforeSuite(func() {
 	_, err = NewContactTracingAPI(ctx, opt)
 	Expect(err).Should(HaveOccurred())
 
+	opt.MessagingClient = messagingClient
+	opt.Pseudonymizer = nil
+	_, err = NewContactTracingAPI(ctx, opt)
+	Expect(err).Should(HaveOccurred())
+
 	opt.MessagingClient = nil
 	opt.Logger = nil
 	_, err = NewContactTracingAPI(ctx, opt)
@@ -61,18 +61,229 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_4f0f35158dcf9f2c, []int{0}
 }
 
+// NotificationChannel is a channel through which users are notified
+type NotificationChannel int32
+
+const (
+	NotificationChannel_PUSH  NotificationChannel = 0
+	NotificationChannel_SMS   NotificationChannel = 1
+	NotificationChannel_EMAIL NotificationChannel = 2
+)
+
+var NotificationChannel_name = map[int32]string{
+	0: "PUSH",
+	1: "SMS",
+	2: "EMAIL",
+}
+
+var NotificationChannel_value = map[string]int32{
+	"PUSH":  0,
+	"SMS":   1,
+	"EMAIL": 2,
+}
+
+func (x NotificationChannel) String() string {
+	return proto.EnumName(NotificationChannel_name, int32(x))
+}
+
+func (NotificationChannel) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{1}
+}
+
+// DevicePlatform is the platform of a device
+type DevicePlatform int32
+
+const (
+	DevicePlatform_PLATFORM_UNSPECIFIED DevicePlatform = 0
+	DevicePlatform_ANDROID              DevicePlatform = 1
+	DevicePlatform_IOS                  DevicePlatform = 2
+	DevicePlatform_WEB                  DevicePlatform = 3
+)
+
+var DevicePlatform_name = map[int32]string{
+	0: "PLATFORM_UNSPECIFIED",
+	1: "ANDROID",
+	2: "IOS",
+	3: "WEB",
+}
+
+var DevicePlatform_value = map[string]int32{
+	"PLATFORM_UNSPECIFIED": 0,
+	"ANDROID":              1,
+	"IOS":                  2,
+	"WEB":                  3,
+}
+
+func (x DevicePlatform) String() string {
+	return proto.EnumName(DevicePlatform_name, int32(x))
+}
+
+func (DevicePlatform) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{2}
+}
+
+// ExportFormat is the file format of exported user data
+type ExportFormat int32
+
+const (
+	ExportFormat_JSON ExportFormat = 0
+	ExportFormat_ZIP  ExportFormat = 1
+)
+
+var ExportFormat_name = map[int32]string{
+	0: "JSON",
+	1: "ZIP",
+}
+
+var ExportFormat_value = map[string]int32{
+	"JSON": 0,
+	"ZIP":  1,
+}
+
+func (x ExportFormat) String() string {
+	return proto.EnumName(ExportFormat_name, int32(x))
+}
+
+func (ExportFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{3}
+}
+
+// ConsentPurpose is a purpose for which a user allows their data to be used
+type ConsentPurpose int32
+
+const (
+	ConsentPurpose_ALL_PURPOSES ConsentPurpose = 0
+	ConsentPurpose_TRACING      ConsentPurpose = 1
+	ConsentPurpose_ANALYTICS    ConsentPurpose = 2
+	ConsentPurpose_RESEARCH     ConsentPurpose = 3
+)
+
+var ConsentPurpose_name = map[int32]string{
+	0: "ALL_PURPOSES",
+	1: "TRACING",
+	2: "ANALYTICS",
+	3: "RESEARCH",
+}
+
+var ConsentPurpose_value = map[string]int32{
+	"ALL_PURPOSES": 0,
+	"TRACING":      1,
+	"ANALYTICS":    2,
+	"RESEARCH":     3,
+}
+
+func (x ConsentPurpose) String() string {
+	return proto.EnumName(ConsentPurpose_name, int32(x))
+}
+
+func (ConsentPurpose) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{4}
+}
+
+// TrajectoryExportFormat is the file format a trajectory is exported to
+type TrajectoryExportFormat int32
+
+const (
+	TrajectoryExportFormat_NO_EXPORT TrajectoryExportFormat = 0
+	TrajectoryExportFormat_GEOJSON   TrajectoryExportFormat = 1
+	TrajectoryExportFormat_GPX       TrajectoryExportFormat = 2
+)
+
+var TrajectoryExportFormat_name = map[int32]string{
+	0: "NO_EXPORT",
+	1: "GEOJSON",
+	2: "GPX",
+}
+
+var TrajectoryExportFormat_value = map[string]int32{
+	"NO_EXPORT": 0,
+	"GEOJSON":   1,
+	"GPX":       2,
+}
+
+func (x TrajectoryExportFormat) String() string {
+	return proto.EnumName(TrajectoryExportFormat_name, int32(x))
+}
+
+func (TrajectoryExportFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{5}
+}
+
+// GeoFenceTrigger is when a geo fence rule sends its message
+type GeoFenceTrigger int32
+
+const (
+	GeoFenceTrigger_ON_ENTER      GeoFenceTrigger = 0
+	GeoFenceTrigger_ON_DWELL      GeoFenceTrigger = 1
+	GeoFenceTrigger_DURING_CURFEW GeoFenceTrigger = 2
+)
+
+var GeoFenceTrigger_name = map[int32]string{
+	0: "ON_ENTER",
+	1: "ON_DWELL",
+	2: "DURING_CURFEW",
+}
+
+var GeoFenceTrigger_value = map[string]int32{
+	"ON_ENTER":      0,
+	"ON_DWELL":      1,
+	"DURING_CURFEW": 2,
+}
+
+func (x GeoFenceTrigger) String() string {
+	return proto.EnumName(GeoFenceTrigger_name, int32(x))
+}
+
+func (GeoFenceTrigger) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{6}
+}
+
+// RestrictionType is the kind of movement restriction
+type RestrictionType int32
+
+const (
+	RestrictionType_CURFEW   RestrictionType = 0
+	RestrictionType_MOVEMENT RestrictionType = 1
+)
+
+var RestrictionType_name = map[int32]string{
+	0: "CURFEW",
+	1: "MOVEMENT",
+}
+
+var RestrictionType_value = map[string]int32{
+	"CURFEW":   0,
+	"MOVEMENT": 1,
+}
+
+func (x RestrictionType) String() string {
+	return proto.EnumName(RestrictionType_name, int32(x))
+}
+
+func (RestrictionType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{7}
+}
+
 // Represents a geographic location
 type Location struct {
-	Longitude            float32  `protobuf:"fixed32,1,opt,name=longitude,proto3" json:"longitude,omitempty"`
-	Latitude             float32  `protobuf:"fixed32,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
-	Timestamp            int64    `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Accuracy             float32  `protobuf:"fixed32,4,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
-	Altitude             float32  `protobuf:"fixed32,5,opt,name=altitude,proto3" json:"altitude,omitempty"`
-	Speed                float32  `protobuf:"fixed32,6,opt,name=speed,proto3" json:"speed,omitempty"`
-	SpeedAccuracy        float32  `protobuf:"fixed32,7,opt,name=speed_accuracy,json=speedAccuracy,proto3" json:"speed_accuracy,omitempty"`
-	Placemark            string   `protobuf:"bytes,8,opt,name=placemark,proto3" json:"placemark,omitempty"`
-	GeoFenceId           string   `protobuf:"bytes,9,opt,name=geo_fence_id,json=geoFenceId,proto3" json:"geo_fence_id,omitempty"`
-	TimeId               string   `protobuf:"bytes,11,opt,name=time_id,json=timeId,proto3" json:"time_id,omitempty"`
+	Longitude     float32 `protobuf:"fixed32,1,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Latitude      float32 `protobuf:"fixed32,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Timestamp     int64   `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Accuracy      float32 `protobuf:"fixed32,4,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	Altitude      float32 `protobuf:"fixed32,5,opt,name=altitude,proto3" json:"altitude,omitempty"`
+	Speed         float32 `protobuf:"fixed32,6,opt,name=speed,proto3" json:"speed,omitempty"`
+	SpeedAccuracy float32 `protobuf:"fixed32,7,opt,name=speed_accuracy,json=speedAccuracy,proto3" json:"speed_accuracy,omitempty"`
+	Placemark     string  `protobuf:"bytes,8,opt,name=placemark,proto3" json:"placemark,omitempty"`
+	// Id of the cell containing the location, set by the server
+	GeoFenceId string `protobuf:"bytes,9,opt,name=geo_fence_id,json=geoFenceId,proto3" json:"geo_fence_id,omitempty"`
+	// Unix time in seconds at which the time window of the location starts, set by the server
+	TimeId string `protobuf:"bytes,11,opt,name=time_id,json=timeId,proto3" json:"time_id,omitempty"`
+	// Whether the device reported the location as coming from a mock provider
+	IsMock bool `protobuf:"varint,12,opt,name=is_mock,json=isMock,proto3" json:"is_mock,omitempty"`
+	// Random id generated by the app on install. Locations with a device id and sequence are saved at most once
+	DeviceId string `protobuf:"bytes,13,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// Number of the location on the device, increasing from 1
+	Sequence             int64    `protobuf:"varint,14,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -173,6 +384,27 @@ func (m *Location) GetTimeId() string {
 	return ""
 }
 
+func (m *Location) GetIsMock() bool {
+	if m != nil {
+		return m.IsMock
+	}
+	return false
+}
+
+func (m *Location) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *Location) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
 // SendLocationRequest is request to send a single location
 type SendLocationRequest struct {
 	UserId               string    `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -285,6 +517,195 @@ func (m *SendLocationsRequest) GetLocations() []*Location {
 	return nil
 }
 
+// LocationResult is the outcome of saving a single location
+type LocationResult struct {
+	// Position of the location in the stream
+	Index    int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Accepted bool  `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	// Accepted locations with low confidence are stored but not used for contact tracing
+	LowConfidence bool `protobuf:"varint,3,opt,name=low_confidence,json=lowConfidence,proto3" json:"low_confidence,omitempty"`
+	// Why the location was rejected
+	Reason               string   `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LocationResult) Reset()         { *m = LocationResult{} }
+func (m *LocationResult) String() string { return proto.CompactTextString(m) }
+func (*LocationResult) ProtoMessage()    {}
+func (*LocationResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{3}
+}
+
+func (m *LocationResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LocationResult.Unmarshal(m, b)
+}
+func (m *LocationResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LocationResult.Marshal(b, m, deterministic)
+}
+func (m *LocationResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LocationResult.Merge(m, src)
+}
+func (m *LocationResult) XXX_Size() int {
+	return xxx_messageInfo_LocationResult.Size(m)
+}
+func (m *LocationResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_LocationResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LocationResult proto.InternalMessageInfo
+
+func (m *LocationResult) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *LocationResult) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *LocationResult) GetLowConfidence() bool {
+	if m != nil {
+		return m.LowConfidence
+	}
+	return false
+}
+
+func (m *LocationResult) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// StreamLocationsResponse contains the outcome of every location in a stream
+type StreamLocationsResponse struct {
+	Results       []*LocationResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	AcceptedCount int32             `protobuf:"varint,2,opt,name=accepted_count,json=acceptedCount,proto3" json:"accepted_count,omitempty"`
+	RejectedCount int32             `protobuf:"varint,3,opt,name=rejected_count,json=rejectedCount,proto3" json:"rejected_count,omitempty"`
+	DeviceId      string            `protobuf:"bytes,4,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// Highest sequence that was processed, locations up to it can be removed from the device
+	AcknowledgedSequence int64    `protobuf:"varint,5,opt,name=acknowledged_sequence,json=acknowledgedSequence,proto3" json:"acknowledged_sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamLocationsResponse) Reset()         { *m = StreamLocationsResponse{} }
+func (m *StreamLocationsResponse) String() string { return proto.CompactTextString(m) }
+func (*StreamLocationsResponse) ProtoMessage()    {}
+func (*StreamLocationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{4}
+}
+
+func (m *StreamLocationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamLocationsResponse.Unmarshal(m, b)
+}
+func (m *StreamLocationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamLocationsResponse.Marshal(b, m, deterministic)
+}
+func (m *StreamLocationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamLocationsResponse.Merge(m, src)
+}
+func (m *StreamLocationsResponse) XXX_Size() int {
+	return xxx_messageInfo_StreamLocationsResponse.Size(m)
+}
+func (m *StreamLocationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamLocationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamLocationsResponse proto.InternalMessageInfo
+
+func (m *StreamLocationsResponse) GetResults() []*LocationResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *StreamLocationsResponse) GetAcceptedCount() int32 {
+	if m != nil {
+		return m.AcceptedCount
+	}
+	return 0
+}
+
+func (m *StreamLocationsResponse) GetRejectedCount() int32 {
+	if m != nil {
+		return m.RejectedCount
+	}
+	return 0
+}
+
+func (m *StreamLocationsResponse) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *StreamLocationsResponse) GetAcknowledgedSequence() int64 {
+	if m != nil {
+		return m.AcknowledgedSequence
+	}
+	return 0
+}
+
+// SendLocationsResponse is response after sending locations
+type SendLocationsResponse struct {
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// Highest sequence that was processed, locations up to it can be removed from the device
+	AcknowledgedSequence int64    `protobuf:"varint,2,opt,name=acknowledged_sequence,json=acknowledgedSequence,proto3" json:"acknowledged_sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendLocationsResponse) Reset()         { *m = SendLocationsResponse{} }
+func (m *SendLocationsResponse) String() string { return proto.CompactTextString(m) }
+func (*SendLocationsResponse) ProtoMessage()    {}
+func (*SendLocationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{5}
+}
+
+func (m *SendLocationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendLocationsResponse.Unmarshal(m, b)
+}
+func (m *SendLocationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendLocationsResponse.Marshal(b, m, deterministic)
+}
+func (m *SendLocationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendLocationsResponse.Merge(m, src)
+}
+func (m *SendLocationsResponse) XXX_Size() int {
+	return xxx_messageInfo_SendLocationsResponse.Size(m)
+}
+func (m *SendLocationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendLocationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendLocationsResponse proto.InternalMessageInfo
+
+func (m *SendLocationsResponse) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *SendLocationsResponse) GetAcknowledgedSequence() int64 {
+	if m != nil {
+		return m.AcknowledgedSequence
+	}
+	return 0
+}
+
 // UpdateUserStatusRequest is request to update user status
 type UpdateUserStatusRequest struct {
 	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
@@ -298,7 +719,7 @@ func (m *UpdateUserStatusRequest) Reset()         { *m = UpdateUserStatusRequest
 func (m *UpdateUserStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateUserStatusRequest) ProtoMessage()    {}
 func (*UpdateUserStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f0f35158dcf9f2c, []int{3}
+	return fileDescriptor_4f0f35158dcf9f2c, []int{6}
 }
 
 func (m *UpdateUserStatusRequest) XXX_Unmarshal(b []byte) error {
@@ -346,7 +767,7 @@ func (m *UpdateUserRequest) Reset()         { *m = UpdateUserRequest{} }
 func (m *UpdateUserRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateUserRequest) ProtoMessage()    {}
 func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f0f35158dcf9f2c, []int{4}
+	return fileDescriptor_4f0f35158dcf9f2c, []int{7}
 }
 
 func (m *UpdateUserRequest) XXX_Unmarshal(b []byte) error {
@@ -393,7 +814,7 @@ func (m *AddUserRequest) Reset()         { *m = AddUserRequest{} }
 func (m *AddUserRequest) String() string { return proto.CompactTextString(m) }
 func (*AddUserRequest) ProtoMessage()    {}
 func (*AddUserRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f0f35158dcf9f2c, []int{5}
+	return fileDescriptor_4f0f35158dcf9f2c, []int{8}
 }
 
 func (m *AddUserRequest) XXX_Unmarshal(b []byte) error {
@@ -423,13 +844,24 @@ func (m *AddUserRequest) GetUser() *User {
 
 // User is an app user
 type User struct {
-	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
-	FullName             string   `protobuf:"bytes,2,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
-	County               string   `protobuf:"bytes,3,opt,name=county,proto3" json:"county,omitempty"`
-	Status               Status   `protobuf:"varint,4,opt,name=status,proto3,enum=covitrace.Status" json:"status,omitempty"`
-	DeviceToken          string   `protobuf:"bytes,5,opt,name=device_token,json=deviceToken,proto3" json:"device_token,omitempty"`
-	Traced               bool     `protobuf:"varint,6,opt,name=traced,proto3" json:"traced,omitempty"`
-	UpdatedTimestamp     int64    `protobuf:"varint,7,opt,name=updated_timestamp,json=updatedTimestamp,proto3" json:"updated_timestamp,omitempty"`
+	PhoneNumber      string `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	FullName         string `protobuf:"bytes,2,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	County           string `protobuf:"bytes,3,opt,name=county,proto3" json:"county,omitempty"`
+	Status           Status `protobuf:"varint,4,opt,name=status,proto3,enum=covitrace.Status" json:"status,omitempty"`
+	DeviceToken      string `protobuf:"bytes,5,opt,name=device_token,json=deviceToken,proto3" json:"device_token,omitempty"`
+	Traced           bool   `protobuf:"varint,6,opt,name=traced,proto3" json:"traced,omitempty"`
+	UpdatedTimestamp int64  `protobuf:"varint,7,opt,name=updated_timestamp,json=updatedTimestamp,proto3" json:"updated_timestamp,omitempty"`
+	// Group of the user such as HEALTH_WORKER, used for exemptions from movement restrictions
+	Group string `protobuf:"bytes,8,opt,name=group,proto3" json:"group,omitempty"`
+	// Constituency and ward where the user lives, resolved from their locations
+	Constituency string `protobuf:"bytes,9,opt,name=constituency,proto3" json:"constituency,omitempty"`
+	Ward         string `protobuf:"bytes,10,opt,name=ward,proto3" json:"ward,omitempty"`
+	// Email address used by the email notification channel
+	Email string `protobuf:"bytes,11,opt,name=email,proto3" json:"email,omitempty"`
+	// Channels the user prefers to be notified through, in order of preference
+	NotificationChannels []NotificationChannel `protobuf:"varint,12,rep,packed,name=notification_channels,json=notificationChannels,proto3,enum=covitrace.NotificationChannel" json:"notification_channels,omitempty"`
+	// Language messages are sent to the user in, one of en, sw or sheng. Defaults to en
+	PreferredLanguage    string   `protobuf:"bytes,13,opt,name=preferred_language,json=preferredLanguage,proto3" json:"preferred_language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -439,7 +871,7 @@ func (m *User) Reset()         { *m = User{} }
 func (m *User) String() string { return proto.CompactTextString(m) }
 func (*User) ProtoMessage()    {}
 func (*User) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f0f35158dcf9f2c, []int{6}
+	return fileDescriptor_4f0f35158dcf9f2c, []int{9}
 }
 
 func (m *User) XXX_Unmarshal(b []byte) error {
@@ -509,49 +941,339 @@ func (m *User) GetUpdatedTimestamp() int64 {
 	return 0
 }
 
-// GetUserRequest is request to retrieve a single user
-type GetUserRequest struct {
-	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *User) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
 }
 
-func (m *GetUserRequest) Reset()         { *m = GetUserRequest{} }
-func (m *GetUserRequest) String() string { return proto.CompactTextString(m) }
-func (*GetUserRequest) ProtoMessage()    {}
-func (*GetUserRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f0f35158dcf9f2c, []int{7}
+func (m *User) GetConstituency() string {
+	if m != nil {
+		return m.Constituency
+	}
+	return ""
 }
 
-func (m *GetUserRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetUserRequest.Unmarshal(m, b)
-}
-func (m *GetUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetUserRequest.Marshal(b, m, deterministic)
-}
-func (m *GetUserRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetUserRequest.Merge(m, src)
-}
-func (m *GetUserRequest) XXX_Size() int {
-	return xxx_messageInfo_GetUserRequest.Size(m)
-}
-func (m *GetUserRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetUserRequest.DiscardUnknown(m)
+func (m *User) GetWard() string {
+	if m != nil {
+		return m.Ward
+	}
+	return ""
 }
 
-var xxx_messageInfo_GetUserRequest proto.InternalMessageInfo
-
-func (m *GetUserRequest) GetPhoneNumber() string {
+func (m *User) GetEmail() string {
 	if m != nil {
-		return m.PhoneNumber
+		return m.Email
 	}
 	return ""
 }
 
-// ListUsersRequest is request to get collection of users from database
-type ListUsersRequest struct {
-	PageSize             int32    `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+func (m *User) GetNotificationChannels() []NotificationChannel {
+	if m != nil {
+		return m.NotificationChannels
+	}
+	return nil
+}
+
+func (m *User) GetPreferredLanguage() string {
+	if m != nil {
+		return m.PreferredLanguage
+	}
+	return ""
+}
+
+// Device is a device a user receives push notifications on
+type Device struct {
+	DeviceToken          string         `protobuf:"bytes,1,opt,name=device_token,json=deviceToken,proto3" json:"device_token,omitempty"`
+	Platform             DevicePlatform `protobuf:"varint,2,opt,name=platform,proto3,enum=covitrace.DevicePlatform" json:"platform,omitempty"`
+	AppVersion           string         `protobuf:"bytes,3,opt,name=app_version,json=appVersion,proto3" json:"app_version,omitempty"`
+	LastSeenTimestamp    int64          `protobuf:"varint,4,opt,name=last_seen_timestamp,json=lastSeenTimestamp,proto3" json:"last_seen_timestamp,omitempty"`
+	RegisteredTimestamp  int64          `protobuf:"varint,5,opt,name=registered_timestamp,json=registeredTimestamp,proto3" json:"registered_timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Device) Reset()         { *m = Device{} }
+func (m *Device) String() string { return proto.CompactTextString(m) }
+func (*Device) ProtoMessage()    {}
+func (*Device) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{10}
+}
+
+func (m *Device) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Device.Unmarshal(m, b)
+}
+func (m *Device) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Device.Marshal(b, m, deterministic)
+}
+func (m *Device) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Device.Merge(m, src)
+}
+func (m *Device) XXX_Size() int {
+	return xxx_messageInfo_Device.Size(m)
+}
+func (m *Device) XXX_DiscardUnknown() {
+	xxx_messageInfo_Device.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Device proto.InternalMessageInfo
+
+func (m *Device) GetDeviceToken() string {
+	if m != nil {
+		return m.DeviceToken
+	}
+	return ""
+}
+
+func (m *Device) GetPlatform() DevicePlatform {
+	if m != nil {
+		return m.Platform
+	}
+	return DevicePlatform_PLATFORM_UNSPECIFIED
+}
+
+func (m *Device) GetAppVersion() string {
+	if m != nil {
+		return m.AppVersion
+	}
+	return ""
+}
+
+func (m *Device) GetLastSeenTimestamp() int64 {
+	if m != nil {
+		return m.LastSeenTimestamp
+	}
+	return 0
+}
+
+func (m *Device) GetRegisteredTimestamp() int64 {
+	if m != nil {
+		return m.RegisteredTimestamp
+	}
+	return 0
+}
+
+// RegisterDeviceRequest is request to register a device of a user
+type RegisterDeviceRequest struct {
+	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Device               *Device  `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterDeviceRequest) Reset()         { *m = RegisterDeviceRequest{} }
+func (m *RegisterDeviceRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterDeviceRequest) ProtoMessage()    {}
+func (*RegisterDeviceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{11}
+}
+
+func (m *RegisterDeviceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterDeviceRequest.Unmarshal(m, b)
+}
+func (m *RegisterDeviceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterDeviceRequest.Marshal(b, m, deterministic)
+}
+func (m *RegisterDeviceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterDeviceRequest.Merge(m, src)
+}
+func (m *RegisterDeviceRequest) XXX_Size() int {
+	return xxx_messageInfo_RegisterDeviceRequest.Size(m)
+}
+func (m *RegisterDeviceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterDeviceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterDeviceRequest proto.InternalMessageInfo
+
+func (m *RegisterDeviceRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *RegisterDeviceRequest) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+// UnregisterDeviceRequest is request to stop notifying a device of a user
+type UnregisterDeviceRequest struct {
+	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	DeviceToken          string   `protobuf:"bytes,2,opt,name=device_token,json=deviceToken,proto3" json:"device_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnregisterDeviceRequest) Reset()         { *m = UnregisterDeviceRequest{} }
+func (m *UnregisterDeviceRequest) String() string { return proto.CompactTextString(m) }
+func (*UnregisterDeviceRequest) ProtoMessage()    {}
+func (*UnregisterDeviceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{12}
+}
+
+func (m *UnregisterDeviceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnregisterDeviceRequest.Unmarshal(m, b)
+}
+func (m *UnregisterDeviceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnregisterDeviceRequest.Marshal(b, m, deterministic)
+}
+func (m *UnregisterDeviceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnregisterDeviceRequest.Merge(m, src)
+}
+func (m *UnregisterDeviceRequest) XXX_Size() int {
+	return xxx_messageInfo_UnregisterDeviceRequest.Size(m)
+}
+func (m *UnregisterDeviceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnregisterDeviceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnregisterDeviceRequest proto.InternalMessageInfo
+
+func (m *UnregisterDeviceRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *UnregisterDeviceRequest) GetDeviceToken() string {
+	if m != nil {
+		return m.DeviceToken
+	}
+	return ""
+}
+
+// ListDevicesRequest is request to retrieve devices of a user
+type ListDevicesRequest struct {
+	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDevicesRequest) Reset()         { *m = ListDevicesRequest{} }
+func (m *ListDevicesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDevicesRequest) ProtoMessage()    {}
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{13}
+}
+
+func (m *ListDevicesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDevicesRequest.Unmarshal(m, b)
+}
+func (m *ListDevicesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDevicesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDevicesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDevicesRequest.Merge(m, src)
+}
+func (m *ListDevicesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDevicesRequest.Size(m)
+}
+func (m *ListDevicesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDevicesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDevicesRequest proto.InternalMessageInfo
+
+func (m *ListDevicesRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+// Devices is a collection of devices of a user
+type Devices struct {
+	Devices              []*Device `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Devices) Reset()         { *m = Devices{} }
+func (m *Devices) String() string { return proto.CompactTextString(m) }
+func (*Devices) ProtoMessage()    {}
+func (*Devices) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{14}
+}
+
+func (m *Devices) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Devices.Unmarshal(m, b)
+}
+func (m *Devices) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Devices.Marshal(b, m, deterministic)
+}
+func (m *Devices) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Devices.Merge(m, src)
+}
+func (m *Devices) XXX_Size() int {
+	return xxx_messageInfo_Devices.Size(m)
+}
+func (m *Devices) XXX_DiscardUnknown() {
+	xxx_messageInfo_Devices.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Devices proto.InternalMessageInfo
+
+func (m *Devices) GetDevices() []*Device {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+// GetUserRequest is request to retrieve a single user
+type GetUserRequest struct {
+	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetUserRequest) Reset()         { *m = GetUserRequest{} }
+func (m *GetUserRequest) String() string { return proto.CompactTextString(m) }
+func (*GetUserRequest) ProtoMessage()    {}
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{15}
+}
+
+func (m *GetUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUserRequest.Unmarshal(m, b)
+}
+func (m *GetUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUserRequest.Marshal(b, m, deterministic)
+}
+func (m *GetUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUserRequest.Merge(m, src)
+}
+func (m *GetUserRequest) XXX_Size() int {
+	return xxx_messageInfo_GetUserRequest.Size(m)
+}
+func (m *GetUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUserRequest proto.InternalMessageInfo
+
+func (m *GetUserRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+// ListUsersRequest is request to get collection of users from database
+type ListUsersRequest struct {
+	PageSize             int32    `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
 	PageToken            int32    `protobuf:"varint,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	FilterStatus         Status   `protobuf:"varint,3,opt,name=filter_status,json=filterStatus,proto3,enum=covitrace.Status" json:"filter_status,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -563,7 +1285,7 @@ func (m *ListUsersRequest) Reset()         { *m = ListUsersRequest{} }
 func (m *ListUsersRequest) String() string { return proto.CompactTextString(m) }
 func (*ListUsersRequest) ProtoMessage()    {}
 func (*ListUsersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f0f35158dcf9f2c, []int{8}
+	return fileDescriptor_4f0f35158dcf9f2c, []int{16}
 }
 
 func (m *ListUsersRequest) XXX_Unmarshal(b []byte) error {
@@ -620,7 +1342,7 @@ func (m *SearchUsersRequest) Reset()         { *m = SearchUsersRequest{} }
 func (m *SearchUsersRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchUsersRequest) ProtoMessage()    {}
 func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f0f35158dcf9f2c, []int{9}
+	return fileDescriptor_4f0f35158dcf9f2c, []int{17}
 }
 
 func (m *SearchUsersRequest) XXX_Unmarshal(b []byte) error {
@@ -682,7 +1404,7 @@ func (m *Users) Reset()         { *m = Users{} }
 func (m *Users) String() string { return proto.CompactTextString(m) }
 func (*Users) ProtoMessage()    {}
 func (*Users) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f0f35158dcf9f2c, []int{10}
+	return fileDescriptor_4f0f35158dcf9f2c, []int{18}
 }
 
 func (m *Users) XXX_Unmarshal(b []byte) error {
@@ -717,366 +1439,3299 @@ func (m *Users) GetNextPageToken() int32 {
 	return 0
 }
 
-func init() {
-	proto.RegisterEnum("covitrace.Status", Status_name, Status_value)
-	proto.RegisterType((*Location)(nil), "covitrace.Location")
-	proto.RegisterType((*SendLocationRequest)(nil), "covitrace.SendLocationRequest")
-	proto.RegisterType((*SendLocationsRequest)(nil), "covitrace.SendLocationsRequest")
-	proto.RegisterType((*UpdateUserStatusRequest)(nil), "covitrace.UpdateUserStatusRequest")
-	proto.RegisterType((*UpdateUserRequest)(nil), "covitrace.UpdateUserRequest")
-	proto.RegisterType((*AddUserRequest)(nil), "covitrace.AddUserRequest")
-	proto.RegisterType((*User)(nil), "covitrace.User")
-	proto.RegisterType((*GetUserRequest)(nil), "covitrace.GetUserRequest")
-	proto.RegisterType((*ListUsersRequest)(nil), "covitrace.ListUsersRequest")
-	proto.RegisterType((*SearchUsersRequest)(nil), "covitrace.SearchUsersRequest")
-	proto.RegisterType((*Users)(nil), "covitrace.Users")
+// ExportMyDataRequest is request to export all data held about a user
+type ExportMyDataRequest struct {
+	PhoneNumber          string       `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Format               ExportFormat `protobuf:"varint,2,opt,name=format,proto3,enum=covitrace.ExportFormat" json:"format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
-func init() { proto.RegisterFile("location.proto", fileDescriptor_4f0f35158dcf9f2c) }
+func (m *ExportMyDataRequest) Reset()         { *m = ExportMyDataRequest{} }
+func (m *ExportMyDataRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportMyDataRequest) ProtoMessage()    {}
+func (*ExportMyDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{19}
+}
 
-var fileDescriptor_4f0f35158dcf9f2c = []byte{
-	// 1034 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xdb, 0x6e, 0xdb, 0x46,
-	0x13, 0xfe, 0xa9, 0x33, 0x47, 0xb6, 0x22, 0x6f, 0x0c, 0x47, 0x91, 0xe4, 0xfc, 0x32, 0xdd, 0xa4,
-	0x6e, 0xda, 0x88, 0x88, 0x82, 0xf6, 0xa2, 0x77, 0x6e, 0xa2, 0x06, 0x42, 0x03, 0xd9, 0xa0, 0x64,
-	0x17, 0x68, 0x50, 0x10, 0x6b, 0x72, 0xcd, 0x10, 0xa6, 0x48, 0x86, 0x07, 0xb7, 0x4e, 0xd1, 0x5e,
-	0xf4, 0xa2, 0xb7, 0x05, 0xda, 0xfb, 0x3e, 0x43, 0xdf, 0xa5, 0x6f, 0x50, 0xf4, 0x05, 0xfa, 0x06,
-	0xc5, 0xce, 0x92, 0x12, 0x75, 0xb2, 0x13, 0x20, 0x57, 0xd2, 0xcc, 0x37, 0x33, 0xdf, 0x70, 0x76,
-	0xf6, 0x5b, 0xa8, 0x39, 0x9e, 0x41, 0x23, 0xdb, 0x73, 0xbb, 0x7e, 0xe0, 0x45, 0x1e, 0x91, 0x0d,
-	0xef, 0xd2, 0x8e, 0x02, 0x6a, 0xb0, 0x66, 0xcb, 0xf2, 0x3c, 0xcb, 0x61, 0x2a, 0x02, 0x67, 0xf1,
-	0xb9, 0xca, 0x26, 0x7e, 0x74, 0x25, 0xe2, 0x9a, 0xfb, 0x09, 0xe8, 0x78, 0xae, 0x15, 0xc4, 0xae,
-	0x6b, 0xbb, 0x96, 0xea, 0xf9, 0x2c, 0xc0, 0x5a, 0x61, 0x12, 0xd4, 0x4e, 0x82, 0xa8, 0x6f, 0xab,
-	0xd4, 0x75, 0xbd, 0x68, 0x0e, 0xfd, 0x04, 0x7f, 0x8c, 0x47, 0x16, 0x73, 0x1f, 0x85, 0xdf, 0x51,
-	0xcb, 0x62, 0x81, 0xea, 0xf9, 0x18, 0xb1, 0x1c, 0xad, 0xfc, 0x99, 0x83, 0xca, 0x8b, 0xa4, 0x57,
-	0xd2, 0x06, 0x99, 0x13, 0xdb, 0x51, 0x6c, 0xb2, 0x86, 0xd4, 0x91, 0x0e, 0x72, 0xda, 0xcc, 0x41,
-	0x9a, 0x50, 0x71, 0x68, 0x24, 0xc0, 0x1c, 0x82, 0x53, 0x9b, 0x67, 0x46, 0xf6, 0x84, 0x85, 0x11,
-	0x9d, 0xf8, 0x8d, 0x7c, 0x47, 0x3a, 0xc8, 0x6b, 0x33, 0x07, 0xcf, 0xa4, 0x86, 0x11, 0x07, 0xd4,
-	0xb8, 0x6a, 0x14, 0x44, 0x66, 0x6a, 0x23, 0xe6, 0x24, 0x55, 0x8b, 0x09, 0x96, 0xd8, 0x64, 0x1b,
-	0x8a, 0xa1, 0xcf, 0x98, 0xd9, 0x28, 0x21, 0x20, 0x0c, 0x72, 0x1f, 0x6a, 0xf8, 0x47, 0x9f, 0xd6,
-	0x2c, 0x23, 0xbc, 0x89, 0xde, 0xc3, 0xb4, 0x70, 0x1b, 0x64, 0xdf, 0xa1, 0x06, 0x9b, 0xd0, 0xe0,
-	0xa2, 0x51, 0xe9, 0x48, 0x07, 0xb2, 0x36, 0x73, 0x90, 0x0e, 0x6c, 0x58, 0xcc, 0xd3, 0xcf, 0x99,
-	0x6b, 0x30, 0xdd, 0x36, 0x1b, 0x32, 0x06, 0x80, 0xc5, 0xbc, 0x2f, 0xb9, 0x6b, 0x60, 0x92, 0x3b,
-	0x50, 0xe6, 0x5f, 0xc0, 0xc1, 0x2a, 0x82, 0x25, 0x6e, 0x0e, 0x4c, 0xe5, 0x57, 0x09, 0x6e, 0x8f,
-	0x98, 0x6b, 0xa6, 0x63, 0xd3, 0xd8, 0xeb, 0x98, 0x85, 0x11, 0x4f, 0x88, 0x43, 0x16, 0xf0, 0x04,
-	0x49, 0x24, 0x70, 0x73, 0x60, 0x92, 0x2e, 0xc8, 0x61, 0x44, 0xa3, 0x38, 0xe4, 0x10, 0x9f, 0x5c,
-	0xad, 0xb7, 0xd5, 0x9d, 0x2e, 0x44, 0x77, 0x84, 0x98, 0x56, 0x11, 0x31, 0x03, 0x93, 0xa8, 0x50,
-	0x49, 0xd7, 0x07, 0x67, 0x59, 0xed, 0xdd, 0xce, 0x84, 0x4f, 0x69, 0xa7, 0x41, 0xca, 0x6f, 0x12,
-	0x6c, 0x67, 0x3b, 0x0a, 0xdf, 0x7b, 0x4b, 0x8f, 0xf9, 0x66, 0x24, 0xc5, 0x1b, 0xf9, 0x4e, 0x7e,
-	0x5d, 0x4f, 0xb3, 0x28, 0xc5, 0x82, 0x3b, 0x27, 0xbe, 0x49, 0x23, 0x76, 0x12, 0xb2, 0x20, 0x29,
-	0x98, 0xb4, 0xb5, 0x07, 0x1b, 0xfe, 0x2b, 0xcf, 0x65, 0xba, 0x1b, 0x4f, 0xce, 0x58, 0x90, 0xf4,
-	0x56, 0x45, 0xdf, 0x10, 0x5d, 0xe4, 0x23, 0x28, 0x09, 0xf2, 0xf5, 0xdd, 0x25, 0x01, 0xca, 0x4b,
-	0xd8, 0x9a, 0x11, 0xbd, 0x03, 0xc5, 0x3e, 0x14, 0xf8, 0x34, 0x90, 0xa0, 0xda, 0xbb, 0x95, 0x21,
-	0xc0, 0x42, 0x08, 0x2a, 0x9f, 0x42, 0xed, 0xd0, 0x34, 0xb3, 0x95, 0xd3, 0x34, 0xe9, 0xba, 0xb4,
-	0x7f, 0x25, 0x28, 0x70, 0xf3, 0x6d, 0xfa, 0x68, 0x81, 0x7c, 0x1e, 0x3b, 0x8e, 0xee, 0xd2, 0x89,
-	0xb8, 0x58, 0xb2, 0x56, 0xe1, 0x8e, 0x21, 0x9d, 0x30, 0xb2, 0x03, 0x25, 0xc3, 0x8b, 0xdd, 0xe8,
-	0x0a, 0x37, 0x41, 0xd6, 0x12, 0x2b, 0x33, 0x9f, 0xc2, 0x0d, 0xf3, 0xe1, 0x2d, 0x98, 0xec, 0xd2,
-	0x36, 0x98, 0x1e, 0x79, 0x17, 0xcc, 0xc5, 0x5b, 0x26, 0x6b, 0x55, 0xe1, 0x1b, 0x73, 0x17, 0x67,
-	0xc1, 0x54, 0x71, 0xd3, 0x2a, 0x5a, 0x62, 0x91, 0x8f, 0x61, 0x2b, 0xc6, 0xd1, 0x9a, 0xfa, 0xec,
-	0x7a, 0x97, 0xf1, 0x7a, 0xd7, 0x13, 0x60, 0x9c, 0xfa, 0x95, 0x27, 0x50, 0x7b, 0xce, 0xa2, 0x77,
-	0x3b, 0x04, 0xe5, 0x17, 0x09, 0xea, 0x2f, 0xec, 0x10, 0xd3, 0xa6, 0xfb, 0xd1, 0x02, 0xd9, 0xa7,
-	0x16, 0xd3, 0x43, 0xfb, 0x8d, 0xd0, 0xa1, 0xa2, 0x56, 0xe1, 0x8e, 0x91, 0xfd, 0x86, 0x91, 0x5d,
-	0x00, 0x04, 0xc5, 0xc7, 0xe4, 0x10, 0xc5, 0x70, 0xf1, 0x29, 0x9f, 0xc1, 0xe6, 0xb9, 0xed, 0x44,
-	0x2c, 0xd0, 0x93, 0xf9, 0xe4, 0xd7, 0xcd, 0x67, 0x43, 0xc4, 0x09, 0x4b, 0xf9, 0x43, 0x02, 0x32,
-	0x62, 0x34, 0x30, 0x5e, 0xbd, 0xb7, 0x56, 0xb6, 0xa1, 0xf8, 0x3a, 0x66, 0x41, 0x7a, 0x74, 0xc2,
-	0x58, 0x6e, 0xb0, 0xf0, 0x76, 0x0d, 0x9e, 0x42, 0x11, 0x3b, 0x23, 0xf7, 0xa1, 0xc8, 0x77, 0x2c,
-	0x6c, 0x48, 0x78, 0x0f, 0x97, 0x36, 0x50, 0xa0, 0xe4, 0x01, 0xdc, 0x72, 0xd9, 0xf7, 0x91, 0xbe,
-	0xd4, 0xe1, 0x26, 0x77, 0x1f, 0xa7, 0x5d, 0x3e, 0x3c, 0x82, 0x92, 0x60, 0x20, 0x55, 0x28, 0x9f,
-	0x0c, 0xbf, 0x1a, 0x1e, 0x7d, 0x3d, 0xac, 0xff, 0x8f, 0x6c, 0x40, 0xe5, 0xf8, 0x68, 0x34, 0x18,
-	0x0f, 0x4e, 0xfb, 0x75, 0x89, 0x5b, 0xc3, 0xfe, 0xf3, 0x43, 0xb4, 0x72, 0x64, 0x13, 0xe4, 0xd1,
-	0xc9, 0xe8, 0xb8, 0xff, 0x74, 0xdc, 0x7f, 0x56, 0xcf, 0x73, 0x53, 0xeb, 0x3f, 0x3d, 0x3a, 0xed,
-	0x6b, 0xfd, 0x67, 0xf5, 0x42, 0xef, 0xef, 0x12, 0x90, 0x54, 0x10, 0xc6, 0x01, 0x35, 0x6c, 0xd7,
-	0x3a, 0x3c, 0x1e, 0x10, 0x1b, 0x36, 0xb2, 0x1a, 0x45, 0xee, 0x65, 0x3f, 0x78, 0x59, 0x4e, 0x9b,
-	0x3b, 0x5d, 0xf1, 0xcc, 0x75, 0xd3, 0x87, 0xb2, 0xdb, 0xe7, 0x0f, 0xa5, 0xb2, 0xf7, 0xf3, 0x5f,
-	0xff, 0xfc, 0x9e, 0x6b, 0x29, 0x3b, 0xf8, 0xfe, 0x5d, 0x3e, 0x56, 0xa7, 0x92, 0xa3, 0x86, 0xcc,
-	0x35, 0x3f, 0x97, 0x1e, 0x12, 0x1f, 0x36, 0xe7, 0xe4, 0x90, 0xfc, 0x7f, 0x0d, 0x57, 0x78, 0x13,
-	0xd9, 0x03, 0x24, 0xeb, 0x28, 0xad, 0xd5, 0x64, 0xea, 0x59, 0xec, 0x5c, 0x70, 0xc6, 0x9f, 0xa0,
-	0xbe, 0x28, 0x76, 0x44, 0xc9, 0x1e, 0xcc, 0x6a, 0x25, 0x5c, 0xcb, 0xdb, 0x45, 0xde, 0x83, 0xde,
-	0x7e, 0xca, 0x8b, 0x67, 0xaa, 0xfe, 0x90, 0xbd, 0x4e, 0x3f, 0xaa, 0x62, 0x81, 0x38, 0xff, 0x05,
-	0xc0, 0x8c, 0x82, 0xb4, 0x57, 0x32, 0xdf, 0xc4, 0xf9, 0x21, 0x72, 0xee, 0xf5, 0xda, 0xd7, 0x71,
-	0x72, 0x32, 0x0a, 0xe5, 0x44, 0x13, 0xc9, 0xdd, 0x0c, 0xd3, 0xbc, 0x4e, 0xae, 0xa5, 0xd9, 0x47,
-	0x9a, 0x5d, 0xa5, 0x31, 0x4f, 0x43, 0x0d, 0x3e, 0x56, 0x95, 0x9a, 0x78, 0x82, 0x2f, 0xa1, 0x9c,
-	0x68, 0xc9, 0x1c, 0xc5, 0xbc, 0xbe, 0x34, 0x17, 0x57, 0x5f, 0xf9, 0x00, 0x6b, 0xdf, 0x23, 0xd7,
-	0x7e, 0x02, 0xf9, 0x16, 0xe4, 0xa9, 0xe4, 0x90, 0x56, 0xf6, 0x19, 0x5b, 0x10, 0xa2, 0x66, 0x7d,
-	0x81, 0x20, 0x4c, 0xb7, 0x8f, 0xdc, 0x5d, 0xd9, 0xbd, 0x63, 0x87, 0x11, 0x31, 0xa0, 0x9a, 0x11,
-	0x12, 0xb2, 0x3b, 0xb7, 0x7b, 0x8b, 0x02, 0xb3, 0x82, 0x22, 0x19, 0x10, 0x69, 0xad, 0xa4, 0x08,
-	0xb1, 0xc4, 0x17, 0xf0, 0xcd, 0xf4, 0xf9, 0x3f, 0x2b, 0xe1, 0x84, 0x9f, 0xfc, 0x17, 0x00, 0x00,
-	0xff, 0xff, 0x36, 0xa5, 0xc9, 0x9d, 0x75, 0x0a, 0x00, 0x00,
+func (m *ExportMyDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportMyDataRequest.Unmarshal(m, b)
+}
+func (m *ExportMyDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportMyDataRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportMyDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportMyDataRequest.Merge(m, src)
+}
+func (m *ExportMyDataRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportMyDataRequest.Size(m)
+}
+func (m *ExportMyDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportMyDataRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_ExportMyDataRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *ExportMyDataRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
 
-// LocationTracingAPIClient is the client API for LocationTracingAPI service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type LocationTracingAPIClient interface {
-	// Send a single location to the server
-	SendLocation(ctx context.Context, in *SendLocationRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Sends user locations
-	SendLocations(ctx context.Context, in *SendLocationsRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Updates user status
-	UpdateUserStatus(ctx context.Context, in *UpdateUserStatusRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Updates user data
-	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Add a new user
-	AddUser(ctx context.Context, in *AddUserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Retrieves a single user
-	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
-	// Retrieves a collection of users
-	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*Users, error)
-	// Searches for users using phone number or full names
-	SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*Users, error)
+func (m *ExportMyDataRequest) GetFormat() ExportFormat {
+	if m != nil {
+		return m.Format
+	}
+	return ExportFormat_JSON
 }
 
-type locationTracingAPIClient struct {
-	cc *grpc.ClientConn
+// ExportMyDataResponse contains exported user data as a downloadable file
+type ExportMyDataResponse struct {
+	FileName             string   `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	ContentType          string   `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportMyDataResponse) Reset()         { *m = ExportMyDataResponse{} }
+func (m *ExportMyDataResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportMyDataResponse) ProtoMessage()    {}
+func (*ExportMyDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{20}
+}
+
+func (m *ExportMyDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportMyDataResponse.Unmarshal(m, b)
+}
+func (m *ExportMyDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportMyDataResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportMyDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportMyDataResponse.Merge(m, src)
+}
+func (m *ExportMyDataResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportMyDataResponse.Size(m)
+}
+func (m *ExportMyDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportMyDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportMyDataResponse proto.InternalMessageInfo
+
+func (m *ExportMyDataResponse) GetFileName() string {
+	if m != nil {
+		return m.FileName
+	}
+	return ""
+}
+
+func (m *ExportMyDataResponse) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *ExportMyDataResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// DeleteMyAccountRequest is request to erase a user account and all data held about them
+type DeleteMyAccountRequest struct {
+	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteMyAccountRequest) Reset()         { *m = DeleteMyAccountRequest{} }
+func (m *DeleteMyAccountRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteMyAccountRequest) ProtoMessage()    {}
+func (*DeleteMyAccountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{21}
+}
+
+func (m *DeleteMyAccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteMyAccountRequest.Unmarshal(m, b)
+}
+func (m *DeleteMyAccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteMyAccountRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteMyAccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteMyAccountRequest.Merge(m, src)
+}
+func (m *DeleteMyAccountRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteMyAccountRequest.Size(m)
+}
+func (m *DeleteMyAccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteMyAccountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteMyAccountRequest proto.InternalMessageInfo
+
+func (m *DeleteMyAccountRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+// Consent is a user consent for a single purpose
+type Consent struct {
+	Purpose              ConsentPurpose `protobuf:"varint,1,opt,name=purpose,proto3,enum=covitrace.ConsentPurpose" json:"purpose,omitempty"`
+	Granted              bool           `protobuf:"varint,2,opt,name=granted,proto3" json:"granted,omitempty"`
+	PrivacyNoticeVersion string         `protobuf:"bytes,3,opt,name=privacy_notice_version,json=privacyNoticeVersion,proto3" json:"privacy_notice_version,omitempty"`
+	GrantedTimestamp     int64          `protobuf:"varint,4,opt,name=granted_timestamp,json=grantedTimestamp,proto3" json:"granted_timestamp,omitempty"`
+	WithdrawnTimestamp   int64          `protobuf:"varint,5,opt,name=withdrawn_timestamp,json=withdrawnTimestamp,proto3" json:"withdrawn_timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Consent) Reset()         { *m = Consent{} }
+func (m *Consent) String() string { return proto.CompactTextString(m) }
+func (*Consent) ProtoMessage()    {}
+func (*Consent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{22}
+}
+
+func (m *Consent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Consent.Unmarshal(m, b)
+}
+func (m *Consent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Consent.Marshal(b, m, deterministic)
+}
+func (m *Consent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Consent.Merge(m, src)
+}
+func (m *Consent) XXX_Size() int {
+	return xxx_messageInfo_Consent.Size(m)
+}
+func (m *Consent) XXX_DiscardUnknown() {
+	xxx_messageInfo_Consent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Consent proto.InternalMessageInfo
+
+func (m *Consent) GetPurpose() ConsentPurpose {
+	if m != nil {
+		return m.Purpose
+	}
+	return ConsentPurpose_ALL_PURPOSES
+}
+
+func (m *Consent) GetGranted() bool {
+	if m != nil {
+		return m.Granted
+	}
+	return false
+}
+
+func (m *Consent) GetPrivacyNoticeVersion() string {
+	if m != nil {
+		return m.PrivacyNoticeVersion
+	}
+	return ""
+}
+
+func (m *Consent) GetGrantedTimestamp() int64 {
+	if m != nil {
+		return m.GrantedTimestamp
+	}
+	return 0
+}
+
+func (m *Consent) GetWithdrawnTimestamp() int64 {
+	if m != nil {
+		return m.WithdrawnTimestamp
+	}
+	return 0
+}
+
+// GrantConsentRequest is request to grant consent for one or more purposes
+type GrantConsentRequest struct {
+	PhoneNumber          string           `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Purposes             []ConsentPurpose `protobuf:"varint,2,rep,packed,name=purposes,proto3,enum=covitrace.ConsentPurpose" json:"purposes,omitempty"`
+	PrivacyNoticeVersion string           `protobuf:"bytes,3,opt,name=privacy_notice_version,json=privacyNoticeVersion,proto3" json:"privacy_notice_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GrantConsentRequest) Reset()         { *m = GrantConsentRequest{} }
+func (m *GrantConsentRequest) String() string { return proto.CompactTextString(m) }
+func (*GrantConsentRequest) ProtoMessage()    {}
+func (*GrantConsentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{23}
+}
+
+func (m *GrantConsentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GrantConsentRequest.Unmarshal(m, b)
+}
+func (m *GrantConsentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GrantConsentRequest.Marshal(b, m, deterministic)
+}
+func (m *GrantConsentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GrantConsentRequest.Merge(m, src)
+}
+func (m *GrantConsentRequest) XXX_Size() int {
+	return xxx_messageInfo_GrantConsentRequest.Size(m)
+}
+func (m *GrantConsentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GrantConsentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GrantConsentRequest proto.InternalMessageInfo
+
+func (m *GrantConsentRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *GrantConsentRequest) GetPurposes() []ConsentPurpose {
+	if m != nil {
+		return m.Purposes
+	}
+	return nil
+}
+
+func (m *GrantConsentRequest) GetPrivacyNoticeVersion() string {
+	if m != nil {
+		return m.PrivacyNoticeVersion
+	}
+	return ""
+}
+
+// WithdrawConsentRequest is request to withdraw consent for one or more purposes
+type WithdrawConsentRequest struct {
+	PhoneNumber          string           `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Purposes             []ConsentPurpose `protobuf:"varint,2,rep,packed,name=purposes,proto3,enum=covitrace.ConsentPurpose" json:"purposes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *WithdrawConsentRequest) Reset()         { *m = WithdrawConsentRequest{} }
+func (m *WithdrawConsentRequest) String() string { return proto.CompactTextString(m) }
+func (*WithdrawConsentRequest) ProtoMessage()    {}
+func (*WithdrawConsentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{24}
+}
+
+func (m *WithdrawConsentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WithdrawConsentRequest.Unmarshal(m, b)
+}
+func (m *WithdrawConsentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WithdrawConsentRequest.Marshal(b, m, deterministic)
+}
+func (m *WithdrawConsentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WithdrawConsentRequest.Merge(m, src)
+}
+func (m *WithdrawConsentRequest) XXX_Size() int {
+	return xxx_messageInfo_WithdrawConsentRequest.Size(m)
+}
+func (m *WithdrawConsentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WithdrawConsentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WithdrawConsentRequest proto.InternalMessageInfo
+
+func (m *WithdrawConsentRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *WithdrawConsentRequest) GetPurposes() []ConsentPurpose {
+	if m != nil {
+		return m.Purposes
+	}
+	return nil
+}
+
+// GetConsentsRequest is request to retrieve user consents
+type GetConsentsRequest struct {
+	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetConsentsRequest) Reset()         { *m = GetConsentsRequest{} }
+func (m *GetConsentsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetConsentsRequest) ProtoMessage()    {}
+func (*GetConsentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{25}
+}
+
+func (m *GetConsentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetConsentsRequest.Unmarshal(m, b)
+}
+func (m *GetConsentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetConsentsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetConsentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetConsentsRequest.Merge(m, src)
+}
+func (m *GetConsentsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetConsentsRequest.Size(m)
+}
+func (m *GetConsentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetConsentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetConsentsRequest proto.InternalMessageInfo
+
+func (m *GetConsentsRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+// Consents is a collection of user consents
+type Consents struct {
+	Consents             []*Consent `protobuf:"bytes,1,rep,name=consents,proto3" json:"consents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Consents) Reset()         { *m = Consents{} }
+func (m *Consents) String() string { return proto.CompactTextString(m) }
+func (*Consents) ProtoMessage()    {}
+func (*Consents) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{26}
+}
+
+func (m *Consents) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Consents.Unmarshal(m, b)
+}
+func (m *Consents) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Consents.Marshal(b, m, deterministic)
+}
+func (m *Consents) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Consents.Merge(m, src)
+}
+func (m *Consents) XXX_Size() int {
+	return xxx_messageInfo_Consents.Size(m)
+}
+func (m *Consents) XXX_DiscardUnknown() {
+	xxx_messageInfo_Consents.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Consents proto.InternalMessageInfo
+
+func (m *Consents) GetConsents() []*Consent {
+	if m != nil {
+		return m.Consents
+	}
+	return nil
+}
+
+// GetUserTrajectoryRequest is request to get the movement history of a user
+type GetUserTrajectoryRequest struct {
+	PhoneNumber             string                 `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	StartTimestamp          int64                  `protobuf:"varint,2,opt,name=start_timestamp,json=startTimestamp,proto3" json:"start_timestamp,omitempty"`
+	EndTimestamp            int64                  `protobuf:"varint,3,opt,name=end_timestamp,json=endTimestamp,proto3" json:"end_timestamp,omitempty"`
+	SimplifyToleranceMeters float32                `protobuf:"fixed32,4,opt,name=simplify_tolerance_meters,json=simplifyToleranceMeters,proto3" json:"simplify_tolerance_meters,omitempty"`
+	DwellMinutes            int32                  `protobuf:"varint,5,opt,name=dwell_minutes,json=dwellMinutes,proto3" json:"dwell_minutes,omitempty"`
+	DwellRadiusMeters       float32                `protobuf:"fixed32,6,opt,name=dwell_radius_meters,json=dwellRadiusMeters,proto3" json:"dwell_radius_meters,omitempty"`
+	ExportFormat            TrajectoryExportFormat `protobuf:"varint,7,opt,name=export_format,json=exportFormat,proto3,enum=covitrace.TrajectoryExportFormat" json:"export_format,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{}               `json:"-"`
+	XXX_unrecognized        []byte                 `json:"-"`
+	XXX_sizecache           int32                  `json:"-"`
+}
+
+func (m *GetUserTrajectoryRequest) Reset()         { *m = GetUserTrajectoryRequest{} }
+func (m *GetUserTrajectoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetUserTrajectoryRequest) ProtoMessage()    {}
+func (*GetUserTrajectoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{27}
+}
+
+func (m *GetUserTrajectoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUserTrajectoryRequest.Unmarshal(m, b)
+}
+func (m *GetUserTrajectoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUserTrajectoryRequest.Marshal(b, m, deterministic)
+}
+func (m *GetUserTrajectoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUserTrajectoryRequest.Merge(m, src)
+}
+func (m *GetUserTrajectoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetUserTrajectoryRequest.Size(m)
+}
+func (m *GetUserTrajectoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUserTrajectoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUserTrajectoryRequest proto.InternalMessageInfo
+
+func (m *GetUserTrajectoryRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *GetUserTrajectoryRequest) GetStartTimestamp() int64 {
+	if m != nil {
+		return m.StartTimestamp
+	}
+	return 0
+}
+
+func (m *GetUserTrajectoryRequest) GetEndTimestamp() int64 {
+	if m != nil {
+		return m.EndTimestamp
+	}
+	return 0
+}
+
+func (m *GetUserTrajectoryRequest) GetSimplifyToleranceMeters() float32 {
+	if m != nil {
+		return m.SimplifyToleranceMeters
+	}
+	return 0
+}
+
+func (m *GetUserTrajectoryRequest) GetDwellMinutes() int32 {
+	if m != nil {
+		return m.DwellMinutes
+	}
+	return 0
+}
+
+func (m *GetUserTrajectoryRequest) GetDwellRadiusMeters() float32 {
+	if m != nil {
+		return m.DwellRadiusMeters
+	}
+	return 0
+}
+
+func (m *GetUserTrajectoryRequest) GetExportFormat() TrajectoryExportFormat {
+	if m != nil {
+		return m.ExportFormat
+	}
+	return TrajectoryExportFormat_NO_EXPORT
+}
+
+// DwellPoint is a place where a user stayed for a while
+type DwellPoint struct {
+	Latitude             float32  `protobuf:"fixed32,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude            float32  `protobuf:"fixed32,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Placemark            string   `protobuf:"bytes,3,opt,name=placemark,proto3" json:"placemark,omitempty"`
+	ArrivalTimestamp     int64    `protobuf:"varint,4,opt,name=arrival_timestamp,json=arrivalTimestamp,proto3" json:"arrival_timestamp,omitempty"`
+	DepartureTimestamp   int64    `protobuf:"varint,5,opt,name=departure_timestamp,json=departureTimestamp,proto3" json:"departure_timestamp,omitempty"`
+	DurationMinutes      int32    `protobuf:"varint,6,opt,name=duration_minutes,json=durationMinutes,proto3" json:"duration_minutes,omitempty"`
+	PointsCount          int32    `protobuf:"varint,7,opt,name=points_count,json=pointsCount,proto3" json:"points_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DwellPoint) Reset()         { *m = DwellPoint{} }
+func (m *DwellPoint) String() string { return proto.CompactTextString(m) }
+func (*DwellPoint) ProtoMessage()    {}
+func (*DwellPoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{28}
+}
+
+func (m *DwellPoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DwellPoint.Unmarshal(m, b)
+}
+func (m *DwellPoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DwellPoint.Marshal(b, m, deterministic)
+}
+func (m *DwellPoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DwellPoint.Merge(m, src)
+}
+func (m *DwellPoint) XXX_Size() int {
+	return xxx_messageInfo_DwellPoint.Size(m)
+}
+func (m *DwellPoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_DwellPoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DwellPoint proto.InternalMessageInfo
+
+func (m *DwellPoint) GetLatitude() float32 {
+	if m != nil {
+		return m.Latitude
+	}
+	return 0
+}
+
+func (m *DwellPoint) GetLongitude() float32 {
+	if m != nil {
+		return m.Longitude
+	}
+	return 0
+}
+
+func (m *DwellPoint) GetPlacemark() string {
+	if m != nil {
+		return m.Placemark
+	}
+	return ""
+}
+
+func (m *DwellPoint) GetArrivalTimestamp() int64 {
+	if m != nil {
+		return m.ArrivalTimestamp
+	}
+	return 0
+}
+
+func (m *DwellPoint) GetDepartureTimestamp() int64 {
+	if m != nil {
+		return m.DepartureTimestamp
+	}
+	return 0
+}
+
+func (m *DwellPoint) GetDurationMinutes() int32 {
+	if m != nil {
+		return m.DurationMinutes
+	}
+	return 0
+}
+
+func (m *DwellPoint) GetPointsCount() int32 {
+	if m != nil {
+		return m.PointsCount
+	}
+	return 0
+}
+
+// Trajectory is the time ordered movement history of a user
+type Trajectory struct {
+	PhoneNumber          string        `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	StartTimestamp       int64         `protobuf:"varint,2,opt,name=start_timestamp,json=startTimestamp,proto3" json:"start_timestamp,omitempty"`
+	EndTimestamp         int64         `protobuf:"varint,3,opt,name=end_timestamp,json=endTimestamp,proto3" json:"end_timestamp,omitempty"`
+	Points               []*Location   `protobuf:"bytes,4,rep,name=points,proto3" json:"points,omitempty"`
+	DwellPoints          []*DwellPoint `protobuf:"bytes,5,rep,name=dwell_points,json=dwellPoints,proto3" json:"dwell_points,omitempty"`
+	OriginalPointsCount  int32         `protobuf:"varint,6,opt,name=original_points_count,json=originalPointsCount,proto3" json:"original_points_count,omitempty"`
+	DistanceMeters       float32       `protobuf:"fixed32,7,opt,name=distance_meters,json=distanceMeters,proto3" json:"distance_meters,omitempty"`
+	FileName             string        `protobuf:"bytes,8,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	ContentType          string        `protobuf:"bytes,9,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Data                 []byte        `protobuf:"bytes,10,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Trajectory) Reset()         { *m = Trajectory{} }
+func (m *Trajectory) String() string { return proto.CompactTextString(m) }
+func (*Trajectory) ProtoMessage()    {}
+func (*Trajectory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{29}
+}
+
+func (m *Trajectory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Trajectory.Unmarshal(m, b)
+}
+func (m *Trajectory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Trajectory.Marshal(b, m, deterministic)
+}
+func (m *Trajectory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Trajectory.Merge(m, src)
+}
+func (m *Trajectory) XXX_Size() int {
+	return xxx_messageInfo_Trajectory.Size(m)
+}
+func (m *Trajectory) XXX_DiscardUnknown() {
+	xxx_messageInfo_Trajectory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Trajectory proto.InternalMessageInfo
+
+func (m *Trajectory) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *Trajectory) GetStartTimestamp() int64 {
+	if m != nil {
+		return m.StartTimestamp
+	}
+	return 0
+}
+
+func (m *Trajectory) GetEndTimestamp() int64 {
+	if m != nil {
+		return m.EndTimestamp
+	}
+	return 0
+}
+
+func (m *Trajectory) GetPoints() []*Location {
+	if m != nil {
+		return m.Points
+	}
+	return nil
+}
+
+func (m *Trajectory) GetDwellPoints() []*DwellPoint {
+	if m != nil {
+		return m.DwellPoints
+	}
+	return nil
+}
+
+func (m *Trajectory) GetOriginalPointsCount() int32 {
+	if m != nil {
+		return m.OriginalPointsCount
+	}
+	return 0
+}
+
+func (m *Trajectory) GetDistanceMeters() float32 {
+	if m != nil {
+		return m.DistanceMeters
+	}
+	return 0
+}
+
+func (m *Trajectory) GetFileName() string {
+	if m != nil {
+		return m.FileName
+	}
+	return ""
+}
+
+func (m *Trajectory) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *Trajectory) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// GeoPoint is a point on the boundary of a geo fence
+type GeoPoint struct {
+	Latitude             float32  `protobuf:"fixed32,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude            float32  `protobuf:"fixed32,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeoPoint) Reset()         { *m = GeoPoint{} }
+func (m *GeoPoint) String() string { return proto.CompactTextString(m) }
+func (*GeoPoint) ProtoMessage()    {}
+func (*GeoPoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{30}
+}
+
+func (m *GeoPoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeoPoint.Unmarshal(m, b)
+}
+func (m *GeoPoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeoPoint.Marshal(b, m, deterministic)
+}
+func (m *GeoPoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeoPoint.Merge(m, src)
+}
+func (m *GeoPoint) XXX_Size() int {
+	return xxx_messageInfo_GeoPoint.Size(m)
+}
+func (m *GeoPoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeoPoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeoPoint proto.InternalMessageInfo
+
+func (m *GeoPoint) GetLatitude() float32 {
+	if m != nil {
+		return m.Latitude
+	}
+	return 0
+}
+
+func (m *GeoPoint) GetLongitude() float32 {
+	if m != nil {
+		return m.Longitude
+	}
+	return 0
+}
+
+// GeoFenceRule sends a message to users in a geo fence
+type GeoFenceRule struct {
+	Trigger GeoFenceTrigger `protobuf:"varint,1,opt,name=trigger,proto3,enum=covitrace.GeoFenceTrigger" json:"trigger,omitempty"`
+	// Minutes a user must stay in the geo fence for ON_DWELL rules
+	DwellMinutes int32 `protobuf:"varint,2,opt,name=dwell_minutes,json=dwellMinutes,proto3" json:"dwell_minutes,omitempty"`
+	// Local time in HH:MM at which the curfew of DURING_CURFEW rules starts and ends, the end may be on the next day
+	CurfewStart string `protobuf:"bytes,3,opt,name=curfew_start,json=curfewStart,proto3" json:"curfew_start,omitempty"`
+	CurfewEnd   string `protobuf:"bytes,4,opt,name=curfew_end,json=curfewEnd,proto3" json:"curfew_end,omitempty"`
+	// Title of the message, defaults to the geo fence name
+	Title string `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	// Go template of the message with fields .Name, .County, .Category, .Placemark, .Time and .DwellMinutes
+	MessageTemplate string `protobuf:"bytes,6,opt,name=message_template,json=messageTemplate,proto3" json:"message_template,omitempty"`
+	// Minimum minutes between messages of the rule to a user, defaults to 60
+	CooldownMinutes      int32    `protobuf:"varint,7,opt,name=cooldown_minutes,json=cooldownMinutes,proto3" json:"cooldown_minutes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeoFenceRule) Reset()         { *m = GeoFenceRule{} }
+func (m *GeoFenceRule) String() string { return proto.CompactTextString(m) }
+func (*GeoFenceRule) ProtoMessage()    {}
+func (*GeoFenceRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{31}
+}
+
+func (m *GeoFenceRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeoFenceRule.Unmarshal(m, b)
+}
+func (m *GeoFenceRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeoFenceRule.Marshal(b, m, deterministic)
+}
+func (m *GeoFenceRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeoFenceRule.Merge(m, src)
+}
+func (m *GeoFenceRule) XXX_Size() int {
+	return xxx_messageInfo_GeoFenceRule.Size(m)
+}
+func (m *GeoFenceRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeoFenceRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeoFenceRule proto.InternalMessageInfo
+
+func (m *GeoFenceRule) GetTrigger() GeoFenceTrigger {
+	if m != nil {
+		return m.Trigger
+	}
+	return GeoFenceTrigger_ON_ENTER
+}
+
+func (m *GeoFenceRule) GetDwellMinutes() int32 {
+	if m != nil {
+		return m.DwellMinutes
+	}
+	return 0
+}
+
+func (m *GeoFenceRule) GetCurfewStart() string {
+	if m != nil {
+		return m.CurfewStart
+	}
+	return ""
+}
+
+func (m *GeoFenceRule) GetCurfewEnd() string {
+	if m != nil {
+		return m.CurfewEnd
+	}
+	return ""
+}
+
+func (m *GeoFenceRule) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *GeoFenceRule) GetMessageTemplate() string {
+	if m != nil {
+		return m.MessageTemplate
+	}
+	return ""
+}
+
+func (m *GeoFenceRule) GetCooldownMinutes() int32 {
+	if m != nil {
+		return m.CooldownMinutes
+	}
+	return 0
+}
+
+// GeoFence is an area such as a market, hospital, lockdown zone or testing site with rules for alerting users in it
+type GeoFence struct {
+	FenceId  string `protobuf:"bytes,1,opt,name=fence_id,json=fenceId,proto3" json:"fence_id,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	County   string `protobuf:"bytes,3,opt,name=county,proto3" json:"county,omitempty"`
+	Category string `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	// Boundary of the geo fence, the last point is joined to the first
+	Polygon []*GeoPoint     `protobuf:"bytes,5,rep,name=polygon,proto3" json:"polygon,omitempty"`
+	Rules   []*GeoFenceRule `protobuf:"bytes,6,rep,name=rules,proto3" json:"rules,omitempty"`
+	// IANA time zone of curfew hours, defaults to UTC
+	TimeZone             string   `protobuf:"bytes,7,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	Active               bool     `protobuf:"varint,8,opt,name=active,proto3" json:"active,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            int64    `protobuf:"varint,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeoFence) Reset()         { *m = GeoFence{} }
+func (m *GeoFence) String() string { return proto.CompactTextString(m) }
+func (*GeoFence) ProtoMessage()    {}
+func (*GeoFence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{32}
+}
+
+func (m *GeoFence) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeoFence.Unmarshal(m, b)
+}
+func (m *GeoFence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeoFence.Marshal(b, m, deterministic)
+}
+func (m *GeoFence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeoFence.Merge(m, src)
+}
+func (m *GeoFence) XXX_Size() int {
+	return xxx_messageInfo_GeoFence.Size(m)
+}
+func (m *GeoFence) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeoFence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeoFence proto.InternalMessageInfo
+
+func (m *GeoFence) GetFenceId() string {
+	if m != nil {
+		return m.FenceId
+	}
+	return ""
+}
+
+func (m *GeoFence) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GeoFence) GetCounty() string {
+	if m != nil {
+		return m.County
+	}
+	return ""
+}
+
+func (m *GeoFence) GetCategory() string {
+	if m != nil {
+		return m.Category
+	}
+	return ""
+}
+
+func (m *GeoFence) GetPolygon() []*GeoPoint {
+	if m != nil {
+		return m.Polygon
+	}
+	return nil
+}
+
+func (m *GeoFence) GetRules() []*GeoFenceRule {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+func (m *GeoFence) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
+func (m *GeoFence) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *GeoFence) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *GeoFence) GetUpdatedAt() int64 {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return 0
+}
+
+// CreateGeoFenceRequest is request to create a geo fence
+type CreateGeoFenceRequest struct {
+	GeoFence             *GeoFence `protobuf:"bytes,1,opt,name=geo_fence,json=geoFence,proto3" json:"geo_fence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CreateGeoFenceRequest) Reset()         { *m = CreateGeoFenceRequest{} }
+func (m *CreateGeoFenceRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateGeoFenceRequest) ProtoMessage()    {}
+func (*CreateGeoFenceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{33}
+}
+
+func (m *CreateGeoFenceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateGeoFenceRequest.Unmarshal(m, b)
+}
+func (m *CreateGeoFenceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateGeoFenceRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateGeoFenceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateGeoFenceRequest.Merge(m, src)
+}
+func (m *CreateGeoFenceRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateGeoFenceRequest.Size(m)
+}
+func (m *CreateGeoFenceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateGeoFenceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateGeoFenceRequest proto.InternalMessageInfo
+
+func (m *CreateGeoFenceRequest) GetGeoFence() *GeoFence {
+	if m != nil {
+		return m.GeoFence
+	}
+	return nil
+}
+
+// UpdateGeoFenceRequest is request to replace a geo fence
+type UpdateGeoFenceRequest struct {
+	GeoFence             *GeoFence `protobuf:"bytes,1,opt,name=geo_fence,json=geoFence,proto3" json:"geo_fence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *UpdateGeoFenceRequest) Reset()         { *m = UpdateGeoFenceRequest{} }
+func (m *UpdateGeoFenceRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateGeoFenceRequest) ProtoMessage()    {}
+func (*UpdateGeoFenceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{34}
+}
+
+func (m *UpdateGeoFenceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateGeoFenceRequest.Unmarshal(m, b)
+}
+func (m *UpdateGeoFenceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateGeoFenceRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateGeoFenceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateGeoFenceRequest.Merge(m, src)
+}
+func (m *UpdateGeoFenceRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateGeoFenceRequest.Size(m)
+}
+func (m *UpdateGeoFenceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateGeoFenceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateGeoFenceRequest proto.InternalMessageInfo
+
+func (m *UpdateGeoFenceRequest) GetGeoFence() *GeoFence {
+	if m != nil {
+		return m.GeoFence
+	}
+	return nil
+}
+
+// DeleteGeoFenceRequest is request to delete a geo fence
+type DeleteGeoFenceRequest struct {
+	FenceId              string   `protobuf:"bytes,1,opt,name=fence_id,json=fenceId,proto3" json:"fence_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteGeoFenceRequest) Reset()         { *m = DeleteGeoFenceRequest{} }
+func (m *DeleteGeoFenceRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteGeoFenceRequest) ProtoMessage()    {}
+func (*DeleteGeoFenceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{35}
+}
+
+func (m *DeleteGeoFenceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteGeoFenceRequest.Unmarshal(m, b)
+}
+func (m *DeleteGeoFenceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteGeoFenceRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteGeoFenceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteGeoFenceRequest.Merge(m, src)
+}
+func (m *DeleteGeoFenceRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteGeoFenceRequest.Size(m)
+}
+func (m *DeleteGeoFenceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteGeoFenceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteGeoFenceRequest proto.InternalMessageInfo
+
+func (m *DeleteGeoFenceRequest) GetFenceId() string {
+	if m != nil {
+		return m.FenceId
+	}
+	return ""
+}
+
+// GetGeoFenceRequest is request to get a geo fence
+type GetGeoFenceRequest struct {
+	FenceId              string   `protobuf:"bytes,1,opt,name=fence_id,json=fenceId,proto3" json:"fence_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetGeoFenceRequest) Reset()         { *m = GetGeoFenceRequest{} }
+func (m *GetGeoFenceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetGeoFenceRequest) ProtoMessage()    {}
+func (*GetGeoFenceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{36}
+}
+
+func (m *GetGeoFenceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetGeoFenceRequest.Unmarshal(m, b)
+}
+func (m *GetGeoFenceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetGeoFenceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetGeoFenceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetGeoFenceRequest.Merge(m, src)
+}
+func (m *GetGeoFenceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetGeoFenceRequest.Size(m)
+}
+func (m *GetGeoFenceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetGeoFenceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetGeoFenceRequest proto.InternalMessageInfo
+
+func (m *GetGeoFenceRequest) GetFenceId() string {
+	if m != nil {
+		return m.FenceId
+	}
+	return ""
+}
+
+// ListGeoFencesRequest is request to list geo fences
+type ListGeoFencesRequest struct {
+	PageSize             int32    `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            int32    `protobuf:"varint,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	FilterCounty         string   `protobuf:"bytes,3,opt,name=filter_county,json=filterCounty,proto3" json:"filter_county,omitempty"`
+	FilterCategory       string   `protobuf:"bytes,4,opt,name=filter_category,json=filterCategory,proto3" json:"filter_category,omitempty"`
+	ActiveOnly           bool     `protobuf:"varint,5,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGeoFencesRequest) Reset()         { *m = ListGeoFencesRequest{} }
+func (m *ListGeoFencesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListGeoFencesRequest) ProtoMessage()    {}
+func (*ListGeoFencesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{37}
+}
+
+func (m *ListGeoFencesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGeoFencesRequest.Unmarshal(m, b)
+}
+func (m *ListGeoFencesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGeoFencesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListGeoFencesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGeoFencesRequest.Merge(m, src)
+}
+func (m *ListGeoFencesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListGeoFencesRequest.Size(m)
+}
+func (m *ListGeoFencesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGeoFencesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGeoFencesRequest proto.InternalMessageInfo
+
+func (m *ListGeoFencesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListGeoFencesRequest) GetPageToken() int32 {
+	if m != nil {
+		return m.PageToken
+	}
+	return 0
+}
+
+func (m *ListGeoFencesRequest) GetFilterCounty() string {
+	if m != nil {
+		return m.FilterCounty
+	}
+	return ""
+}
+
+func (m *ListGeoFencesRequest) GetFilterCategory() string {
+	if m != nil {
+		return m.FilterCategory
+	}
+	return ""
+}
+
+func (m *ListGeoFencesRequest) GetActiveOnly() bool {
+	if m != nil {
+		return m.ActiveOnly
+	}
+	return false
+}
+
+// GeoFences is a collection of geo fences
+type GeoFences struct {
+	GeoFences            []*GeoFence `protobuf:"bytes,1,rep,name=geo_fences,json=geoFences,proto3" json:"geo_fences,omitempty"`
+	NextPageToken        int32       `protobuf:"varint,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GeoFences) Reset()         { *m = GeoFences{} }
+func (m *GeoFences) String() string { return proto.CompactTextString(m) }
+func (*GeoFences) ProtoMessage()    {}
+func (*GeoFences) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{38}
+}
+
+func (m *GeoFences) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeoFences.Unmarshal(m, b)
+}
+func (m *GeoFences) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeoFences.Marshal(b, m, deterministic)
+}
+func (m *GeoFences) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeoFences.Merge(m, src)
+}
+func (m *GeoFences) XXX_Size() int {
+	return xxx_messageInfo_GeoFences.Size(m)
+}
+func (m *GeoFences) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeoFences.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeoFences proto.InternalMessageInfo
+
+func (m *GeoFences) GetGeoFences() []*GeoFence {
+	if m != nil {
+		return m.GeoFences
+	}
+	return nil
+}
+
+func (m *GeoFences) GetNextPageToken() int32 {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return 0
+}
+
+// ListGeoFenceVisitorsRequest is request to list users seen in geo fences
+type ListGeoFenceVisitorsRequest struct {
+	GeoFenceIds          []string `protobuf:"bytes,1,rep,name=geo_fence_ids,json=geoFenceIds,proto3" json:"geo_fence_ids,omitempty"`
+	SeenSinceTimestamp   int64    `protobuf:"varint,2,opt,name=seen_since_timestamp,json=seenSinceTimestamp,proto3" json:"seen_since_timestamp,omitempty"`
+	PageSize             int32    `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            string   `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGeoFenceVisitorsRequest) Reset()         { *m = ListGeoFenceVisitorsRequest{} }
+func (m *ListGeoFenceVisitorsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListGeoFenceVisitorsRequest) ProtoMessage()    {}
+func (*ListGeoFenceVisitorsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{39}
+}
+
+func (m *ListGeoFenceVisitorsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGeoFenceVisitorsRequest.Unmarshal(m, b)
+}
+func (m *ListGeoFenceVisitorsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGeoFenceVisitorsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListGeoFenceVisitorsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGeoFenceVisitorsRequest.Merge(m, src)
+}
+func (m *ListGeoFenceVisitorsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListGeoFenceVisitorsRequest.Size(m)
+}
+func (m *ListGeoFenceVisitorsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGeoFenceVisitorsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGeoFenceVisitorsRequest proto.InternalMessageInfo
+
+func (m *ListGeoFenceVisitorsRequest) GetGeoFenceIds() []string {
+	if m != nil {
+		return m.GeoFenceIds
+	}
+	return nil
+}
+
+func (m *ListGeoFenceVisitorsRequest) GetSeenSinceTimestamp() int64 {
+	if m != nil {
+		return m.SeenSinceTimestamp
+	}
+	return 0
+}
+
+func (m *ListGeoFenceVisitorsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListGeoFenceVisitorsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// GeoFenceVisitors contains phone numbers of users seen in geo fences.
+// A user may be listed in more than one page
+type GeoFenceVisitors struct {
+	PhoneNumbers         []string `protobuf:"bytes,1,rep,name=phone_numbers,json=phoneNumbers,proto3" json:"phone_numbers,omitempty"`
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeoFenceVisitors) Reset()         { *m = GeoFenceVisitors{} }
+func (m *GeoFenceVisitors) String() string { return proto.CompactTextString(m) }
+func (*GeoFenceVisitors) ProtoMessage()    {}
+func (*GeoFenceVisitors) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{40}
+}
+
+func (m *GeoFenceVisitors) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeoFenceVisitors.Unmarshal(m, b)
+}
+func (m *GeoFenceVisitors) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeoFenceVisitors.Marshal(b, m, deterministic)
+}
+func (m *GeoFenceVisitors) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeoFenceVisitors.Merge(m, src)
+}
+func (m *GeoFenceVisitors) XXX_Size() int {
+	return xxx_messageInfo_GeoFenceVisitors.Size(m)
+}
+func (m *GeoFenceVisitors) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeoFenceVisitors.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeoFenceVisitors proto.InternalMessageInfo
+
+func (m *GeoFenceVisitors) GetPhoneNumbers() []string {
+	if m != nil {
+		return m.PhoneNumbers
+	}
+	return nil
+}
+
+func (m *GeoFenceVisitors) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// RestrictionZone is an area named in a movement restriction
+type RestrictionZone struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Boundary of the zone, the last point is joined to the first
+	Polygon              []*GeoPoint `protobuf:"bytes,2,rep,name=polygon,proto3" json:"polygon,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *RestrictionZone) Reset()         { *m = RestrictionZone{} }
+func (m *RestrictionZone) String() string { return proto.CompactTextString(m) }
+func (*RestrictionZone) ProtoMessage()    {}
+func (*RestrictionZone) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{41}
+}
+
+func (m *RestrictionZone) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestrictionZone.Unmarshal(m, b)
+}
+func (m *RestrictionZone) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestrictionZone.Marshal(b, m, deterministic)
+}
+func (m *RestrictionZone) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestrictionZone.Merge(m, src)
+}
+func (m *RestrictionZone) XXX_Size() int {
+	return xxx_messageInfo_RestrictionZone.Size(m)
+}
+func (m *RestrictionZone) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestrictionZone.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestrictionZone proto.InternalMessageInfo
+
+func (m *RestrictionZone) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RestrictionZone) GetPolygon() []*GeoPoint {
+	if m != nil {
+		return m.Polygon
+	}
+	return nil
+}
+
+// Restriction is a curfew or a restriction on movement between zones such as counties
+type Restriction struct {
+	RestrictionId string          `protobuf:"bytes,1,opt,name=restriction_id,json=restrictionId,proto3" json:"restriction_id,omitempty"`
+	Name          string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type          RestrictionType `protobuf:"varint,3,opt,name=type,proto3,enum=covitrace.RestrictionType" json:"type,omitempty"`
+	// Local time in HH:MM at which a CURFEW starts and ends, the end may be on the next day
+	CurfewStart string `protobuf:"bytes,4,opt,name=curfew_start,json=curfewStart,proto3" json:"curfew_start,omitempty"`
+	CurfewEnd   string `protobuf:"bytes,5,opt,name=curfew_end,json=curfewEnd,proto3" json:"curfew_end,omitempty"`
+	// IANA time zone of curfew hours and report days, defaults to UTC
+	TimeZone string `protobuf:"bytes,6,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Zones users may move in during a CURFEW, or zones users may not move between for MOVEMENT restrictions
+	Zones []*RestrictionZone `protobuf:"bytes,7,rep,name=zones,proto3" json:"zones,omitempty"`
+	// User groups that are exempted from the restriction
+	ExemptGroups []string `protobuf:"bytes,8,rep,name=exempt_groups,json=exemptGroups,proto3" json:"exempt_groups,omitempty"`
+	// Dates in YYYY-MM-DD on which the restriction starts and ends, an empty end date has no end
+	StartDate            string   `protobuf:"bytes,9,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate              string   `protobuf:"bytes,10,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Active               bool     `protobuf:"varint,11,opt,name=active,proto3" json:"active,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            int64    `protobuf:"varint,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Restriction) Reset()         { *m = Restriction{} }
+func (m *Restriction) String() string { return proto.CompactTextString(m) }
+func (*Restriction) ProtoMessage()    {}
+func (*Restriction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{42}
+}
+
+func (m *Restriction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Restriction.Unmarshal(m, b)
+}
+func (m *Restriction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Restriction.Marshal(b, m, deterministic)
+}
+func (m *Restriction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Restriction.Merge(m, src)
+}
+func (m *Restriction) XXX_Size() int {
+	return xxx_messageInfo_Restriction.Size(m)
+}
+func (m *Restriction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Restriction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Restriction proto.InternalMessageInfo
+
+func (m *Restriction) GetRestrictionId() string {
+	if m != nil {
+		return m.RestrictionId
+	}
+	return ""
+}
+
+func (m *Restriction) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Restriction) GetType() RestrictionType {
+	if m != nil {
+		return m.Type
+	}
+	return RestrictionType_CURFEW
+}
+
+func (m *Restriction) GetCurfewStart() string {
+	if m != nil {
+		return m.CurfewStart
+	}
+	return ""
+}
+
+func (m *Restriction) GetCurfewEnd() string {
+	if m != nil {
+		return m.CurfewEnd
+	}
+	return ""
+}
+
+func (m *Restriction) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
+func (m *Restriction) GetZones() []*RestrictionZone {
+	if m != nil {
+		return m.Zones
+	}
+	return nil
+}
+
+func (m *Restriction) GetExemptGroups() []string {
+	if m != nil {
+		return m.ExemptGroups
+	}
+	return nil
+}
+
+func (m *Restriction) GetStartDate() string {
+	if m != nil {
+		return m.StartDate
+	}
+	return ""
+}
+
+func (m *Restriction) GetEndDate() string {
+	if m != nil {
+		return m.EndDate
+	}
+	return ""
+}
+
+func (m *Restriction) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *Restriction) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Restriction) GetUpdatedAt() int64 {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return 0
+}
+
+// CreateRestrictionRequest is request to create a restriction
+type CreateRestrictionRequest struct {
+	Restriction          *Restriction `protobuf:"bytes,1,opt,name=restriction,proto3" json:"restriction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *CreateRestrictionRequest) Reset()         { *m = CreateRestrictionRequest{} }
+func (m *CreateRestrictionRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateRestrictionRequest) ProtoMessage()    {}
+func (*CreateRestrictionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{43}
+}
+
+func (m *CreateRestrictionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateRestrictionRequest.Unmarshal(m, b)
+}
+func (m *CreateRestrictionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateRestrictionRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateRestrictionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateRestrictionRequest.Merge(m, src)
+}
+func (m *CreateRestrictionRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateRestrictionRequest.Size(m)
+}
+func (m *CreateRestrictionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateRestrictionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateRestrictionRequest proto.InternalMessageInfo
+
+func (m *CreateRestrictionRequest) GetRestriction() *Restriction {
+	if m != nil {
+		return m.Restriction
+	}
+	return nil
+}
+
+// UpdateRestrictionRequest is request to replace a restriction
+type UpdateRestrictionRequest struct {
+	Restriction          *Restriction `protobuf:"bytes,1,opt,name=restriction,proto3" json:"restriction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *UpdateRestrictionRequest) Reset()         { *m = UpdateRestrictionRequest{} }
+func (m *UpdateRestrictionRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateRestrictionRequest) ProtoMessage()    {}
+func (*UpdateRestrictionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{44}
+}
+
+func (m *UpdateRestrictionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateRestrictionRequest.Unmarshal(m, b)
+}
+func (m *UpdateRestrictionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateRestrictionRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateRestrictionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateRestrictionRequest.Merge(m, src)
+}
+func (m *UpdateRestrictionRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateRestrictionRequest.Size(m)
+}
+func (m *UpdateRestrictionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateRestrictionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateRestrictionRequest proto.InternalMessageInfo
+
+func (m *UpdateRestrictionRequest) GetRestriction() *Restriction {
+	if m != nil {
+		return m.Restriction
+	}
+	return nil
+}
+
+// DeleteRestrictionRequest is request to delete a restriction
+type DeleteRestrictionRequest struct {
+	RestrictionId        string   `protobuf:"bytes,1,opt,name=restriction_id,json=restrictionId,proto3" json:"restriction_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteRestrictionRequest) Reset()         { *m = DeleteRestrictionRequest{} }
+func (m *DeleteRestrictionRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteRestrictionRequest) ProtoMessage()    {}
+func (*DeleteRestrictionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{45}
+}
+
+func (m *DeleteRestrictionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRestrictionRequest.Unmarshal(m, b)
+}
+func (m *DeleteRestrictionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRestrictionRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteRestrictionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRestrictionRequest.Merge(m, src)
+}
+func (m *DeleteRestrictionRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteRestrictionRequest.Size(m)
+}
+func (m *DeleteRestrictionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRestrictionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteRestrictionRequest proto.InternalMessageInfo
+
+func (m *DeleteRestrictionRequest) GetRestrictionId() string {
+	if m != nil {
+		return m.RestrictionId
+	}
+	return ""
+}
+
+// GetRestrictionRequest is request to get a restriction
+type GetRestrictionRequest struct {
+	RestrictionId        string   `protobuf:"bytes,1,opt,name=restriction_id,json=restrictionId,proto3" json:"restriction_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRestrictionRequest) Reset()         { *m = GetRestrictionRequest{} }
+func (m *GetRestrictionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRestrictionRequest) ProtoMessage()    {}
+func (*GetRestrictionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{46}
+}
+
+func (m *GetRestrictionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRestrictionRequest.Unmarshal(m, b)
+}
+func (m *GetRestrictionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRestrictionRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRestrictionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRestrictionRequest.Merge(m, src)
+}
+func (m *GetRestrictionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRestrictionRequest.Size(m)
+}
+func (m *GetRestrictionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRestrictionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRestrictionRequest proto.InternalMessageInfo
+
+func (m *GetRestrictionRequest) GetRestrictionId() string {
+	if m != nil {
+		return m.RestrictionId
+	}
+	return ""
+}
+
+// ListRestrictionsRequest is request to list restrictions
+type ListRestrictionsRequest struct {
+	PageSize             int32    `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            int32    `protobuf:"varint,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	ActiveOnly           bool     `protobuf:"varint,3,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRestrictionsRequest) Reset()         { *m = ListRestrictionsRequest{} }
+func (m *ListRestrictionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRestrictionsRequest) ProtoMessage()    {}
+func (*ListRestrictionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{47}
+}
+
+func (m *ListRestrictionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRestrictionsRequest.Unmarshal(m, b)
+}
+func (m *ListRestrictionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRestrictionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRestrictionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRestrictionsRequest.Merge(m, src)
+}
+func (m *ListRestrictionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRestrictionsRequest.Size(m)
+}
+func (m *ListRestrictionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRestrictionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRestrictionsRequest proto.InternalMessageInfo
+
+func (m *ListRestrictionsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListRestrictionsRequest) GetPageToken() int32 {
+	if m != nil {
+		return m.PageToken
+	}
+	return 0
+}
+
+func (m *ListRestrictionsRequest) GetActiveOnly() bool {
+	if m != nil {
+		return m.ActiveOnly
+	}
+	return false
+}
+
+// Restrictions is a collection of restrictions
+type Restrictions struct {
+	Restrictions         []*Restriction `protobuf:"bytes,1,rep,name=restrictions,proto3" json:"restrictions,omitempty"`
+	NextPageToken        int32          `protobuf:"varint,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Restrictions) Reset()         { *m = Restrictions{} }
+func (m *Restrictions) String() string { return proto.CompactTextString(m) }
+func (*Restrictions) ProtoMessage()    {}
+func (*Restrictions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{48}
+}
+
+func (m *Restrictions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Restrictions.Unmarshal(m, b)
+}
+func (m *Restrictions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Restrictions.Marshal(b, m, deterministic)
+}
+func (m *Restrictions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Restrictions.Merge(m, src)
+}
+func (m *Restrictions) XXX_Size() int {
+	return xxx_messageInfo_Restrictions.Size(m)
+}
+func (m *Restrictions) XXX_DiscardUnknown() {
+	xxx_messageInfo_Restrictions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Restrictions proto.InternalMessageInfo
+
+func (m *Restrictions) GetRestrictions() []*Restriction {
+	if m != nil {
+		return m.Restrictions
+	}
+	return nil
+}
+
+func (m *Restrictions) GetNextPageToken() int32 {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return 0
+}
+
+// GetComplianceReportRequest is request to get compliance with a restriction over a range of days
+type GetComplianceReportRequest struct {
+	RestrictionId string `protobuf:"bytes,1,opt,name=restriction_id,json=restrictionId,proto3" json:"restriction_id,omitempty"`
+	// Dates in YYYY-MM-DD of the first and last days of the report, at most 31 days apart
+	StartDate            string   `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate              string   `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetComplianceReportRequest) Reset()         { *m = GetComplianceReportRequest{} }
+func (m *GetComplianceReportRequest) String() string { return proto.CompactTextString(m) }
+func (*GetComplianceReportRequest) ProtoMessage()    {}
+func (*GetComplianceReportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{49}
+}
+
+func (m *GetComplianceReportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetComplianceReportRequest.Unmarshal(m, b)
+}
+func (m *GetComplianceReportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetComplianceReportRequest.Marshal(b, m, deterministic)
+}
+func (m *GetComplianceReportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetComplianceReportRequest.Merge(m, src)
+}
+func (m *GetComplianceReportRequest) XXX_Size() int {
+	return xxx_messageInfo_GetComplianceReportRequest.Size(m)
+}
+func (m *GetComplianceReportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetComplianceReportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetComplianceReportRequest proto.InternalMessageInfo
+
+func (m *GetComplianceReportRequest) GetRestrictionId() string {
+	if m != nil {
+		return m.RestrictionId
+	}
+	return ""
+}
+
+func (m *GetComplianceReportRequest) GetStartDate() string {
+	if m != nil {
+		return m.StartDate
+	}
+	return ""
+}
+
+func (m *GetComplianceReportRequest) GetEndDate() string {
+	if m != nil {
+		return m.EndDate
+	}
+	return ""
+}
+
+// DailyCompliance is compliance with a restriction on a day
+type DailyCompliance struct {
+	// Date in YYYY-MM-DD
+	Date string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// Users with locations in the restriction on the day, exempted users are left out
+	ObservedUsers int64 `protobuf:"varint,2,opt,name=observed_users,json=observedUsers,proto3" json:"observed_users,omitempty"`
+	// Users that moved during a CURFEW or moved between zones of a MOVEMENT restriction
+	NonCompliantUsers int64 `protobuf:"varint,3,opt,name=non_compliant_users,json=nonCompliantUsers,proto3" json:"non_compliant_users,omitempty"`
+	// Fraction of observed users that did not comply
+	NonComplianceRate float32 `protobuf:"fixed32,4,opt,name=non_compliance_rate,json=nonComplianceRate,proto3" json:"non_compliance_rate,omitempty"`
+	// Number of moves between zones of a MOVEMENT restriction
+	Crossings int64 `protobuf:"varint,5,opt,name=crossings,proto3" json:"crossings,omitempty"`
+	// Whether the counts were withheld because too few users were observed to keep them anonymous
+	Suppressed           bool     `protobuf:"varint,6,opt,name=suppressed,proto3" json:"suppressed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DailyCompliance) Reset()         { *m = DailyCompliance{} }
+func (m *DailyCompliance) String() string { return proto.CompactTextString(m) }
+func (*DailyCompliance) ProtoMessage()    {}
+func (*DailyCompliance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{50}
+}
+
+func (m *DailyCompliance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DailyCompliance.Unmarshal(m, b)
+}
+func (m *DailyCompliance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DailyCompliance.Marshal(b, m, deterministic)
+}
+func (m *DailyCompliance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DailyCompliance.Merge(m, src)
+}
+func (m *DailyCompliance) XXX_Size() int {
+	return xxx_messageInfo_DailyCompliance.Size(m)
+}
+func (m *DailyCompliance) XXX_DiscardUnknown() {
+	xxx_messageInfo_DailyCompliance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DailyCompliance proto.InternalMessageInfo
+
+func (m *DailyCompliance) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+func (m *DailyCompliance) GetObservedUsers() int64 {
+	if m != nil {
+		return m.ObservedUsers
+	}
+	return 0
+}
+
+func (m *DailyCompliance) GetNonCompliantUsers() int64 {
+	if m != nil {
+		return m.NonCompliantUsers
+	}
+	return 0
+}
+
+func (m *DailyCompliance) GetNonComplianceRate() float32 {
+	if m != nil {
+		return m.NonComplianceRate
+	}
+	return 0
+}
+
+func (m *DailyCompliance) GetCrossings() int64 {
+	if m != nil {
+		return m.Crossings
+	}
+	return 0
+}
+
+func (m *DailyCompliance) GetSuppressed() bool {
+	if m != nil {
+		return m.Suppressed
+	}
+	return false
+}
+
+// ComplianceReport is aggregated compliance with a restriction, it contains no data of individual users
+type ComplianceReport struct {
+	RestrictionId string             `protobuf:"bytes,1,opt,name=restriction_id,json=restrictionId,proto3" json:"restriction_id,omitempty"`
+	Type          RestrictionType    `protobuf:"varint,2,opt,name=type,proto3,enum=covitrace.RestrictionType" json:"type,omitempty"`
+	Days          []*DailyCompliance `protobuf:"bytes,3,rep,name=days,proto3" json:"days,omitempty"`
+	// Minimum number of observed users for the counts of a day to be reported
+	MinGroupSize         int32    `protobuf:"varint,4,opt,name=min_group_size,json=minGroupSize,proto3" json:"min_group_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ComplianceReport) Reset()         { *m = ComplianceReport{} }
+func (m *ComplianceReport) String() string { return proto.CompactTextString(m) }
+func (*ComplianceReport) ProtoMessage()    {}
+func (*ComplianceReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f0f35158dcf9f2c, []int{51}
+}
+
+func (m *ComplianceReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ComplianceReport.Unmarshal(m, b)
+}
+func (m *ComplianceReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ComplianceReport.Marshal(b, m, deterministic)
+}
+func (m *ComplianceReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ComplianceReport.Merge(m, src)
+}
+func (m *ComplianceReport) XXX_Size() int {
+	return xxx_messageInfo_ComplianceReport.Size(m)
+}
+func (m *ComplianceReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_ComplianceReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ComplianceReport proto.InternalMessageInfo
+
+func (m *ComplianceReport) GetRestrictionId() string {
+	if m != nil {
+		return m.RestrictionId
+	}
+	return ""
+}
+
+func (m *ComplianceReport) GetType() RestrictionType {
+	if m != nil {
+		return m.Type
+	}
+	return RestrictionType_CURFEW
+}
+
+func (m *ComplianceReport) GetDays() []*DailyCompliance {
+	if m != nil {
+		return m.Days
+	}
+	return nil
+}
+
+func (m *ComplianceReport) GetMinGroupSize() int32 {
+	if m != nil {
+		return m.MinGroupSize
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("covitrace.Status", Status_name, Status_value)
+	proto.RegisterEnum("covitrace.NotificationChannel", NotificationChannel_name, NotificationChannel_value)
+	proto.RegisterEnum("covitrace.DevicePlatform", DevicePlatform_name, DevicePlatform_value)
+	proto.RegisterEnum("covitrace.ExportFormat", ExportFormat_name, ExportFormat_value)
+	proto.RegisterEnum("covitrace.ConsentPurpose", ConsentPurpose_name, ConsentPurpose_value)
+	proto.RegisterEnum("covitrace.TrajectoryExportFormat", TrajectoryExportFormat_name, TrajectoryExportFormat_value)
+	proto.RegisterEnum("covitrace.GeoFenceTrigger", GeoFenceTrigger_name, GeoFenceTrigger_value)
+	proto.RegisterEnum("covitrace.RestrictionType", RestrictionType_name, RestrictionType_value)
+	proto.RegisterType((*Location)(nil), "covitrace.Location")
+	proto.RegisterType((*SendLocationRequest)(nil), "covitrace.SendLocationRequest")
+	proto.RegisterType((*SendLocationsRequest)(nil), "covitrace.SendLocationsRequest")
+	proto.RegisterType((*LocationResult)(nil), "covitrace.LocationResult")
+	proto.RegisterType((*StreamLocationsResponse)(nil), "covitrace.StreamLocationsResponse")
+	proto.RegisterType((*SendLocationsResponse)(nil), "covitrace.SendLocationsResponse")
+	proto.RegisterType((*UpdateUserStatusRequest)(nil), "covitrace.UpdateUserStatusRequest")
+	proto.RegisterType((*UpdateUserRequest)(nil), "covitrace.UpdateUserRequest")
+	proto.RegisterType((*AddUserRequest)(nil), "covitrace.AddUserRequest")
+	proto.RegisterType((*User)(nil), "covitrace.User")
+	proto.RegisterType((*Device)(nil), "covitrace.Device")
+	proto.RegisterType((*RegisterDeviceRequest)(nil), "covitrace.RegisterDeviceRequest")
+	proto.RegisterType((*UnregisterDeviceRequest)(nil), "covitrace.UnregisterDeviceRequest")
+	proto.RegisterType((*ListDevicesRequest)(nil), "covitrace.ListDevicesRequest")
+	proto.RegisterType((*Devices)(nil), "covitrace.Devices")
+	proto.RegisterType((*GetUserRequest)(nil), "covitrace.GetUserRequest")
+	proto.RegisterType((*ListUsersRequest)(nil), "covitrace.ListUsersRequest")
+	proto.RegisterType((*SearchUsersRequest)(nil), "covitrace.SearchUsersRequest")
+	proto.RegisterType((*Users)(nil), "covitrace.Users")
+	proto.RegisterType((*ExportMyDataRequest)(nil), "covitrace.ExportMyDataRequest")
+	proto.RegisterType((*ExportMyDataResponse)(nil), "covitrace.ExportMyDataResponse")
+	proto.RegisterType((*DeleteMyAccountRequest)(nil), "covitrace.DeleteMyAccountRequest")
+	proto.RegisterType((*Consent)(nil), "covitrace.Consent")
+	proto.RegisterType((*GrantConsentRequest)(nil), "covitrace.GrantConsentRequest")
+	proto.RegisterType((*WithdrawConsentRequest)(nil), "covitrace.WithdrawConsentRequest")
+	proto.RegisterType((*GetConsentsRequest)(nil), "covitrace.GetConsentsRequest")
+	proto.RegisterType((*Consents)(nil), "covitrace.Consents")
+	proto.RegisterType((*GetUserTrajectoryRequest)(nil), "covitrace.GetUserTrajectoryRequest")
+	proto.RegisterType((*DwellPoint)(nil), "covitrace.DwellPoint")
+	proto.RegisterType((*Trajectory)(nil), "covitrace.Trajectory")
+	proto.RegisterType((*GeoPoint)(nil), "covitrace.GeoPoint")
+	proto.RegisterType((*GeoFenceRule)(nil), "covitrace.GeoFenceRule")
+	proto.RegisterType((*GeoFence)(nil), "covitrace.GeoFence")
+	proto.RegisterType((*CreateGeoFenceRequest)(nil), "covitrace.CreateGeoFenceRequest")
+	proto.RegisterType((*UpdateGeoFenceRequest)(nil), "covitrace.UpdateGeoFenceRequest")
+	proto.RegisterType((*DeleteGeoFenceRequest)(nil), "covitrace.DeleteGeoFenceRequest")
+	proto.RegisterType((*GetGeoFenceRequest)(nil), "covitrace.GetGeoFenceRequest")
+	proto.RegisterType((*ListGeoFencesRequest)(nil), "covitrace.ListGeoFencesRequest")
+	proto.RegisterType((*GeoFences)(nil), "covitrace.GeoFences")
+	proto.RegisterType((*ListGeoFenceVisitorsRequest)(nil), "covitrace.ListGeoFenceVisitorsRequest")
+	proto.RegisterType((*GeoFenceVisitors)(nil), "covitrace.GeoFenceVisitors")
+	proto.RegisterType((*RestrictionZone)(nil), "covitrace.RestrictionZone")
+	proto.RegisterType((*Restriction)(nil), "covitrace.Restriction")
+	proto.RegisterType((*CreateRestrictionRequest)(nil), "covitrace.CreateRestrictionRequest")
+	proto.RegisterType((*UpdateRestrictionRequest)(nil), "covitrace.UpdateRestrictionRequest")
+	proto.RegisterType((*DeleteRestrictionRequest)(nil), "covitrace.DeleteRestrictionRequest")
+	proto.RegisterType((*GetRestrictionRequest)(nil), "covitrace.GetRestrictionRequest")
+	proto.RegisterType((*ListRestrictionsRequest)(nil), "covitrace.ListRestrictionsRequest")
+	proto.RegisterType((*Restrictions)(nil), "covitrace.Restrictions")
+	proto.RegisterType((*GetComplianceReportRequest)(nil), "covitrace.GetComplianceReportRequest")
+	proto.RegisterType((*DailyCompliance)(nil), "covitrace.DailyCompliance")
+	proto.RegisterType((*ComplianceReport)(nil), "covitrace.ComplianceReport")
+}
+
+func init() { proto.RegisterFile("location.proto", fileDescriptor_4f0f35158dcf9f2c) }
+
+var fileDescriptor_4f0f35158dcf9f2c = []byte{
+	// 3755 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5a, 0x5f, 0x73, 0x1b, 0x47,
+	0x72, 0xf7, 0x02, 0x24, 0x01, 0x34, 0x40, 0x10, 0x1c, 0xfe, 0x83, 0x40, 0xd9, 0xa6, 0x56, 0x96,
+	0x2c, 0x53, 0x16, 0x61, 0x51, 0xb6, 0x73, 0xd1, 0x25, 0xa9, 0xf0, 0x48, 0x88, 0x87, 0x84, 0x04,
+	0x59, 0x0b, 0x50, 0x72, 0xec, 0x4a, 0x6d, 0xad, 0x80, 0x21, 0xbc, 0xa7, 0xc5, 0xee, 0x7a, 0x77,
+	0x41, 0x1a, 0x72, 0x9c, 0x3f, 0x57, 0xa9, 0xbc, 0xa4, 0x2a, 0x49, 0x25, 0x95, 0xca, 0x5b, 0x2a,
+	0x2f, 0x79, 0xce, 0x37, 0xb8, 0x54, 0x3e, 0x43, 0x9e, 0xf2, 0x7e, 0x95, 0x87, 0x54, 0x2a, 0x9f,
+	0x21, 0x35, 0x3d, 0x33, 0x8b, 0xfd, 0x07, 0x8a, 0xf4, 0xa9, 0xfc, 0x44, 0x4e, 0x77, 0xcf, 0x74,
+	0x4f, 0x77, 0xcf, 0x6f, 0x76, 0xba, 0x01, 0x55, 0xcb, 0xe9, 0x1b, 0x81, 0xe9, 0xd8, 0x3b, 0xae,
+	0xe7, 0x04, 0x0e, 0x29, 0xf5, 0x9d, 0x0b, 0x33, 0xf0, 0x8c, 0x3e, 0x6d, 0x6c, 0x0e, 0x1d, 0x67,
+	0x68, 0xd1, 0x26, 0x32, 0x5e, 0x8e, 0xcf, 0x9b, 0x74, 0xe4, 0x06, 0x13, 0x2e, 0xd7, 0xb8, 0x2b,
+	0x98, 0x96, 0x63, 0x0f, 0xbd, 0xb1, 0x6d, 0x9b, 0xf6, 0xb0, 0xe9, 0xb8, 0xd4, 0xc3, 0xb5, 0x7c,
+	0x21, 0x74, 0x5b, 0x08, 0x19, 0xae, 0xd9, 0x34, 0x6c, 0xdb, 0x09, 0x62, 0xdc, 0x8f, 0xf1, 0x4f,
+	0xff, 0xd1, 0x90, 0xda, 0x8f, 0xfc, 0x4b, 0x63, 0x38, 0xa4, 0x5e, 0xd3, 0x71, 0x51, 0x22, 0x2d,
+	0xad, 0xfe, 0x32, 0x0f, 0xc5, 0x23, 0x61, 0x2b, 0xb9, 0x0d, 0x25, 0xa6, 0xd8, 0x0c, 0xc6, 0x03,
+	0x5a, 0x57, 0xb6, 0x94, 0x07, 0x39, 0x6d, 0x4a, 0x20, 0x0d, 0x28, 0x5a, 0x46, 0xc0, 0x99, 0x39,
+	0x64, 0x86, 0x63, 0x36, 0x33, 0x30, 0x47, 0xd4, 0x0f, 0x8c, 0x91, 0x5b, 0xcf, 0x6f, 0x29, 0x0f,
+	0xf2, 0xda, 0x94, 0xc0, 0x66, 0x1a, 0xfd, 0xfe, 0xd8, 0x33, 0xfa, 0x93, 0xfa, 0x1c, 0x9f, 0x29,
+	0xc7, 0xc8, 0xb3, 0xc4, 0xaa, 0xf3, 0x82, 0x27, 0xc6, 0x64, 0x15, 0xe6, 0x7d, 0x97, 0xd2, 0x41,
+	0x7d, 0x01, 0x19, 0x7c, 0x40, 0xee, 0x41, 0x15, 0xff, 0xd1, 0xc3, 0x35, 0x0b, 0xc8, 0x5e, 0x44,
+	0xea, 0x9e, 0x5c, 0xf8, 0x36, 0x94, 0x5c, 0xcb, 0xe8, 0xd3, 0x91, 0xe1, 0xbd, 0xaa, 0x17, 0xb7,
+	0x94, 0x07, 0x25, 0x6d, 0x4a, 0x20, 0x5b, 0x50, 0x19, 0x52, 0x47, 0x3f, 0xa7, 0x76, 0x9f, 0xea,
+	0xe6, 0xa0, 0x5e, 0x42, 0x01, 0x18, 0x52, 0xe7, 0x19, 0x23, 0xb5, 0x07, 0x64, 0x03, 0x0a, 0x6c,
+	0x07, 0x8c, 0x59, 0x46, 0xe6, 0x02, 0x1b, 0x72, 0x86, 0xe9, 0xeb, 0x23, 0xa7, 0xff, 0xaa, 0x5e,
+	0xd9, 0x52, 0x1e, 0x14, 0xb5, 0x05, 0xd3, 0x3f, 0x76, 0xfa, 0xaf, 0xc8, 0x26, 0x94, 0x06, 0xf4,
+	0xc2, 0xe4, 0x0b, 0x2e, 0xe2, 0x9c, 0x22, 0x27, 0xb4, 0x07, 0x6c, 0x9f, 0x3e, 0xfd, 0x66, 0xcc,
+	0x16, 0xaf, 0x57, 0xd1, 0x41, 0xe1, 0x58, 0xfd, 0x5b, 0x05, 0x56, 0xba, 0xd4, 0x1e, 0xc8, 0x40,
+	0x68, 0x8c, 0xe1, 0x07, 0x4c, 0xd3, 0xd8, 0xa7, 0x1e, 0x5b, 0x4e, 0xe1, 0x26, 0xb0, 0x61, 0x7b,
+	0x40, 0x76, 0xa0, 0xe4, 0x07, 0x46, 0x30, 0xf6, 0x19, 0x8b, 0xc5, 0xa2, 0xba, 0xbb, 0xbc, 0x13,
+	0xa6, 0xd8, 0x4e, 0x17, 0x79, 0x5a, 0x91, 0xcb, 0xb4, 0x07, 0xa4, 0x09, 0x45, 0x99, 0x90, 0x18,
+	0x9d, 0xf2, 0xee, 0x4a, 0x44, 0x3c, 0x54, 0x1b, 0x0a, 0xa9, 0x7f, 0xaf, 0xc0, 0x6a, 0xd4, 0x22,
+	0xff, 0xad, 0x9b, 0xf4, 0x98, 0xe5, 0x9a, 0x58, 0xbc, 0x9e, 0xdf, 0xca, 0xcf, 0xb2, 0x69, 0x2a,
+	0xa5, 0xfe, 0x85, 0x02, 0xd5, 0x90, 0x4e, 0xfd, 0xb1, 0x15, 0xb0, 0x0c, 0x31, 0xed, 0x01, 0xfd,
+	0x16, 0x8d, 0x99, 0xd7, 0xf8, 0x40, 0xe4, 0x1b, 0x75, 0x03, 0xca, 0x4d, 0x29, 0x6a, 0xe1, 0x98,
+	0x65, 0x8f, 0xe5, 0x5c, 0xea, 0x7d, 0xc7, 0x3e, 0x37, 0x07, 0x18, 0x8d, 0x3c, 0x4a, 0x2c, 0x5a,
+	0xce, 0xe5, 0x7e, 0x48, 0x24, 0xeb, 0xb0, 0xe0, 0x51, 0xc3, 0x77, 0x6c, 0x4c, 0xd8, 0x92, 0x26,
+	0x46, 0xea, 0xff, 0x29, 0xb0, 0xd1, 0x0d, 0x3c, 0x6a, 0x8c, 0x22, 0xae, 0xf1, 0x5d, 0xc7, 0xf6,
+	0x29, 0x79, 0x02, 0x05, 0x0f, 0xcd, 0xf2, 0xeb, 0x0a, 0x6e, 0xe8, 0x56, 0xd6, 0x86, 0x50, 0x42,
+	0x93, 0x92, 0xcc, 0x1e, 0x69, 0x9b, 0xde, 0x77, 0xc6, 0x76, 0x80, 0x16, 0xcf, 0x6b, 0x8b, 0x92,
+	0xba, 0xcf, 0x88, 0x4c, 0xcc, 0xa3, 0xbf, 0xa0, 0xfd, 0xa9, 0x58, 0x9e, 0x8b, 0x49, 0x2a, 0x17,
+	0x8b, 0xa5, 0xe0, 0x5c, 0x22, 0x05, 0x9f, 0xc0, 0x9a, 0xd1, 0x7f, 0x65, 0x3b, 0x97, 0x16, 0x1d,
+	0x0c, 0xe9, 0x40, 0x0f, 0xf3, 0x71, 0x1e, 0xf3, 0x71, 0x35, 0xca, 0xec, 0xca, 0xdc, 0x34, 0x61,
+	0x2d, 0x91, 0x08, 0x62, 0xb7, 0x31, 0x55, 0xca, 0x75, 0x55, 0xe5, 0xae, 0x50, 0x35, 0x84, 0x8d,
+	0x33, 0x77, 0x60, 0x04, 0xf4, 0xcc, 0xa7, 0x9e, 0x48, 0x18, 0x91, 0x76, 0x77, 0xa0, 0xe2, 0x7e,
+	0xed, 0xd8, 0x54, 0xb7, 0xc7, 0xa3, 0x97, 0xd4, 0x13, 0xfa, 0xca, 0x48, 0xeb, 0x20, 0x89, 0x7c,
+	0x04, 0x0b, 0x3c, 0xb9, 0x66, 0x67, 0x9f, 0x10, 0x50, 0xbf, 0x82, 0xe5, 0xa9, 0xa2, 0x1b, 0xa8,
+	0xb8, 0x0b, 0x73, 0x2c, 0xdb, 0x51, 0x41, 0x79, 0x77, 0x29, 0xa2, 0x00, 0x17, 0x42, 0xa6, 0xfa,
+	0x19, 0x54, 0xf7, 0x06, 0x83, 0xe8, 0xca, 0x72, 0x9a, 0x72, 0xd5, 0xb4, 0xff, 0xca, 0xc3, 0x1c,
+	0x1b, 0x5e, 0xc7, 0x8e, 0x4d, 0x28, 0x9d, 0x8f, 0x2d, 0x4b, 0xb7, 0x8d, 0x11, 0xf7, 0x68, 0x49,
+	0x2b, 0x32, 0x42, 0xc7, 0x18, 0x61, 0xe6, 0x62, 0x82, 0x4c, 0x30, 0x43, 0x4a, 0x9a, 0x18, 0x45,
+	0xfc, 0x33, 0xf7, 0x06, 0xff, 0x30, 0x13, 0x44, 0x68, 0x03, 0xe7, 0x15, 0xb5, 0x31, 0x3f, 0x4a,
+	0x5a, 0x99, 0xd3, 0x7a, 0x8c, 0xc4, 0xb4, 0xe0, 0x54, 0x8e, 0xcd, 0x45, 0x4d, 0x8c, 0xc8, 0x43,
+	0x58, 0x1e, 0xa3, 0x6b, 0x07, 0xfa, 0xf4, 0x42, 0x28, 0x60, 0xd0, 0x6b, 0x82, 0xd1, 0x93, 0x74,
+	0x76, 0x7a, 0x87, 0x9e, 0x33, 0x76, 0x05, 0x3c, 0xf3, 0x01, 0x51, 0xa1, 0xd2, 0x77, 0x6c, 0x9f,
+	0xdd, 0x01, 0xd4, 0xee, 0x4f, 0x04, 0x34, 0xc7, 0x68, 0x84, 0xc0, 0xdc, 0xa5, 0xe1, 0x0d, 0xea,
+	0x80, 0x3c, 0xfc, 0x9f, 0xad, 0x46, 0x47, 0x86, 0x69, 0x09, 0xb8, 0xe6, 0x03, 0xd2, 0x85, 0x35,
+	0xdb, 0x09, 0xcc, 0x73, 0x93, 0xe7, 0xaf, 0xde, 0xff, 0xda, 0xb0, 0x6d, 0x6a, 0xf9, 0xf5, 0xca,
+	0x56, 0xfe, 0x41, 0x75, 0xf7, 0xbd, 0x88, 0x17, 0x3a, 0x11, 0xb9, 0x7d, 0x2e, 0xa6, 0xad, 0xda,
+	0x69, 0xa2, 0x4f, 0x1e, 0x01, 0x71, 0x3d, 0x7a, 0x4e, 0x3d, 0x8f, 0x0e, 0x74, 0xcb, 0xb0, 0x87,
+	0x63, 0x63, 0x48, 0x05, 0xe4, 0x2f, 0x87, 0x9c, 0x23, 0xc1, 0x50, 0xff, 0x5b, 0x81, 0x85, 0x03,
+	0x74, 0x5e, 0xca, 0xb5, 0x4a, 0xda, 0xb5, 0x9f, 0x41, 0xd1, 0xb5, 0x8c, 0xe0, 0xdc, 0xf1, 0x46,
+	0x22, 0x95, 0xa3, 0x38, 0xc2, 0xd7, 0x39, 0x15, 0x02, 0x5a, 0x28, 0x4a, 0xde, 0x87, 0xb2, 0xe1,
+	0xba, 0xfa, 0x05, 0xf5, 0x7c, 0x09, 0xf3, 0x25, 0x0d, 0x0c, 0xd7, 0x7d, 0xce, 0x29, 0x64, 0x07,
+	0x56, 0x2c, 0xc3, 0x0f, 0x74, 0x9f, 0x52, 0x3b, 0x12, 0x9c, 0x39, 0x0c, 0xce, 0x32, 0x63, 0x75,
+	0x29, 0xb5, 0xa7, 0xd1, 0x79, 0x0c, 0xab, 0x1e, 0x1d, 0x9a, 0x7e, 0x40, 0xbd, 0x58, 0x34, 0x39,
+	0x5a, 0xac, 0x4c, 0x79, 0xe1, 0x14, 0x95, 0xc2, 0x9a, 0x26, 0xc8, 0xdc, 0xce, 0x9b, 0x9d, 0x5f,
+	0xee, 0x05, 0x71, 0xbc, 0x96, 0x53, 0x9b, 0xd6, 0x84, 0x80, 0xaa, 0xc3, 0xc6, 0x99, 0xed, 0xfd,
+	0x50, 0x45, 0xc9, 0x10, 0xe4, 0x52, 0x21, 0x50, 0x7f, 0x0b, 0xc8, 0x91, 0xe9, 0x07, 0x7c, 0xe9,
+	0x1b, 0x80, 0x90, 0xfa, 0x39, 0x14, 0xc4, 0x24, 0xf2, 0x10, 0x0a, 0x7c, 0x49, 0x79, 0x1b, 0x64,
+	0x6c, 0x48, 0x4a, 0xa8, 0x4f, 0xa0, 0x7a, 0x48, 0x83, 0x9b, 0xc1, 0x91, 0xfa, 0x57, 0x0a, 0xd4,
+	0x98, 0x99, 0x6c, 0x5a, 0x68, 0xe4, 0x26, 0x94, 0x5c, 0x63, 0x48, 0x75, 0xdf, 0x7c, 0x4d, 0xc5,
+	0xad, 0x58, 0x64, 0x84, 0xae, 0xf9, 0x9a, 0x92, 0x77, 0x01, 0x90, 0x39, 0xdd, 0xf8, 0xbc, 0x86,
+	0xe2, 0x3c, 0xf3, 0x3e, 0x87, 0xc5, 0x73, 0xd3, 0x0a, 0xa8, 0xa7, 0x0b, 0xa4, 0xc8, 0xcf, 0x42,
+	0x8a, 0x0a, 0x97, 0xe3, 0x23, 0xf5, 0x9f, 0x15, 0x20, 0x5d, 0x6a, 0x78, 0xfd, 0xaf, 0xdf, 0x9a,
+	0x29, 0xab, 0x30, 0xff, 0xcd, 0x98, 0x7a, 0x12, 0xc4, 0xf8, 0x20, 0x6d, 0xe0, 0xdc, 0xf5, 0x0c,
+	0x7c, 0x0e, 0xf3, 0x68, 0x19, 0xb9, 0x07, 0xf3, 0x0c, 0x6d, 0x65, 0x48, 0x52, 0x58, 0xcc, 0xb9,
+	0xe4, 0x3e, 0x2c, 0xd9, 0xf4, 0xdb, 0x40, 0x4f, 0x59, 0xb8, 0xc8, 0xc8, 0xa7, 0xd2, 0x4a, 0xd5,
+	0x84, 0x95, 0xd6, 0xb7, 0xae, 0xe3, 0x05, 0xc7, 0x93, 0x03, 0x23, 0x30, 0x6e, 0x90, 0x84, 0x4d,
+	0x58, 0x60, 0xa7, 0xd6, 0x08, 0xc4, 0x11, 0xdf, 0x88, 0x58, 0xc2, 0x97, 0x7c, 0x86, 0x6c, 0x4d,
+	0x88, 0xa9, 0xbf, 0x80, 0xd5, 0xb8, 0xaa, 0xe9, 0x35, 0x7c, 0x6e, 0x5a, 0x94, 0xdf, 0x05, 0xe2,
+	0x1a, 0x66, 0x04, 0xbc, 0x0b, 0xee, 0x20, 0x94, 0x06, 0xd4, 0x0e, 0xf4, 0x60, 0xe2, 0xca, 0xbb,
+	0xa2, 0x2c, 0x68, 0xbd, 0x89, 0x4b, 0x19, 0x92, 0x0e, 0x8c, 0xc0, 0x40, 0x3f, 0x57, 0x34, 0xfc,
+	0x5f, 0xfd, 0x29, 0xac, 0x1f, 0x50, 0x8b, 0x06, 0xf4, 0x78, 0xb2, 0xd7, 0xc7, 0xeb, 0xe3, 0x06,
+	0x59, 0xf9, 0xbf, 0x0a, 0x14, 0xf6, 0x99, 0x69, 0x76, 0xc0, 0xbe, 0x88, 0xdc, 0xb1, 0xe7, 0x3a,
+	0x3e, 0x37, 0x2d, 0x8e, 0x64, 0x42, 0xe8, 0x94, 0x0b, 0x68, 0x52, 0x92, 0xd4, 0xa1, 0x30, 0xf4,
+	0x0c, 0x7b, 0xfa, 0xf1, 0x26, 0x87, 0xe4, 0x53, 0x58, 0x77, 0x3d, 0xf3, 0xc2, 0xe8, 0x4f, 0x74,
+	0x06, 0xcb, 0x7d, 0x9a, 0x40, 0xbb, 0x55, 0xc1, 0xed, 0x20, 0x53, 0xe2, 0xde, 0x43, 0x58, 0x16,
+	0x0b, 0xa4, 0x50, 0xaf, 0x26, 0x18, 0x53, 0xd0, 0x6b, 0xc2, 0xca, 0xa5, 0x19, 0x7c, 0x3d, 0xf0,
+	0x8c, 0x4b, 0x3b, 0x85, 0x79, 0x24, 0x64, 0x4d, 0x21, 0xef, 0x5f, 0x15, 0x58, 0x39, 0x64, 0xab,
+	0x88, 0xed, 0xdc, 0x20, 0x07, 0x18, 0xd0, 0xf3, 0x3d, 0xb3, 0x6f, 0x96, 0xfc, 0xd5, 0xee, 0x09,
+	0x45, 0x7f, 0x98, 0x17, 0x54, 0x0f, 0xd6, 0x5f, 0x08, 0xeb, 0x7f, 0x2c, 0x4b, 0x19, 0x8c, 0x1e,
+	0x52, 0xe9, 0x98, 0x9b, 0xc0, 0xe8, 0x53, 0x28, 0xca, 0x59, 0x64, 0x07, 0x8a, 0x7d, 0xf1, 0xbf,
+	0x38, 0xb5, 0x24, 0xad, 0x5b, 0x0b, 0x65, 0xd4, 0x5f, 0xe7, 0xa0, 0x2e, 0xb0, 0xb4, 0xe7, 0x19,
+	0xec, 0xe3, 0xd8, 0xf1, 0x26, 0x37, 0xd8, 0xeb, 0x87, 0xb0, 0xe4, 0x07, 0x86, 0x17, 0x44, 0xa2,
+	0xcf, 0x3f, 0x5a, 0xab, 0x48, 0x9e, 0xa6, 0xca, 0x5d, 0x58, 0xa4, 0x76, 0x34, 0xa7, 0xf8, 0xbb,
+	0xb7, 0x42, 0xed, 0x48, 0x3e, 0x3d, 0x85, 0x5b, 0xbe, 0x39, 0x72, 0x2d, 0xf3, 0x7c, 0xa2, 0x07,
+	0x8e, 0x45, 0x3d, 0x83, 0x3d, 0x38, 0x47, 0x34, 0x60, 0x20, 0xc4, 0xdf, 0xc2, 0x1b, 0x52, 0xa0,
+	0x27, 0xf9, 0xc7, 0xc8, 0x66, 0x0a, 0x06, 0x97, 0xd4, 0xb2, 0xf4, 0x91, 0x69, 0x8f, 0x03, 0xea,
+	0x63, 0x16, 0xce, 0x6b, 0x15, 0x24, 0x1e, 0x73, 0x1a, 0xbb, 0xd5, 0xb9, 0x90, 0x67, 0x0c, 0xcc,
+	0xb1, 0x2f, 0x97, 0xe6, 0x2f, 0xe6, 0x65, 0x64, 0x69, 0xc8, 0x11, 0x8b, 0x3e, 0x83, 0x45, 0x8a,
+	0x38, 0xa2, 0x0b, 0xfc, 0x29, 0xe0, 0xc1, 0xbc, 0x13, 0xf1, 0xe9, 0xd4, 0x6d, 0x31, 0x24, 0xaa,
+	0xd0, 0xc8, 0x48, 0xfd, 0xbb, 0x1c, 0xc0, 0x01, 0x5b, 0xfd, 0xd4, 0x31, 0xed, 0x20, 0x56, 0x1c,
+	0x50, 0xd2, 0xc5, 0x81, 0x69, 0x59, 0x21, 0x97, 0x2c, 0x2b, 0xc4, 0xde, 0xe9, 0xf9, 0xe4, 0x3b,
+	0xfd, 0x21, 0x2c, 0x1b, 0x1e, 0xcb, 0x67, 0x2b, 0x7d, 0x78, 0x05, 0x23, 0x76, 0x78, 0x07, 0xd4,
+	0x35, 0xbc, 0x60, 0xec, 0xd1, 0xf4, 0xe1, 0x0d, 0x59, 0xd3, 0x09, 0x1f, 0x41, 0x6d, 0x30, 0xe6,
+	0xc5, 0x95, 0xd0, 0xc9, 0x0b, 0xe8, 0xe4, 0x25, 0x49, 0x97, 0x7e, 0x66, 0x99, 0xc3, 0x76, 0xea,
+	0x8b, 0xe7, 0x57, 0x01, 0xc5, 0xca, 0x9c, 0x86, 0x8f, 0x2f, 0xf5, 0x1f, 0xf3, 0x00, 0x53, 0xdf,
+	0xfd, 0xf8, 0xb9, 0xf6, 0x10, 0x16, 0xb8, 0x39, 0xf5, 0xb9, 0xd9, 0xef, 0x69, 0x21, 0x42, 0x7e,
+	0x02, 0x3c, 0x8f, 0x74, 0x31, 0x65, 0x1e, 0xa7, 0xac, 0x45, 0xbf, 0x51, 0xc2, 0xe8, 0x6a, 0xe5,
+	0x41, 0xf8, 0xbf, 0x4f, 0x76, 0x61, 0xcd, 0xf1, 0xcc, 0xa1, 0x69, 0x1b, 0x96, 0x1e, 0x73, 0x09,
+	0xf7, 0xdc, 0x8a, 0x64, 0x9e, 0x4e, 0x5d, 0xc3, 0x36, 0x3a, 0x30, 0xfd, 0x20, 0x9a, 0xfc, 0xbc,
+	0x68, 0x53, 0x95, 0x64, 0x91, 0x9e, 0xb1, 0xeb, 0xac, 0xf8, 0x86, 0xeb, 0xac, 0x34, 0xfb, 0x3a,
+	0x83, 0xc8, 0x75, 0x76, 0x00, 0xc5, 0x43, 0xea, 0xfc, 0x86, 0x79, 0xaa, 0xfe, 0x53, 0x0e, 0x2a,
+	0x87, 0xa2, 0x3c, 0xa4, 0x8d, 0x2d, 0x4a, 0x3e, 0x85, 0x42, 0xe0, 0x99, 0xac, 0xbe, 0x26, 0x2e,
+	0xb7, 0x46, 0xc4, 0x79, 0x52, 0xb2, 0xc7, 0x25, 0x34, 0x29, 0x9a, 0x3e, 0xd4, 0xb9, 0x8c, 0x43,
+	0xcd, 0x36, 0x3a, 0xf6, 0xce, 0xe9, 0xa5, 0x8e, 0x79, 0x20, 0x8e, 0x45, 0x99, 0xd3, 0xba, 0x8c,
+	0xc4, 0xbe, 0x9f, 0x84, 0x08, 0xb5, 0xe5, 0x53, 0xbf, 0xc4, 0x29, 0x2d, 0x1b, 0x1f, 0x43, 0x81,
+	0x19, 0x58, 0x54, 0xbc, 0xdd, 0xf8, 0x80, 0xe5, 0xfb, 0x88, 0xfa, 0x3e, 0x7e, 0xd5, 0xd0, 0x11,
+	0x7b, 0x3a, 0x50, 0x8c, 0x5a, 0x49, 0x5b, 0x12, 0xf4, 0x9e, 0x20, 0x33, 0xd1, 0xbe, 0xe3, 0x58,
+	0x03, 0xe7, 0x72, 0x7a, 0x34, 0x78, 0xce, 0x2f, 0x49, 0xba, 0xb0, 0x56, 0xfd, 0x55, 0x0e, 0x8a,
+	0x72, 0xbf, 0xe4, 0x16, 0x14, 0xc3, 0xa2, 0x1a, 0xcf, 0xf8, 0xc2, 0xb9, 0xa8, 0xa8, 0x11, 0x98,
+	0x8b, 0xbc, 0x58, 0xf1, 0xff, 0x99, 0xaf, 0xd5, 0x06, 0x14, 0xfb, 0x46, 0x40, 0x87, 0x8e, 0x37,
+	0x91, 0x75, 0x0c, 0x39, 0x26, 0x8f, 0xa0, 0xe0, 0x3a, 0xd6, 0x64, 0xe8, 0xd8, 0xf5, 0xf9, 0x54,
+	0xa2, 0xcb, 0x48, 0x6b, 0x52, 0x86, 0x3c, 0x82, 0x79, 0x6f, 0x6c, 0xe1, 0xc9, 0x66, 0xc2, 0x1b,
+	0x19, 0x51, 0x62, 0xf1, 0xd4, 0xb8, 0x14, 0xcb, 0x40, 0xac, 0xfb, 0xbd, 0x76, 0x6c, 0x8a, 0x3b,
+	0x2e, 0x69, 0x45, 0x46, 0xf8, 0xd2, 0xb1, 0xd1, 0x5c, 0xa3, 0x1f, 0x98, 0x17, 0x3c, 0x37, 0x8b,
+	0x9a, 0x18, 0x61, 0x34, 0x3c, 0x8a, 0xcf, 0x5e, 0x23, 0xc0, 0xbc, 0xcc, 0x6b, 0x25, 0x41, 0xd9,
+	0xc3, 0x60, 0xc9, 0x57, 0xb1, 0x11, 0x60, 0x6e, 0xe6, 0xb5, 0x92, 0xa0, 0xec, 0x05, 0x6a, 0x1b,
+	0xd6, 0xf6, 0x51, 0x36, 0xb4, 0x47, 0x5c, 0x57, 0x9f, 0x40, 0x29, 0xac, 0x52, 0x8a, 0xf2, 0xc1,
+	0x4a, 0x96, 0xf9, 0x45, 0x59, 0xb7, 0x64, 0x4b, 0xf1, 0xd2, 0xc6, 0x6f, 0xbe, 0xd4, 0x2e, 0xac,
+	0xf1, 0xaf, 0xc0, 0xe4, 0x52, 0xb3, 0x43, 0xac, 0x36, 0xf1, 0xc6, 0xbf, 0xc1, 0x84, 0xff, 0x50,
+	0x60, 0x95, 0xbd, 0x61, 0xe4, 0x94, 0xb7, 0xf2, 0x78, 0xb8, 0x1b, 0x3e, 0x13, 0x62, 0xb9, 0x25,
+	0xde, 0x04, 0x88, 0x48, 0x13, 0x06, 0x49, 0x52, 0x28, 0x9e, 0x68, 0x55, 0x21, 0x26, 0xa8, 0xf8,
+	0xb0, 0xc6, 0x28, 0xeb, 0x8e, 0x6d, 0x4d, 0xf0, 0x40, 0x15, 0x35, 0xe0, 0xa4, 0x13, 0xdb, 0x9a,
+	0xa8, 0x43, 0x28, 0x85, 0xe6, 0x93, 0x5d, 0x80, 0xd0, 0xcf, 0xf2, 0x83, 0x25, 0xd3, 0xd1, 0xa5,
+	0x61, 0x38, 0xe7, 0xba, 0xcf, 0x8d, 0x7f, 0x53, 0x60, 0x33, 0xea, 0xac, 0xe7, 0xa6, 0x6f, 0x06,
+	0xce, 0xf4, 0xc1, 0xa5, 0xc2, 0x62, 0xb4, 0xa8, 0xcd, 0xd5, 0x97, 0xb4, 0xf2, 0xb4, 0xaa, 0xed,
+	0x93, 0x4f, 0x60, 0x15, 0x0b, 0x00, 0xbe, 0xc9, 0x84, 0x92, 0xf7, 0x0e, 0x61, 0xbc, 0x2e, 0x63,
+	0x4d, 0xaf, 0x95, 0x58, 0x24, 0xf2, 0x57, 0x46, 0x42, 0xc0, 0x50, 0x18, 0x09, 0x55, 0x87, 0x5a,
+	0xd2, 0x58, 0x16, 0x9d, 0xe8, 0xbd, 0x28, 0xad, 0xac, 0x44, 0x2e, 0xc6, 0x99, 0x2e, 0x29, 0x25,
+	0x5d, 0xd2, 0x83, 0x25, 0x8d, 0xfa, 0x81, 0x67, 0xf6, 0xd9, 0xed, 0x86, 0x67, 0x54, 0xc2, 0x8c,
+	0x12, 0x81, 0x99, 0x08, 0x64, 0xe4, 0xde, 0x0c, 0x19, 0xea, 0xaf, 0xf2, 0x50, 0x8e, 0x2c, 0xcb,
+	0xab, 0xaf, 0xe1, 0x70, 0x9a, 0xc6, 0x8b, 0x11, 0xea, 0x0c, 0x80, 0xdb, 0x81, 0x39, 0xbc, 0xab,
+	0xf2, 0xa9, 0x2b, 0x22, 0xa2, 0x80, 0x5d, 0x5d, 0x1a, 0xca, 0xa5, 0xa0, 0x7f, 0xee, 0x4d, 0xd0,
+	0x3f, 0x9f, 0x84, 0xfe, 0x18, 0x80, 0x2d, 0x24, 0x00, 0xec, 0x13, 0x98, 0x67, 0x74, 0x86, 0xe5,
+	0xcc, 0x0d, 0x33, 0xec, 0x61, 0xa2, 0x1a, 0x17, 0x64, 0xe1, 0xa2, 0xdf, 0xd2, 0x91, 0x1b, 0xe8,
+	0x58, 0x9e, 0xf3, 0xeb, 0x45, 0x1e, 0x2e, 0x4e, 0x3c, 0x44, 0x1a, 0x33, 0x89, 0x7f, 0xc8, 0x30,
+	0xe8, 0x11, 0xf7, 0x72, 0x09, 0x29, 0x07, 0xec, 0x32, 0xb9, 0x05, 0x45, 0xf6, 0xf9, 0x82, 0x4c,
+	0x5e, 0xb2, 0x2b, 0x50, 0x7b, 0x80, 0xac, 0x29, 0xa2, 0x96, 0xaf, 0x40, 0xd4, 0xca, 0xd5, 0x88,
+	0xba, 0x98, 0x44, 0xd4, 0x1e, 0xd4, 0x39, 0xa2, 0x46, 0x36, 0x25, 0x4f, 0xc9, 0x4f, 0xa0, 0x1c,
+	0x09, 0x9b, 0xc0, 0xc2, 0xf5, 0x6c, 0x47, 0x68, 0x51, 0x51, 0xb6, 0x2a, 0x07, 0xd7, 0xb7, 0xba,
+	0xea, 0x1e, 0xd4, 0x39, 0xce, 0x66, 0xac, 0x7a, 0xbd, 0xc4, 0x53, 0x7f, 0x0f, 0xd6, 0x0e, 0x69,
+	0xf0, 0xc3, 0xe7, 0x5f, 0xc0, 0x06, 0xc3, 0x95, 0xc8, 0x02, 0x6f, 0x05, 0x87, 0x13, 0xc8, 0x99,
+	0x4f, 0x21, 0xa7, 0x07, 0x95, 0xa8, 0x4e, 0xf2, 0x14, 0x2a, 0x11, 0xc3, 0x24, 0x7c, 0xce, 0xf2,
+	0x62, 0x4c, 0xf6, 0xda, 0x20, 0xfa, 0x3d, 0x34, 0xf0, 0x51, 0xca, 0xde, 0x5c, 0x06, 0x5e, 0x52,
+	0xec, 0x55, 0x73, 0x33, 0x87, 0x25, 0xf2, 0x3d, 0x77, 0x55, 0xbe, 0xe7, 0x63, 0xf9, 0xae, 0xfe,
+	0x8f, 0x02, 0x4b, 0x07, 0x86, 0x69, 0x4d, 0xa6, 0x16, 0x88, 0x8f, 0xd6, 0x10, 0xb1, 0xd8, 0xff,
+	0xcc, 0x10, 0xe7, 0xa5, 0x4f, 0xbd, 0x0b, 0x3a, 0xd0, 0x79, 0xc9, 0x8a, 0x23, 0xf4, 0xa2, 0xa4,
+	0xf2, 0x82, 0xd6, 0x0e, 0xac, 0xd8, 0xac, 0xaa, 0x2d, 0x16, 0x0b, 0x84, 0x2c, 0x7f, 0x1e, 0x2c,
+	0xdb, 0x8e, 0x2d, 0xd5, 0x04, 0x99, 0xf2, 0x7d, 0xaa, 0x7b, 0x4c, 0x33, 0x7f, 0x89, 0x46, 0xe5,
+	0xfb, 0x54, 0x63, 0x66, 0xdc, 0x86, 0x52, 0xdf, 0x73, 0x7c, 0xdf, 0xb4, 0x87, 0xbe, 0x78, 0x48,
+	0x4d, 0x09, 0xe4, 0x3d, 0x00, 0x7f, 0xec, 0xba, 0x1e, 0xf5, 0xfd, 0xb0, 0x13, 0x10, 0xa1, 0xa8,
+	0xff, 0xae, 0x40, 0x2d, 0xe9, 0xe9, 0xeb, 0xba, 0x58, 0x02, 0x67, 0xee, 0x9a, 0xc0, 0xb9, 0xc3,
+	0x9c, 0x38, 0x91, 0xbd, 0xc4, 0xa8, 0x7c, 0xc2, 0xdd, 0x1a, 0xca, 0x91, 0x0f, 0xa0, 0x3a, 0x32,
+	0x6d, 0x0e, 0x6a, 0x3c, 0xbb, 0xe7, 0xf8, 0x97, 0xf8, 0xc8, 0xb4, 0x11, 0xd5, 0x58, 0x86, 0x6f,
+	0x9f, 0xc0, 0x02, 0xaf, 0x21, 0x92, 0x32, 0x14, 0xce, 0x3a, 0x7f, 0xd8, 0x39, 0x79, 0xd1, 0xa9,
+	0xbd, 0x43, 0x2a, 0x50, 0x3c, 0x3d, 0xe9, 0xb6, 0x7b, 0xed, 0xe7, 0xad, 0x9a, 0xc2, 0x46, 0x9d,
+	0xd6, 0xe1, 0x1e, 0x8e, 0x72, 0x64, 0x11, 0x4a, 0xdd, 0xb3, 0xee, 0x69, 0x6b, 0xbf, 0xd7, 0x3a,
+	0xa8, 0xe5, 0xd9, 0x50, 0x6b, 0xed, 0x9f, 0x3c, 0x6f, 0x69, 0xad, 0x83, 0xda, 0xdc, 0xf6, 0x13,
+	0x58, 0xc9, 0xe8, 0x33, 0x90, 0x22, 0xcc, 0x9d, 0x9e, 0x75, 0x7f, 0x5e, 0x7b, 0x87, 0x14, 0x20,
+	0xdf, 0x3d, 0xee, 0xd6, 0x14, 0x52, 0x82, 0xf9, 0xd6, 0xf1, 0x5e, 0xfb, 0xa8, 0x96, 0xdb, 0x6e,
+	0x43, 0x35, 0x5e, 0xf7, 0x27, 0x75, 0x58, 0x3d, 0x3d, 0xda, 0xeb, 0x3d, 0x3b, 0xd1, 0x8e, 0xf5,
+	0xb3, 0x0e, 0xd3, 0xd6, 0x7e, 0xd6, 0x6e, 0x1d, 0xd4, 0xde, 0x61, 0x76, 0xee, 0x75, 0x0e, 0xb4,
+	0x93, 0xf6, 0x41, 0x4d, 0x61, 0x8b, 0xb5, 0x4f, 0xba, 0xb5, 0x1c, 0xfb, 0xe7, 0x45, 0xeb, 0x67,
+	0xb5, 0xfc, 0xf6, 0x1d, 0xa8, 0x44, 0x5f, 0xf5, 0x4c, 0xf1, 0x1f, 0x74, 0x4f, 0x3a, 0x5c, 0xf1,
+	0x97, 0xed, 0xd3, 0x9a, 0xb2, 0x7d, 0x04, 0xd5, 0x78, 0x49, 0x87, 0xd4, 0xa0, 0xb2, 0x77, 0x74,
+	0xa4, 0x9f, 0x9e, 0x69, 0xa7, 0x27, 0xdd, 0x56, 0x97, 0x6b, 0xe9, 0x69, 0x7b, 0xfb, 0xed, 0xce,
+	0x61, 0x4d, 0x61, 0x5b, 0xdc, 0xeb, 0xec, 0x1d, 0xfd, 0x51, 0xaf, 0xbd, 0xcf, 0x74, 0x55, 0xa0,
+	0xa8, 0xb5, 0xba, 0xad, 0x3d, 0x6d, 0xff, 0xe7, 0xb5, 0xfc, 0xf6, 0xef, 0xc2, 0x7a, 0x76, 0x41,
+	0x81, 0x4d, 0xeb, 0x9c, 0xe8, 0xad, 0x2f, 0x4e, 0x4f, 0xb4, 0x1e, 0x5f, 0xf2, 0xb0, 0x75, 0x82,
+	0xc6, 0xa0, 0xe1, 0x87, 0xa7, 0x5f, 0xd4, 0x72, 0xdb, 0xbf, 0x0f, 0x4b, 0x89, 0xb7, 0x14, 0x5b,
+	0xff, 0xa4, 0xa3, 0xb7, 0x3a, 0xbd, 0x96, 0xc6, 0x43, 0x71, 0xd2, 0xd1, 0x0f, 0x5e, 0xb4, 0x8e,
+	0x8e, 0x6a, 0x0a, 0x59, 0x86, 0xc5, 0x83, 0x33, 0xad, 0xdd, 0x39, 0xd4, 0xf7, 0xcf, 0xb4, 0x67,
+	0xad, 0x17, 0xb5, 0xdc, 0xf6, 0x43, 0x58, 0x4a, 0x64, 0x0c, 0x01, 0x58, 0x10, 0x6c, 0x9c, 0x7f,
+	0x7c, 0xf2, 0xbc, 0x75, 0xdc, 0xea, 0xf4, 0x6a, 0xca, 0xee, 0xbf, 0x6c, 0x02, 0x91, 0x6f, 0xe5,
+	0x9e, 0x67, 0xf4, 0x4d, 0x7b, 0xb8, 0x77, 0xda, 0x26, 0x26, 0x54, 0xa2, 0x5d, 0x50, 0x12, 0x6d,
+	0x1b, 0x65, 0x74, 0xee, 0x1b, 0xeb, 0x3b, 0xfc, 0x37, 0x1a, 0x3b, 0xf2, 0x57, 0x1e, 0x3b, 0x2d,
+	0xf6, 0x2b, 0x0f, 0xf5, 0xce, 0x2f, 0xff, 0xf3, 0xd7, 0xff, 0x90, 0xdb, 0x54, 0xd7, 0xf1, 0xc7,
+	0x1b, 0x17, 0x8f, 0x9b, 0x61, 0x77, 0xbb, 0xe9, 0x53, 0x7b, 0xf0, 0x54, 0xd9, 0x26, 0xaf, 0x61,
+	0x31, 0xba, 0xa2, 0x4f, 0xde, 0x9f, 0xa1, 0x4b, 0x42, 0x74, 0x63, 0x6b, 0xb6, 0x00, 0x2f, 0x12,
+	0xab, 0xf7, 0x51, 0xed, 0x96, 0xba, 0x99, 0xad, 0xb6, 0xf9, 0x72, 0x6c, 0xbd, 0x62, 0xba, 0xbf,
+	0x80, 0xa5, 0x44, 0x73, 0xfb, 0xcd, 0xda, 0xd5, 0xa8, 0x40, 0x76, 0x67, 0xfc, 0x81, 0x42, 0xfe,
+	0x14, 0x6a, 0xc9, 0xde, 0x2e, 0x89, 0xce, 0x9c, 0xd1, 0xf8, 0x9d, 0xe9, 0xc8, 0x1d, 0xdc, 0xd1,
+	0x83, 0xdd, 0xbb, 0x72, 0x47, 0x88, 0x7b, 0xcd, 0xef, 0xa2, 0x5f, 0x96, 0xdf, 0x37, 0x79, 0x97,
+	0x80, 0xed, 0xec, 0x15, 0xc0, 0x54, 0x05, 0xb9, 0x9d, 0xa9, 0xf9, 0x4d, 0x3a, 0x3f, 0x44, 0x9d,
+	0x77, 0x9e, 0x2a, 0xdb, 0xbb, 0xb7, 0xaf, 0x52, 0x4b, 0x0c, 0x28, 0x88, 0x16, 0x30, 0x89, 0xd6,
+	0x49, 0xe3, 0x6d, 0xe1, 0x99, 0x6a, 0xee, 0xa2, 0x9a, 0x77, 0xd5, 0x7a, 0x5c, 0x87, 0x81, 0xe9,
+	0xdb, 0x34, 0x06, 0x98, 0x25, 0x5f, 0x41, 0x41, 0x14, 0x39, 0x63, 0x2a, 0xe2, 0x4d, 0xa4, 0x46,
+	0xb2, 0xbf, 0xa1, 0x7e, 0x80, 0x6b, 0xbf, 0x47, 0xae, 0xb6, 0xff, 0x8f, 0xa1, 0x14, 0xf6, 0x95,
+	0xc8, 0x66, 0xb4, 0x8a, 0x94, 0xe8, 0x36, 0x35, 0x6a, 0x09, 0x05, 0xbe, 0xcc, 0x70, 0x72, 0x2b,
+	0xd3, 0x7a, 0xcb, 0xf4, 0x03, 0xd2, 0x87, 0x72, 0xa4, 0x5b, 0x44, 0xde, 0x8d, 0x65, 0x58, 0xb2,
+	0x8b, 0x94, 0xa1, 0x42, 0x38, 0x88, 0x6c, 0x66, 0xaa, 0xf0, 0x71, 0x09, 0x72, 0x01, 0xd5, 0x78,
+	0x2b, 0x92, 0x6c, 0xc5, 0xae, 0x90, 0x8c, 0xe6, 0x61, 0x23, 0xdd, 0xa1, 0x53, 0x9b, 0xa8, 0xeb,
+	0x23, 0xf5, 0x83, 0x2b, 0xf3, 0x4c, 0xb4, 0xf1, 0x58, 0x60, 0xfe, 0x5a, 0x81, 0x5a, 0xb2, 0x39,
+	0x19, 0xcf, 0xf4, 0xec, 0xce, 0xe5, 0xcc, 0x74, 0xf8, 0x29, 0x5a, 0xf0, 0xd9, 0xf6, 0x93, 0xeb,
+	0x58, 0xd0, 0xfc, 0x2e, 0xda, 0xda, 0xfc, 0x9e, 0xd8, 0x50, 0x8e, 0x34, 0x32, 0x63, 0xae, 0x4e,
+	0x37, 0x38, 0x1b, 0x24, 0xb5, 0x7f, 0x5f, 0xfd, 0x18, 0xd5, 0xdf, 0x27, 0xd7, 0x72, 0x00, 0xf9,
+	0x13, 0xa8, 0x44, 0xbb, 0x54, 0x31, 0x9c, 0xcc, 0xe8, 0x94, 0x35, 0xde, 0x9f, 0xc9, 0x17, 0xc8,
+	0xf5, 0x10, 0xd5, 0xdf, 0x23, 0x57, 0x9f, 0x73, 0x5e, 0x99, 0x26, 0x1e, 0x2c, 0x25, 0xfa, 0x56,
+	0xe4, 0x4e, 0x6c, 0x4b, 0x59, 0x3d, 0xad, 0x99, 0x8e, 0x17, 0x67, 0x65, 0xfb, 0xea, 0xb3, 0x32,
+	0x86, 0x4a, 0xb4, 0xfd, 0x13, 0xdb, 0x71, 0x46, 0x5f, 0xa8, 0xb1, 0x92, 0x6e, 0x5e, 0xf8, 0xea,
+	0x27, 0xa8, 0x6a, 0xfb, 0xa9, 0xb2, 0xad, 0xde, 0xbb, 0x72, 0xa3, 0xb2, 0xcb, 0x41, 0xfe, 0x52,
+	0x81, 0xa5, 0x44, 0x3f, 0x27, 0xb6, 0xd7, 0xec, 0x5e, 0x4f, 0xb6, 0xf6, 0xdf, 0x46, 0xed, 0x4f,
+	0xd4, 0x9d, 0x6b, 0xa9, 0x6e, 0xca, 0x26, 0x18, 0xcb, 0x76, 0x17, 0xca, 0x91, 0x0e, 0x4f, 0x2c,
+	0xbf, 0xd2, 0x9d, 0x9f, 0x6c, 0xed, 0x8f, 0x50, 0xfb, 0x87, 0xe4, 0x9a, 0x1b, 0xff, 0x73, 0x05,
+	0x96, 0x53, 0xed, 0x1d, 0x72, 0x37, 0x8d, 0x81, 0xa9, 0xe6, 0x4f, 0x63, 0x2d, 0xb3, 0xc7, 0x21,
+	0x8f, 0x38, 0xf9, 0xf0, 0x4a, 0x03, 0x82, 0xa9, 0x32, 0x0a, 0xd5, 0x78, 0xb9, 0x2e, 0x06, 0x2d,
+	0x99, 0x95, 0xbc, 0x46, 0x56, 0x09, 0x48, 0xbd, 0x8d, 0x9a, 0xd7, 0x59, 0xd8, 0x97, 0xa5, 0xf2,
+	0x21, 0x75, 0x78, 0xed, 0x88, 0xbc, 0x86, 0x6a, 0xbc, 0x94, 0x17, 0x53, 0x93, 0x59, 0xe5, 0xcb,
+	0x56, 0xf3, 0x18, 0xd5, 0x3c, 0x6c, 0xdc, 0x4f, 0xe9, 0x68, 0x7e, 0x17, 0xd6, 0x8b, 0x76, 0x64,
+	0xd5, 0xe8, 0x7b, 0x1e, 0xd7, 0x6a, 0xbc, 0xf6, 0x17, 0xd3, 0x9d, 0x59, 0x16, 0xbc, 0xfe, 0x39,
+	0x8a, 0xa8, 0x0f, 0x95, 0x92, 0x21, 0x66, 0x52, 0xa8, 0x2e, 0x91, 0x49, 0xd7, 0xda, 0x67, 0xea,
+	0x72, 0xcb, 0x54, 0x64, 0xc0, 0x62, 0xac, 0xe0, 0x18, 0xfb, 0xc2, 0xc9, 0x2a, 0x45, 0x36, 0x56,
+	0x33, 0x94, 0xf9, 0xea, 0x2d, 0xd4, 0xb6, 0x42, 0x32, 0x22, 0xf7, 0x55, 0xbc, 0xa6, 0x19, 0x56,
+	0xbe, 0xee, 0xcf, 0xd0, 0x94, 0xa8, 0xe3, 0x35, 0x36, 0x33, 0x14, 0x86, 0x8b, 0x7c, 0x03, 0xcb,
+	0xa9, 0xd2, 0x46, 0x2c, 0xff, 0x67, 0x15, 0x3e, 0x1a, 0x33, 0xde, 0xd1, 0xea, 0xfb, 0xb8, 0x93,
+	0x5b, 0xea, 0xaa, 0xdc, 0x49, 0xf4, 0x5d, 0xcd, 0xb2, 0xe1, 0x6f, 0x14, 0xf9, 0x83, 0xb9, 0x59,
+	0x3a, 0x67, 0x95, 0x45, 0x66, 0xea, 0xfc, 0x1d, 0xd4, 0xf9, 0x79, 0xe3, 0x71, 0x96, 0xce, 0xe6,
+	0x77, 0x91, 0xd1, 0x4e, 0xfc, 0xb1, 0x88, 0xe9, 0xf9, 0x67, 0xb0, 0x9c, 0x2a, 0x99, 0xc4, 0xec,
+	0x99, 0x55, 0x50, 0x99, 0x99, 0xa4, 0x02, 0x85, 0xb6, 0xef, 0xbd, 0xd1, 0x1e, 0x4c, 0xa2, 0x09,
+	0xfe, 0x5e, 0x27, 0xaa, 0x7d, 0x2b, 0x9e, 0xb0, 0x37, 0x70, 0x45, 0x0a, 0x00, 0xaf, 0x56, 0x3d,
+	0xe2, 0x3f, 0xfa, 0x89, 0xd5, 0x4d, 0xd4, 0x44, 0x62, 0x65, 0x14, 0x72, 0x1a, 0x1b, 0xd9, 0xea,
+	0x7d, 0x89, 0x42, 0x24, 0x33, 0xfc, 0x84, 0xfd, 0x36, 0x39, 0xa3, 0x5e, 0x42, 0xee, 0x25, 0xa1,
+	0x3e, 0xb3, 0x9e, 0x12, 0x4b, 0xe5, 0xa4, 0x8c, 0xfa, 0x29, 0x6a, 0xde, 0x21, 0x1f, 0x5f, 0x6b,
+	0xe7, 0x4d, 0x0f, 0x67, 0xfd, 0x0c, 0xbe, 0x0c, 0x7f, 0xa7, 0xfc, 0x72, 0x01, 0xc3, 0xf8, 0xe4,
+	0xff, 0x07, 0x00, 0xf8, 0x94, 0xe8, 0x23, 0x70, 0x2f, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// LocationTracingAPIClient is the client API for LocationTracingAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LocationTracingAPIClient interface {
+	// Send a single location to the server
+	SendLocation(ctx context.Context, in *SendLocationRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Sends user locations
+	SendLocations(ctx context.Context, in *SendLocationsRequest, opts ...grpc.CallOption) (*SendLocationsResponse, error)
+	// Streams user locations in batches, used by devices uploading many locations at once
+	StreamLocations(ctx context.Context, opts ...grpc.CallOption) (LocationTracingAPI_StreamLocationsClient, error)
+	// Updates user status
+	UpdateUserStatus(ctx context.Context, in *UpdateUserStatusRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Updates user data
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Add a new user
+	AddUser(ctx context.Context, in *AddUserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Retrieves a single user
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+	// Retrieves a collection of users
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*Users, error)
+	// Searches for users using phone number or full names
+	SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*Users, error)
+	// Registers a device to notify the user on, devices already registered are updated
+	RegisterDevice(ctx context.Context, in *RegisterDeviceRequest, opts ...grpc.CallOption) (*Device, error)
+	// Stops notifying the user on a device
+	UnregisterDevice(ctx context.Context, in *UnregisterDeviceRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Retrieves devices of a user
+	ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*Devices, error)
+	// Exports all data held about a user
+	ExportMyData(ctx context.Context, in *ExportMyDataRequest, opts ...grpc.CallOption) (*ExportMyDataResponse, error)
+	// Deletes a user account together with their locations, messages and contact points
+	DeleteMyAccount(ctx context.Context, in *DeleteMyAccountRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Grants consent for the given purposes
+	GrantConsent(ctx context.Context, in *GrantConsentRequest, opts ...grpc.CallOption) (*Consents, error)
+	// Withdraws consent for the given purposes
+	WithdrawConsent(ctx context.Context, in *WithdrawConsentRequest, opts ...grpc.CallOption) (*Consents, error)
+	// Retrieves user consents
+	GetConsents(ctx context.Context, in *GetConsentsRequest, opts ...grpc.CallOption) (*Consents, error)
+	// Retrieves the movement history of a user for case investigation
+	GetUserTrajectory(ctx context.Context, in *GetUserTrajectoryRequest, opts ...grpc.CallOption) (*Trajectory, error)
+	// Creates a geo fence with alert rules
+	CreateGeoFence(ctx context.Context, in *CreateGeoFenceRequest, opts ...grpc.CallOption) (*GeoFence, error)
+	// Replaces a geo fence and its alert rules
+	UpdateGeoFence(ctx context.Context, in *UpdateGeoFenceRequest, opts ...grpc.CallOption) (*GeoFence, error)
+	// Deletes a geo fence
+	DeleteGeoFence(ctx context.Context, in *DeleteGeoFenceRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Retrieves a geo fence
+	GetGeoFence(ctx context.Context, in *GetGeoFenceRequest, opts ...grpc.CallOption) (*GeoFence, error)
+	// Retrieves a collection of geo fences
+	ListGeoFences(ctx context.Context, in *ListGeoFencesRequest, opts ...grpc.CallOption) (*GeoFences, error)
+	// Retrieves users seen in any of the geo fences, used by the messaging service to target broadcasts.
+	// It is internal to services and not exposed through the gateway
+	ListGeoFenceVisitors(ctx context.Context, in *ListGeoFenceVisitorsRequest, opts ...grpc.CallOption) (*GeoFenceVisitors, error)
+	// Creates a curfew or movement restriction
+	CreateRestriction(ctx context.Context, in *CreateRestrictionRequest, opts ...grpc.CallOption) (*Restriction, error)
+	// Replaces a restriction
+	UpdateRestriction(ctx context.Context, in *UpdateRestrictionRequest, opts ...grpc.CallOption) (*Restriction, error)
+	// Deletes a restriction
+	DeleteRestriction(ctx context.Context, in *DeleteRestrictionRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Retrieves a restriction
+	GetRestriction(ctx context.Context, in *GetRestrictionRequest, opts ...grpc.CallOption) (*Restriction, error)
+	// Retrieves a collection of restrictions
+	ListRestrictions(ctx context.Context, in *ListRestrictionsRequest, opts ...grpc.CallOption) (*Restrictions, error)
+	// Retrieves aggregated and anonymized compliance with a restriction
+	GetComplianceReport(ctx context.Context, in *GetComplianceReportRequest, opts ...grpc.CallOption) (*ComplianceReport, error)
+}
+
+type locationTracingAPIClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLocationTracingAPIClient(cc *grpc.ClientConn) LocationTracingAPIClient {
+	return &locationTracingAPIClient{cc}
+}
+
+func (c *locationTracingAPIClient) SendLocation(ctx context.Context, in *SendLocationRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/SendLocation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) SendLocations(ctx context.Context, in *SendLocationsRequest, opts ...grpc.CallOption) (*SendLocationsResponse, error) {
+	out := new(SendLocationsResponse)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/SendLocations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) StreamLocations(ctx context.Context, opts ...grpc.CallOption) (LocationTracingAPI_StreamLocationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LocationTracingAPI_serviceDesc.Streams[0], "/covitrace.LocationTracingAPI/StreamLocations", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &locationTracingAPIStreamLocationsClient{stream}
+	return x, nil
+}
+
+type LocationTracingAPI_StreamLocationsClient interface {
+	Send(*SendLocationsRequest) error
+	CloseAndRecv() (*StreamLocationsResponse, error)
+	grpc.ClientStream
+}
+
+type locationTracingAPIStreamLocationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *locationTracingAPIStreamLocationsClient) Send(m *SendLocationsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *locationTracingAPIStreamLocationsClient) CloseAndRecv() (*StreamLocationsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(StreamLocationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *locationTracingAPIClient) UpdateUserStatus(ctx context.Context, in *UpdateUserStatusRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/UpdateUserStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/UpdateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) AddUser(ctx context.Context, in *AddUserRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/AddUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/GetUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*Users, error) {
+	out := new(Users)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*Users, error) {
+	out := new(Users)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/SearchUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) RegisterDevice(ctx context.Context, in *RegisterDeviceRequest, opts ...grpc.CallOption) (*Device, error) {
+	out := new(Device)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/RegisterDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) UnregisterDevice(ctx context.Context, in *UnregisterDeviceRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/UnregisterDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*Devices, error) {
+	out := new(Devices)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/ListDevices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) ExportMyData(ctx context.Context, in *ExportMyDataRequest, opts ...grpc.CallOption) (*ExportMyDataResponse, error) {
+	out := new(ExportMyDataResponse)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/ExportMyData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) DeleteMyAccount(ctx context.Context, in *DeleteMyAccountRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/DeleteMyAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) GrantConsent(ctx context.Context, in *GrantConsentRequest, opts ...grpc.CallOption) (*Consents, error) {
+	out := new(Consents)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/GrantConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) WithdrawConsent(ctx context.Context, in *WithdrawConsentRequest, opts ...grpc.CallOption) (*Consents, error) {
+	out := new(Consents)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/WithdrawConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) GetConsents(ctx context.Context, in *GetConsentsRequest, opts ...grpc.CallOption) (*Consents, error) {
+	out := new(Consents)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/GetConsents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) GetUserTrajectory(ctx context.Context, in *GetUserTrajectoryRequest, opts ...grpc.CallOption) (*Trajectory, error) {
+	out := new(Trajectory)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/GetUserTrajectory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) CreateGeoFence(ctx context.Context, in *CreateGeoFenceRequest, opts ...grpc.CallOption) (*GeoFence, error) {
+	out := new(GeoFence)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/CreateGeoFence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) UpdateGeoFence(ctx context.Context, in *UpdateGeoFenceRequest, opts ...grpc.CallOption) (*GeoFence, error) {
+	out := new(GeoFence)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/UpdateGeoFence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) DeleteGeoFence(ctx context.Context, in *DeleteGeoFenceRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/DeleteGeoFence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) GetGeoFence(ctx context.Context, in *GetGeoFenceRequest, opts ...grpc.CallOption) (*GeoFence, error) {
+	out := new(GeoFence)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/GetGeoFence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) ListGeoFences(ctx context.Context, in *ListGeoFencesRequest, opts ...grpc.CallOption) (*GeoFences, error) {
+	out := new(GeoFences)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/ListGeoFences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) ListGeoFenceVisitors(ctx context.Context, in *ListGeoFenceVisitorsRequest, opts ...grpc.CallOption) (*GeoFenceVisitors, error) {
+	out := new(GeoFenceVisitors)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/ListGeoFenceVisitors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) CreateRestriction(ctx context.Context, in *CreateRestrictionRequest, opts ...grpc.CallOption) (*Restriction, error) {
+	out := new(Restriction)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/CreateRestriction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) UpdateRestriction(ctx context.Context, in *UpdateRestrictionRequest, opts ...grpc.CallOption) (*Restriction, error) {
+	out := new(Restriction)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/UpdateRestriction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) DeleteRestriction(ctx context.Context, in *DeleteRestrictionRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/DeleteRestriction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) GetRestriction(ctx context.Context, in *GetRestrictionRequest, opts ...grpc.CallOption) (*Restriction, error) {
+	out := new(Restriction)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/GetRestriction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) ListRestrictions(ctx context.Context, in *ListRestrictionsRequest, opts ...grpc.CallOption) (*Restrictions, error) {
+	out := new(Restrictions)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/ListRestrictions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationTracingAPIClient) GetComplianceReport(ctx context.Context, in *GetComplianceReportRequest, opts ...grpc.CallOption) (*ComplianceReport, error) {
+	out := new(ComplianceReport)
+	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/GetComplianceReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LocationTracingAPIServer is the server API for LocationTracingAPI service.
+type LocationTracingAPIServer interface {
+	// Send a single location to the server
+	SendLocation(context.Context, *SendLocationRequest) (*empty.Empty, error)
+	// Sends user locations
+	SendLocations(context.Context, *SendLocationsRequest) (*SendLocationsResponse, error)
+	// Streams user locations in batches, used by devices uploading many locations at once
+	StreamLocations(LocationTracingAPI_StreamLocationsServer) error
+	// Updates user status
+	UpdateUserStatus(context.Context, *UpdateUserStatusRequest) (*empty.Empty, error)
+	// Updates user data
+	UpdateUser(context.Context, *UpdateUserRequest) (*empty.Empty, error)
+	// Add a new user
+	AddUser(context.Context, *AddUserRequest) (*empty.Empty, error)
+	// Retrieves a single user
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	// Retrieves a collection of users
+	ListUsers(context.Context, *ListUsersRequest) (*Users, error)
+	// Searches for users using phone number or full names
+	SearchUsers(context.Context, *SearchUsersRequest) (*Users, error)
+	// Registers a device to notify the user on, devices already registered are updated
+	RegisterDevice(context.Context, *RegisterDeviceRequest) (*Device, error)
+	// Stops notifying the user on a device
+	UnregisterDevice(context.Context, *UnregisterDeviceRequest) (*empty.Empty, error)
+	// Retrieves devices of a user
+	ListDevices(context.Context, *ListDevicesRequest) (*Devices, error)
+	// Exports all data held about a user
+	ExportMyData(context.Context, *ExportMyDataRequest) (*ExportMyDataResponse, error)
+	// Deletes a user account together with their locations, messages and contact points
+	DeleteMyAccount(context.Context, *DeleteMyAccountRequest) (*empty.Empty, error)
+	// Grants consent for the given purposes
+	GrantConsent(context.Context, *GrantConsentRequest) (*Consents, error)
+	// Withdraws consent for the given purposes
+	WithdrawConsent(context.Context, *WithdrawConsentRequest) (*Consents, error)
+	// Retrieves user consents
+	GetConsents(context.Context, *GetConsentsRequest) (*Consents, error)
+	// Retrieves the movement history of a user for case investigation
+	GetUserTrajectory(context.Context, *GetUserTrajectoryRequest) (*Trajectory, error)
+	// Creates a geo fence with alert rules
+	CreateGeoFence(context.Context, *CreateGeoFenceRequest) (*GeoFence, error)
+	// Replaces a geo fence and its alert rules
+	UpdateGeoFence(context.Context, *UpdateGeoFenceRequest) (*GeoFence, error)
+	// Deletes a geo fence
+	DeleteGeoFence(context.Context, *DeleteGeoFenceRequest) (*empty.Empty, error)
+	// Retrieves a geo fence
+	GetGeoFence(context.Context, *GetGeoFenceRequest) (*GeoFence, error)
+	// Retrieves a collection of geo fences
+	ListGeoFences(context.Context, *ListGeoFencesRequest) (*GeoFences, error)
+	// Retrieves users seen in any of the geo fences, used by the messaging service to target broadcasts.
+	// It is internal to services and not exposed through the gateway
+	ListGeoFenceVisitors(context.Context, *ListGeoFenceVisitorsRequest) (*GeoFenceVisitors, error)
+	// Creates a curfew or movement restriction
+	CreateRestriction(context.Context, *CreateRestrictionRequest) (*Restriction, error)
+	// Replaces a restriction
+	UpdateRestriction(context.Context, *UpdateRestrictionRequest) (*Restriction, error)
+	// Deletes a restriction
+	DeleteRestriction(context.Context, *DeleteRestrictionRequest) (*empty.Empty, error)
+	// Retrieves a restriction
+	GetRestriction(context.Context, *GetRestrictionRequest) (*Restriction, error)
+	// Retrieves a collection of restrictions
+	ListRestrictions(context.Context, *ListRestrictionsRequest) (*Restrictions, error)
+	// Retrieves aggregated and anonymized compliance with a restriction
+	GetComplianceReport(context.Context, *GetComplianceReportRequest) (*ComplianceReport, error)
+}
+
+func RegisterLocationTracingAPIServer(s *grpc.Server, srv LocationTracingAPIServer) {
+	s.RegisterService(&_LocationTracingAPI_serviceDesc, srv)
+}
+
+func _LocationTracingAPI_SendLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).SendLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/SendLocation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).SendLocation(ctx, req.(*SendLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationTracingAPI_SendLocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendLocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).SendLocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/SendLocations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).SendLocations(ctx, req.(*SendLocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationTracingAPI_StreamLocations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LocationTracingAPIServer).StreamLocations(&locationTracingAPIStreamLocationsServer{stream})
+}
+
+type LocationTracingAPI_StreamLocationsServer interface {
+	SendAndClose(*StreamLocationsResponse) error
+	Recv() (*SendLocationsRequest, error)
+	grpc.ServerStream
+}
+
+type locationTracingAPIStreamLocationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *locationTracingAPIStreamLocationsServer) SendAndClose(m *StreamLocationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *locationTracingAPIStreamLocationsServer) Recv() (*SendLocationsRequest, error) {
+	m := new(SendLocationsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LocationTracingAPI_UpdateUserStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).UpdateUserStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/UpdateUserStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).UpdateUserStatus(ctx, req.(*UpdateUserStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationTracingAPI_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/UpdateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationTracingAPI_AddUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).AddUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/AddUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).AddUser(ctx, req.(*AddUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationTracingAPI_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/GetUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationTracingAPI_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationTracingAPI_SearchUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).SearchUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/SearchUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).SearchUsers(ctx, req.(*SearchUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationTracingAPI_RegisterDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).RegisterDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/RegisterDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).RegisterDevice(ctx, req.(*RegisterDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationTracingAPI_UnregisterDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).UnregisterDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/UnregisterDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).UnregisterDevice(ctx, req.(*UnregisterDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func NewLocationTracingAPIClient(cc *grpc.ClientConn) LocationTracingAPIClient {
-	return &locationTracingAPIClient{cc}
+func _LocationTracingAPI_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).ListDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/ListDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).ListDevices(ctx, req.(*ListDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *locationTracingAPIClient) SendLocation(ctx context.Context, in *SendLocationRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/SendLocation", in, out, opts...)
-	if err != nil {
+func _LocationTracingAPI_ExportMyData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportMyDataRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).ExportMyData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/ExportMyData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).ExportMyData(ctx, req.(*ExportMyDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *locationTracingAPIClient) SendLocations(ctx context.Context, in *SendLocationsRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/SendLocations", in, out, opts...)
-	if err != nil {
+func _LocationTracingAPI_DeleteMyAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMyAccountRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).DeleteMyAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/DeleteMyAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).DeleteMyAccount(ctx, req.(*DeleteMyAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *locationTracingAPIClient) UpdateUserStatus(ctx context.Context, in *UpdateUserStatusRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/UpdateUserStatus", in, out, opts...)
-	if err != nil {
+func _LocationTracingAPI_GrantConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantConsentRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).GrantConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/GrantConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).GrantConsent(ctx, req.(*GrantConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *locationTracingAPIClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/UpdateUser", in, out, opts...)
-	if err != nil {
+func _LocationTracingAPI_WithdrawConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawConsentRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).WithdrawConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/WithdrawConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).WithdrawConsent(ctx, req.(*WithdrawConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *locationTracingAPIClient) AddUser(ctx context.Context, in *AddUserRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/AddUser", in, out, opts...)
-	if err != nil {
+func _LocationTracingAPI_GetConsents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConsentsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).GetConsents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/GetConsents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).GetConsents(ctx, req.(*GetConsentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *locationTracingAPIClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
-	out := new(User)
-	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/GetUser", in, out, opts...)
-	if err != nil {
+func _LocationTracingAPI_GetUserTrajectory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserTrajectoryRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).GetUserTrajectory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/GetUserTrajectory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).GetUserTrajectory(ctx, req.(*GetUserTrajectoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *locationTracingAPIClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*Users, error) {
-	out := new(Users)
-	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/ListUsers", in, out, opts...)
-	if err != nil {
+func _LocationTracingAPI_CreateGeoFence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGeoFenceRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).CreateGeoFence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/CreateGeoFence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).CreateGeoFence(ctx, req.(*CreateGeoFenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *locationTracingAPIClient) SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*Users, error) {
-	out := new(Users)
-	err := c.cc.Invoke(ctx, "/covitrace.LocationTracingAPI/SearchUsers", in, out, opts...)
-	if err != nil {
+func _LocationTracingAPI_UpdateGeoFence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateGeoFenceRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).UpdateGeoFence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/UpdateGeoFence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).UpdateGeoFence(ctx, req.(*UpdateGeoFenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// LocationTracingAPIServer is the server API for LocationTracingAPI service.
-type LocationTracingAPIServer interface {
-	// Send a single location to the server
-	SendLocation(context.Context, *SendLocationRequest) (*empty.Empty, error)
-	// Sends user locations
-	SendLocations(context.Context, *SendLocationsRequest) (*empty.Empty, error)
-	// Updates user status
-	UpdateUserStatus(context.Context, *UpdateUserStatusRequest) (*empty.Empty, error)
-	// Updates user data
-	UpdateUser(context.Context, *UpdateUserRequest) (*empty.Empty, error)
-	// Add a new user
-	AddUser(context.Context, *AddUserRequest) (*empty.Empty, error)
-	// Retrieves a single user
-	GetUser(context.Context, *GetUserRequest) (*User, error)
-	// Retrieves a collection of users
-	ListUsers(context.Context, *ListUsersRequest) (*Users, error)
-	// Searches for users using phone number or full names
-	SearchUsers(context.Context, *SearchUsersRequest) (*Users, error)
+func _LocationTracingAPI_DeleteGeoFence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGeoFenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).DeleteGeoFence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/DeleteGeoFence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).DeleteGeoFence(ctx, req.(*DeleteGeoFenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterLocationTracingAPIServer(s *grpc.Server, srv LocationTracingAPIServer) {
-	s.RegisterService(&_LocationTracingAPI_serviceDesc, srv)
+func _LocationTracingAPI_GetGeoFence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGeoFenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationTracingAPIServer).GetGeoFence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.LocationTracingAPI/GetGeoFence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationTracingAPIServer).GetGeoFence(ctx, req.(*GetGeoFenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _LocationTracingAPI_SendLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendLocationRequest)
+func _LocationTracingAPI_ListGeoFences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGeoFencesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LocationTracingAPIServer).SendLocation(ctx, in)
+		return srv.(LocationTracingAPIServer).ListGeoFences(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/covitrace.LocationTracingAPI/SendLocation",
+		FullMethod: "/covitrace.LocationTracingAPI/ListGeoFences",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LocationTracingAPIServer).SendLocation(ctx, req.(*SendLocationRequest))
+		return srv.(LocationTracingAPIServer).ListGeoFences(ctx, req.(*ListGeoFencesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _LocationTracingAPI_SendLocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendLocationsRequest)
+func _LocationTracingAPI_ListGeoFenceVisitors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGeoFenceVisitorsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LocationTracingAPIServer).SendLocations(ctx, in)
+		return srv.(LocationTracingAPIServer).ListGeoFenceVisitors(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/covitrace.LocationTracingAPI/SendLocations",
+		FullMethod: "/covitrace.LocationTracingAPI/ListGeoFenceVisitors",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LocationTracingAPIServer).SendLocations(ctx, req.(*SendLocationsRequest))
+		return srv.(LocationTracingAPIServer).ListGeoFenceVisitors(ctx, req.(*ListGeoFenceVisitorsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _LocationTracingAPI_UpdateUserStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateUserStatusRequest)
+func _LocationTracingAPI_CreateRestriction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRestrictionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LocationTracingAPIServer).UpdateUserStatus(ctx, in)
+		return srv.(LocationTracingAPIServer).CreateRestriction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/covitrace.LocationTracingAPI/UpdateUserStatus",
+		FullMethod: "/covitrace.LocationTracingAPI/CreateRestriction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LocationTracingAPIServer).UpdateUserStatus(ctx, req.(*UpdateUserStatusRequest))
+		return srv.(LocationTracingAPIServer).CreateRestriction(ctx, req.(*CreateRestrictionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _LocationTracingAPI_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateUserRequest)
+func _LocationTracingAPI_UpdateRestriction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRestrictionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LocationTracingAPIServer).UpdateUser(ctx, in)
+		return srv.(LocationTracingAPIServer).UpdateRestriction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/covitrace.LocationTracingAPI/UpdateUser",
+		FullMethod: "/covitrace.LocationTracingAPI/UpdateRestriction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LocationTracingAPIServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+		return srv.(LocationTracingAPIServer).UpdateRestriction(ctx, req.(*UpdateRestrictionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _LocationTracingAPI_AddUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddUserRequest)
+func _LocationTracingAPI_DeleteRestriction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRestrictionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LocationTracingAPIServer).AddUser(ctx, in)
+		return srv.(LocationTracingAPIServer).DeleteRestriction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/covitrace.LocationTracingAPI/AddUser",
+		FullMethod: "/covitrace.LocationTracingAPI/DeleteRestriction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LocationTracingAPIServer).AddUser(ctx, req.(*AddUserRequest))
+		return srv.(LocationTracingAPIServer).DeleteRestriction(ctx, req.(*DeleteRestrictionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _LocationTracingAPI_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetUserRequest)
+func _LocationTracingAPI_GetRestriction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRestrictionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LocationTracingAPIServer).GetUser(ctx, in)
+		return srv.(LocationTracingAPIServer).GetRestriction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/covitrace.LocationTracingAPI/GetUser",
+		FullMethod: "/covitrace.LocationTracingAPI/GetRestriction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LocationTracingAPIServer).GetUser(ctx, req.(*GetUserRequest))
+		return srv.(LocationTracingAPIServer).GetRestriction(ctx, req.(*GetRestrictionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _LocationTracingAPI_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListUsersRequest)
+func _LocationTracingAPI_ListRestrictions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRestrictionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LocationTracingAPIServer).ListUsers(ctx, in)
+		return srv.(LocationTracingAPIServer).ListRestrictions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/covitrace.LocationTracingAPI/ListUsers",
+		FullMethod: "/covitrace.LocationTracingAPI/ListRestrictions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LocationTracingAPIServer).ListUsers(ctx, req.(*ListUsersRequest))
+		return srv.(LocationTracingAPIServer).ListRestrictions(ctx, req.(*ListRestrictionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _LocationTracingAPI_SearchUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchUsersRequest)
+func _LocationTracingAPI_GetComplianceReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetComplianceReportRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LocationTracingAPIServer).SearchUsers(ctx, in)
+		return srv.(LocationTracingAPIServer).GetComplianceReport(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/covitrace.LocationTracingAPI/SearchUsers",
+		FullMethod: "/covitrace.LocationTracingAPI/GetComplianceReport",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LocationTracingAPIServer).SearchUsers(ctx, req.(*SearchUsersRequest))
+		return srv.(LocationTracingAPIServer).GetComplianceReport(ctx, req.(*GetComplianceReportRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1117,7 +4772,97 @@ var _LocationTracingAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SearchUsers",
 			Handler:    _LocationTracingAPI_SearchUsers_Handler,
 		},
+		{
+			MethodName: "RegisterDevice",
+			Handler:    _LocationTracingAPI_RegisterDevice_Handler,
+		},
+		{
+			MethodName: "UnregisterDevice",
+			Handler:    _LocationTracingAPI_UnregisterDevice_Handler,
+		},
+		{
+			MethodName: "ListDevices",
+			Handler:    _LocationTracingAPI_ListDevices_Handler,
+		},
+		{
+			MethodName: "ExportMyData",
+			Handler:    _LocationTracingAPI_ExportMyData_Handler,
+		},
+		{
+			MethodName: "DeleteMyAccount",
+			Handler:    _LocationTracingAPI_DeleteMyAccount_Handler,
+		},
+		{
+			MethodName: "GrantConsent",
+			Handler:    _LocationTracingAPI_GrantConsent_Handler,
+		},
+		{
+			MethodName: "WithdrawConsent",
+			Handler:    _LocationTracingAPI_WithdrawConsent_Handler,
+		},
+		{
+			MethodName: "GetConsents",
+			Handler:    _LocationTracingAPI_GetConsents_Handler,
+		},
+		{
+			MethodName: "GetUserTrajectory",
+			Handler:    _LocationTracingAPI_GetUserTrajectory_Handler,
+		},
+		{
+			MethodName: "CreateGeoFence",
+			Handler:    _LocationTracingAPI_CreateGeoFence_Handler,
+		},
+		{
+			MethodName: "UpdateGeoFence",
+			Handler:    _LocationTracingAPI_UpdateGeoFence_Handler,
+		},
+		{
+			MethodName: "DeleteGeoFence",
+			Handler:    _LocationTracingAPI_DeleteGeoFence_Handler,
+		},
+		{
+			MethodName: "GetGeoFence",
+			Handler:    _LocationTracingAPI_GetGeoFence_Handler,
+		},
+		{
+			MethodName: "ListGeoFences",
+			Handler:    _LocationTracingAPI_ListGeoFences_Handler,
+		},
+		{
+			MethodName: "ListGeoFenceVisitors",
+			Handler:    _LocationTracingAPI_ListGeoFenceVisitors_Handler,
+		},
+		{
+			MethodName: "CreateRestriction",
+			Handler:    _LocationTracingAPI_CreateRestriction_Handler,
+		},
+		{
+			MethodName: "UpdateRestriction",
+			Handler:    _LocationTracingAPI_UpdateRestriction_Handler,
+		},
+		{
+			MethodName: "DeleteRestriction",
+			Handler:    _LocationTracingAPI_DeleteRestriction_Handler,
+		},
+		{
+			MethodName: "GetRestriction",
+			Handler:    _LocationTracingAPI_GetRestriction_Handler,
+		},
+		{
+			MethodName: "ListRestrictions",
+			Handler:    _LocationTracingAPI_ListRestrictions_Handler,
+		},
+		{
+			MethodName: "GetComplianceReport",
+			Handler:    _LocationTracingAPI_GetComplianceReport_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLocations",
+			Handler:       _LocationTracingAPI_StreamLocations_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "location.proto",
 }
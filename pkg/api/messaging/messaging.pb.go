@@ -58,6 +58,35 @@ func (BroadCastMessageFilter) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_42a1718997f046ec, []int{0}
 }
 
+// QuarantineState is whether users are in quarantine, users are in quarantine while in the QUARANTINE group
+type QuarantineState int32
+
+const (
+	QuarantineState_QUARANTINE_ANY  QuarantineState = 0
+	QuarantineState_QUARANTINED     QuarantineState = 1
+	QuarantineState_NOT_QUARANTINED QuarantineState = 2
+)
+
+var QuarantineState_name = map[int32]string{
+	0: "QUARANTINE_ANY",
+	1: "QUARANTINED",
+	2: "NOT_QUARANTINED",
+}
+
+var QuarantineState_value = map[string]int32{
+	"QUARANTINE_ANY":  0,
+	"QUARANTINED":     1,
+	"NOT_QUARANTINED": 2,
+}
+
+func (x QuarantineState) String() string {
+	return proto.EnumName(QuarantineState_name, int32(x))
+}
+
+func (QuarantineState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{1}
+}
+
 // MessageType is category of a message
 type MessageType int32
 
@@ -87,7 +116,105 @@ func (x MessageType) String() string {
 }
 
 func (MessageType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_42a1718997f046ec, []int{1}
+	return fileDescriptor_42a1718997f046ec, []int{2}
+}
+
+// DeliveryStatus is the state of delivery of a message
+type DeliveryStatus int32
+
+const (
+	// Messages saved without a delivery, such as those saved before deliveries were recorded
+	DeliveryStatus_NOT_TRACKED DeliveryStatus = 0
+	DeliveryStatus_PENDING     DeliveryStatus = 1
+	DeliveryStatus_DELIVERED   DeliveryStatus = 2
+	DeliveryStatus_FAILED      DeliveryStatus = 3
+)
+
+var DeliveryStatus_name = map[int32]string{
+	0: "NOT_TRACKED",
+	1: "PENDING",
+	2: "DELIVERED",
+	3: "FAILED",
+}
+
+var DeliveryStatus_value = map[string]int32{
+	"NOT_TRACKED": 0,
+	"PENDING":     1,
+	"DELIVERED":   2,
+	"FAILED":      3,
+}
+
+func (x DeliveryStatus) String() string {
+	return proto.EnumName(DeliveryStatus_name, int32(x))
+}
+
+func (DeliveryStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{3}
+}
+
+// BroadcastStatus is the status of a broadcast
+type BroadcastStatus int32
+
+const (
+	BroadcastStatus_BROADCAST_PENDING   BroadcastStatus = 0
+	BroadcastStatus_BROADCAST_RUNNING   BroadcastStatus = 1
+	BroadcastStatus_BROADCAST_COMPLETED BroadcastStatus = 2
+	BroadcastStatus_BROADCAST_CANCELLED BroadcastStatus = 3
+	BroadcastStatus_BROADCAST_FAILED    BroadcastStatus = 4
+)
+
+var BroadcastStatus_name = map[int32]string{
+	0: "BROADCAST_PENDING",
+	1: "BROADCAST_RUNNING",
+	2: "BROADCAST_COMPLETED",
+	3: "BROADCAST_CANCELLED",
+	4: "BROADCAST_FAILED",
+}
+
+var BroadcastStatus_value = map[string]int32{
+	"BROADCAST_PENDING":   0,
+	"BROADCAST_RUNNING":   1,
+	"BROADCAST_COMPLETED": 2,
+	"BROADCAST_CANCELLED": 3,
+	"BROADCAST_FAILED":    4,
+}
+
+func (x BroadcastStatus) String() string {
+	return proto.EnumName(BroadcastStatus_name, int32(x))
+}
+
+func (BroadcastStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{4}
+}
+
+// TemplateVariableType is the type of value of a template variable
+type TemplateVariableType int32
+
+const (
+	TemplateVariableType_STRING TemplateVariableType = 0
+	TemplateVariableType_NUMBER TemplateVariableType = 1
+	// Unix timestamp formatted in templates with its Format method e.g {{.Since.Format "3:04PM"}}
+	TemplateVariableType_TIMESTAMP TemplateVariableType = 2
+)
+
+var TemplateVariableType_name = map[int32]string{
+	0: "STRING",
+	1: "NUMBER",
+	2: "TIMESTAMP",
+}
+
+var TemplateVariableType_value = map[string]int32{
+	"STRING":    0,
+	"NUMBER":    1,
+	"TIMESTAMP": 2,
+}
+
+func (x TemplateVariableType) String() string {
+	return proto.EnumName(TemplateVariableType_name, int32(x))
+}
+
+func (TemplateVariableType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{5}
 }
 
 // ContactData contains locational contacts infomation
@@ -172,7 +299,9 @@ func (m *ContactData) GetContactTime() string {
 
 // BroadCastMessageResponse is response after a message has been broadcasted containing the broadcast id
 type BroadCastMessageResponse struct {
-	BroadcastMessageId   string   `protobuf:"bytes,1,opt,name=broadcast_message_id,json=broadcastMessageId,proto3" json:"broadcast_message_id,omitempty"`
+	BroadcastMessageId string `protobuf:"bytes,1,opt,name=broadcast_message_id,json=broadcastMessageId,proto3" json:"broadcast_message_id,omitempty"`
+	// Set instead of the broadcast id when the broadcast is scheduled
+	ScheduleId           string   `protobuf:"bytes,2,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -210,17 +339,28 @@ func (m *BroadCastMessageResponse) GetBroadcastMessageId() string {
 	return ""
 }
 
+func (m *BroadCastMessageResponse) GetScheduleId() string {
+	if m != nil {
+		return m.ScheduleId
+	}
+	return ""
+}
+
 // BroadCastMessageRequest is request to broadcast message to users
 type BroadCastMessageRequest struct {
-	Title                string                   `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Message              string                   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Type                 MessageType              `protobuf:"varint,3,opt,name=type,proto3,enum=covitrace.MessageType" json:"type,omitempty"`
-	Filters              []BroadCastMessageFilter `protobuf:"varint,4,rep,packed,name=filters,proto3,enum=covitrace.BroadCastMessageFilter" json:"filters,omitempty"`
-	Topics               []string                 `protobuf:"bytes,5,rep,name=topics,proto3" json:"topics,omitempty"`
-	Payload              map[string]string        `protobuf:"bytes,6,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
-	XXX_unrecognized     []byte                   `json:"-"`
-	XXX_sizecache        int32                    `json:"-"`
+	Title   string                   `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Message string                   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Type    MessageType              `protobuf:"varint,3,opt,name=type,proto3,enum=covitrace.MessageType" json:"type,omitempty"`
+	Filters []BroadCastMessageFilter `protobuf:"varint,4,rep,packed,name=filters,proto3,enum=covitrace.BroadCastMessageFilter" json:"filters,omitempty"`
+	Topics  []string                 `protobuf:"bytes,5,rep,name=topics,proto3" json:"topics,omitempty"`
+	Payload map[string]string        `protobuf:"bytes,6,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Broadcasts without a schedule are sent immediately
+	Schedule *BroadcastSchedule `protobuf:"bytes,7,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	// Users the broadcast is sent to, filters and topics are ignored when set
+	Audience             *Audience `protobuf:"bytes,8,opt,name=audience,proto3" json:"audience,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *BroadCastMessageRequest) Reset()         { *m = BroadCastMessageRequest{} }
@@ -290,419 +430,2491 @@ func (m *BroadCastMessageRequest) GetPayload() map[string]string {
 	return nil
 }
 
-// Message is a message payload
-type Message struct {
-	MessageId            string            `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	UserPhone            string            `protobuf:"bytes,2,opt,name=user_phone,json=userPhone,proto3" json:"user_phone,omitempty"`
-	Title                string            `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Notification         string            `protobuf:"bytes,4,opt,name=notification,proto3" json:"notification,omitempty"`
-	Timestamp            int64             `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Sent                 bool              `protobuf:"varint,6,opt,name=sent,proto3" json:"sent,omitempty"`
-	Seen                 bool              `protobuf:"varint,7,opt,name=seen,proto3" json:"seen,omitempty"`
-	Type                 MessageType       `protobuf:"varint,8,opt,name=type,proto3,enum=covitrace.MessageType" json:"type,omitempty"`
-	Data                 map[string]string `protobuf:"bytes,9,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+func (m *BroadCastMessageRequest) GetSchedule() *BroadcastSchedule {
+	if m != nil {
+		return m.Schedule
+	}
+	return nil
 }
 
-func (m *Message) Reset()         { *m = Message{} }
-func (m *Message) String() string { return proto.CompactTextString(m) }
-func (*Message) ProtoMessage()    {}
-func (*Message) Descriptor() ([]byte, []int) {
+func (m *BroadCastMessageRequest) GetAudience() *Audience {
+	if m != nil {
+		return m.Audience
+	}
+	return nil
+}
+
+// Audience is an expression of the users a broadcast is sent to.
+// Users must match every field that is set, and any of the values of a repeated field
+type Audience struct {
+	// Status names such as POSITIVE or SUSPECTED
+	Statuses []string `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	Counties []string `protobuf:"bytes,2,rep,name=counties,proto3" json:"counties,omitempty"`
+	Wards    []string `protobuf:"bytes,3,rep,name=wards,proto3" json:"wards,omitempty"`
+	// Users seen in any of the geo fences since seen_since_timestamp, or within the last 14 days when it is not set
+	GeoFenceIds        []string        `protobuf:"bytes,4,rep,name=geo_fence_ids,json=geoFenceIds,proto3" json:"geo_fence_ids,omitempty"`
+	SeenSinceTimestamp int64           `protobuf:"varint,5,opt,name=seen_since_timestamp,json=seenSinceTimestamp,proto3" json:"seen_since_timestamp,omitempty"`
+	Quarantine         QuarantineState `protobuf:"varint,6,opt,name=quarantine,proto3,enum=covitrace.QuarantineState" json:"quarantine,omitempty"`
+	// Preferred languages such as en or sw
+	Languages []string `protobuf:"bytes,7,rep,name=languages,proto3" json:"languages,omitempty"`
+	// Users with a device running this app version or later e.g 1.4.0
+	MinAppVersion string `protobuf:"bytes,8,opt,name=min_app_version,json=minAppVersion,proto3" json:"min_app_version,omitempty"`
+	// Users matching any of the audiences
+	AnyOf []*Audience `protobuf:"bytes,9,rep,name=any_of,json=anyOf,proto3" json:"any_of,omitempty"`
+	// Users matching the audience are left out
+	Exclude              *Audience `protobuf:"bytes,10,opt,name=exclude,proto3" json:"exclude,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Audience) Reset()         { *m = Audience{} }
+func (m *Audience) String() string { return proto.CompactTextString(m) }
+func (*Audience) ProtoMessage()    {}
+func (*Audience) Descriptor() ([]byte, []int) {
 	return fileDescriptor_42a1718997f046ec, []int{3}
 }
 
-func (m *Message) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Message.Unmarshal(m, b)
+func (m *Audience) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Audience.Unmarshal(m, b)
 }
-func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+func (m *Audience) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Audience.Marshal(b, m, deterministic)
 }
-func (m *Message) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Message.Merge(m, src)
+func (m *Audience) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Audience.Merge(m, src)
 }
-func (m *Message) XXX_Size() int {
-	return xxx_messageInfo_Message.Size(m)
+func (m *Audience) XXX_Size() int {
+	return xxx_messageInfo_Audience.Size(m)
 }
-func (m *Message) XXX_DiscardUnknown() {
-	xxx_messageInfo_Message.DiscardUnknown(m)
+func (m *Audience) XXX_DiscardUnknown() {
+	xxx_messageInfo_Audience.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Message proto.InternalMessageInfo
+var xxx_messageInfo_Audience proto.InternalMessageInfo
 
-func (m *Message) GetMessageId() string {
+func (m *Audience) GetStatuses() []string {
 	if m != nil {
-		return m.MessageId
+		return m.Statuses
 	}
-	return ""
+	return nil
 }
 
-func (m *Message) GetUserPhone() string {
+func (m *Audience) GetCounties() []string {
 	if m != nil {
-		return m.UserPhone
+		return m.Counties
 	}
-	return ""
+	return nil
 }
 
-func (m *Message) GetTitle() string {
+func (m *Audience) GetWards() []string {
 	if m != nil {
-		return m.Title
+		return m.Wards
 	}
-	return ""
+	return nil
 }
 
-func (m *Message) GetNotification() string {
+func (m *Audience) GetGeoFenceIds() []string {
 	if m != nil {
-		return m.Notification
+		return m.GeoFenceIds
 	}
-	return ""
+	return nil
 }
 
-func (m *Message) GetTimestamp() int64 {
+func (m *Audience) GetSeenSinceTimestamp() int64 {
 	if m != nil {
-		return m.Timestamp
+		return m.SeenSinceTimestamp
 	}
 	return 0
 }
 
-func (m *Message) GetSent() bool {
+func (m *Audience) GetQuarantine() QuarantineState {
 	if m != nil {
-		return m.Sent
+		return m.Quarantine
 	}
-	return false
+	return QuarantineState_QUARANTINE_ANY
 }
 
-func (m *Message) GetSeen() bool {
+func (m *Audience) GetLanguages() []string {
 	if m != nil {
-		return m.Seen
+		return m.Languages
 	}
-	return false
+	return nil
 }
 
-func (m *Message) GetType() MessageType {
+func (m *Audience) GetMinAppVersion() string {
 	if m != nil {
-		return m.Type
+		return m.MinAppVersion
 	}
-	return MessageType_ANY
+	return ""
 }
 
-func (m *Message) GetData() map[string]string {
+func (m *Audience) GetAnyOf() []*Audience {
 	if m != nil {
-		return m.Data
+		return m.AnyOf
 	}
 	return nil
 }
 
-// SendMessageResponse is response after sending message contains message id
-type SendMessageResponse struct {
-	MessageId            string   `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Audience) GetExclude() *Audience {
+	if m != nil {
+		return m.Exclude
+	}
+	return nil
 }
 
-func (m *SendMessageResponse) Reset()         { *m = SendMessageResponse{} }
-func (m *SendMessageResponse) String() string { return proto.CompactTextString(m) }
-func (*SendMessageResponse) ProtoMessage()    {}
-func (*SendMessageResponse) Descriptor() ([]byte, []int) {
+// EstimateAudienceRequest is request to count the users a broadcast would be sent to
+type EstimateAudienceRequest struct {
+	Audience             *Audience `protobuf:"bytes,1,opt,name=audience,proto3" json:"audience,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *EstimateAudienceRequest) Reset()         { *m = EstimateAudienceRequest{} }
+func (m *EstimateAudienceRequest) String() string { return proto.CompactTextString(m) }
+func (*EstimateAudienceRequest) ProtoMessage()    {}
+func (*EstimateAudienceRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_42a1718997f046ec, []int{4}
 }
 
-func (m *SendMessageResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SendMessageResponse.Unmarshal(m, b)
+func (m *EstimateAudienceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EstimateAudienceRequest.Unmarshal(m, b)
 }
-func (m *SendMessageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SendMessageResponse.Marshal(b, m, deterministic)
+func (m *EstimateAudienceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EstimateAudienceRequest.Marshal(b, m, deterministic)
 }
-func (m *SendMessageResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SendMessageResponse.Merge(m, src)
+func (m *EstimateAudienceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EstimateAudienceRequest.Merge(m, src)
 }
-func (m *SendMessageResponse) XXX_Size() int {
-	return xxx_messageInfo_SendMessageResponse.Size(m)
+func (m *EstimateAudienceRequest) XXX_Size() int {
+	return xxx_messageInfo_EstimateAudienceRequest.Size(m)
 }
-func (m *SendMessageResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SendMessageResponse.DiscardUnknown(m)
+func (m *EstimateAudienceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EstimateAudienceRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SendMessageResponse proto.InternalMessageInfo
+var xxx_messageInfo_EstimateAudienceRequest proto.InternalMessageInfo
 
-func (m *SendMessageResponse) GetMessageId() string {
+func (m *EstimateAudienceRequest) GetAudience() *Audience {
 	if m != nil {
-		return m.MessageId
+		return m.Audience
 	}
-	return ""
+	return nil
 }
 
-// ListMessagesRequest is request to get user messages
-type ListMessagesRequest struct {
-	PhoneNumber          string        `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
-	PageToken            int32         `protobuf:"varint,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
-	PageSize             int32         `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	FilterType           []MessageType `protobuf:"varint,4,rep,packed,name=filter_type,json=filterType,proto3,enum=covitrace.MessageType" json:"filter_type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+// AudienceEstimate is how many users a broadcast would be sent to
+type AudienceEstimate struct {
+	Recipients int64 `protobuf:"varint,1,opt,name=recipients,proto3" json:"recipients,omitempty"`
+	// Recipients with a device registered for push notifications
+	PushRecipients       int64    `protobuf:"varint,2,opt,name=push_recipients,json=pushRecipients,proto3" json:"push_recipients,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ListMessagesRequest) Reset()         { *m = ListMessagesRequest{} }
-func (m *ListMessagesRequest) String() string { return proto.CompactTextString(m) }
-func (*ListMessagesRequest) ProtoMessage()    {}
-func (*ListMessagesRequest) Descriptor() ([]byte, []int) {
+func (m *AudienceEstimate) Reset()         { *m = AudienceEstimate{} }
+func (m *AudienceEstimate) String() string { return proto.CompactTextString(m) }
+func (*AudienceEstimate) ProtoMessage()    {}
+func (*AudienceEstimate) Descriptor() ([]byte, []int) {
 	return fileDescriptor_42a1718997f046ec, []int{5}
 }
 
-func (m *ListMessagesRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListMessagesRequest.Unmarshal(m, b)
+func (m *AudienceEstimate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AudienceEstimate.Unmarshal(m, b)
 }
-func (m *ListMessagesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListMessagesRequest.Marshal(b, m, deterministic)
+func (m *AudienceEstimate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AudienceEstimate.Marshal(b, m, deterministic)
 }
-func (m *ListMessagesRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListMessagesRequest.Merge(m, src)
+func (m *AudienceEstimate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AudienceEstimate.Merge(m, src)
 }
-func (m *ListMessagesRequest) XXX_Size() int {
-	return xxx_messageInfo_ListMessagesRequest.Size(m)
+func (m *AudienceEstimate) XXX_Size() int {
+	return xxx_messageInfo_AudienceEstimate.Size(m)
 }
-func (m *ListMessagesRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListMessagesRequest.DiscardUnknown(m)
+func (m *AudienceEstimate) XXX_DiscardUnknown() {
+	xxx_messageInfo_AudienceEstimate.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ListMessagesRequest proto.InternalMessageInfo
-
-func (m *ListMessagesRequest) GetPhoneNumber() string {
-	if m != nil {
-		return m.PhoneNumber
-	}
-	return ""
-}
+var xxx_messageInfo_AudienceEstimate proto.InternalMessageInfo
 
-func (m *ListMessagesRequest) GetPageToken() int32 {
+func (m *AudienceEstimate) GetRecipients() int64 {
 	if m != nil {
-		return m.PageToken
+		return m.Recipients
 	}
 	return 0
 }
 
-func (m *ListMessagesRequest) GetPageSize() int32 {
+func (m *AudienceEstimate) GetPushRecipients() int64 {
 	if m != nil {
-		return m.PageSize
+		return m.PushRecipients
 	}
 	return 0
 }
 
-func (m *ListMessagesRequest) GetFilterType() []MessageType {
-	if m != nil {
-		return m.FilterType
-	}
-	return nil
-}
-
-// Messages is a collection of user messages
-type Messages struct {
-	Messages             []*Message `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+// BroadcastSchedule is when a broadcast is sent, either once or repeatedly
+type BroadcastSchedule struct {
+	// When a one-off broadcast is sent, or when a recurring broadcast starts
+	SendAtTimestamp int64 `protobuf:"varint,1,opt,name=send_at_timestamp,json=sendAtTimestamp,proto3" json:"send_at_timestamp,omitempty"`
+	// Five field cron expression of a recurring broadcast e.g "0 21 * * *" for 9pm daily
+	CronExpression string `protobuf:"bytes,2,opt,name=cron_expression,json=cronExpression,proto3" json:"cron_expression,omitempty"`
+	// IANA timezone the cron expression is in, defaults to Africa/Nairobi
+	Timezone string `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// When a recurring broadcast stops
+	EndTimestamp         int64    `protobuf:"varint,4,opt,name=end_timestamp,json=endTimestamp,proto3" json:"end_timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Messages) Reset()         { *m = Messages{} }
-func (m *Messages) String() string { return proto.CompactTextString(m) }
-func (*Messages) ProtoMessage()    {}
-func (*Messages) Descriptor() ([]byte, []int) {
+func (m *BroadcastSchedule) Reset()         { *m = BroadcastSchedule{} }
+func (m *BroadcastSchedule) String() string { return proto.CompactTextString(m) }
+func (*BroadcastSchedule) ProtoMessage()    {}
+func (*BroadcastSchedule) Descriptor() ([]byte, []int) {
 	return fileDescriptor_42a1718997f046ec, []int{6}
 }
 
-func (m *Messages) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Messages.Unmarshal(m, b)
+func (m *BroadcastSchedule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BroadcastSchedule.Unmarshal(m, b)
 }
-func (m *Messages) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Messages.Marshal(b, m, deterministic)
+func (m *BroadcastSchedule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BroadcastSchedule.Marshal(b, m, deterministic)
 }
-func (m *Messages) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Messages.Merge(m, src)
+func (m *BroadcastSchedule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BroadcastSchedule.Merge(m, src)
 }
-func (m *Messages) XXX_Size() int {
-	return xxx_messageInfo_Messages.Size(m)
+func (m *BroadcastSchedule) XXX_Size() int {
+	return xxx_messageInfo_BroadcastSchedule.Size(m)
 }
-func (m *Messages) XXX_DiscardUnknown() {
-	xxx_messageInfo_Messages.DiscardUnknown(m)
+func (m *BroadcastSchedule) XXX_DiscardUnknown() {
+	xxx_messageInfo_BroadcastSchedule.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Messages proto.InternalMessageInfo
+var xxx_messageInfo_BroadcastSchedule proto.InternalMessageInfo
 
-func (m *Messages) GetMessages() []*Message {
+func (m *BroadcastSchedule) GetSendAtTimestamp() int64 {
 	if m != nil {
-		return m.Messages
+		return m.SendAtTimestamp
 	}
-	return nil
+	return 0
 }
 
-// MessageRequest is request to fetch or perform action to messages owned by user
-type MessageRequest struct {
-	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *BroadcastSchedule) GetCronExpression() string {
+	if m != nil {
+		return m.CronExpression
+	}
+	return ""
 }
 
-func (m *MessageRequest) Reset()         { *m = MessageRequest{} }
-func (m *MessageRequest) String() string { return proto.CompactTextString(m) }
-func (*MessageRequest) ProtoMessage()    {}
-func (*MessageRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_42a1718997f046ec, []int{7}
+func (m *BroadcastSchedule) GetTimezone() string {
+	if m != nil {
+		return m.Timezone
+	}
+	return ""
 }
 
-func (m *MessageRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessageRequest.Unmarshal(m, b)
-}
-func (m *MessageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessageRequest.Marshal(b, m, deterministic)
-}
-func (m *MessageRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessageRequest.Merge(m, src)
-}
-func (m *MessageRequest) XXX_Size() int {
-	return xxx_messageInfo_MessageRequest.Size(m)
-}
-func (m *MessageRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessageRequest.DiscardUnknown(m)
+func (m *BroadcastSchedule) GetEndTimestamp() int64 {
+	if m != nil {
+		return m.EndTimestamp
+	}
+	return 0
+}
+
+// Message is a message payload
+type Message struct {
+	MessageId    string            `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	UserPhone    string            `protobuf:"bytes,2,opt,name=user_phone,json=userPhone,proto3" json:"user_phone,omitempty"`
+	Title        string            `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Notification string            `protobuf:"bytes,4,opt,name=notification,proto3" json:"notification,omitempty"`
+	Timestamp    int64             `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Sent         bool              `protobuf:"varint,6,opt,name=sent,proto3" json:"sent,omitempty"`
+	Seen         bool              `protobuf:"varint,7,opt,name=seen,proto3" json:"seen,omitempty"`
+	Type         MessageType       `protobuf:"varint,8,opt,name=type,proto3,enum=covitrace.MessageType" json:"type,omitempty"`
+	Data         map[string]string `protobuf:"bytes,9,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Channel the message was delivered through
+	Channel          string         `protobuf:"bytes,10,opt,name=channel,proto3" json:"channel,omitempty"`
+	DeliveryStatus   DeliveryStatus `protobuf:"varint,11,opt,name=delivery_status,json=deliveryStatus,proto3,enum=covitrace.DeliveryStatus" json:"delivery_status,omitempty"`
+	DeliveryAttempts int32          `protobuf:"varint,12,opt,name=delivery_attempts,json=deliveryAttempts,proto3" json:"delivery_attempts,omitempty"`
+	// Messages sent with a template have their title and notification rendered in the language of the user
+	Template             string            `protobuf:"bytes,13,opt,name=template,proto3" json:"template,omitempty"`
+	TemplateVariables    map[string]string `protobuf:"bytes,14,rep,name=template_variables,json=templateVariables,proto3" json:"template_variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Message) Reset()         { *m = Message{} }
+func (m *Message) String() string { return proto.CompactTextString(m) }
+func (*Message) ProtoMessage()    {}
+func (*Message) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{7}
+}
+
+func (m *Message) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Message.Unmarshal(m, b)
+}
+func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+}
+func (m *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(m, src)
+}
+func (m *Message) XXX_Size() int {
+	return xxx_messageInfo_Message.Size(m)
+}
+func (m *Message) XXX_DiscardUnknown() {
+	xxx_messageInfo_Message.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Message proto.InternalMessageInfo
+
+func (m *Message) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *Message) GetUserPhone() string {
+	if m != nil {
+		return m.UserPhone
+	}
+	return ""
+}
+
+func (m *Message) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *Message) GetNotification() string {
+	if m != nil {
+		return m.Notification
+	}
+	return ""
+}
+
+func (m *Message) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Message) GetSent() bool {
+	if m != nil {
+		return m.Sent
+	}
+	return false
+}
+
+func (m *Message) GetSeen() bool {
+	if m != nil {
+		return m.Seen
+	}
+	return false
+}
+
+func (m *Message) GetType() MessageType {
+	if m != nil {
+		return m.Type
+	}
+	return MessageType_ANY
+}
+
+func (m *Message) GetData() map[string]string {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Message) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *Message) GetDeliveryStatus() DeliveryStatus {
+	if m != nil {
+		return m.DeliveryStatus
+	}
+	return DeliveryStatus_NOT_TRACKED
+}
+
+func (m *Message) GetDeliveryAttempts() int32 {
+	if m != nil {
+		return m.DeliveryAttempts
+	}
+	return 0
+}
+
+func (m *Message) GetTemplate() string {
+	if m != nil {
+		return m.Template
+	}
+	return ""
+}
+
+func (m *Message) GetTemplateVariables() map[string]string {
+	if m != nil {
+		return m.TemplateVariables
+	}
+	return nil
+}
+
+// DeliveryAttempt is an attempt to deliver a message through a channel
+type DeliveryAttempt struct {
+	Channel   string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Delivered bool   `protobuf:"varint,2,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	// Identifier of the message with the provider, such as the FCM message id
+	ProviderMessageId string `protobuf:"bytes,3,opt,name=provider_message_id,json=providerMessageId,proto3" json:"provider_message_id,omitempty"`
+	// Error code of the provider, such as NotRegistered for FCM
+	ErrorCode string `protobuf:"bytes,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Error     string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// Token the device is registered with when FCM returned a canonical registration id
+	CanonicalRegistrationId string   `protobuf:"bytes,6,opt,name=canonical_registration_id,json=canonicalRegistrationId,proto3" json:"canonical_registration_id,omitempty"`
+	Timestamp               int64    `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
+	XXX_unrecognized        []byte   `json:"-"`
+	XXX_sizecache           int32    `json:"-"`
+}
+
+func (m *DeliveryAttempt) Reset()         { *m = DeliveryAttempt{} }
+func (m *DeliveryAttempt) String() string { return proto.CompactTextString(m) }
+func (*DeliveryAttempt) ProtoMessage()    {}
+func (*DeliveryAttempt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{8}
+}
+
+func (m *DeliveryAttempt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeliveryAttempt.Unmarshal(m, b)
+}
+func (m *DeliveryAttempt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeliveryAttempt.Marshal(b, m, deterministic)
+}
+func (m *DeliveryAttempt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeliveryAttempt.Merge(m, src)
+}
+func (m *DeliveryAttempt) XXX_Size() int {
+	return xxx_messageInfo_DeliveryAttempt.Size(m)
+}
+func (m *DeliveryAttempt) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeliveryAttempt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeliveryAttempt proto.InternalMessageInfo
+
+func (m *DeliveryAttempt) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *DeliveryAttempt) GetDelivered() bool {
+	if m != nil {
+		return m.Delivered
+	}
+	return false
+}
+
+func (m *DeliveryAttempt) GetProviderMessageId() string {
+	if m != nil {
+		return m.ProviderMessageId
+	}
+	return ""
+}
+
+func (m *DeliveryAttempt) GetErrorCode() string {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ""
+}
+
+func (m *DeliveryAttempt) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *DeliveryAttempt) GetCanonicalRegistrationId() string {
+	if m != nil {
+		return m.CanonicalRegistrationId
+	}
+	return ""
+}
+
+func (m *DeliveryAttempt) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+// GetMessageDeliveryStatusRequest is request to get the delivery status of a message or a broadcast
+type GetMessageDeliveryStatusRequest struct {
+	MessageId            string   `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	BroadcastMessageId   string   `protobuf:"bytes,2,opt,name=broadcast_message_id,json=broadcastMessageId,proto3" json:"broadcast_message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMessageDeliveryStatusRequest) Reset()         { *m = GetMessageDeliveryStatusRequest{} }
+func (m *GetMessageDeliveryStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMessageDeliveryStatusRequest) ProtoMessage()    {}
+func (*GetMessageDeliveryStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{9}
+}
+
+func (m *GetMessageDeliveryStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMessageDeliveryStatusRequest.Unmarshal(m, b)
+}
+func (m *GetMessageDeliveryStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMessageDeliveryStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMessageDeliveryStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMessageDeliveryStatusRequest.Merge(m, src)
+}
+func (m *GetMessageDeliveryStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMessageDeliveryStatusRequest.Size(m)
+}
+func (m *GetMessageDeliveryStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMessageDeliveryStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMessageDeliveryStatusRequest proto.InternalMessageInfo
+
+func (m *GetMessageDeliveryStatusRequest) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *GetMessageDeliveryStatusRequest) GetBroadcastMessageId() string {
+	if m != nil {
+		return m.BroadcastMessageId
+	}
+	return ""
+}
+
+// MessageDeliveryStatus is the delivery status of a message, or the counts of deliveries of a broadcast
+type MessageDeliveryStatus struct {
+	MessageId            string             `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Status               DeliveryStatus     `protobuf:"varint,2,opt,name=status,proto3,enum=covitrace.DeliveryStatus" json:"status,omitempty"`
+	Channel              string             `protobuf:"bytes,3,opt,name=channel,proto3" json:"channel,omitempty"`
+	Attempts             int32              `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError            string             `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	DeliveryAttempts     []*DeliveryAttempt `protobuf:"bytes,6,rep,name=delivery_attempts,json=deliveryAttempts,proto3" json:"delivery_attempts,omitempty"`
+	BroadcastMessageId   string             `protobuf:"bytes,7,opt,name=broadcast_message_id,json=broadcastMessageId,proto3" json:"broadcast_message_id,omitempty"`
+	Recipients           int64              `protobuf:"varint,8,opt,name=recipients,proto3" json:"recipients,omitempty"`
+	Delivered            int64              `protobuf:"varint,9,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Failed               int64              `protobuf:"varint,10,opt,name=failed,proto3" json:"failed,omitempty"`
+	Pending              int64              `protobuf:"varint,11,opt,name=pending,proto3" json:"pending,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *MessageDeliveryStatus) Reset()         { *m = MessageDeliveryStatus{} }
+func (m *MessageDeliveryStatus) String() string { return proto.CompactTextString(m) }
+func (*MessageDeliveryStatus) ProtoMessage()    {}
+func (*MessageDeliveryStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{10}
+}
+
+func (m *MessageDeliveryStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageDeliveryStatus.Unmarshal(m, b)
+}
+func (m *MessageDeliveryStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageDeliveryStatus.Marshal(b, m, deterministic)
+}
+func (m *MessageDeliveryStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageDeliveryStatus.Merge(m, src)
+}
+func (m *MessageDeliveryStatus) XXX_Size() int {
+	return xxx_messageInfo_MessageDeliveryStatus.Size(m)
+}
+func (m *MessageDeliveryStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageDeliveryStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageDeliveryStatus proto.InternalMessageInfo
+
+func (m *MessageDeliveryStatus) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *MessageDeliveryStatus) GetStatus() DeliveryStatus {
+	if m != nil {
+		return m.Status
+	}
+	return DeliveryStatus_NOT_TRACKED
+}
+
+func (m *MessageDeliveryStatus) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *MessageDeliveryStatus) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *MessageDeliveryStatus) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *MessageDeliveryStatus) GetDeliveryAttempts() []*DeliveryAttempt {
+	if m != nil {
+		return m.DeliveryAttempts
+	}
+	return nil
+}
+
+func (m *MessageDeliveryStatus) GetBroadcastMessageId() string {
+	if m != nil {
+		return m.BroadcastMessageId
+	}
+	return ""
+}
+
+func (m *MessageDeliveryStatus) GetRecipients() int64 {
+	if m != nil {
+		return m.Recipients
+	}
+	return 0
+}
+
+func (m *MessageDeliveryStatus) GetDelivered() int64 {
+	if m != nil {
+		return m.Delivered
+	}
+	return 0
+}
+
+func (m *MessageDeliveryStatus) GetFailed() int64 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *MessageDeliveryStatus) GetPending() int64 {
+	if m != nil {
+		return m.Pending
+	}
+	return 0
+}
+
+// SendMessageResponse is response after sending message contains message id
+type SendMessageResponse struct {
+	MessageId            string   `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendMessageResponse) Reset()         { *m = SendMessageResponse{} }
+func (m *SendMessageResponse) String() string { return proto.CompactTextString(m) }
+func (*SendMessageResponse) ProtoMessage()    {}
+func (*SendMessageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{11}
+}
+
+func (m *SendMessageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendMessageResponse.Unmarshal(m, b)
+}
+func (m *SendMessageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendMessageResponse.Marshal(b, m, deterministic)
+}
+func (m *SendMessageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendMessageResponse.Merge(m, src)
+}
+func (m *SendMessageResponse) XXX_Size() int {
+	return xxx_messageInfo_SendMessageResponse.Size(m)
+}
+func (m *SendMessageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendMessageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendMessageResponse proto.InternalMessageInfo
+
+func (m *SendMessageResponse) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+// ListMessagesRequest is request to get user messages
+type ListMessagesRequest struct {
+	PhoneNumber          string        `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	PageToken            int32         `protobuf:"varint,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	PageSize             int32         `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	FilterType           []MessageType `protobuf:"varint,4,rep,packed,name=filter_type,json=filterType,proto3,enum=covitrace.MessageType" json:"filter_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListMessagesRequest) Reset()         { *m = ListMessagesRequest{} }
+func (m *ListMessagesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListMessagesRequest) ProtoMessage()    {}
+func (*ListMessagesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{12}
+}
+
+func (m *ListMessagesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMessagesRequest.Unmarshal(m, b)
+}
+func (m *ListMessagesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMessagesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListMessagesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMessagesRequest.Merge(m, src)
+}
+func (m *ListMessagesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListMessagesRequest.Size(m)
+}
+func (m *ListMessagesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMessagesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMessagesRequest proto.InternalMessageInfo
+
+func (m *ListMessagesRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *ListMessagesRequest) GetPageToken() int32 {
+	if m != nil {
+		return m.PageToken
+	}
+	return 0
+}
+
+func (m *ListMessagesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListMessagesRequest) GetFilterType() []MessageType {
+	if m != nil {
+		return m.FilterType
+	}
+	return nil
+}
+
+// Messages is a collection of user messages
+type Messages struct {
+	Messages             []*Message `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Messages) Reset()         { *m = Messages{} }
+func (m *Messages) String() string { return proto.CompactTextString(m) }
+func (*Messages) ProtoMessage()    {}
+func (*Messages) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{13}
+}
+
+func (m *Messages) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Messages.Unmarshal(m, b)
+}
+func (m *Messages) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Messages.Marshal(b, m, deterministic)
+}
+func (m *Messages) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Messages.Merge(m, src)
+}
+func (m *Messages) XXX_Size() int {
+	return xxx_messageInfo_Messages.Size(m)
+}
+func (m *Messages) XXX_DiscardUnknown() {
+	xxx_messageInfo_Messages.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Messages proto.InternalMessageInfo
+
+func (m *Messages) GetMessages() []*Message {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+// MessageRequest is request to fetch or perform action to messages owned by user
+type MessageRequest struct {
+	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MessageRequest) Reset()         { *m = MessageRequest{} }
+func (m *MessageRequest) String() string { return proto.CompactTextString(m) }
+func (*MessageRequest) ProtoMessage()    {}
+func (*MessageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{14}
+}
+
+func (m *MessageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageRequest.Unmarshal(m, b)
+}
+func (m *MessageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageRequest.Marshal(b, m, deterministic)
+}
+func (m *MessageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageRequest.Merge(m, src)
+}
+func (m *MessageRequest) XXX_Size() int {
+	return xxx_messageInfo_MessageRequest.Size(m)
+}
+func (m *MessageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageRequest.DiscardUnknown(m)
 }
 
 var xxx_messageInfo_MessageRequest proto.InternalMessageInfo
 
-func (m *MessageRequest) GetPhoneNumber() string {
+func (m *MessageRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+// NewMessagesCount contains the count of new messages
+type NewMessagesCount struct {
+	Count                int32    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NewMessagesCount) Reset()         { *m = NewMessagesCount{} }
+func (m *NewMessagesCount) String() string { return proto.CompactTextString(m) }
+func (*NewMessagesCount) ProtoMessage()    {}
+func (*NewMessagesCount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{15}
+}
+
+func (m *NewMessagesCount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewMessagesCount.Unmarshal(m, b)
+}
+func (m *NewMessagesCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewMessagesCount.Marshal(b, m, deterministic)
+}
+func (m *NewMessagesCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewMessagesCount.Merge(m, src)
+}
+func (m *NewMessagesCount) XXX_Size() int {
+	return xxx_messageInfo_NewMessagesCount.Size(m)
+}
+func (m *NewMessagesCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewMessagesCount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewMessagesCount proto.InternalMessageInfo
+
+func (m *NewMessagesCount) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// CountStaleDeviceTokensRequest is request to count users whose device tokens were found invalid
+type CountStaleDeviceTokensRequest struct {
+	Counties             []string `protobuf:"bytes,1,rep,name=counties,proto3" json:"counties,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CountStaleDeviceTokensRequest) Reset()         { *m = CountStaleDeviceTokensRequest{} }
+func (m *CountStaleDeviceTokensRequest) String() string { return proto.CompactTextString(m) }
+func (*CountStaleDeviceTokensRequest) ProtoMessage()    {}
+func (*CountStaleDeviceTokensRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{16}
+}
+
+func (m *CountStaleDeviceTokensRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CountStaleDeviceTokensRequest.Unmarshal(m, b)
+}
+func (m *CountStaleDeviceTokensRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CountStaleDeviceTokensRequest.Marshal(b, m, deterministic)
+}
+func (m *CountStaleDeviceTokensRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountStaleDeviceTokensRequest.Merge(m, src)
+}
+func (m *CountStaleDeviceTokensRequest) XXX_Size() int {
+	return xxx_messageInfo_CountStaleDeviceTokensRequest.Size(m)
+}
+func (m *CountStaleDeviceTokensRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountStaleDeviceTokensRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountStaleDeviceTokensRequest proto.InternalMessageInfo
+
+func (m *CountStaleDeviceTokensRequest) GetCounties() []string {
+	if m != nil {
+		return m.Counties
+	}
+	return nil
+}
+
+// CountyDeviceTokens contains counts of device tokens of users in a county
+type CountyDeviceTokens struct {
+	County               string   `protobuf:"bytes,1,opt,name=county,proto3" json:"county,omitempty"`
+	Users                int64    `protobuf:"varint,2,opt,name=users,proto3" json:"users,omitempty"`
+	StaleTokens          int64    `protobuf:"varint,3,opt,name=stale_tokens,json=staleTokens,proto3" json:"stale_tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CountyDeviceTokens) Reset()         { *m = CountyDeviceTokens{} }
+func (m *CountyDeviceTokens) String() string { return proto.CompactTextString(m) }
+func (*CountyDeviceTokens) ProtoMessage()    {}
+func (*CountyDeviceTokens) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{17}
+}
+
+func (m *CountyDeviceTokens) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CountyDeviceTokens.Unmarshal(m, b)
+}
+func (m *CountyDeviceTokens) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CountyDeviceTokens.Marshal(b, m, deterministic)
+}
+func (m *CountyDeviceTokens) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountyDeviceTokens.Merge(m, src)
+}
+func (m *CountyDeviceTokens) XXX_Size() int {
+	return xxx_messageInfo_CountyDeviceTokens.Size(m)
+}
+func (m *CountyDeviceTokens) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountyDeviceTokens.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountyDeviceTokens proto.InternalMessageInfo
+
+func (m *CountyDeviceTokens) GetCounty() string {
+	if m != nil {
+		return m.County
+	}
+	return ""
+}
+
+func (m *CountyDeviceTokens) GetUsers() int64 {
+	if m != nil {
+		return m.Users
+	}
+	return 0
+}
+
+func (m *CountyDeviceTokens) GetStaleTokens() int64 {
+	if m != nil {
+		return m.StaleTokens
+	}
+	return 0
+}
+
+// StaleDeviceTokens contains counts of users whose device tokens were cleared after FCM reported them invalid
+type StaleDeviceTokens struct {
+	Counties             []*CountyDeviceTokens `protobuf:"bytes,1,rep,name=counties,proto3" json:"counties,omitempty"`
+	StaleTokens          int64                 `protobuf:"varint,2,opt,name=stale_tokens,json=staleTokens,proto3" json:"stale_tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *StaleDeviceTokens) Reset()         { *m = StaleDeviceTokens{} }
+func (m *StaleDeviceTokens) String() string { return proto.CompactTextString(m) }
+func (*StaleDeviceTokens) ProtoMessage()    {}
+func (*StaleDeviceTokens) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{18}
+}
+
+func (m *StaleDeviceTokens) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StaleDeviceTokens.Unmarshal(m, b)
+}
+func (m *StaleDeviceTokens) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StaleDeviceTokens.Marshal(b, m, deterministic)
+}
+func (m *StaleDeviceTokens) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StaleDeviceTokens.Merge(m, src)
+}
+func (m *StaleDeviceTokens) XXX_Size() int {
+	return xxx_messageInfo_StaleDeviceTokens.Size(m)
+}
+func (m *StaleDeviceTokens) XXX_DiscardUnknown() {
+	xxx_messageInfo_StaleDeviceTokens.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StaleDeviceTokens proto.InternalMessageInfo
+
+func (m *StaleDeviceTokens) GetCounties() []*CountyDeviceTokens {
+	if m != nil {
+		return m.Counties
+	}
+	return nil
+}
+
+func (m *StaleDeviceTokens) GetStaleTokens() int64 {
+	if m != nil {
+		return m.StaleTokens
+	}
+	return 0
+}
+
+// BroadcastBatch is what FCM reported for a multicast message sent to a batch of devices
+type BroadcastBatch struct {
+	Tokens               int32    `protobuf:"varint,1,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	Success              int32    `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Failure              int32    `protobuf:"varint,3,opt,name=failure,proto3" json:"failure,omitempty"`
+	CanonicalIds         int32    `protobuf:"varint,4,opt,name=canonical_ids,json=canonicalIds,proto3" json:"canonical_ids,omitempty"`
+	MulticastId          int64    `protobuf:"varint,5,opt,name=multicast_id,json=multicastId,proto3" json:"multicast_id,omitempty"`
+	Error                string   `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Timestamp            int64    `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BroadcastBatch) Reset()         { *m = BroadcastBatch{} }
+func (m *BroadcastBatch) String() string { return proto.CompactTextString(m) }
+func (*BroadcastBatch) ProtoMessage()    {}
+func (*BroadcastBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{19}
+}
+
+func (m *BroadcastBatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BroadcastBatch.Unmarshal(m, b)
+}
+func (m *BroadcastBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BroadcastBatch.Marshal(b, m, deterministic)
+}
+func (m *BroadcastBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BroadcastBatch.Merge(m, src)
+}
+func (m *BroadcastBatch) XXX_Size() int {
+	return xxx_messageInfo_BroadcastBatch.Size(m)
+}
+func (m *BroadcastBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_BroadcastBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BroadcastBatch proto.InternalMessageInfo
+
+func (m *BroadcastBatch) GetTokens() int32 {
+	if m != nil {
+		return m.Tokens
+	}
+	return 0
+}
+
+func (m *BroadcastBatch) GetSuccess() int32 {
+	if m != nil {
+		return m.Success
+	}
+	return 0
+}
+
+func (m *BroadcastBatch) GetFailure() int32 {
+	if m != nil {
+		return m.Failure
+	}
+	return 0
+}
+
+func (m *BroadcastBatch) GetCanonicalIds() int32 {
+	if m != nil {
+		return m.CanonicalIds
+	}
+	return 0
+}
+
+func (m *BroadcastBatch) GetMulticastId() int64 {
+	if m != nil {
+		return m.MulticastId
+	}
+	return 0
+}
+
+func (m *BroadcastBatch) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *BroadcastBatch) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+// Broadcast is a message broadcasted to users together with its progress
+type Broadcast struct {
+	BroadcastMessageId string                   `protobuf:"bytes,1,opt,name=broadcast_message_id,json=broadcastMessageId,proto3" json:"broadcast_message_id,omitempty"`
+	Title              string                   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Message            string                   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Type               MessageType              `protobuf:"varint,4,opt,name=type,proto3,enum=covitrace.MessageType" json:"type,omitempty"`
+	Filters            []BroadCastMessageFilter `protobuf:"varint,5,rep,packed,name=filters,proto3,enum=covitrace.BroadCastMessageFilter" json:"filters,omitempty"`
+	Topics             []string                 `protobuf:"bytes,6,rep,name=topics,proto3" json:"topics,omitempty"`
+	Payload            map[string]string        `protobuf:"bytes,7,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Status             BroadcastStatus          `protobuf:"varint,8,opt,name=status,proto3,enum=covitrace.BroadcastStatus" json:"status,omitempty"`
+	// Users matching the filters when the broadcast was created
+	Recipients int64 `protobuf:"varint,9,opt,name=recipients,proto3" json:"recipients,omitempty"`
+	// Users the broadcast was sent to, and whether it was delivered to them
+	Processed int64  `protobuf:"varint,10,opt,name=processed,proto3" json:"processed,omitempty"`
+	Delivered int64  `protobuf:"varint,11,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Failed    int64  `protobuf:"varint,12,opt,name=failed,proto3" json:"failed,omitempty"`
+	LastError string `protobuf:"bytes,13,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// Multicast messages sent, only set when retrieving a single broadcast
+	Batches           []*BroadcastBatch `protobuf:"bytes,14,rep,name=batches,proto3" json:"batches,omitempty"`
+	CreatedTimestamp  int64             `protobuf:"varint,15,opt,name=created_timestamp,json=createdTimestamp,proto3" json:"created_timestamp,omitempty"`
+	StartedTimestamp  int64             `protobuf:"varint,16,opt,name=started_timestamp,json=startedTimestamp,proto3" json:"started_timestamp,omitempty"`
+	FinishedTimestamp int64             `protobuf:"varint,17,opt,name=finished_timestamp,json=finishedTimestamp,proto3" json:"finished_timestamp,omitempty"`
+	ScheduleId        string            `protobuf:"bytes,18,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Audience          *Audience         `protobuf:"bytes,19,opt,name=audience,proto3" json:"audience,omitempty"`
+	// FCM topic the broadcast was pushed to, users without push notifications are still sent it one by one
+	Topic                string   `protobuf:"bytes,20,opt,name=topic,proto3" json:"topic,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Broadcast) Reset()         { *m = Broadcast{} }
+func (m *Broadcast) String() string { return proto.CompactTextString(m) }
+func (*Broadcast) ProtoMessage()    {}
+func (*Broadcast) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{20}
+}
+
+func (m *Broadcast) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Broadcast.Unmarshal(m, b)
+}
+func (m *Broadcast) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Broadcast.Marshal(b, m, deterministic)
+}
+func (m *Broadcast) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Broadcast.Merge(m, src)
+}
+func (m *Broadcast) XXX_Size() int {
+	return xxx_messageInfo_Broadcast.Size(m)
+}
+func (m *Broadcast) XXX_DiscardUnknown() {
+	xxx_messageInfo_Broadcast.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Broadcast proto.InternalMessageInfo
+
+func (m *Broadcast) GetBroadcastMessageId() string {
+	if m != nil {
+		return m.BroadcastMessageId
+	}
+	return ""
+}
+
+func (m *Broadcast) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *Broadcast) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Broadcast) GetType() MessageType {
+	if m != nil {
+		return m.Type
+	}
+	return MessageType_ANY
+}
+
+func (m *Broadcast) GetFilters() []BroadCastMessageFilter {
+	if m != nil {
+		return m.Filters
+	}
+	return nil
+}
+
+func (m *Broadcast) GetTopics() []string {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *Broadcast) GetPayload() map[string]string {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *Broadcast) GetStatus() BroadcastStatus {
+	if m != nil {
+		return m.Status
+	}
+	return BroadcastStatus_BROADCAST_PENDING
+}
+
+func (m *Broadcast) GetRecipients() int64 {
+	if m != nil {
+		return m.Recipients
+	}
+	return 0
+}
+
+func (m *Broadcast) GetProcessed() int64 {
+	if m != nil {
+		return m.Processed
+	}
+	return 0
+}
+
+func (m *Broadcast) GetDelivered() int64 {
+	if m != nil {
+		return m.Delivered
+	}
+	return 0
+}
+
+func (m *Broadcast) GetFailed() int64 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *Broadcast) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *Broadcast) GetBatches() []*BroadcastBatch {
+	if m != nil {
+		return m.Batches
+	}
+	return nil
+}
+
+func (m *Broadcast) GetCreatedTimestamp() int64 {
+	if m != nil {
+		return m.CreatedTimestamp
+	}
+	return 0
+}
+
+func (m *Broadcast) GetStartedTimestamp() int64 {
+	if m != nil {
+		return m.StartedTimestamp
+	}
+	return 0
+}
+
+func (m *Broadcast) GetFinishedTimestamp() int64 {
+	if m != nil {
+		return m.FinishedTimestamp
+	}
+	return 0
+}
+
+func (m *Broadcast) GetScheduleId() string {
+	if m != nil {
+		return m.ScheduleId
+	}
+	return ""
+}
+
+func (m *Broadcast) GetAudience() *Audience {
+	if m != nil {
+		return m.Audience
+	}
+	return nil
+}
+
+func (m *Broadcast) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+// GetBroadcastRequest is request to retrieve a broadcast
+type GetBroadcastRequest struct {
+	BroadcastMessageId   string   `protobuf:"bytes,1,opt,name=broadcast_message_id,json=broadcastMessageId,proto3" json:"broadcast_message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBroadcastRequest) Reset()         { *m = GetBroadcastRequest{} }
+func (m *GetBroadcastRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBroadcastRequest) ProtoMessage()    {}
+func (*GetBroadcastRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{21}
+}
+
+func (m *GetBroadcastRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBroadcastRequest.Unmarshal(m, b)
+}
+func (m *GetBroadcastRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBroadcastRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBroadcastRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBroadcastRequest.Merge(m, src)
+}
+func (m *GetBroadcastRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBroadcastRequest.Size(m)
+}
+func (m *GetBroadcastRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBroadcastRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBroadcastRequest proto.InternalMessageInfo
+
+func (m *GetBroadcastRequest) GetBroadcastMessageId() string {
+	if m != nil {
+		return m.BroadcastMessageId
+	}
+	return ""
+}
+
+// ListBroadcastsRequest is request to retrieve broadcasts, most recent first
+type ListBroadcastsRequest struct {
+	PageToken            int32             `protobuf:"varint,1,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	PageSize             int32             `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	FilterStatus         []BroadcastStatus `protobuf:"varint,3,rep,packed,name=filter_status,json=filterStatus,proto3,enum=covitrace.BroadcastStatus" json:"filter_status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ListBroadcastsRequest) Reset()         { *m = ListBroadcastsRequest{} }
+func (m *ListBroadcastsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListBroadcastsRequest) ProtoMessage()    {}
+func (*ListBroadcastsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{22}
+}
+
+func (m *ListBroadcastsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBroadcastsRequest.Unmarshal(m, b)
+}
+func (m *ListBroadcastsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBroadcastsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListBroadcastsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBroadcastsRequest.Merge(m, src)
+}
+func (m *ListBroadcastsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListBroadcastsRequest.Size(m)
+}
+func (m *ListBroadcastsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBroadcastsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBroadcastsRequest proto.InternalMessageInfo
+
+func (m *ListBroadcastsRequest) GetPageToken() int32 {
+	if m != nil {
+		return m.PageToken
+	}
+	return 0
+}
+
+func (m *ListBroadcastsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListBroadcastsRequest) GetFilterStatus() []BroadcastStatus {
+	if m != nil {
+		return m.FilterStatus
+	}
+	return nil
+}
+
+// Broadcasts is a collection of broadcasts
+type Broadcasts struct {
+	Broadcasts           []*Broadcast `protobuf:"bytes,1,rep,name=broadcasts,proto3" json:"broadcasts,omitempty"`
+	NextPageToken        int32        `protobuf:"varint,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Broadcasts) Reset()         { *m = Broadcasts{} }
+func (m *Broadcasts) String() string { return proto.CompactTextString(m) }
+func (*Broadcasts) ProtoMessage()    {}
+func (*Broadcasts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{23}
+}
+
+func (m *Broadcasts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Broadcasts.Unmarshal(m, b)
+}
+func (m *Broadcasts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Broadcasts.Marshal(b, m, deterministic)
+}
+func (m *Broadcasts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Broadcasts.Merge(m, src)
+}
+func (m *Broadcasts) XXX_Size() int {
+	return xxx_messageInfo_Broadcasts.Size(m)
+}
+func (m *Broadcasts) XXX_DiscardUnknown() {
+	xxx_messageInfo_Broadcasts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Broadcasts proto.InternalMessageInfo
+
+func (m *Broadcasts) GetBroadcasts() []*Broadcast {
+	if m != nil {
+		return m.Broadcasts
+	}
+	return nil
+}
+
+func (m *Broadcasts) GetNextPageToken() int32 {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return 0
+}
+
+// CancelBroadcastRequest is request to stop sending a broadcast to users it has not been sent to
+type CancelBroadcastRequest struct {
+	BroadcastMessageId   string   `protobuf:"bytes,1,opt,name=broadcast_message_id,json=broadcastMessageId,proto3" json:"broadcast_message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelBroadcastRequest) Reset()         { *m = CancelBroadcastRequest{} }
+func (m *CancelBroadcastRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelBroadcastRequest) ProtoMessage()    {}
+func (*CancelBroadcastRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{24}
+}
+
+func (m *CancelBroadcastRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelBroadcastRequest.Unmarshal(m, b)
+}
+func (m *CancelBroadcastRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelBroadcastRequest.Marshal(b, m, deterministic)
+}
+func (m *CancelBroadcastRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelBroadcastRequest.Merge(m, src)
+}
+func (m *CancelBroadcastRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelBroadcastRequest.Size(m)
+}
+func (m *CancelBroadcastRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelBroadcastRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelBroadcastRequest proto.InternalMessageInfo
+
+func (m *CancelBroadcastRequest) GetBroadcastMessageId() string {
+	if m != nil {
+		return m.BroadcastMessageId
+	}
+	return ""
+}
+
+// Schedule is a scheduled broadcast
+type Schedule struct {
+	ScheduleId string                   `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Title      string                   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Message    string                   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Type       MessageType              `protobuf:"varint,4,opt,name=type,proto3,enum=covitrace.MessageType" json:"type,omitempty"`
+	Filters    []BroadCastMessageFilter `protobuf:"varint,5,rep,packed,name=filters,proto3,enum=covitrace.BroadCastMessageFilter" json:"filters,omitempty"`
+	Topics     []string                 `protobuf:"bytes,6,rep,name=topics,proto3" json:"topics,omitempty"`
+	Payload    map[string]string        `protobuf:"bytes,7,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Schedule   *BroadcastSchedule       `protobuf:"bytes,8,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Paused     bool                     `protobuf:"varint,9,opt,name=paused,proto3" json:"paused,omitempty"`
+	// Not set once the schedule has ended
+	NextRunTimestamp       int64     `protobuf:"varint,10,opt,name=next_run_timestamp,json=nextRunTimestamp,proto3" json:"next_run_timestamp,omitempty"`
+	LastRunTimestamp       int64     `protobuf:"varint,11,opt,name=last_run_timestamp,json=lastRunTimestamp,proto3" json:"last_run_timestamp,omitempty"`
+	LastBroadcastMessageId string    `protobuf:"bytes,12,opt,name=last_broadcast_message_id,json=lastBroadcastMessageId,proto3" json:"last_broadcast_message_id,omitempty"`
+	Runs                   int64     `protobuf:"varint,13,opt,name=runs,proto3" json:"runs,omitempty"`
+	CreatedTimestamp       int64     `protobuf:"varint,14,opt,name=created_timestamp,json=createdTimestamp,proto3" json:"created_timestamp,omitempty"`
+	Audience               *Audience `protobuf:"bytes,15,opt,name=audience,proto3" json:"audience,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{}  `json:"-"`
+	XXX_unrecognized       []byte    `json:"-"`
+	XXX_sizecache          int32     `json:"-"`
+}
+
+func (m *Schedule) Reset()         { *m = Schedule{} }
+func (m *Schedule) String() string { return proto.CompactTextString(m) }
+func (*Schedule) ProtoMessage()    {}
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{25}
+}
+
+func (m *Schedule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Schedule.Unmarshal(m, b)
+}
+func (m *Schedule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Schedule.Marshal(b, m, deterministic)
+}
+func (m *Schedule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schedule.Merge(m, src)
+}
+func (m *Schedule) XXX_Size() int {
+	return xxx_messageInfo_Schedule.Size(m)
+}
+func (m *Schedule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schedule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schedule proto.InternalMessageInfo
+
+func (m *Schedule) GetScheduleId() string {
+	if m != nil {
+		return m.ScheduleId
+	}
+	return ""
+}
+
+func (m *Schedule) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *Schedule) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Schedule) GetType() MessageType {
+	if m != nil {
+		return m.Type
+	}
+	return MessageType_ANY
+}
+
+func (m *Schedule) GetFilters() []BroadCastMessageFilter {
+	if m != nil {
+		return m.Filters
+	}
+	return nil
+}
+
+func (m *Schedule) GetTopics() []string {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *Schedule) GetPayload() map[string]string {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *Schedule) GetSchedule() *BroadcastSchedule {
+	if m != nil {
+		return m.Schedule
+	}
+	return nil
+}
+
+func (m *Schedule) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+func (m *Schedule) GetNextRunTimestamp() int64 {
+	if m != nil {
+		return m.NextRunTimestamp
+	}
+	return 0
+}
+
+func (m *Schedule) GetLastRunTimestamp() int64 {
+	if m != nil {
+		return m.LastRunTimestamp
+	}
+	return 0
+}
+
+func (m *Schedule) GetLastBroadcastMessageId() string {
+	if m != nil {
+		return m.LastBroadcastMessageId
+	}
+	return ""
+}
+
+func (m *Schedule) GetRuns() int64 {
+	if m != nil {
+		return m.Runs
+	}
+	return 0
+}
+
+func (m *Schedule) GetCreatedTimestamp() int64 {
+	if m != nil {
+		return m.CreatedTimestamp
+	}
+	return 0
+}
+
+func (m *Schedule) GetAudience() *Audience {
+	if m != nil {
+		return m.Audience
+	}
+	return nil
+}
+
+// ListSchedulesRequest is request to retrieve scheduled broadcasts, most recent first
+type ListSchedulesRequest struct {
+	PageToken            int32    `protobuf:"varint,1,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	PageSize             int32    `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSchedulesRequest) Reset()         { *m = ListSchedulesRequest{} }
+func (m *ListSchedulesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSchedulesRequest) ProtoMessage()    {}
+func (*ListSchedulesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{26}
+}
+
+func (m *ListSchedulesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSchedulesRequest.Unmarshal(m, b)
+}
+func (m *ListSchedulesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSchedulesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSchedulesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSchedulesRequest.Merge(m, src)
+}
+func (m *ListSchedulesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSchedulesRequest.Size(m)
+}
+func (m *ListSchedulesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSchedulesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSchedulesRequest proto.InternalMessageInfo
+
+func (m *ListSchedulesRequest) GetPageToken() int32 {
+	if m != nil {
+		return m.PageToken
+	}
+	return 0
+}
+
+func (m *ListSchedulesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+// Schedules is a collection of scheduled broadcasts
+type Schedules struct {
+	Schedules            []*Schedule `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	NextPageToken        int32       `protobuf:"varint,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Schedules) Reset()         { *m = Schedules{} }
+func (m *Schedules) String() string { return proto.CompactTextString(m) }
+func (*Schedules) ProtoMessage()    {}
+func (*Schedules) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{27}
+}
+
+func (m *Schedules) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Schedules.Unmarshal(m, b)
+}
+func (m *Schedules) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Schedules.Marshal(b, m, deterministic)
+}
+func (m *Schedules) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schedules.Merge(m, src)
+}
+func (m *Schedules) XXX_Size() int {
+	return xxx_messageInfo_Schedules.Size(m)
+}
+func (m *Schedules) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schedules.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schedules proto.InternalMessageInfo
+
+func (m *Schedules) GetSchedules() []*Schedule {
+	if m != nil {
+		return m.Schedules
+	}
+	return nil
+}
+
+func (m *Schedules) GetNextPageToken() int32 {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return 0
+}
+
+// SyncTopicSubscriptionsRequest is request to subscribe the devices of a user to the FCM topics of their county, status and language
+type SyncTopicSubscriptionsRequest struct {
+	// Devices of every user are synced in the background when empty
+	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SyncTopicSubscriptionsRequest) Reset()         { *m = SyncTopicSubscriptionsRequest{} }
+func (m *SyncTopicSubscriptionsRequest) String() string { return proto.CompactTextString(m) }
+func (*SyncTopicSubscriptionsRequest) ProtoMessage()    {}
+func (*SyncTopicSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{28}
+}
+
+func (m *SyncTopicSubscriptionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncTopicSubscriptionsRequest.Unmarshal(m, b)
+}
+func (m *SyncTopicSubscriptionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncTopicSubscriptionsRequest.Marshal(b, m, deterministic)
+}
+func (m *SyncTopicSubscriptionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncTopicSubscriptionsRequest.Merge(m, src)
+}
+func (m *SyncTopicSubscriptionsRequest) XXX_Size() int {
+	return xxx_messageInfo_SyncTopicSubscriptionsRequest.Size(m)
+}
+func (m *SyncTopicSubscriptionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncTopicSubscriptionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncTopicSubscriptionsRequest proto.InternalMessageInfo
+
+func (m *SyncTopicSubscriptionsRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+// ScheduleRequest is request for a scheduled broadcast
+type ScheduleRequest struct {
+	ScheduleId           string   `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScheduleRequest) Reset()         { *m = ScheduleRequest{} }
+func (m *ScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*ScheduleRequest) ProtoMessage()    {}
+func (*ScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{29}
+}
+
+func (m *ScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleRequest.Unmarshal(m, b)
+}
+func (m *ScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleRequest.Marshal(b, m, deterministic)
+}
+func (m *ScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleRequest.Merge(m, src)
+}
+func (m *ScheduleRequest) XXX_Size() int {
+	return xxx_messageInfo_ScheduleRequest.Size(m)
+}
+func (m *ScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleRequest proto.InternalMessageInfo
+
+func (m *ScheduleRequest) GetScheduleId() string {
+	if m != nil {
+		return m.ScheduleId
+	}
+	return ""
+}
+
+// TemplateVariable is a variable a template is rendered with
+type TemplateVariable struct {
+	Name                 string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 TemplateVariableType `protobuf:"varint,2,opt,name=type,proto3,enum=covitrace.TemplateVariableType" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *TemplateVariable) Reset()         { *m = TemplateVariable{} }
+func (m *TemplateVariable) String() string { return proto.CompactTextString(m) }
+func (*TemplateVariable) ProtoMessage()    {}
+func (*TemplateVariable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{30}
+}
+
+func (m *TemplateVariable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TemplateVariable.Unmarshal(m, b)
+}
+func (m *TemplateVariable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TemplateVariable.Marshal(b, m, deterministic)
+}
+func (m *TemplateVariable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TemplateVariable.Merge(m, src)
+}
+func (m *TemplateVariable) XXX_Size() int {
+	return xxx_messageInfo_TemplateVariable.Size(m)
+}
+func (m *TemplateVariable) XXX_DiscardUnknown() {
+	xxx_messageInfo_TemplateVariable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TemplateVariable proto.InternalMessageInfo
+
+func (m *TemplateVariable) GetName() string {
 	if m != nil {
-		return m.PhoneNumber
+		return m.Name
 	}
 	return ""
 }
 
-// NewMessagesCount contains the count of new messages
-type NewMessagesCount struct {
-	Count                int32    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+func (m *TemplateVariable) GetType() TemplateVariableType {
+	if m != nil {
+		return m.Type
+	}
+	return TemplateVariableType_STRING
+}
+
+// MessageTemplate is the wording of a message in a language, written as a Go template e.g "Hello {{.FullName}}"
+type MessageTemplate struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// One of en, sw or sheng
+	Language             string              `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Title                string              `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Body                 string              `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	Variables            []*TemplateVariable `protobuf:"bytes,5,rep,name=variables,proto3" json:"variables,omitempty"`
+	UpdatedTimestamp     int64               `protobuf:"varint,6,opt,name=updated_timestamp,json=updatedTimestamp,proto3" json:"updated_timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *MessageTemplate) Reset()         { *m = MessageTemplate{} }
+func (m *MessageTemplate) String() string { return proto.CompactTextString(m) }
+func (*MessageTemplate) ProtoMessage()    {}
+func (*MessageTemplate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{31}
+}
+
+func (m *MessageTemplate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageTemplate.Unmarshal(m, b)
+}
+func (m *MessageTemplate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageTemplate.Marshal(b, m, deterministic)
+}
+func (m *MessageTemplate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageTemplate.Merge(m, src)
+}
+func (m *MessageTemplate) XXX_Size() int {
+	return xxx_messageInfo_MessageTemplate.Size(m)
+}
+func (m *MessageTemplate) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageTemplate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageTemplate proto.InternalMessageInfo
+
+func (m *MessageTemplate) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *MessageTemplate) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+func (m *MessageTemplate) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *MessageTemplate) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+func (m *MessageTemplate) GetVariables() []*TemplateVariable {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+func (m *MessageTemplate) GetUpdatedTimestamp() int64 {
+	if m != nil {
+		return m.UpdatedTimestamp
+	}
+	return 0
+}
+
+// CreateTemplateRequest is request to add a template or a language variant of a template
+type CreateTemplateRequest struct {
+	Template             *MessageTemplate `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *CreateTemplateRequest) Reset()         { *m = CreateTemplateRequest{} }
+func (m *CreateTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateTemplateRequest) ProtoMessage()    {}
+func (*CreateTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{32}
+}
+
+func (m *CreateTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTemplateRequest.Unmarshal(m, b)
+}
+func (m *CreateTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTemplateRequest.Merge(m, src)
+}
+func (m *CreateTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateTemplateRequest.Size(m)
+}
+func (m *CreateTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTemplateRequest proto.InternalMessageInfo
+
+func (m *CreateTemplateRequest) GetTemplate() *MessageTemplate {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+// UpdateTemplateRequest is request to change the wording of a template
+type UpdateTemplateRequest struct {
+	Template             *MessageTemplate `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *UpdateTemplateRequest) Reset()         { *m = UpdateTemplateRequest{} }
+func (m *UpdateTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateTemplateRequest) ProtoMessage()    {}
+func (*UpdateTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{33}
+}
+
+func (m *UpdateTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTemplateRequest.Unmarshal(m, b)
+}
+func (m *UpdateTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTemplateRequest.Merge(m, src)
+}
+func (m *UpdateTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateTemplateRequest.Size(m)
+}
+func (m *UpdateTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTemplateRequest proto.InternalMessageInfo
+
+func (m *UpdateTemplateRequest) GetTemplate() *MessageTemplate {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+// TemplateRequest is request for the language variant of a template
+type TemplateRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Language             string   `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *NewMessagesCount) Reset()         { *m = NewMessagesCount{} }
-func (m *NewMessagesCount) String() string { return proto.CompactTextString(m) }
-func (*NewMessagesCount) ProtoMessage()    {}
-func (*NewMessagesCount) Descriptor() ([]byte, []int) {
-	return fileDescriptor_42a1718997f046ec, []int{8}
+func (m *TemplateRequest) Reset()         { *m = TemplateRequest{} }
+func (m *TemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*TemplateRequest) ProtoMessage()    {}
+func (*TemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{34}
 }
 
-func (m *NewMessagesCount) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_NewMessagesCount.Unmarshal(m, b)
+func (m *TemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TemplateRequest.Unmarshal(m, b)
 }
-func (m *NewMessagesCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_NewMessagesCount.Marshal(b, m, deterministic)
+func (m *TemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TemplateRequest.Marshal(b, m, deterministic)
 }
-func (m *NewMessagesCount) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NewMessagesCount.Merge(m, src)
+func (m *TemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TemplateRequest.Merge(m, src)
 }
-func (m *NewMessagesCount) XXX_Size() int {
-	return xxx_messageInfo_NewMessagesCount.Size(m)
+func (m *TemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_TemplateRequest.Size(m)
 }
-func (m *NewMessagesCount) XXX_DiscardUnknown() {
-	xxx_messageInfo_NewMessagesCount.DiscardUnknown(m)
+func (m *TemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TemplateRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_NewMessagesCount proto.InternalMessageInfo
+var xxx_messageInfo_TemplateRequest proto.InternalMessageInfo
 
-func (m *NewMessagesCount) GetCount() int32 {
+func (m *TemplateRequest) GetName() string {
 	if m != nil {
-		return m.Count
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TemplateRequest) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+// ListTemplatesRequest is request to retrieve templates
+type ListTemplatesRequest struct {
+	PageToken            int32    `protobuf:"varint,1,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	PageSize             int32    `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	FilterName           string   `protobuf:"bytes,3,opt,name=filter_name,json=filterName,proto3" json:"filter_name,omitempty"`
+	FilterLanguage       string   `protobuf:"bytes,4,opt,name=filter_language,json=filterLanguage,proto3" json:"filter_language,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTemplatesRequest) Reset()         { *m = ListTemplatesRequest{} }
+func (m *ListTemplatesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTemplatesRequest) ProtoMessage()    {}
+func (*ListTemplatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{35}
+}
+
+func (m *ListTemplatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTemplatesRequest.Unmarshal(m, b)
+}
+func (m *ListTemplatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTemplatesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTemplatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTemplatesRequest.Merge(m, src)
+}
+func (m *ListTemplatesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTemplatesRequest.Size(m)
+}
+func (m *ListTemplatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTemplatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTemplatesRequest proto.InternalMessageInfo
+
+func (m *ListTemplatesRequest) GetPageToken() int32 {
+	if m != nil {
+		return m.PageToken
+	}
+	return 0
+}
+
+func (m *ListTemplatesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListTemplatesRequest) GetFilterName() string {
+	if m != nil {
+		return m.FilterName
+	}
+	return ""
+}
+
+func (m *ListTemplatesRequest) GetFilterLanguage() string {
+	if m != nil {
+		return m.FilterLanguage
+	}
+	return ""
+}
+
+// MessageTemplates is a collection of templates
+type MessageTemplates struct {
+	Templates            []*MessageTemplate `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	NextPageToken        int32              `protobuf:"varint,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *MessageTemplates) Reset()         { *m = MessageTemplates{} }
+func (m *MessageTemplates) String() string { return proto.CompactTextString(m) }
+func (*MessageTemplates) ProtoMessage()    {}
+func (*MessageTemplates) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42a1718997f046ec, []int{36}
+}
+
+func (m *MessageTemplates) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageTemplates.Unmarshal(m, b)
+}
+func (m *MessageTemplates) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageTemplates.Marshal(b, m, deterministic)
+}
+func (m *MessageTemplates) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageTemplates.Merge(m, src)
+}
+func (m *MessageTemplates) XXX_Size() int {
+	return xxx_messageInfo_MessageTemplates.Size(m)
+}
+func (m *MessageTemplates) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageTemplates.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageTemplates proto.InternalMessageInfo
+
+func (m *MessageTemplates) GetTemplates() []*MessageTemplate {
+	if m != nil {
+		return m.Templates
+	}
+	return nil
+}
+
+func (m *MessageTemplates) GetNextPageToken() int32 {
+	if m != nil {
+		return m.NextPageToken
 	}
 	return 0
 }
 
 func init() {
 	proto.RegisterEnum("covitrace.BroadCastMessageFilter", BroadCastMessageFilter_name, BroadCastMessageFilter_value)
+	proto.RegisterEnum("covitrace.QuarantineState", QuarantineState_name, QuarantineState_value)
 	proto.RegisterEnum("covitrace.MessageType", MessageType_name, MessageType_value)
+	proto.RegisterEnum("covitrace.DeliveryStatus", DeliveryStatus_name, DeliveryStatus_value)
+	proto.RegisterEnum("covitrace.BroadcastStatus", BroadcastStatus_name, BroadcastStatus_value)
+	proto.RegisterEnum("covitrace.TemplateVariableType", TemplateVariableType_name, TemplateVariableType_value)
 	proto.RegisterType((*ContactData)(nil), "covitrace.ContactData")
 	proto.RegisterType((*BroadCastMessageResponse)(nil), "covitrace.BroadCastMessageResponse")
 	proto.RegisterType((*BroadCastMessageRequest)(nil), "covitrace.BroadCastMessageRequest")
 	proto.RegisterMapType((map[string]string)(nil), "covitrace.BroadCastMessageRequest.PayloadEntry")
+	proto.RegisterType((*Audience)(nil), "covitrace.Audience")
+	proto.RegisterType((*EstimateAudienceRequest)(nil), "covitrace.EstimateAudienceRequest")
+	proto.RegisterType((*AudienceEstimate)(nil), "covitrace.AudienceEstimate")
+	proto.RegisterType((*BroadcastSchedule)(nil), "covitrace.BroadcastSchedule")
 	proto.RegisterType((*Message)(nil), "covitrace.Message")
 	proto.RegisterMapType((map[string]string)(nil), "covitrace.Message.DataEntry")
+	proto.RegisterMapType((map[string]string)(nil), "covitrace.Message.TemplateVariablesEntry")
+	proto.RegisterType((*DeliveryAttempt)(nil), "covitrace.DeliveryAttempt")
+	proto.RegisterType((*GetMessageDeliveryStatusRequest)(nil), "covitrace.GetMessageDeliveryStatusRequest")
+	proto.RegisterType((*MessageDeliveryStatus)(nil), "covitrace.MessageDeliveryStatus")
 	proto.RegisterType((*SendMessageResponse)(nil), "covitrace.SendMessageResponse")
 	proto.RegisterType((*ListMessagesRequest)(nil), "covitrace.ListMessagesRequest")
 	proto.RegisterType((*Messages)(nil), "covitrace.Messages")
 	proto.RegisterType((*MessageRequest)(nil), "covitrace.MessageRequest")
 	proto.RegisterType((*NewMessagesCount)(nil), "covitrace.NewMessagesCount")
+	proto.RegisterType((*CountStaleDeviceTokensRequest)(nil), "covitrace.CountStaleDeviceTokensRequest")
+	proto.RegisterType((*CountyDeviceTokens)(nil), "covitrace.CountyDeviceTokens")
+	proto.RegisterType((*StaleDeviceTokens)(nil), "covitrace.StaleDeviceTokens")
+	proto.RegisterType((*BroadcastBatch)(nil), "covitrace.BroadcastBatch")
+	proto.RegisterType((*Broadcast)(nil), "covitrace.Broadcast")
+	proto.RegisterMapType((map[string]string)(nil), "covitrace.Broadcast.PayloadEntry")
+	proto.RegisterType((*GetBroadcastRequest)(nil), "covitrace.GetBroadcastRequest")
+	proto.RegisterType((*ListBroadcastsRequest)(nil), "covitrace.ListBroadcastsRequest")
+	proto.RegisterType((*Broadcasts)(nil), "covitrace.Broadcasts")
+	proto.RegisterType((*CancelBroadcastRequest)(nil), "covitrace.CancelBroadcastRequest")
+	proto.RegisterType((*Schedule)(nil), "covitrace.Schedule")
+	proto.RegisterMapType((map[string]string)(nil), "covitrace.Schedule.PayloadEntry")
+	proto.RegisterType((*ListSchedulesRequest)(nil), "covitrace.ListSchedulesRequest")
+	proto.RegisterType((*Schedules)(nil), "covitrace.Schedules")
+	proto.RegisterType((*SyncTopicSubscriptionsRequest)(nil), "covitrace.SyncTopicSubscriptionsRequest")
+	proto.RegisterType((*ScheduleRequest)(nil), "covitrace.ScheduleRequest")
+	proto.RegisterType((*TemplateVariable)(nil), "covitrace.TemplateVariable")
+	proto.RegisterType((*MessageTemplate)(nil), "covitrace.MessageTemplate")
+	proto.RegisterType((*CreateTemplateRequest)(nil), "covitrace.CreateTemplateRequest")
+	proto.RegisterType((*UpdateTemplateRequest)(nil), "covitrace.UpdateTemplateRequest")
+	proto.RegisterType((*TemplateRequest)(nil), "covitrace.TemplateRequest")
+	proto.RegisterType((*ListTemplatesRequest)(nil), "covitrace.ListTemplatesRequest")
+	proto.RegisterType((*MessageTemplates)(nil), "covitrace.MessageTemplates")
 }
 
 func init() { proto.RegisterFile("messaging.proto", fileDescriptor_42a1718997f046ec) }
 
 var fileDescriptor_42a1718997f046ec = []byte{
-	// 1031 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x4f, 0x6f, 0xe3, 0x44,
-	0x14, 0x5f, 0xe7, 0x4f, 0x13, 0xbf, 0xa4, 0x25, 0x9a, 0xae, 0xba, 0x26, 0x29, 0x28, 0x75, 0x25,
-	0x14, 0x0a, 0x9b, 0x6c, 0xd3, 0x95, 0x76, 0x09, 0xa7, 0xb4, 0x74, 0xab, 0x48, 0xdd, 0xb4, 0x72,
-	0x03, 0xa8, 0x5c, 0xa2, 0x69, 0xfc, 0x1a, 0xac, 0x75, 0xc6, 0xc6, 0x33, 0x69, 0x95, 0x45, 0x5c,
-	0xe0, 0xc4, 0x99, 0xcf, 0xc1, 0x17, 0xe1, 0x86, 0x38, 0x22, 0x71, 0xe2, 0x83, 0xa0, 0x99, 0xb1,
-	0x83, 0x49, 0xb2, 0x6d, 0x39, 0xd9, 0xef, 0xcd, 0x6f, 0xde, 0x7b, 0xf3, 0x7b, 0xbf, 0x37, 0x03,
-	0xef, 0x4d, 0x90, 0x73, 0x3a, 0xf6, 0xd8, 0xb8, 0x19, 0x46, 0x81, 0x08, 0x88, 0x39, 0x0a, 0x6e,
-	0x3c, 0x11, 0xd1, 0x11, 0x56, 0x6b, 0xe3, 0x20, 0x18, 0xfb, 0xd8, 0x52, 0x0b, 0x57, 0xd3, 0xeb,
-	0x16, 0x4e, 0x42, 0x31, 0xd3, 0xb8, 0xea, 0x6e, 0xbc, 0xe8, 0x07, 0x6c, 0x1c, 0x4d, 0x19, 0xf3,
-	0xd8, 0xb8, 0x15, 0x84, 0x18, 0x51, 0xe1, 0x05, 0x8c, 0xc7, 0xa0, 0xed, 0x18, 0x44, 0x43, 0xaf,
-	0x45, 0x19, 0x0b, 0xc4, 0x7f, 0x56, 0x3f, 0x55, 0x9f, 0xd1, 0xd3, 0x31, 0xb2, 0xa7, 0xfc, 0x96,
-	0x8e, 0xc7, 0x18, 0xb5, 0x82, 0x50, 0x21, 0x96, 0xd1, 0xf6, 0x6f, 0x06, 0x94, 0x8e, 0x02, 0x26,
-	0xe8, 0x48, 0x7c, 0x41, 0x05, 0x25, 0x8f, 0x21, 0x3f, 0x0a, 0xa6, 0x4c, 0x58, 0x46, 0xdd, 0x68,
-	0xe4, 0x1d, 0x6d, 0x90, 0x0f, 0x00, 0xa6, 0x1c, 0xa3, 0x61, 0xf8, 0x6d, 0xc0, 0xd0, 0xca, 0xd4,
-	0x8d, 0x86, 0xe9, 0x98, 0xd2, 0x73, 0x2e, 0x1d, 0xa4, 0x06, 0xe6, 0xf5, 0xd4, 0xf7, 0x87, 0x8c,
-	0x4e, 0xd0, 0xca, 0xaa, 0xd5, 0xa2, 0x74, 0xf4, 0xe9, 0x04, 0xc9, 0x2e, 0xac, 0x87, 0x54, 0x78,
-	0xc8, 0x44, 0xbc, 0x3d, 0xa7, 0x00, 0xe5, 0xd8, 0xa9, 0x23, 0xec, 0x40, 0xd9, 0xc5, 0x1b, 0x6f,
-	0x84, 0x43, 0x11, 0xbc, 0x41, 0x66, 0xe5, 0x15, 0xa6, 0xa4, 0x7d, 0x03, 0xe9, 0x92, 0x90, 0x91,
-	0x2e, 0x74, 0x28, 0xbc, 0x09, 0x5a, 0x6b, 0x1a, 0x12, 0xfb, 0x06, 0xde, 0x04, 0xed, 0x53, 0xb0,
-	0x0e, 0xa3, 0x80, 0xba, 0x47, 0x94, 0x8b, 0xd7, 0xaa, 0x03, 0xe8, 0x20, 0x0f, 0x03, 0xc6, 0x91,
-	0x3c, 0x83, 0xc7, 0x57, 0x72, 0x6d, 0x44, 0xb9, 0x18, 0xea, 0xf6, 0xe0, 0xd0, 0x73, 0xd5, 0x39,
-	0x4d, 0x87, 0xcc, 0xd7, 0xe2, 0x7d, 0x3d, 0xd7, 0xfe, 0x3d, 0x03, 0x4f, 0x96, 0xc3, 0x7d, 0x37,
-	0x45, 0x2e, 0x24, 0x4d, 0xc2, 0x13, 0x3e, 0xc6, 0xdb, 0xb5, 0x41, 0x2c, 0x28, 0xc4, 0x91, 0x63,
-	0x8e, 0x12, 0x93, 0xec, 0x41, 0x4e, 0xcc, 0x42, 0x4d, 0xce, 0x46, 0x7b, 0xab, 0x39, 0x97, 0x43,
-	0x33, 0x0e, 0x3c, 0x98, 0x85, 0xe8, 0x28, 0x0c, 0xf9, 0x1c, 0x0a, 0xd7, 0x9e, 0x2f, 0x30, 0xe2,
-	0x56, 0xae, 0x9e, 0x6d, 0x6c, 0xb4, 0x77, 0x52, 0xf0, 0xc5, 0x82, 0x5e, 0x29, 0xa4, 0x93, 0xec,
-	0x20, 0x5b, 0xb0, 0x26, 0x82, 0xd0, 0x1b, 0x71, 0x2b, 0x5f, 0xcf, 0x36, 0x4c, 0x27, 0xb6, 0x48,
-	0x0f, 0x0a, 0x21, 0x9d, 0xf9, 0x01, 0x75, 0xad, 0xb5, 0x7a, 0xb6, 0x51, 0x6a, 0xb7, 0xee, 0x08,
-	0x1a, 0x9f, 0xb2, 0x79, 0xae, 0x77, 0x1c, 0x33, 0x11, 0xcd, 0x9c, 0x64, 0x7f, 0xb5, 0x03, 0xe5,
-	0xf4, 0x02, 0xa9, 0x40, 0xf6, 0x0d, 0xce, 0x62, 0x26, 0xe4, 0xaf, 0x64, 0xe7, 0x86, 0xfa, 0xd3,
-	0x84, 0x05, 0x6d, 0x74, 0x32, 0x2f, 0x0d, 0xfb, 0xaf, 0x0c, 0x14, 0xe2, 0x24, 0x52, 0x54, 0x4b,
-	0x7d, 0x30, 0x27, 0x09, 0xfd, 0xf7, 0x69, 0x6e, 0xde, 0x81, 0x6c, 0xba, 0x03, 0x36, 0x94, 0x59,
-	0x20, 0xbc, 0x6b, 0x6f, 0xa4, 0x54, 0x9e, 0x68, 0x2d, 0xed, 0x23, 0xdb, 0x60, 0x4a, 0x01, 0x71,
-	0x41, 0x27, 0xa1, 0x12, 0x5a, 0xd6, 0xf9, 0xd7, 0x41, 0x08, 0xe4, 0x38, 0x32, 0xa1, 0xe4, 0x55,
-	0x74, 0xd4, 0xbf, 0xf6, 0x21, 0xb3, 0x0a, 0x89, 0x0f, 0xd9, 0xbc, 0xa3, 0xc5, 0x07, 0x74, 0xf4,
-	0x19, 0xe4, 0x5c, 0x2a, 0xa8, 0x65, 0x2a, 0xe6, 0xb7, 0x97, 0xb1, 0x4d, 0x39, 0x7b, 0x9a, 0x66,
-	0x85, 0xac, 0xbe, 0x00, 0x73, 0xee, 0xfa, 0x5f, 0x04, 0x3f, 0x87, 0xcd, 0x0b, 0x64, 0xee, 0xa2,
-	0xfa, 0xef, 0xe6, 0xda, 0xfe, 0xd5, 0x80, 0xcd, 0x53, 0x6f, 0xde, 0x7f, 0x9e, 0xc8, 0x7c, 0x07,
-	0xca, 0x8a, 0xfe, 0x21, 0x9b, 0x4e, 0xae, 0x30, 0x8a, 0x37, 0x96, 0x94, 0xaf, 0xaf, 0x5c, 0x32,
-	0x72, 0x28, 0xc3, 0xea, 0xb9, 0xcd, 0xa8, 0x5b, 0xc3, 0x94, 0x1e, 0x3d, 0xb5, 0x35, 0x50, 0xc6,
-	0x90, 0x7b, 0x6f, 0x75, 0xab, 0xf2, 0x4e, 0x51, 0x3a, 0x2e, 0xbc, 0xb7, 0x48, 0x5e, 0x40, 0x49,
-	0xeb, 0x76, 0xa8, 0xa8, 0xd4, 0x6a, 0x7f, 0x17, 0x95, 0xa0, 0xa1, 0xf2, 0xdf, 0xee, 0x40, 0x31,
-	0x29, 0x95, 0x34, 0xa1, 0x18, 0x1f, 0x84, 0x5b, 0x86, 0x22, 0x98, 0x2c, 0x47, 0x70, 0xe6, 0x18,
-	0xfb, 0x00, 0x36, 0x16, 0x86, 0xf9, 0xfe, 0x53, 0xda, 0x0d, 0xa8, 0xf4, 0xf1, 0x36, 0xc9, 0x79,
-	0xa4, 0x2e, 0xc5, 0x95, 0x57, 0xe5, 0x5e, 0x1f, 0xb6, 0x56, 0xcf, 0x28, 0x29, 0x40, 0xb6, 0x7b,
-	0x7a, 0x5a, 0x79, 0x44, 0xd6, 0xc1, 0x3c, 0xbc, 0x1c, 0x1e, 0x9d, 0x7d, 0xd9, 0x1f, 0x5c, 0x56,
-	0x0c, 0x69, 0x9e, 0x9f, 0x5d, 0xf4, 0x06, 0xbd, 0xaf, 0x8e, 0x2f, 0x2a, 0x19, 0x69, 0xf6, 0x8f,
-	0x4f, 0xba, 0xda, 0xcc, 0xee, 0xbd, 0x84, 0x52, 0x8a, 0x05, 0x15, 0xa4, 0x7f, 0x59, 0x79, 0x44,
-	0x4c, 0xc8, 0x77, 0x4f, 0x8f, 0x9d, 0x41, 0xc5, 0x20, 0x25, 0x28, 0x7c, 0xdd, 0x75, 0xfa, 0xbd,
-	0xfe, 0x49, 0x25, 0x43, 0x8a, 0x90, 0xeb, 0xf5, 0x5f, 0x9d, 0x55, 0xb2, 0xed, 0x3f, 0xf3, 0x60,
-	0xbe, 0x4e, 0xde, 0x21, 0x82, 0xb0, 0xde, 0xf5, 0x31, 0x12, 0xf1, 0x65, 0xcf, 0x49, 0x9a, 0xe7,
-	0xd4, 0x0b, 0x50, 0xdd, 0x6a, 0xea, 0xe7, 0xa5, 0x99, 0x3c, 0x50, 0xcd, 0x63, 0xf9, 0x40, 0xd9,
-	0xf6, 0x8f, 0x7f, 0xfc, 0xfd, 0x4b, 0x66, 0xdb, 0x7e, 0xa2, 0xde, 0x9d, 0x9b, 0xfd, 0xd6, 0xfc,
-	0x8d, 0x6b, 0x51, 0x19, 0xb8, 0x63, 0xec, 0x35, 0x0c, 0xf2, 0x93, 0x01, 0x95, 0xc5, 0xf3, 0x13,
-	0xfb, 0xfe, 0xbb, 0xa6, 0xba, 0x7b, 0x27, 0x46, 0xcb, 0xd8, 0xfe, 0x48, 0xd5, 0x50, 0xb7, 0x6b,
-	0xcb, 0x35, 0xcc, 0x2f, 0xf0, 0x8e, 0xb1, 0x47, 0x5c, 0x28, 0xa5, 0xa6, 0x80, 0xac, 0x10, 0x44,
-	0xf5, 0xc3, 0x94, 0x6f, 0xc5, 0xc4, 0xd8, 0x3b, 0x2a, 0x55, 0xcd, 0xde, 0x5a, 0x4e, 0xc5, 0x91,
-	0xb9, 0x32, 0xcb, 0x0d, 0x94, 0xd3, 0x43, 0x43, 0xd2, 0x21, 0x57, 0x4c, 0x53, 0x75, 0x73, 0xb9,
-	0x0c, 0x6e, 0xef, 0xab, 0x3c, 0x9f, 0x90, 0x8f, 0x97, 0xf3, 0x24, 0x92, 0x6d, 0x7d, 0x9f, 0x96,
-	0xe7, 0x0f, 0xe4, 0x16, 0x0a, 0x0e, 0x52, 0xb7, 0xeb, 0xfb, 0xe4, 0xfd, 0x15, 0x52, 0x8f, 0xb3,
-	0xbd, 0xab, 0x8f, 0x9f, 0xa9, 0x84, 0x07, 0xed, 0xfd, 0x07, 0x27, 0x6c, 0x45, 0x48, 0x5d, 0xea,
-	0xfb, 0xe4, 0x67, 0x03, 0x36, 0x4f, 0x50, 0x2c, 0x4d, 0xc2, 0x1d, 0x55, 0xd4, 0x52, 0x4b, 0x8b,
-	0xfb, 0xec, 0x8e, 0x2a, 0xe5, 0x39, 0x69, 0x3f, 0xbc, 0x14, 0x86, 0xb7, 0x6a, 0xce, 0x0e, 0x4b,
-	0xdf, 0x98, 0x73, 0xf4, 0xd5, 0x9a, 0x3a, 0xe3, 0xc1, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xab,
-	0xd1, 0xe0, 0x2a, 0x78, 0x09, 0x00, 0x00,
+	// 3186 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x39, 0x4f, 0x6f, 0x1b, 0xc7,
+	0xf5, 0x5e, 0x52, 0x12, 0xb9, 0x8f, 0x14, 0x49, 0x8d, 0x6c, 0x99, 0xa6, 0xec, 0x58, 0x5a, 0x07,
+	0xb1, 0xc2, 0xc4, 0x62, 0x2c, 0x27, 0xbf, 0xd8, 0x32, 0xf2, 0x0b, 0x28, 0x89, 0x16, 0x98, 0x48,
+	0x94, 0xb2, 0xa4, 0xdd, 0x26, 0x3d, 0x10, 0xab, 0xdd, 0x11, 0xb5, 0x0d, 0xb9, 0xcb, 0xec, 0x2e,
+	0xe5, 0x28, 0x8e, 0x8b, 0xa2, 0x2d, 0xd0, 0x16, 0xbd, 0x14, 0x68, 0x4f, 0x45, 0xaf, 0x3d, 0xe4,
+	0x50, 0xf4, 0x0b, 0x14, 0xe8, 0xbd, 0xd7, 0xf4, 0x90, 0x0f, 0xd0, 0x0f, 0x52, 0xcc, 0xbf, 0xfd,
+	0xc7, 0x25, 0x25, 0x25, 0xee, 0xa1, 0x27, 0x72, 0xde, 0x7b, 0xf3, 0xde, 0x9b, 0x79, 0xff, 0x77,
+	0xa0, 0x38, 0xc0, 0xae, 0xab, 0xf5, 0x4c, 0xab, 0xb7, 0x3e, 0x74, 0x6c, 0xcf, 0x46, 0xb2, 0x6e,
+	0x9f, 0x9a, 0x9e, 0xa3, 0xe9, 0xb8, 0xb2, 0xdc, 0xb3, 0xed, 0x5e, 0x1f, 0xd7, 0x28, 0xe2, 0x68,
+	0x74, 0x5c, 0xc3, 0x83, 0xa1, 0x77, 0xc6, 0xe8, 0x2a, 0x77, 0x38, 0xb2, 0x6f, 0x5b, 0x3d, 0x67,
+	0x64, 0x59, 0xa6, 0xd5, 0xab, 0xd9, 0x43, 0xec, 0x68, 0x9e, 0x69, 0x5b, 0x2e, 0x27, 0xba, 0xc9,
+	0x89, 0xb4, 0xa1, 0x59, 0xd3, 0x2c, 0xcb, 0xf6, 0x22, 0xd8, 0xb7, 0xe9, 0x8f, 0x7e, 0xaf, 0x87,
+	0xad, 0x7b, 0xee, 0x73, 0xad, 0xd7, 0xc3, 0x4e, 0xcd, 0x1e, 0x52, 0x8a, 0x71, 0x6a, 0xe5, 0x9f,
+	0x12, 0xe4, 0xb6, 0x6d, 0xcb, 0xd3, 0x74, 0x6f, 0x47, 0xf3, 0x34, 0x74, 0x15, 0x66, 0x75, 0x7b,
+	0x64, 0x79, 0x65, 0x69, 0x45, 0x5a, 0x9b, 0x55, 0xd9, 0x02, 0xdd, 0x02, 0x18, 0xb9, 0xd8, 0xe9,
+	0x0e, 0x4f, 0x6c, 0x0b, 0x97, 0x53, 0x2b, 0xd2, 0x9a, 0xac, 0xca, 0x04, 0x72, 0x48, 0x00, 0x68,
+	0x19, 0xe4, 0xe3, 0x51, 0xbf, 0xdf, 0xb5, 0xb4, 0x01, 0x2e, 0xa7, 0x29, 0x36, 0x4b, 0x00, 0x2d,
+	0x6d, 0x80, 0xd1, 0x1d, 0x98, 0x1f, 0x6a, 0x9e, 0x89, 0x2d, 0x8f, 0x6f, 0x9f, 0xa1, 0x04, 0x79,
+	0x0e, 0x64, 0x1c, 0x56, 0x21, 0x6f, 0xe0, 0x53, 0x53, 0xc7, 0x5d, 0xcf, 0xfe, 0x1c, 0x5b, 0xe5,
+	0x59, 0x4a, 0x93, 0x63, 0xb0, 0x0e, 0x01, 0x11, 0x12, 0x9d, 0x29, 0xda, 0xf5, 0xcc, 0x01, 0x2e,
+	0xcf, 0x31, 0x12, 0x0e, 0xeb, 0x98, 0x03, 0xac, 0x0c, 0xa0, 0xbc, 0xe5, 0xd8, 0x9a, 0xb1, 0xad,
+	0xb9, 0xde, 0x3e, 0xb5, 0x00, 0x56, 0xb1, 0x3b, 0xb4, 0x2d, 0x17, 0xa3, 0x77, 0xe0, 0xea, 0x11,
+	0xc1, 0xe9, 0x9a, 0xeb, 0x75, 0x99, 0x79, 0x70, 0xd7, 0x34, 0xe8, 0x39, 0x65, 0x15, 0xf9, 0x38,
+	0xbe, 0xaf, 0x69, 0xa0, 0xdb, 0x90, 0x73, 0xf5, 0x13, 0x6c, 0x8c, 0xfa, 0x94, 0x90, 0x9d, 0x1a,
+	0x04, 0xa8, 0x69, 0x28, 0x7f, 0x4f, 0xc3, 0xf5, 0x71, 0x79, 0x5f, 0x8c, 0xb0, 0xeb, 0x91, 0x7b,
+	0xf4, 0x4c, 0xaf, 0x8f, 0x39, 0x7f, 0xb6, 0x40, 0x65, 0xc8, 0x70, 0xd1, 0x9c, 0x9d, 0x58, 0xa2,
+	0x2a, 0xcc, 0x78, 0x67, 0x43, 0x76, 0x7b, 0x85, 0x8d, 0xa5, 0x75, 0xdf, 0x5f, 0xd6, 0x39, 0xe3,
+	0xce, 0xd9, 0x10, 0xab, 0x94, 0x06, 0x3d, 0x86, 0xcc, 0xb1, 0xd9, 0xf7, 0xb0, 0xe3, 0x96, 0x67,
+	0x56, 0xd2, 0x6b, 0x85, 0x8d, 0xd5, 0x10, 0x79, 0x5c, 0xa1, 0x27, 0x94, 0x52, 0x15, 0x3b, 0xd0,
+	0x12, 0xcc, 0x79, 0xf6, 0xd0, 0xd4, 0xdd, 0xf2, 0xec, 0x4a, 0x7a, 0x4d, 0x56, 0xf9, 0x0a, 0x35,
+	0x21, 0x33, 0xd4, 0xce, 0xfa, 0xb6, 0x66, 0x94, 0xe7, 0x56, 0xd2, 0x6b, 0xb9, 0x8d, 0xda, 0x14,
+	0xa6, 0xfc, 0x94, 0xeb, 0x87, 0x6c, 0x47, 0xc3, 0xf2, 0x9c, 0x33, 0x55, 0xec, 0x47, 0x0f, 0x21,
+	0x2b, 0x6e, 0xa9, 0x9c, 0x59, 0x91, 0xd6, 0x72, 0x1b, 0x37, 0xe3, 0xbc, 0xc8, 0x4d, 0xb7, 0x39,
+	0x8d, 0xea, 0x53, 0xa3, 0x1a, 0x64, 0xb5, 0x91, 0x61, 0x62, 0x4b, 0xc7, 0xe5, 0x2c, 0xdd, 0xb9,
+	0x18, 0xda, 0x59, 0xe7, 0x28, 0xd5, 0x27, 0xaa, 0x6c, 0x42, 0x3e, 0xac, 0x03, 0x2a, 0x41, 0xfa,
+	0x73, 0x7c, 0xc6, 0x2f, 0x9d, 0xfc, 0x25, 0x86, 0x38, 0xd5, 0xfa, 0x23, 0x71, 0xe1, 0x6c, 0xb1,
+	0x99, 0x7a, 0x28, 0x29, 0xbf, 0x4b, 0x43, 0x56, 0xb0, 0x44, 0x15, 0xc8, 0xba, 0x9e, 0xe6, 0x8d,
+	0x5c, 0xec, 0x96, 0x25, 0x7a, 0x31, 0xfe, 0x9a, 0xe0, 0x68, 0x18, 0x98, 0xd8, 0x2d, 0xa7, 0x18,
+	0x4e, 0xac, 0x09, 0xfb, 0xe7, 0x9a, 0x63, 0xb8, 0xe5, 0x34, 0x45, 0xb0, 0x05, 0x52, 0x60, 0xbe,
+	0x87, 0xed, 0xee, 0x31, 0x61, 0xdd, 0x35, 0x0d, 0x66, 0x27, 0x59, 0xcd, 0xf5, 0xb0, 0xfd, 0x84,
+	0xc0, 0x9a, 0x86, 0x4b, 0x1c, 0xd2, 0xc5, 0xd8, 0xea, 0xba, 0x26, 0x21, 0x22, 0x2e, 0xed, 0x7a,
+	0xda, 0x60, 0x48, 0x5d, 0x3f, 0xad, 0x22, 0x82, 0x6b, 0x13, 0x54, 0x47, 0x60, 0xd0, 0x26, 0xc0,
+	0x17, 0x23, 0xcd, 0xd1, 0x2c, 0xcf, 0xb4, 0x98, 0xff, 0x17, 0x36, 0x2a, 0xa1, 0xfb, 0xf9, 0xc4,
+	0x47, 0xb6, 0x3d, 0xcd, 0xc3, 0x6a, 0x88, 0x1a, 0xdd, 0x04, 0xb9, 0xaf, 0x59, 0xbd, 0x91, 0xd6,
+	0xc3, 0x6e, 0x39, 0x43, 0xb5, 0x09, 0x00, 0xe8, 0x0d, 0x28, 0x0e, 0x4c, 0xab, 0xab, 0x0d, 0x87,
+	0xdd, 0x53, 0xec, 0xb8, 0xa6, 0x6d, 0xd1, 0xeb, 0x97, 0xd5, 0xf9, 0x81, 0x69, 0xd5, 0x87, 0xc3,
+	0x67, 0x0c, 0x88, 0xaa, 0x30, 0xa7, 0x59, 0x67, 0x5d, 0xfb, 0xb8, 0x2c, 0xaf, 0xa4, 0x27, 0x59,
+	0x67, 0x56, 0xb3, 0xce, 0x0e, 0x8e, 0xd1, 0x3d, 0xc8, 0xe0, 0x2f, 0xf5, 0xfe, 0xc8, 0xc0, 0x65,
+	0x98, 0x6c, 0x4a, 0x41, 0xa3, 0x7c, 0x04, 0xd7, 0x1b, 0xae, 0x67, 0x0e, 0x34, 0x0f, 0xfb, 0x48,
+	0x1e, 0x4b, 0x61, 0xaf, 0x90, 0x2e, 0xe0, 0x15, 0xca, 0x4f, 0xa0, 0x24, 0xa0, 0x82, 0x27, 0x7a,
+	0x0d, 0xc0, 0xc1, 0xba, 0x39, 0x24, 0x39, 0xc7, 0xa5, 0x6c, 0xd2, 0x6a, 0x08, 0x82, 0xee, 0x42,
+	0x71, 0x38, 0x72, 0x4f, 0xba, 0x21, 0xa2, 0x14, 0x25, 0x2a, 0x10, 0xb0, 0xea, 0x43, 0x95, 0x6f,
+	0x24, 0x58, 0x18, 0xf3, 0x61, 0x54, 0x85, 0x05, 0x17, 0x5b, 0x46, 0x57, 0xf3, 0x42, 0xa6, 0x64,
+	0x52, 0x8a, 0x04, 0x51, 0xf7, 0x02, 0x3b, 0xde, 0x85, 0xa2, 0xee, 0xd8, 0x56, 0x17, 0x7f, 0x39,
+	0x74, 0xb0, 0x4b, 0x6f, 0x9b, 0x39, 0x67, 0x81, 0x80, 0x1b, 0x3e, 0x94, 0x38, 0x1e, 0x61, 0xf6,
+	0x95, 0x6d, 0xb1, 0xc4, 0x20, 0xab, 0xfe, 0x9a, 0xa4, 0x55, 0x22, 0x2f, 0x10, 0x36, 0x43, 0x85,
+	0xe5, 0xb1, 0x65, 0xf8, 0x92, 0x94, 0xbf, 0xcc, 0x42, 0x86, 0x87, 0x2c, 0xc9, 0xe1, 0x63, 0x69,
+	0x4f, 0x1e, 0xf8, 0xd9, 0xee, 0x9c, 0x14, 0xef, 0xe7, 0xb3, 0x74, 0x38, 0x9f, 0x29, 0x90, 0xb7,
+	0x6c, 0xcf, 0x3c, 0x36, 0x75, 0x5a, 0x54, 0x44, 0x6a, 0x0f, 0xc3, 0x88, 0xe7, 0xc5, 0x9d, 0x3b,
+	0x00, 0x20, 0x04, 0x33, 0x2e, 0xb6, 0x3c, 0xea, 0xcd, 0x59, 0x95, 0xfe, 0x67, 0x30, 0x6c, 0x95,
+	0x33, 0x02, 0x86, 0x2d, 0x3f, 0x3f, 0x66, 0x2f, 0x90, 0x1f, 0xdf, 0x81, 0x19, 0x43, 0xf3, 0x34,
+	0xee, 0xa3, 0x37, 0xc7, 0x69, 0xd7, 0x49, 0xa9, 0x63, 0x49, 0x8b, 0x52, 0x92, 0xbc, 0xac, 0x9f,
+	0x68, 0x96, 0x85, 0xfb, 0xd4, 0x57, 0x65, 0x55, 0x2c, 0xd1, 0x16, 0x14, 0x0d, 0xdc, 0x37, 0x4f,
+	0xb1, 0x73, 0xd6, 0x65, 0x09, 0xa1, 0x9c, 0xa3, 0x2a, 0xdc, 0x08, 0xb1, 0xdd, 0xe1, 0x14, 0x6d,
+	0x4a, 0xa0, 0x16, 0x8c, 0xc8, 0x1a, 0xbd, 0x05, 0x0b, 0x3e, 0x0f, 0xcd, 0xf3, 0x48, 0xbd, 0x77,
+	0xcb, 0x79, 0x5a, 0x5f, 0x4b, 0x02, 0x51, 0xe7, 0x70, 0x6a, 0x73, 0x3c, 0x18, 0xf6, 0x35, 0x0f,
+	0x97, 0xe7, 0xb9, 0xcd, 0xf9, 0x1a, 0xfd, 0x18, 0x90, 0xf8, 0xdf, 0x3d, 0xd5, 0x1c, 0x53, 0x3b,
+	0xea, 0x63, 0xb7, 0x5c, 0xa0, 0xc7, 0x7c, 0x33, 0xe1, 0x98, 0x1d, 0x4e, 0xfc, 0x4c, 0xd0, 0xb2,
+	0x33, 0x2f, 0x78, 0x71, 0x78, 0xe5, 0x7d, 0x90, 0xfd, 0x3b, 0xb9, 0x4c, 0x12, 0xad, 0xec, 0xc0,
+	0x52, 0xb2, 0x94, 0x4b, 0xa5, 0xe2, 0xdf, 0xa4, 0xa0, 0xb8, 0x13, 0xbd, 0x89, 0xb0, 0x4d, 0xa4,
+	0xa8, 0x4d, 0x6e, 0x82, 0xcc, 0xaf, 0x0d, 0xb3, 0xb2, 0x9c, 0x55, 0x03, 0x00, 0x5a, 0x87, 0xc5,
+	0xa1, 0x63, 0x9f, 0x9a, 0x06, 0x76, 0xc2, 0x75, 0x9e, 0xf9, 0xed, 0x82, 0x40, 0xed, 0x87, 0x1d,
+	0x1f, 0x3b, 0x8e, 0xed, 0x74, 0x75, 0xdb, 0x10, 0xcd, 0x89, 0x4c, 0x21, 0xdb, 0xb6, 0x41, 0x1d,
+	0x9f, 0x2e, 0x78, 0x4b, 0xc2, 0x16, 0x68, 0x13, 0x6e, 0xe8, 0x9a, 0x65, 0x5b, 0xa6, 0xae, 0xf5,
+	0xbb, 0x0e, 0xee, 0x99, 0xae, 0xc7, 0x7a, 0x34, 0x22, 0x8a, 0x75, 0x26, 0xd7, 0x7d, 0x02, 0x35,
+	0x84, 0x6f, 0x1a, 0xd1, 0x80, 0xc8, 0xc4, 0x02, 0x42, 0x71, 0xe0, 0xf6, 0x2e, 0x16, 0x75, 0x36,
+	0xe6, 0x58, 0x3c, 0x1f, 0x9e, 0x13, 0xc9, 0x93, 0x3a, 0x9d, 0xd4, 0xa4, 0x4e, 0x47, 0xf9, 0x26,
+	0x0d, 0xd7, 0x12, 0x25, 0x9e, 0x27, 0xea, 0x3e, 0xcc, 0xf1, 0xa0, 0x48, 0x9d, 0x17, 0x14, 0x9c,
+	0x30, 0x6c, 0xd6, 0x74, 0xd4, 0xac, 0x15, 0xc8, 0xfa, 0xd1, 0x31, 0x43, 0xa3, 0xc3, 0x5f, 0x13,
+	0x3d, 0xfa, 0xe4, 0x38, 0x61, 0x53, 0xc8, 0x04, 0xd2, 0xa0, 0xe6, 0xd8, 0x4d, 0x8a, 0x30, 0xd6,
+	0xc6, 0x54, 0x12, 0x54, 0xe2, 0x2e, 0x96, 0x10, 0x7d, 0x93, 0xee, 0x2e, 0x33, 0xb1, 0x4b, 0x8c,
+	0xd6, 0x95, 0xec, 0x58, 0x5d, 0x89, 0x38, 0xab, 0xcc, 0xac, 0xed, 0x03, 0x48, 0x37, 0x76, 0xac,
+	0x99, 0x7d, 0x6c, 0xd0, 0xbc, 0x93, 0x56, 0xf9, 0x8a, 0xdc, 0xd2, 0x10, 0x5b, 0x86, 0x69, 0xf5,
+	0x68, 0xba, 0x49, 0xab, 0x62, 0xa9, 0xbc, 0x0b, 0x8b, 0x6d, 0x6c, 0x19, 0xf1, 0xf6, 0x76, 0xba,
+	0xa1, 0x94, 0xbf, 0x4a, 0xb0, 0xb8, 0x67, 0xfa, 0x7a, 0xfb, 0xae, 0xb4, 0x0a, 0x79, 0x9a, 0xf0,
+	0xbb, 0xd6, 0x68, 0x70, 0x84, 0x1d, 0xbe, 0x31, 0x47, 0x61, 0x2d, 0x0a, 0x22, 0x9c, 0x87, 0x5a,
+	0x4f, 0x34, 0xe6, 0x29, 0x6a, 0x18, 0x99, 0x40, 0x58, 0x5b, 0xbe, 0x0c, 0x74, 0xd1, 0x75, 0xcd,
+	0xaf, 0x58, 0x71, 0x98, 0x55, 0xb3, 0x04, 0xd0, 0x36, 0xbf, 0xc2, 0xe8, 0x7d, 0xc8, 0xb1, 0xbe,
+	0xb3, 0x4b, 0x93, 0x37, 0xeb, 0x56, 0x27, 0x25, 0x6f, 0x60, 0xa4, 0xe4, 0xbf, 0xb2, 0x09, 0x59,
+	0xa1, 0x2a, 0x5a, 0x87, 0x2c, 0x3f, 0x08, 0x6b, 0xcd, 0x72, 0x1b, 0x68, 0x9c, 0x83, 0xea, 0xd3,
+	0x28, 0x0f, 0xa0, 0x10, 0x6b, 0xc6, 0xcf, 0x3f, 0xa5, 0xb2, 0x06, 0xa5, 0x16, 0x7e, 0x2e, 0x64,
+	0x6e, 0xd3, 0xa9, 0x27, 0x71, 0x16, 0x52, 0x1e, 0xc3, 0x2d, 0x8a, 0x6e, 0x7b, 0x5a, 0x1f, 0xef,
+	0x04, 0x03, 0x8a, 0x7f, 0xa7, 0xe1, 0x76, 0x51, 0x8a, 0xb6, 0x8b, 0x0a, 0x06, 0x44, 0x37, 0x9f,
+	0x85, 0x37, 0x12, 0x2f, 0xa0, 0x14, 0x22, 0x5b, 0xf2, 0x15, 0x51, 0x80, 0x54, 0x60, 0xd1, 0x89,
+	0xb0, 0x05, 0x39, 0x8d, 0x4b, 0x64, 0x33, 0x8b, 0xb8, 0xf4, 0xd2, 0xd3, 0x6a, 0x8e, 0xc2, 0x18,
+	0x43, 0xe5, 0x0b, 0x58, 0x18, 0x53, 0x0f, 0x3d, 0x8a, 0xe9, 0x95, 0xdb, 0xb8, 0x15, 0xba, 0xc7,
+	0x71, 0xb5, 0x02, 0xb5, 0xc7, 0x44, 0xa6, 0xc6, 0x45, 0x7e, 0x27, 0x41, 0xc1, 0x6f, 0x8b, 0xb6,
+	0x34, 0x4f, 0x3f, 0x61, 0xa3, 0x06, 0xa5, 0x67, 0x17, 0xc8, 0x57, 0xc4, 0xb9, 0xdd, 0x91, 0xae,
+	0x63, 0xd7, 0xe5, 0xee, 0x24, 0x96, 0x04, 0x43, 0x02, 0x60, 0xe4, 0x08, 0x57, 0x12, 0x4b, 0xd2,
+	0xee, 0x04, 0x09, 0x97, 0x75, 0xd4, 0x04, 0x9f, 0xf7, 0x81, 0xa4, 0xa5, 0x5e, 0x85, 0xfc, 0x60,
+	0xd4, 0xf7, 0x4c, 0x1a, 0xbd, 0xa6, 0xc1, 0xbb, 0x8d, 0x9c, 0x0f, 0x6b, 0x1a, 0x41, 0x3a, 0x9f,
+	0x0b, 0xa7, 0xf3, 0xe9, 0x29, 0xf9, 0x5f, 0x73, 0x20, 0xfb, 0x47, 0xfb, 0x1e, 0x83, 0xa4, 0xdf,
+	0x3b, 0xa5, 0x26, 0xcc, 0x82, 0xe9, 0xe4, 0x59, 0x70, 0xe6, 0x72, 0xb3, 0xe0, 0xec, 0x0f, 0x98,
+	0x05, 0xe7, 0x22, 0xb3, 0xe0, 0xe3, 0x60, 0x16, 0xcc, 0x50, 0x47, 0x59, 0x4d, 0x9a, 0xdf, 0x26,
+	0x4c, 0x7f, 0x1b, 0x7e, 0x4d, 0xc8, 0x8e, 0x4d, 0x28, 0x41, 0xdf, 0x1c, 0x2d, 0x0a, 0xd1, 0x24,
+	0x2a, 0x27, 0x25, 0xd1, 0xa1, 0x63, 0x13, 0x17, 0xf1, 0x33, 0x65, 0x00, 0x88, 0xa6, 0xd8, 0xdc,
+	0xe4, 0x14, 0x9b, 0x8f, 0xa4, 0xd8, 0x68, 0x49, 0x99, 0x8f, 0x97, 0x94, 0x07, 0x90, 0x39, 0x22,
+	0x5e, 0xec, 0x37, 0x58, 0x37, 0x92, 0xce, 0x41, 0x1d, 0x5d, 0x15, 0x94, 0xa4, 0xd3, 0xd3, 0x1d,
+	0xac, 0x79, 0x38, 0xdc, 0x98, 0x17, 0xa9, 0xd8, 0x12, 0x47, 0x04, 0x63, 0xc0, 0x5b, 0xb0, 0xe0,
+	0x7a, 0x9a, 0x13, 0x25, 0x2e, 0x31, 0x62, 0x8e, 0x08, 0x88, 0xef, 0x01, 0x3a, 0x36, 0x2d, 0xd3,
+	0x3d, 0x89, 0x50, 0x2f, 0x50, 0xea, 0x05, 0x81, 0x09, 0xc8, 0x63, 0xdf, 0x2e, 0x50, 0xfc, 0xdb,
+	0x45, 0x64, 0xa6, 0x5a, 0xbc, 0xc0, 0x4c, 0x45, 0x9d, 0x98, 0x78, 0x47, 0xf9, 0x2a, 0x77, 0x62,
+	0xb2, 0xf8, 0x41, 0xf3, 0xf7, 0x2e, 0x2c, 0xee, 0x62, 0xcf, 0xbf, 0x4a, 0x91, 0x3e, 0x2f, 0x1d,
+	0x5f, 0xca, 0x1f, 0x25, 0xb8, 0x46, 0x8a, 0x9b, 0xcf, 0x2a, 0xdc, 0x29, 0x85, 0x6a, 0x97, 0x34,
+	0xb5, 0x76, 0xa5, 0x62, 0xb5, 0xeb, 0x43, 0x98, 0xe7, 0xb5, 0x8b, 0xbb, 0x73, 0x7a, 0x25, 0x7d,
+	0x8e, 0x3b, 0xe7, 0xd9, 0x06, 0xb6, 0x52, 0x7e, 0x0a, 0x10, 0x68, 0x84, 0xde, 0x05, 0xf0, 0x55,
+	0x17, 0xf9, 0xf7, 0x6a, 0x12, 0x2f, 0x35, 0x44, 0x47, 0x06, 0x73, 0x0b, 0x7f, 0xe9, 0x75, 0xc7,
+	0x2a, 0xf0, 0x3c, 0x01, 0x1f, 0x8a, 0x93, 0x28, 0x1f, 0xc1, 0xd2, 0xb6, 0x66, 0xe9, 0xb8, 0xff,
+	0x0a, 0xae, 0xf3, 0x6f, 0xb3, 0x90, 0xf5, 0xe7, 0xda, 0x98, 0x23, 0x49, 0x63, 0x8e, 0xf4, 0x3f,
+	0x97, 0xdc, 0x36, 0xe3, 0xc9, 0x6d, 0x25, 0xc4, 0x54, 0x9c, 0xfb, 0x02, 0x5f, 0xb6, 0xb2, 0x97,
+	0xfa, 0xb2, 0xb5, 0x04, 0x73, 0x43, 0x6d, 0xe4, 0xf2, 0x1e, 0x30, 0xab, 0xf2, 0x15, 0x7a, 0x1b,
+	0x10, 0x35, 0xb0, 0x33, 0xb2, 0x42, 0x71, 0xcd, 0x52, 0x5c, 0x89, 0x60, 0xd4, 0x91, 0x15, 0x84,
+	0xf5, 0xdb, 0x80, 0x68, 0xce, 0x8a, 0x52, 0xb3, 0x94, 0x57, 0x22, 0x98, 0x08, 0xf5, 0x23, 0xb8,
+	0x41, 0xa9, 0x13, 0xed, 0x9f, 0xa7, 0x66, 0x59, 0x22, 0x04, 0x5b, 0xe3, 0x25, 0x0b, 0xc1, 0x8c,
+	0x33, 0xb2, 0x5c, 0x9a, 0x16, 0xd3, 0x2a, 0xfd, 0x9f, 0x9c, 0xdc, 0x0a, 0x13, 0x92, 0x5b, 0x38,
+	0xbf, 0x14, 0xff, 0xdb, 0x5f, 0xf2, 0x54, 0xb8, 0xba, 0x67, 0x06, 0xd7, 0xfe, 0x2a, 0xc2, 0x5f,
+	0x39, 0x06, 0xd9, 0xe7, 0x87, 0xee, 0x83, 0x2c, 0x2c, 0x29, 0x62, 0x77, 0x31, 0xc1, 0x6b, 0xd4,
+	0x80, 0xea, 0xc2, 0x91, 0xbb, 0x05, 0xb7, 0xda, 0x67, 0x96, 0xde, 0x21, 0xce, 0xd9, 0x1e, 0x1d,
+	0xb9, 0xba, 0x63, 0xb2, 0x8f, 0xf5, 0x97, 0x68, 0x5e, 0x37, 0xa0, 0xe8, 0xab, 0xc0, 0x77, 0x9d,
+	0x17, 0xb7, 0xe4, 0x1b, 0x59, 0x7c, 0x70, 0x27, 0x56, 0xa7, 0x9f, 0xf0, 0x19, 0x35, 0xfd, 0x8f,
+	0x1e, 0xf0, 0x78, 0x65, 0x03, 0xde, 0xed, 0xd0, 0xa9, 0xe3, 0xdb, 0x83, 0xc0, 0x55, 0xbe, 0x95,
+	0xa0, 0x28, 0xc2, 0x99, 0x53, 0x25, 0x32, 0xaf, 0x40, 0x56, 0x7c, 0x84, 0xe4, 0x56, 0xf5, 0xd7,
+	0x13, 0xbe, 0x38, 0x21, 0x98, 0x39, 0xb2, 0x8d, 0x33, 0x3e, 0xa7, 0xd3, 0xff, 0xe8, 0x11, 0xc8,
+	0xc1, 0xd7, 0x90, 0x59, 0x6a, 0x9d, 0xe5, 0x29, 0x7a, 0xaa, 0x01, 0x35, 0xf1, 0xe9, 0xd1, 0xd0,
+	0x88, 0xf9, 0xf4, 0x1c, 0xf3, 0x69, 0x8e, 0x08, 0xbe, 0xa6, 0x1d, 0xc0, 0xb5, 0x6d, 0xea, 0xe7,
+	0x82, 0xa3, 0xb8, 0xec, 0xff, 0x0b, 0x7d, 0xb3, 0x61, 0x1f, 0x28, 0x2b, 0x09, 0x79, 0x4d, 0x6c,
+	0xf2, 0x69, 0x09, 0xc3, 0xa7, 0x54, 0xc8, 0xab, 0x62, 0x58, 0x87, 0x62, 0x9c, 0xd5, 0x25, 0xaf,
+	0x5d, 0xf9, 0xb3, 0xc4, 0x82, 0x49, 0xf0, 0x79, 0x25, 0xb5, 0xf4, 0xb6, 0x3f, 0x07, 0x86, 0x9e,
+	0x88, 0xf8, 0xbc, 0x47, 0x1f, 0x89, 0xee, 0x42, 0x91, 0x13, 0xf8, 0x8a, 0x31, 0x0b, 0x17, 0x18,
+	0x78, 0x4f, 0xa8, 0xe7, 0x41, 0x29, 0x76, 0x7c, 0x17, 0x3d, 0x04, 0x59, 0xdc, 0x80, 0x88, 0xce,
+	0x69, 0xd7, 0x15, 0x10, 0x5f, 0x34, 0x48, 0xab, 0x2d, 0x58, 0x4a, 0x2e, 0x37, 0x28, 0x03, 0xe9,
+	0xfa, 0xde, 0x5e, 0xe9, 0x0a, 0x9a, 0x07, 0x79, 0xeb, 0xd3, 0xee, 0xf6, 0xc1, 0xd3, 0x56, 0xe7,
+	0xd3, 0x92, 0x44, 0x96, 0x87, 0x07, 0xed, 0x66, 0xa7, 0xf9, 0xac, 0xd1, 0x2e, 0xa5, 0xc8, 0xb2,
+	0xd5, 0xd8, 0xad, 0xb3, 0x65, 0xba, 0xfa, 0x31, 0x14, 0x63, 0x1f, 0xeb, 0x11, 0x82, 0xc2, 0x27,
+	0x4f, 0xeb, 0x6a, 0xbd, 0xd5, 0x69, 0xb6, 0x1a, 0xdd, 0x7a, 0xeb, 0xd3, 0xd2, 0x15, 0x54, 0x84,
+	0x5c, 0x00, 0xdb, 0x29, 0x49, 0x68, 0x11, 0x8a, 0xad, 0x83, 0x4e, 0x37, 0x0c, 0x4c, 0x55, 0x1f,
+	0x42, 0x2e, 0x54, 0x3a, 0xa9, 0x46, 0x74, 0xb7, 0x0c, 0xb3, 0xf5, 0xbd, 0x86, 0xda, 0x29, 0x49,
+	0x28, 0x07, 0x99, 0x1f, 0xd5, 0xd5, 0x56, 0xb3, 0xb5, 0x5b, 0x4a, 0xa1, 0x2c, 0xcc, 0x34, 0x5b,
+	0x4f, 0x0e, 0x4a, 0xe9, 0x6a, 0x13, 0x0a, 0xb1, 0xef, 0x3d, 0x45, 0xc8, 0x11, 0x01, 0x1d, 0xb5,
+	0xbe, 0xfd, 0x71, 0x63, 0xa7, 0x74, 0x85, 0xec, 0x3c, 0x6c, 0xb4, 0x76, 0xc8, 0x4e, 0x7a, 0xa8,
+	0x9d, 0xc6, 0x5e, 0xf3, 0x59, 0x43, 0x25, 0x82, 0x11, 0xc0, 0xdc, 0x93, 0x7a, 0x73, 0xaf, 0xb1,
+	0x53, 0x4a, 0x57, 0x7f, 0x2d, 0x41, 0x31, 0xd6, 0x0e, 0xa1, 0x6b, 0xb0, 0xb0, 0xa5, 0x1e, 0xd4,
+	0x77, 0xb6, 0xeb, 0xed, 0x4e, 0x57, 0x70, 0xb9, 0x12, 0x05, 0xab, 0x4f, 0x5b, 0x2d, 0xc6, 0xfc,
+	0x3a, 0x2c, 0x06, 0xe0, 0xed, 0x83, 0xfd, 0xc3, 0xbd, 0x46, 0x87, 0x8a, 0x89, 0x22, 0xea, 0xad,
+	0xed, 0xc6, 0x1e, 0x95, 0x89, 0xae, 0x42, 0x29, 0x40, 0x70, 0x4d, 0x66, 0xaa, 0x1f, 0xc0, 0xd5,
+	0xa4, 0xcc, 0x44, 0xb4, 0x6d, 0x77, 0x54, 0xa6, 0x02, 0xc0, 0x5c, 0xeb, 0xe9, 0xfe, 0x56, 0x43,
+	0x65, 0x87, 0xea, 0x34, 0xf7, 0x1b, 0xed, 0x4e, 0x7d, 0xff, 0xb0, 0x94, 0xda, 0xf8, 0xc7, 0x12,
+	0xc8, 0xfb, 0xe2, 0xf5, 0x16, 0x61, 0x98, 0xaf, 0xf7, 0xb1, 0xe3, 0xf1, 0x27, 0x52, 0x17, 0x2d,
+	0x45, 0x46, 0x66, 0xff, 0xdd, 0xb4, 0xb2, 0xb4, 0xce, 0x1e, 0x65, 0xd7, 0xc5, 0xb3, 0xee, 0x7a,
+	0x83, 0x3c, 0xeb, 0x2a, 0xca, 0x2f, 0xbe, 0xfd, 0xf7, 0x1f, 0x52, 0x37, 0x95, 0xeb, 0xf4, 0xb5,
+	0xf6, 0xf4, 0x7e, 0xcd, 0x7f, 0x19, 0xae, 0x69, 0x84, 0xf1, 0xa6, 0x54, 0x5d, 0x93, 0xd0, 0x2f,
+	0x25, 0x28, 0xc5, 0x1d, 0x0c, 0x29, 0xe7, 0x3f, 0xc0, 0x55, 0xee, 0x4c, 0xa5, 0x61, 0xdf, 0x86,
+	0x94, 0x37, 0xa8, 0x0e, 0x2b, 0xca, 0xf2, 0xb8, 0x0e, 0x7e, 0x6b, 0xb0, 0x29, 0x55, 0xd1, 0xaf,
+	0x24, 0x28, 0xc5, 0xdf, 0x60, 0x22, 0x5a, 0x4c, 0x78, 0xa0, 0xa9, 0x2c, 0x27, 0x94, 0x76, 0x41,
+	0xab, 0xd4, 0xa8, 0xf4, 0x37, 0x95, 0xd7, 0xa7, 0x48, 0xaf, 0x61, 0x4e, 0xcd, 0xd5, 0x58, 0x4a,
+	0x2e, 0x89, 0x68, 0x2d, 0x5c, 0x74, 0xa7, 0x55, 0xcd, 0x89, 0xf6, 0x58, 0xa3, 0xda, 0x28, 0xca,
+	0xad, 0x71, 0x6d, 0x58, 0x7f, 0x58, 0x73, 0xcf, 0x2c, 0x9d, 0xa8, 0xf1, 0x73, 0x09, 0xf2, 0xe1,
+	0xf9, 0x04, 0xbd, 0x16, 0x12, 0x9e, 0x30, 0xb8, 0x54, 0x12, 0xbb, 0x79, 0x65, 0x93, 0x0a, 0x7c,
+	0x17, 0x6d, 0x4c, 0x3b, 0xfe, 0x8b, 0xa4, 0x16, 0xed, 0x25, 0xb2, 0xa1, 0x10, 0x9d, 0x6b, 0x50,
+	0xb8, 0x57, 0x4d, 0x1c, 0x79, 0x2a, 0xd7, 0x92, 0xb4, 0x70, 0x95, 0xd7, 0xa9, 0x1a, 0xaf, 0xa1,
+	0x9b, 0x53, 0xd4, 0x70, 0xd1, 0xef, 0x25, 0x28, 0xc6, 0xe6, 0x08, 0x14, 0xee, 0xb9, 0x93, 0x67,
+	0x8c, 0x09, 0x27, 0xdf, 0xa1, 0x22, 0xff, 0x5f, 0x79, 0x74, 0xf9, 0x93, 0xd7, 0x74, 0x2a, 0x88,
+	0x98, 0xe1, 0x73, 0x98, 0x8f, 0xf4, 0x76, 0xe8, 0x76, 0xec, 0x0a, 0xe2, 0x5d, 0x5f, 0x44, 0x1b,
+	0x1f, 0xa9, 0xdc, 0xa1, 0xda, 0xdc, 0x42, 0x09, 0x41, 0x10, 0x34, 0x6d, 0x5f, 0xc3, 0xfc, 0xa1,
+	0x36, 0x72, 0xb1, 0xd8, 0x86, 0x2a, 0x49, 0x5d, 0x1e, 0x97, 0x93, 0xd4, 0x01, 0x0a, 0x73, 0x2b,
+	0xb5, 0x29, 0x62, 0x6a, 0x2f, 0x42, 0xad, 0xd9, 0xcb, 0x1a, 0x9d, 0x04, 0xc8, 0x51, 0x7f, 0x06,
+	0x05, 0x15, 0xbb, 0xa3, 0xc1, 0x0f, 0x10, 0xff, 0x98, 0x8a, 0x7f, 0x4f, 0x79, 0xe7, 0xe2, 0xe2,
+	0x1d, 0x2a, 0x92, 0xc8, 0x7f, 0x4e, 0xcb, 0x01, 0xf6, 0x2e, 0x26, 0x7f, 0x52, 0x84, 0xdd, 0xa7,
+	0x2a, 0xbc, 0x55, 0x7d, 0xf3, 0xc2, 0x2a, 0xa0, 0x53, 0x28, 0x44, 0x1b, 0xab, 0x88, 0x9f, 0x27,
+	0xf6, 0x5c, 0x95, 0x29, 0x15, 0x5e, 0x24, 0xbc, 0x4d, 0xa9, 0x9a, 0x94, 0xf3, 0x82, 0xf2, 0xff,
+	0x27, 0x09, 0x0a, 0xd1, 0x06, 0x2c, 0x22, 0x38, 0xb1, 0x37, 0x9b, 0x2a, 0xf8, 0x23, 0x2a, 0x78,
+	0x67, 0x53, 0xaa, 0x6e, 0x7c, 0x38, 0x45, 0x70, 0xed, 0x85, 0xf8, 0xbb, 0x4e, 0xfa, 0xa1, 0x97,
+	0xa1, 0xb5, 0x68, 0x7f, 0x5e, 0xa2, 0xaf, 0x21, 0xb7, 0x8b, 0xfd, 0x36, 0x2c, 0x62, 0x8a, 0xcb,
+	0xa8, 0xf4, 0x1e, 0x55, 0xa9, 0x86, 0xee, 0x4d, 0xd5, 0x87, 0xab, 0x11, 0x48, 0x77, 0x58, 0xd8,
+	0x05, 0x4d, 0x56, 0x3c, 0xec, 0xe2, 0xfd, 0x61, 0x65, 0x79, 0xb2, 0x12, 0x53, 0xa3, 0x2f, 0x30,
+	0xc7, 0x0b, 0xe1, 0x7f, 0x17, 0x3a, 0xf4, 0x24, 0xff, 0xe3, 0x07, 0xae, 0x5e, 0xf2, 0xc0, 0x06,
+	0xe4, 0x42, 0xef, 0x2a, 0x28, 0xe1, 0x89, 0xa1, 0x12, 0x2e, 0x00, 0x09, 0x6f, 0x30, 0xca, 0x2a,
+	0x95, 0xbc, 0xac, 0x2c, 0x8d, 0x4b, 0x76, 0xb1, 0x65, 0x90, 0x10, 0xfb, 0x4e, 0x82, 0xf2, 0xa4,
+	0xe7, 0x3d, 0x54, 0x8d, 0x16, 0x98, 0x69, 0x6f, 0x80, 0x95, 0x95, 0x71, 0xfd, 0xa2, 0x84, 0x8a,
+	0x45, 0xb5, 0x39, 0xf9, 0xec, 0x03, 0xf4, 0xf8, 0x7b, 0x24, 0x60, 0xf1, 0x32, 0x86, 0xee, 0x8e,
+	0x6f, 0x16, 0xb8, 0xda, 0x8b, 0xd0, 0x0e, 0xf4, 0x5b, 0x09, 0x96, 0x92, 0x1f, 0x46, 0x22, 0x65,
+	0x7b, 0xea, 0xdb, 0x49, 0x25, 0xfc, 0x39, 0x65, 0x8c, 0x48, 0xb9, 0x4b, 0x8f, 0xb4, 0x8a, 0x6e,
+	0x27, 0xe9, 0x44, 0xe8, 0xdc, 0x1a, 0x7d, 0x92, 0x40, 0xa7, 0x90, 0x0f, 0xbf, 0x76, 0x45, 0x4a,
+	0x77, 0xc2, 0x33, 0x58, 0x24, 0x97, 0x0a, 0x9c, 0x48, 0x64, 0x28, 0x21, 0x91, 0x0d, 0x38, 0x4d,
+	0xed, 0x45, 0x78, 0x34, 0x7f, 0x89, 0x9e, 0x43, 0x46, 0xc5, 0x9a, 0x51, 0xef, 0xf7, 0xd1, 0x8d,
+	0x71, 0x96, 0xe7, 0x79, 0xee, 0x23, 0x2a, 0xf0, 0xc1, 0xc6, 0xfd, 0x0b, 0x0b, 0xac, 0x39, 0x58,
+	0x33, 0xb4, 0x7e, 0x9f, 0x5c, 0x3e, 0xf9, 0x98, 0x3a, 0xf6, 0x84, 0x35, 0x45, 0x8b, 0x70, 0xbc,
+	0xc6, 0xf7, 0x4d, 0xeb, 0x5a, 0x26, 0xa9, 0x62, 0xe1, 0xe7, 0xf4, 0xbd, 0x68, 0x2b, 0xf7, 0x99,
+	0xec, 0x53, 0x1f, 0xcd, 0xd1, 0x33, 0x3e, 0xf8, 0xcf, 0x00, 0xdd, 0xbf, 0xe8, 0x88, 0x12, 0x29,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -721,8 +2933,40 @@ type MessagingClient interface {
 	AlertContacts(ctx context.Context, opts ...grpc.CallOption) (Messaging_AlertContactsClient, error)
 	// Broadcasts a message
 	BroadCastMessage(ctx context.Context, in *BroadCastMessageRequest, opts ...grpc.CallOption) (*BroadCastMessageResponse, error)
+	// Counts the users a broadcast would be sent to without sending it
+	EstimateAudience(ctx context.Context, in *EstimateAudienceRequest, opts ...grpc.CallOption) (*AudienceEstimate, error)
+	// Subscribes the devices of a user to the FCM topics of their county, status and language
+	SyncTopicSubscriptions(ctx context.Context, in *SyncTopicSubscriptionsRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Retrieves a broadcast together with its progress
+	GetBroadcast(ctx context.Context, in *GetBroadcastRequest, opts ...grpc.CallOption) (*Broadcast, error)
+	// Retrieves broadcasts, most recent first
+	ListBroadcasts(ctx context.Context, in *ListBroadcastsRequest, opts ...grpc.CallOption) (*Broadcasts, error)
+	// Cancels a broadcast, users it was sent to keep the message
+	CancelBroadcast(ctx context.Context, in *CancelBroadcastRequest, opts ...grpc.CallOption) (*Broadcast, error)
+	// Retrieves scheduled broadcasts, most recent first
+	ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*Schedules, error)
+	// Pauses a scheduled broadcast
+	PauseSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error)
+	// Resumes a paused scheduled broadcast, runs missed while paused are skipped
+	ResumeSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error)
+	// Deletes a scheduled broadcast, broadcasts already sent are kept
+	DeleteSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Adds a message template
+	CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*MessageTemplate, error)
+	// Changes the wording of a message template
+	UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*MessageTemplate, error)
+	// Retrieves the language variant of a message template
+	GetTemplate(ctx context.Context, in *TemplateRequest, opts ...grpc.CallOption) (*MessageTemplate, error)
+	// Retrieves message templates
+	ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*MessageTemplates, error)
+	// Deletes the language variant of a message template
+	DeleteTemplate(ctx context.Context, in *TemplateRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	// Sends message to a single destination
 	SendMessage(ctx context.Context, in *Message, opts ...grpc.CallOption) (*SendMessageResponse, error)
+	// Retrieves the delivery status of a message or how many users received a broadcast
+	GetMessageDeliveryStatus(ctx context.Context, in *GetMessageDeliveryStatusRequest, opts ...grpc.CallOption) (*MessageDeliveryStatus, error)
+	// Counts users per county whose device tokens were found invalid
+	CountStaleDeviceTokens(ctx context.Context, in *CountStaleDeviceTokensRequest, opts ...grpc.CallOption) (*StaleDeviceTokens, error)
 	// Retrieves user messages
 	ListMessages(ctx context.Context, in *ListMessagesRequest, opts ...grpc.CallOption) (*Messages, error)
 	// Marks all messages as read for a user
@@ -773,9 +3017,135 @@ func (x *messagingAlertContactsClient) CloseAndRecv() (*empty.Empty, error) {
 	return m, nil
 }
 
-func (c *messagingClient) BroadCastMessage(ctx context.Context, in *BroadCastMessageRequest, opts ...grpc.CallOption) (*BroadCastMessageResponse, error) {
-	out := new(BroadCastMessageResponse)
-	err := c.cc.Invoke(ctx, "/covitrace.Messaging/BroadCastMessage", in, out, opts...)
+func (c *messagingClient) BroadCastMessage(ctx context.Context, in *BroadCastMessageRequest, opts ...grpc.CallOption) (*BroadCastMessageResponse, error) {
+	out := new(BroadCastMessageResponse)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/BroadCastMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) EstimateAudience(ctx context.Context, in *EstimateAudienceRequest, opts ...grpc.CallOption) (*AudienceEstimate, error) {
+	out := new(AudienceEstimate)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/EstimateAudience", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) SyncTopicSubscriptions(ctx context.Context, in *SyncTopicSubscriptionsRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/SyncTopicSubscriptions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) GetBroadcast(ctx context.Context, in *GetBroadcastRequest, opts ...grpc.CallOption) (*Broadcast, error) {
+	out := new(Broadcast)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/GetBroadcast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) ListBroadcasts(ctx context.Context, in *ListBroadcastsRequest, opts ...grpc.CallOption) (*Broadcasts, error) {
+	out := new(Broadcasts)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/ListBroadcasts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) CancelBroadcast(ctx context.Context, in *CancelBroadcastRequest, opts ...grpc.CallOption) (*Broadcast, error) {
+	out := new(Broadcast)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/CancelBroadcast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*Schedules, error) {
+	out := new(Schedules)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/ListSchedules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) PauseSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error) {
+	out := new(Schedule)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/PauseSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) ResumeSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Schedule, error) {
+	out := new(Schedule)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/ResumeSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) DeleteSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/DeleteSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*MessageTemplate, error) {
+	out := new(MessageTemplate)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/CreateTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*MessageTemplate, error) {
+	out := new(MessageTemplate)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/UpdateTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) GetTemplate(ctx context.Context, in *TemplateRequest, opts ...grpc.CallOption) (*MessageTemplate, error) {
+	out := new(MessageTemplate)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/GetTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*MessageTemplates, error) {
+	out := new(MessageTemplates)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/ListTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) DeleteTemplate(ctx context.Context, in *TemplateRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/DeleteTemplate", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -791,6 +3161,24 @@ func (c *messagingClient) SendMessage(ctx context.Context, in *Message, opts ...
 	return out, nil
 }
 
+func (c *messagingClient) GetMessageDeliveryStatus(ctx context.Context, in *GetMessageDeliveryStatusRequest, opts ...grpc.CallOption) (*MessageDeliveryStatus, error) {
+	out := new(MessageDeliveryStatus)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/GetMessageDeliveryStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagingClient) CountStaleDeviceTokens(ctx context.Context, in *CountStaleDeviceTokensRequest, opts ...grpc.CallOption) (*StaleDeviceTokens, error) {
+	out := new(StaleDeviceTokens)
+	err := c.cc.Invoke(ctx, "/covitrace.Messaging/CountStaleDeviceTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *messagingClient) ListMessages(ctx context.Context, in *ListMessagesRequest, opts ...grpc.CallOption) (*Messages, error) {
 	out := new(Messages)
 	err := c.cc.Invoke(ctx, "/covitrace.Messaging/ListMessages", in, out, opts...)
@@ -824,8 +3212,40 @@ type MessagingServer interface {
 	AlertContacts(Messaging_AlertContactsServer) error
 	// Broadcasts a message
 	BroadCastMessage(context.Context, *BroadCastMessageRequest) (*BroadCastMessageResponse, error)
+	// Counts the users a broadcast would be sent to without sending it
+	EstimateAudience(context.Context, *EstimateAudienceRequest) (*AudienceEstimate, error)
+	// Subscribes the devices of a user to the FCM topics of their county, status and language
+	SyncTopicSubscriptions(context.Context, *SyncTopicSubscriptionsRequest) (*empty.Empty, error)
+	// Retrieves a broadcast together with its progress
+	GetBroadcast(context.Context, *GetBroadcastRequest) (*Broadcast, error)
+	// Retrieves broadcasts, most recent first
+	ListBroadcasts(context.Context, *ListBroadcastsRequest) (*Broadcasts, error)
+	// Cancels a broadcast, users it was sent to keep the message
+	CancelBroadcast(context.Context, *CancelBroadcastRequest) (*Broadcast, error)
+	// Retrieves scheduled broadcasts, most recent first
+	ListSchedules(context.Context, *ListSchedulesRequest) (*Schedules, error)
+	// Pauses a scheduled broadcast
+	PauseSchedule(context.Context, *ScheduleRequest) (*Schedule, error)
+	// Resumes a paused scheduled broadcast, runs missed while paused are skipped
+	ResumeSchedule(context.Context, *ScheduleRequest) (*Schedule, error)
+	// Deletes a scheduled broadcast, broadcasts already sent are kept
+	DeleteSchedule(context.Context, *ScheduleRequest) (*empty.Empty, error)
+	// Adds a message template
+	CreateTemplate(context.Context, *CreateTemplateRequest) (*MessageTemplate, error)
+	// Changes the wording of a message template
+	UpdateTemplate(context.Context, *UpdateTemplateRequest) (*MessageTemplate, error)
+	// Retrieves the language variant of a message template
+	GetTemplate(context.Context, *TemplateRequest) (*MessageTemplate, error)
+	// Retrieves message templates
+	ListTemplates(context.Context, *ListTemplatesRequest) (*MessageTemplates, error)
+	// Deletes the language variant of a message template
+	DeleteTemplate(context.Context, *TemplateRequest) (*empty.Empty, error)
 	// Sends message to a single destination
 	SendMessage(context.Context, *Message) (*SendMessageResponse, error)
+	// Retrieves the delivery status of a message or how many users received a broadcast
+	GetMessageDeliveryStatus(context.Context, *GetMessageDeliveryStatusRequest) (*MessageDeliveryStatus, error)
+	// Counts users per county whose device tokens were found invalid
+	CountStaleDeviceTokens(context.Context, *CountStaleDeviceTokensRequest) (*StaleDeviceTokens, error)
 	// Retrieves user messages
 	ListMessages(context.Context, *ListMessagesRequest) (*Messages, error)
 	// Marks all messages as read for a user
@@ -882,6 +3302,258 @@ func _Messaging_BroadCastMessage_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Messaging_EstimateAudience_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateAudienceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).EstimateAudience(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/EstimateAudience",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).EstimateAudience(ctx, req.(*EstimateAudienceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_SyncTopicSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncTopicSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).SyncTopicSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/SyncTopicSubscriptions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).SyncTopicSubscriptions(ctx, req.(*SyncTopicSubscriptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_GetBroadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBroadcastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).GetBroadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/GetBroadcast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).GetBroadcast(ctx, req.(*GetBroadcastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_ListBroadcasts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBroadcastsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).ListBroadcasts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/ListBroadcasts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).ListBroadcasts(ctx, req.(*ListBroadcastsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_CancelBroadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelBroadcastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).CancelBroadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/CancelBroadcast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).CancelBroadcast(ctx, req.(*CancelBroadcastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_ListSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).ListSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/ListSchedules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).ListSchedules(ctx, req.(*ListSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_PauseSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).PauseSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/PauseSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).PauseSchedule(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_ResumeSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).ResumeSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/ResumeSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).ResumeSchedule(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_DeleteSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).DeleteSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/DeleteSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).DeleteSchedule(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_CreateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).CreateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/CreateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).CreateTemplate(ctx, req.(*CreateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_UpdateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).UpdateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/UpdateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).UpdateTemplate(ctx, req.(*UpdateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_GetTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).GetTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/GetTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).GetTemplate(ctx, req.(*TemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).ListTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/ListTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).ListTemplates(ctx, req.(*ListTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_DeleteTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).DeleteTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/DeleteTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).DeleteTemplate(ctx, req.(*TemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Messaging_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Message)
 	if err := dec(in); err != nil {
@@ -900,6 +3572,42 @@ func _Messaging_SendMessage_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Messaging_GetMessageDeliveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMessageDeliveryStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).GetMessageDeliveryStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/GetMessageDeliveryStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).GetMessageDeliveryStatus(ctx, req.(*GetMessageDeliveryStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Messaging_CountStaleDeviceTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountStaleDeviceTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagingServer).CountStaleDeviceTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/covitrace.Messaging/CountStaleDeviceTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagingServer).CountStaleDeviceTokens(ctx, req.(*CountStaleDeviceTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Messaging_ListMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListMessagesRequest)
 	if err := dec(in); err != nil {
@@ -962,10 +3670,74 @@ var _Messaging_serviceDesc = grpc.ServiceDesc{
 			MethodName: "BroadCastMessage",
 			Handler:    _Messaging_BroadCastMessage_Handler,
 		},
+		{
+			MethodName: "EstimateAudience",
+			Handler:    _Messaging_EstimateAudience_Handler,
+		},
+		{
+			MethodName: "SyncTopicSubscriptions",
+			Handler:    _Messaging_SyncTopicSubscriptions_Handler,
+		},
+		{
+			MethodName: "GetBroadcast",
+			Handler:    _Messaging_GetBroadcast_Handler,
+		},
+		{
+			MethodName: "ListBroadcasts",
+			Handler:    _Messaging_ListBroadcasts_Handler,
+		},
+		{
+			MethodName: "CancelBroadcast",
+			Handler:    _Messaging_CancelBroadcast_Handler,
+		},
+		{
+			MethodName: "ListSchedules",
+			Handler:    _Messaging_ListSchedules_Handler,
+		},
+		{
+			MethodName: "PauseSchedule",
+			Handler:    _Messaging_PauseSchedule_Handler,
+		},
+		{
+			MethodName: "ResumeSchedule",
+			Handler:    _Messaging_ResumeSchedule_Handler,
+		},
+		{
+			MethodName: "DeleteSchedule",
+			Handler:    _Messaging_DeleteSchedule_Handler,
+		},
+		{
+			MethodName: "CreateTemplate",
+			Handler:    _Messaging_CreateTemplate_Handler,
+		},
+		{
+			MethodName: "UpdateTemplate",
+			Handler:    _Messaging_UpdateTemplate_Handler,
+		},
+		{
+			MethodName: "GetTemplate",
+			Handler:    _Messaging_GetTemplate_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _Messaging_ListTemplates_Handler,
+		},
+		{
+			MethodName: "DeleteTemplate",
+			Handler:    _Messaging_DeleteTemplate_Handler,
+		},
 		{
 			MethodName: "SendMessage",
 			Handler:    _Messaging_SendMessage_Handler,
 		},
+		{
+			MethodName: "GetMessageDeliveryStatus",
+			Handler:    _Messaging_GetMessageDeliveryStatus_Handler,
+		},
+		{
+			MethodName: "CountStaleDeviceTokens",
+			Handler:    _Messaging_CountStaleDeviceTokens_Handler,
+		},
 		{
 			MethodName: "ListMessages",
 			Handler:    _Messaging_ListMessages_Handler,